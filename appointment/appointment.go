@@ -1,6 +1,7 @@
 package appointment
 
 import (
+	"errors"
 	"log"
 
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
@@ -12,7 +13,9 @@ type AppointmentLogic interface {
 	GetAppointmentByID(ID uint) (*model.Appointment, error)
 	GetAllAppointments(limit, offset int) ([]model.Appointment, error)
 	CreateAppointment(appointment *model.Appointment) (model.PriceDetails, error)
+	QuoteAppointment(appointment *model.Appointment, currency string) (*model.AppointmentQuote, error)
 	UpdateAppointment(ID uint, appointment *model.Appointment) (model.PriceDetails, error)
+	UpdateAppointmentStatus(ID uint, status model.AppointmentStatus) error
 	DeleteAppointment(ID uint) error
 }
 
@@ -81,6 +84,16 @@ func (l *appointmentLogic) CreateAppointment(appointment *model.Appointment) (mo
 	return finalPrice, nil
 }
 
+func (l *appointmentLogic) QuoteAppointment(appointment *model.Appointment, currency string) (*model.AppointmentQuote, error) {
+	quote, err := l.logicAppointmentCreate.QuoteAppointment(appointment, currency)
+	if err != nil {
+		log.Printf("appointment-logic -> method: QuoteAppointment: Error to quote: %v", err)
+		return nil, err
+	}
+
+	return quote, nil
+}
+
 func (l *appointmentLogic) UpdateAppointment(ID uint, appointment *model.Appointment) (model.PriceDetails, error) {
 	finalPrice, err := l.logicAppointmentUpdate.UpdateAppointment(ID, appointment)
 	if err != nil {
@@ -91,13 +104,42 @@ func (l *appointmentLogic) UpdateAppointment(ID uint, appointment *model.Appoint
 	return finalPrice, nil
 }
 
+// Solo una cita programada puede completarse o cancelarse; al cancelarla el repositorio devuelve la
+// sesión prepagada en la misma transacción
+func (l *appointmentLogic) UpdateAppointmentStatus(ID uint, status model.AppointmentStatus) error {
+	appointment, err := l.GetAppointmentByID(ID)
+	if err != nil {
+		return err
+	}
+
+	if status != model.AppointmentCompleted && status != model.AppointmentCancelled {
+		return response.ErrorInvalidAppointmentStatus
+	}
+
+	if appointment.Status != model.AppointmentScheduled {
+		return response.ErrorAppointmentNotScheduled
+	}
+
+	err = l.repositoryAppointmentMain.UpdateStatus(ID, status)
+	if err != nil {
+		if errors.Is(err, response.ErrorAppointmentNotScheduled) {
+			return err
+		}
+
+		log.Printf("appointment-logic: Error updating status of appointment with ID %d: %v", ID, err)
+		return response.ErrorToUpdatedAppointment
+	}
+
+	return nil
+}
+
 func (l *appointmentLogic) DeleteAppointment(ID uint) error {
-	_, err := l.GetAppointmentByID(ID)
+	appointment, err := l.GetAppointmentByID(ID)
 	if err != nil {
 		return response.ErrorAppointmentNotFound
 	}
 
-	err = l.repositoryAppointment.Delete(ID)
+	err = l.repositoryAppointmentMain.Delete(appointment)
 	if err != nil {
 		return response.ErrorToDeletedAppointment
 	}
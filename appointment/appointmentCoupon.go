@@ -0,0 +1,99 @@
+package appointment
+
+import (
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/calculation"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+type appointmentCoupon struct {
+	repositoryAppointmentMain repository.AppointmentRepository
+	repositoryCouponMain      repository.CouponRepository
+}
+
+type AppointmentCoupon interface {
+	ApplyCoupon(appointment *model.Appointment, patientID uint, priceDetails model.PriceDetails) (*model.Coupon, error)
+	ReapplyCoupon(couponID uint, appointment *model.Appointment, priceDetails model.PriceDetails) (*model.Coupon, error)
+	RegisterAppointment(appointment *model.Appointment, coupon *model.Coupon) error
+}
+
+func NewAppointmentCoupon(repositoryAppointmentMain repository.AppointmentRepository, repositoryCouponMain repository.CouponRepository) AppointmentCoupon {
+	return &appointmentCoupon{repositoryAppointmentMain: repositoryAppointmentMain, repositoryCouponMain: repositoryCouponMain}
+}
+
+// Valida el cupón indicado en la cita y descuenta su monto del precio.
+// Sin paciente (cotización sin DNI) no se verifica el límite por paciente.
+func (l *appointmentCoupon) ApplyCoupon(appointment *model.Appointment, patientID uint, priceDetails model.PriceDetails) (*model.Coupon, error) {
+	code := strings.ToUpper(strings.TrimSpace(appointment.CouponCode))
+
+	coupon, err := l.repositoryCouponMain.GetByCode(code)
+	if err != nil {
+		return nil, response.ErrorCouponNotFound
+	}
+
+	if !calculation.IsCouponValidOn(*coupon, validate.FormatDate(time.Now())) {
+		return nil, response.ErrorCouponNotValid
+	}
+
+	discounts, eligible := calculation.CouponItemDiscounts(*coupon, priceDetails.GetItems())
+	if !eligible {
+		return nil, response.ErrorCouponNotApplicable
+	}
+
+	if coupon.MaxUses > 0 {
+		used, err := l.repositoryCouponMain.CountRedemptions(coupon.ID)
+		if err != nil {
+			return nil, response.ErrorFetchingCouponUsage
+		}
+
+		if used >= int64(coupon.MaxUses) {
+			return nil, response.ErrorCouponExhausted
+		}
+	}
+
+	if coupon.MaxUsesPerPatient > 0 && patientID != 0 {
+		used, err := l.repositoryCouponMain.CountPatientRedemptions(coupon.ID, patientID)
+		if err != nil {
+			return nil, response.ErrorFetchingCouponUsage
+		}
+
+		if used >= int64(coupon.MaxUsesPerPatient) {
+			return nil, response.ErrorCouponPatientLimit
+		}
+	}
+
+	priceDetails.ApplyCoupon(coupon.Code, discounts)
+
+	return coupon, nil
+}
+
+// Recalcula el descuento de un cupón ya canjeado en la cita; sus límites y vigencia ya se verificaron al reservar
+func (l *appointmentCoupon) ReapplyCoupon(couponID uint, appointment *model.Appointment, priceDetails model.PriceDetails) (*model.Coupon, error) {
+	coupon, err := l.repositoryCouponMain.GetByID(couponID)
+	if err != nil {
+		return nil, response.ErrorCouponNotFound
+	}
+
+	discounts, eligible := calculation.CouponItemDiscounts(*coupon, priceDetails.GetItems())
+	if !eligible {
+		return nil, response.ErrorCouponNotApplicable
+	}
+
+	priceDetails.ApplyCoupon(coupon.Code, discounts)
+
+	return coupon, nil
+}
+
+// Registra la cita; con cupón, el uso se registra en la misma transacción
+func (l *appointmentCoupon) RegisterAppointment(appointment *model.Appointment, coupon *model.Coupon) error {
+	if coupon == nil {
+		return l.repositoryAppointmentMain.Create(appointment)
+	}
+
+	return l.repositoryCouponMain.CreateAppointmentWithCoupon(appointment, coupon)
+}
@@ -0,0 +1,72 @@
+package appointment
+
+import (
+	"errors"
+	"log"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+type appointmentCredit struct {
+	repositoryCreditMain repository.PackageCreditRepository
+}
+
+type AppointmentCredit interface {
+	FindCredit(patientID, serviceID uint, date string) (*model.PackageCredit, error)
+	CheckCredit(creditID uint, date string) error
+	RegisterAppointment(appointment *model.Appointment) error
+}
+
+func NewAppointmentCredit(repositoryCreditMain repository.PackageCreditRepository) AppointmentCredit {
+	return &appointmentCredit{repositoryCreditMain: repositoryCreditMain}
+}
+
+// Busca un crédito de paquete con sesiones disponibles vigente en la fecha de la cita; nil si no hay
+func (l *appointmentCredit) FindCredit(patientID, serviceID uint, date string) (*model.PackageCredit, error) {
+	if patientID == 0 || serviceID == 0 {
+		return nil, nil
+	}
+
+	credit, err := l.repositoryCreditMain.GetAvailableCredit(patientID, serviceID, date)
+	if err != nil {
+		if errors.Is(err, response.ErrorPackageCreditNotFound) {
+			return nil, nil
+		}
+
+		log.Printf("appointment-credit: Error fetching package credit of patient with ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingPackageCredits
+	}
+
+	return credit, nil
+}
+
+// Verifica que el crédito ya consumido por la cita siga vigente en la nueva fecha
+func (l *appointmentCredit) CheckCredit(creditID uint, date string) error {
+	credit, err := l.repositoryCreditMain.GetCreditByID(creditID)
+	if err != nil {
+		return response.ErrorPackageCreditNotFound
+	}
+
+	if credit.ExpiresOn != "" && credit.ExpiresOn < date {
+		return response.ErrorPackageCreditExpired
+	}
+
+	return nil
+}
+
+// Registra la cita consumiendo una sesión del crédito
+func (l *appointmentCredit) RegisterAppointment(appointment *model.Appointment) error {
+	err := l.repositoryCreditMain.CreateAppointmentWithCredit(appointment)
+	if err != nil {
+		if errors.Is(err, response.ErrorPackageCreditExhausted) || errors.Is(err, response.ErrorPackageCreditExpired) {
+			return err
+		}
+
+		log.Printf("appointment-credit: Error saving appointment with package credit: %v", err)
+		return response.ErrorToCreatedAppointment
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package appointment
+
+import (
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/calculation"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+type appointmentItems struct {
+	appointmentServiceID AppointmentServiceID
+	appointmentPackageID AppointmentPackageID
+}
+
+type AppointmentItems interface {
+	PriceItems(items []model.AppointmentItem, policy *model.PatientPolicy, booked []model.AppointmentItem) (*model.FinalAppointmentPrice, error)
+}
+
+func NewAppointmentItems(appointmentServiceID AppointmentServiceID, appointmentPackageID AppointmentPackageID) AppointmentItems {
+	return &appointmentItems{appointmentServiceID: appointmentServiceID, appointmentPackageID: appointmentPackageID}
+}
+
+// Cada línea se cotiza como un servicio o un paquete individual y sus montos se multiplican por la cantidad.
+// Un paquete que ya estaba en las líneas reservadas (booked) conserva el precio de la reserva.
+func (l *appointmentItems) PriceItems(items []model.AppointmentItem, policy *model.PatientPolicy, booked []model.AppointmentItem) (*model.FinalAppointmentPrice, error) {
+	pricedItems := make([]model.AppointmentItem, 0, len(items))
+
+	for _, item := range items {
+		bookedItem, found := bookedPackageItem(booked, item.PackageID)
+		if found {
+			pricedItems = append(pricedItems, calculation.ItemTimes(calculation.BookedUnitItem(bookedItem), item.Quantity))
+			continue
+		}
+
+		var priceDetails model.PriceDetails
+		if item.ServiceID != 0 {
+			finalServicePrice, err := l.appointmentServiceID.IsServiceIDEXists(item.ServiceID, policy)
+			if err != nil {
+				return nil, err
+			}
+
+			priceDetails = finalServicePrice
+		} else {
+			finalPkgPrice, err := l.appointmentPackageID.IsPackageIDExists(item.PackageID, policy)
+			if err != nil {
+				return nil, err
+			}
+
+			priceDetails = finalPkgPrice
+		}
+
+		pricedItems = append(pricedItems, calculation.ItemTimes(priceDetails.GetItems()[0], item.Quantity))
+	}
+
+	return calculation.TotalAppointmentAmount(pricedItems, policy), nil
+}
+
+// Línea reservada del paquete indicado; las líneas de servicios siempre se cotizan de nuevo
+func bookedPackageItem(booked []model.AppointmentItem, packageID uint) (model.AppointmentItem, bool) {
+	if packageID == 0 {
+		return model.AppointmentItem{}, false
+	}
+
+	for _, item := range booked {
+		if item.PackageID == packageID {
+			return item, true
+		}
+	}
+
+	return model.AppointmentItem{}, false
+}
+
+// Valida las líneas solicitadas. Sin líneas se usa el servicio o paquete de la cita, y una sola línea
+// de cantidad uno se registra como servicio o paquete de la cita para cotizarse como hasta ahora.
+// Con varias líneas la cita no tiene un servicio ni un paquete propio.
+func normalizeItems(appointment *model.Appointment) error {
+	if len(appointment.Items) == 0 {
+		if appointment.ServiceID == 0 && appointment.PackageID == 0 {
+			return response.ErrorPackageAndServiceEmpty
+		}
+
+		return nil
+	}
+
+	for i := range appointment.Items {
+		item := &appointment.Items[i]
+		if (item.ServiceID == 0) == (item.PackageID == 0) {
+			return response.ErrorInvalidAppointmentItem
+		}
+
+		if item.Quantity < 0 {
+			return response.ErrorInvalidItemQuantity
+		}
+
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+	}
+
+	if len(appointment.Items) == 1 && appointment.Items[0].Quantity == 1 {
+		appointment.ServiceID = appointment.Items[0].ServiceID
+		appointment.PackageID = appointment.Items[0].PackageID
+		appointment.Items = nil
+
+		return nil
+	}
+
+	appointment.ServiceID = 0
+	appointment.PackageID = 0
+
+	return nil
+}
@@ -1,38 +1,51 @@
 package appointment
 
 import (
+	"time"
+
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/calculation"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
 )
 
 type appointmentPackageID struct {
 	repositoryPackageMain repository.PackageRepository
+	repositoryPricingRule repository.PricingRuleRepository
+	logicCurrency         logic.CurrencyLogic
 }
 
 type AppointmentPackageID interface {
-	IsPackageIDExists(ID uint, hasInsurance bool) (*model.FinalPackagePriceWithInsegurance, error)
+	IsPackageIDExists(ID uint, policy *model.PatientPolicy) (*model.FinalPackagePriceWithInsegurance, error)
 }
 
-func NewAppointmentPackageID(repositoryPackageMain repository.PackageRepository) AppointmentPackageID {
-	return &appointmentPackageID{repositoryPackageMain: repositoryPackageMain}
+func NewAppointmentPackageID(repositoryPackageMain repository.PackageRepository, repositoryPricingRule repository.PricingRuleRepository, logicCurrency logic.CurrencyLogic) AppointmentPackageID {
+	return &appointmentPackageID{repositoryPackageMain: repositoryPackageMain, repositoryPricingRule: repositoryPricingRule, logicCurrency: logicCurrency}
 }
 
-func (l *appointmentPackageID) IsPackageIDExists(ID uint, hasInsurance bool) (*model.FinalPackagePriceWithInsegurance, error) {
+func (l *appointmentPackageID) IsPackageIDExists(ID uint, policy *model.PatientPolicy) (*model.FinalPackagePriceWithInsegurance, error) {
 	pkg, err := l.repositoryPackageMain.GetByID(ID)
 	if err != nil || pkg == nil {
 		return nil, response.ErrorPackageNotFound
 	}
 
-	finalPricePkg := calculation.TotalServicePackageAmountToAppointment(pkg.Services, hasInsurance)
+	today := validate.FormatDate(time.Now())
+
+	rules, err := l.repositoryPricingRule.GetActiveRules(today)
+	if err != nil {
+		return nil, response.ErrorFetchingPricingRules
+	}
+
+	//los precios se calculan siempre en moneda base
+	services, err := l.logicCurrency.ServicesToBase(pkg.Services, today)
+	if err != nil {
+		return nil, err
+	}
+
+	finalPricePkg := calculation.TotalServicePackageAmountToAppointment(pkg.ID, services, calculation.PackageSessions(*pkg), calculation.PackageRules(*pkg, rules), policy)
+	finalPricePkg.Name = pkg.Name
 
-	return &model.FinalPackagePriceWithInsegurance{
-		InsuranceDiscount: finalPricePkg.InsuranceDiscount,
-		FinalPackagePrice: model.FinalPackagePrice{
-			TotalAmount:     finalPricePkg.TotalAmount,
-			DiscountPackage: finalPricePkg.DiscountPackage,
-			FinalPrice:      finalPricePkg.FinalPrice,
-		},
-	}, nil
+	return finalPricePkg, nil
 }
@@ -1,32 +1,51 @@
 package appointment
 
 import (
+	"time"
+
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/calculation"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
 )
 
 type appointmentServiceID struct {
 	repositoryAppointment repository.Repository[model.Appointment]
 	repositoryService     repository.Repository[model.Service]
+	repositoryPricingRule repository.PricingRuleRepository
+	logicCurrency         logic.CurrencyLogic
 }
 
 type AppointmentServiceID interface {
-	IsServiceIDEXists(ID uint, hasInsurance bool) (*model.FinalServicePrice, error)
+	IsServiceIDEXists(ID uint, policy *model.PatientPolicy) (*model.FinalServicePrice, error)
 }
 
-func NewAppointmentServiceID(repositoryAppointment repository.Repository[model.Appointment], repositoryService repository.Repository[model.Service]) AppointmentServiceID {
-	return &appointmentServiceID{repositoryAppointment: repositoryAppointment, repositoryService: repositoryService}
+func NewAppointmentServiceID(repositoryAppointment repository.Repository[model.Appointment], repositoryService repository.Repository[model.Service], repositoryPricingRule repository.PricingRuleRepository, logicCurrency logic.CurrencyLogic) AppointmentServiceID {
+	return &appointmentServiceID{repositoryAppointment: repositoryAppointment, repositoryService: repositoryService, repositoryPricingRule: repositoryPricingRule, logicCurrency: logicCurrency}
 }
 
-func (l *appointmentServiceID) IsServiceIDEXists(ID uint, hasInsurance bool) (*model.FinalServicePrice, error) {
+func (l *appointmentServiceID) IsServiceIDEXists(ID uint, policy *model.PatientPolicy) (*model.FinalServicePrice, error) {
 	service, err := l.repositoryService.GetByID(ID)
 	if err != nil {
 		return nil, response.ErrorServiceNotFound
 	}
 
-	finalServicePrice := calculation.TotalServiceAmount(service.Price, hasInsurance)
+	today := validate.FormatDate(time.Now())
+
+	rules, err := l.repositoryPricingRule.GetActiveRules(today)
+	if err != nil {
+		return nil, response.ErrorFetchingPricingRules
+	}
+
+	//los precios se calculan siempre en moneda base
+	services, err := l.logicCurrency.ServicesToBase([]model.Service{*service}, today)
+	if err != nil {
+		return nil, err
+	}
+
+	finalServicePrice := calculation.TotalServiceAmount(services[0], rules, policy)
 
 	return finalServicePrice, nil
 }
@@ -0,0 +1,136 @@
+package appointment
+
+import (
+	"log"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/calculation"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+type appointmentServices struct {
+	repositoryDoctorMain  repository.DoctorRepository
+	repositoryService     repository.Repository[model.Service]
+	repositoryPackageMain repository.PackageRepository
+}
+
+type AppointmentServices interface {
+	CheckServices(appointment, existingAppointment *model.Appointment) error
+}
+
+func NewAppointmentServices(repositoryDoctorMain repository.DoctorRepository, repositoryService repository.Repository[model.Service], repositoryPackageMain repository.PackageRepository) AppointmentServices {
+	return &appointmentServices{repositoryDoctorMain: repositoryDoctorMain, repositoryService: repositoryService, repositoryPackageMain: repositoryPackageMain}
+}
+
+// Verifica que los servicios de la cita, incluidos los de sus paquetes, estén activos y que el médico
+// tenga la especialidad que requieren, y que sus paquetes estén a la venta. Al reprogramar
+// (existingAppointment no nil) se admiten los servicios y paquetes que la cita ya tenía reservados.
+// Una cotización sin médico elegido solo verifica los servicios y paquetes.
+func (l *appointmentServices) CheckServices(appointment, existingAppointment *model.Appointment) error {
+	var doctor *model.Doctor
+	if appointment.DoctorID != 0 {
+		doctorFound, err := l.repositoryDoctorMain.GetByID(appointment.DoctorID)
+		if err != nil {
+			return response.ErrorDoctorNotFoundID
+		}
+
+		doctor = doctorFound
+	}
+
+	services, packages, err := l.getServices(appointment)
+	if err != nil {
+		return err
+	}
+
+	booked := map[uint]bool{}
+	bookedPackages := map[uint]bool{}
+	if existingAppointment != nil {
+		bookedServices, bookedPkgs, err := l.getServices(existingAppointment)
+		if err != nil {
+			return err
+		}
+
+		for _, service := range bookedServices {
+			booked[service.ID] = true
+		}
+
+		for _, pkg := range bookedPkgs {
+			bookedPackages[pkg.ID] = true
+		}
+	}
+
+	//el paquete se vende al reservar, por eso el periodo de venta se compara con la fecha de hoy
+	today := validate.FormatDate(time.Now())
+	for _, pkg := range packages {
+		if !bookedPackages[pkg.ID] && !calculation.IsPackageOnSale(pkg, today) {
+			return response.ErrorPackageNotOnSale
+		}
+	}
+
+	for _, service := range services {
+		if !service.Active && !booked[service.ID] {
+			return response.ErrorServiceInactive
+		}
+
+		if doctor != nil && !hasSpecialty(doctor, service) {
+			return response.ErrorDoctorSpecialty
+		}
+	}
+
+	return nil
+}
+
+// Servicios de la cita: su servicio, los servicios de su paquete o los de cada una de sus líneas,
+// junto con los paquetes de la cita
+func (l *appointmentServices) getServices(appointment *model.Appointment) ([]model.Service, []model.Package, error) {
+	items := appointment.Items
+	if len(items) == 0 {
+		items = []model.AppointmentItem{{ServiceID: appointment.ServiceID, PackageID: appointment.PackageID}}
+	}
+
+	services := []model.Service{}
+	packages := []model.Package{}
+	for _, item := range items {
+		if item.ServiceID != 0 {
+			service, err := l.repositoryService.GetByID(item.ServiceID)
+			if err != nil {
+				log.Printf("appointment-services: Error fetching service with ID %d: %v", item.ServiceID, err)
+				return nil, nil, response.ErrorServiceNotFound
+			}
+
+			services = append(services, *service)
+			continue
+		}
+
+		if item.PackageID != 0 {
+			pkg, err := l.repositoryPackageMain.GetByID(item.PackageID)
+			if err != nil {
+				log.Printf("appointment-services: Error fetching package with ID %d: %v", item.PackageID, err)
+				return nil, nil, response.ErrorPackageNotFound
+			}
+
+			services = append(services, pkg.Services...)
+			packages = append(packages, *pkg)
+		}
+	}
+
+	return services, packages, nil
+}
+
+// Un servicio sin especialidad requerida lo puede realizar cualquier médico
+func hasSpecialty(doctor *model.Doctor, service model.Service) bool {
+	if service.SpecialtyID == nil {
+		return true
+	}
+
+	for _, specialty := range doctor.Specialties {
+		if specialty.ID == *service.SpecialtyID {
+			return true
+		}
+	}
+
+	return false
+}
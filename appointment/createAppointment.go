@@ -2,14 +2,19 @@ package appointment
 
 import (
 	"errors"
+	"time"
 
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
 )
 
 type AppointmentCreate interface {
 	CreateAppointment(appointment *model.Appointment) (model.PriceDetails, error)
+	QuoteAppointment(appointment *model.Appointment, currency string) (*model.AppointmentQuote, error)
 }
 
 type appointmentCreate struct {
@@ -23,6 +28,12 @@ type appointmentCreate struct {
 	appointmentPackageID  AppointmentPackageID
 	appointmentServiceID  AppointmentServiceID
 	appointmentTime       AppointmentTime
+	logicCurrency         logic.CurrencyLogic
+	appointmentCoupon     AppointmentCoupon
+	logicInsurance        logic.InsuranceLogic
+	appointmentCredit     AppointmentCredit
+	appointmentItems      AppointmentItems
+	appointmentServices   AppointmentServices
 }
 
 func NewAppointmentCreate(
@@ -36,6 +47,12 @@ func NewAppointmentCreate(
 	appointmentPackageID AppointmentPackageID,
 	appointmentServiceID AppointmentServiceID,
 	appointmentTime AppointmentTime,
+	logicCurrency logic.CurrencyLogic,
+	appointmentCoupon AppointmentCoupon,
+	logicInsurance logic.InsuranceLogic,
+	appointmentCredit AppointmentCredit,
+	appointmentItems AppointmentItems,
+	appointmentServices AppointmentServices,
 ) AppointmentCreate {
 	return &appointmentCreate{
 		repositoryAppointment: repositoryAppointment,
@@ -48,6 +65,12 @@ func NewAppointmentCreate(
 		appointmentPackageID:  appointmentPackageID,
 		appointmentServiceID:  appointmentServiceID,
 		appointmentTime:       appointmentTime,
+		logicCurrency:         logicCurrency,
+		appointmentCoupon:     appointmentCoupon,
+		logicInsurance:        logicInsurance,
+		appointmentCredit:     appointmentCredit,
+		appointmentItems:      appointmentItems,
+		appointmentServices:   appointmentServices,
 	}
 }
 
@@ -77,16 +100,100 @@ func (l *appointmentCreate) CreateAppointment(appointment *model.Appointment) (m
 		return nil, err
 	}
 
-	appointmentCreated := l.buildAppointment(appointment, patientFound, priceDetails)
+	//solo se reservan servicios activos con un médico de la especialidad requerida
+	err = l.appointmentServices.CheckServices(appointment, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.CreditID != nil && appointment.CouponCode != "" {
+		return nil, response.ErrorCouponWithCredit
+	}
+
+	var coupon *model.Coupon
+	if appointment.CouponCode != "" {
+		coupon, err = l.appointmentCoupon.ApplyCoupon(appointment, patientFound.ID, priceDetails)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	appointmentCreated := l.buildAppointment(appointment, patientFound, priceDetails, coupon)
+
+	//la cita cubierta por un crédito de paquete consume una sesión al registrarse
+	if appointmentCreated.CreditID != nil {
+		err = l.appointmentCredit.RegisterAppointment(appointmentCreated)
+	} else {
+		err = l.appointmentCoupon.RegisterAppointment(appointmentCreated, coupon)
+	}
 
-	err = l.repositoryAppointment.Create(appointmentCreated)
 	if err != nil {
 		return nil, err
 	}
 
+	// Quien reservó recibe el ID de la cita registrada
+	appointment.ID = appointmentCreated.ID
+
 	return priceDetails, nil
 }
 
+// Cotiza el precio de la cita sin registrarla; sin DNI se cotiza como paciente sin seguro.
+// El precio se calcula en moneda base y el total final se expresa en la moneda solicitada.
+func (l *appointmentCreate) QuoteAppointment(appointment *model.Appointment, currency string) (*model.AppointmentQuote, error) {
+	patient := &model.Patient{}
+	if appointment.PatientDNI != "" {
+		patientFound, err := l.isPatientDNIExists(appointment.PatientDNI)
+		if err != nil {
+			return nil, err
+		}
+
+		patient = patientFound
+	}
+
+	priceDetails, err := l.getPriceDetails(appointment, patient)
+	if err != nil {
+		return nil, err
+	}
+
+	//se cotiza solo lo que se puede reservar, con las mismas verificaciones que al crear la cita
+	err = l.appointmentServices.CheckServices(appointment, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.CreditID != nil && appointment.CouponCode != "" {
+		return nil, response.ErrorCouponWithCredit
+	}
+
+	if appointment.CouponCode != "" {
+		_, err = l.appointmentCoupon.ApplyCoupon(appointment, patient.ID, priceDetails)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	currency = logic.NormalizeCurrency(currency)
+
+	finalPrice, rate, err := l.logicCurrency.FromBase(priceDetails.GetFinalPrice(), currency, validate.FormatDate(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	patientAmount, _, err := l.logicCurrency.FromBase(priceDetails.GetPatientAmount(), currency, validate.FormatDate(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AppointmentQuote{
+		Price:         priceDetails,
+		BaseCurrency:  config.Envs.BaseCurrency,
+		Currency:      currency,
+		ExchangeRate:  rate,
+		FinalPrice:    finalPrice,
+		PatientAmount: patientAmount,
+	}, nil
+}
+
 func (l *appointmentCreate) isPatientDNIExists(DNI string) (*model.Patient, error) {
 	patient, err := l.repositoryPatientMain.GetPatientByDNI(DNI)
 	if err != nil {
@@ -96,46 +203,96 @@ func (l *appointmentCreate) isPatientDNIExists(DNI string) (*model.Patient, erro
 	return patient, nil
 }
 
+// Un servicio con sesiones prepagadas disponibles se cubre con el crédito del paquete; si no,
+// el precio se reparte entre la aseguradora y el paciente según la póliza vigente en la fecha de la cita.
+// Una cita con varias líneas suma el precio de cada una y no consume créditos de paquete.
 func (l *appointmentCreate) getPriceDetails(appointment *model.Appointment, patient *model.Patient) (model.PriceDetails, error) {
+	err := normalizeItems(appointment)
+	if err != nil {
+		return nil, err
+	}
+
+	date := appointment.Date
+	if date == "" {
+		date = validate.FormatDate(time.Now())
+	}
+
+	credit, err := l.appointmentCredit.FindCredit(patient.ID, appointment.ServiceID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment.CreditID = nil
+	if credit != nil {
+		finalServicePrice, err := l.appointmentServiceID.IsServiceIDEXists(appointment.ServiceID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		finalServicePrice.ApplyPackageCredit(credit.ID)
+		appointment.CreditID = &credit.ID
+		appointment.PolicyID = nil
+
+		return finalServicePrice, nil
+	}
+
+	policy, err := l.logicInsurance.GetValidPolicy(patient.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	//la póliza usada queda registrada en la cita
+	appointment.PolicyID = nil
+	if policy != nil {
+		appointment.PolicyID = &policy.ID
+	}
+
+	if len(appointment.Items) > 0 {
+		return l.appointmentItems.PriceItems(appointment.Items, policy, nil)
+	}
+
 	if appointment.ServiceID != 0 {
-		finalServicePrice, err := l.appointmentServiceID.IsServiceIDEXists(appointment.ServiceID, patient.Insurance)
+		finalServicePrice, err := l.appointmentServiceID.IsServiceIDEXists(appointment.ServiceID, policy)
 		if err != nil {
 			return nil, err
 		}
 
-		return &model.FinalServicePrice{
-			TotalAmount:       finalServicePrice.TotalAmount,
-			InsuranceDiscount: finalServicePrice.InsuranceDiscount,
-			FinalPrice:        finalServicePrice.FinalPrice,
-		}, nil
+		return finalServicePrice, nil
 	}
 
-	finalPkgPrice, err := l.appointmentPackageID.IsPackageIDExists(appointment.PackageID, patient.Insurance)
+	finalPkgPrice, err := l.appointmentPackageID.IsPackageIDExists(appointment.PackageID, policy)
 	if err != nil {
 		return nil, err
 	}
 
-	return &model.FinalPackagePriceWithInsegurance{
-		InsuranceDiscount: finalPkgPrice.InsuranceDiscount,
-		FinalPackagePrice: model.FinalPackagePrice{
-			TotalAmount:     finalPkgPrice.TotalAmount,
-			DiscountPackage: finalPkgPrice.DiscountPackage,
-			FinalPrice:      finalPkgPrice.FinalPrice,
-		},
-	}, nil
+	return finalPkgPrice, nil
 }
 
 // Método para construir la cita
-func (l *appointmentCreate) buildAppointment(appointment *model.Appointment, patient *model.Patient, priceDetails model.PriceDetails) *model.Appointment {
-	return &model.Appointment{
-		PatientID:   patient.ID,
-		DoctorID:    appointment.DoctorID,
-		ServiceID:   appointment.ServiceID,
-		PackageID:   appointment.PackageID,
-		Date:        appointment.Date,
-		StartTime:   appointment.StartTime,
-		EndTime:     appointment.EndTime,
-		Paid:        false,
-		TotalAmount: priceDetails.GetFinalPrice(),
+func (l *appointmentCreate) buildAppointment(appointment *model.Appointment, patient *model.Patient, priceDetails model.PriceDetails, coupon *model.Coupon) *model.Appointment {
+	appointmentCreated := &model.Appointment{
+		PatientID:     patient.ID,
+		DoctorID:      appointment.DoctorID,
+		ServiceID:     appointment.ServiceID,
+		PackageID:     appointment.PackageID,
+		Date:          appointment.Date,
+		StartTime:     appointment.StartTime,
+		EndTime:       appointment.EndTime,
+		Paid:          false,
+		Status:        model.AppointmentScheduled,
+		PolicyID:      appointment.PolicyID,
+		CreditID:      appointment.CreditID,
+		TotalAmount:   priceDetails.GetFinalPrice(),
+		InsurerAmount: priceDetails.GetInsurerAmount(),
+		PatientAmount: priceDetails.GetPatientAmount(),
+		Items:         priceDetails.GetItems(),
+	}
+
+	if coupon != nil {
+		appointmentCreated.CouponID = &coupon.ID
+		appointmentCreated.CouponCode = coupon.Code
+		appointmentCreated.CouponDiscount = priceDetails.GetCouponDiscount()
 	}
+
+	return appointmentCreated
 }
@@ -0,0 +1,117 @@
+package appointment
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+// Dobles de las dependencias; las interfaces embebidas hacen fallar cualquier método que la prueba no use
+type fakeDoctorRepository struct {
+	repository.DoctorRepository
+	doctor *model.Doctor
+}
+
+func (r *fakeDoctorRepository) GetByID(ID uint) (*model.Doctor, error) {
+	if r.doctor == nil || r.doctor.ID != ID {
+		return nil, response.ErrorDoctorNotFoundID
+	}
+
+	return r.doctor, nil
+}
+
+type fakeServiceRepository struct {
+	repository.Repository[model.Service]
+	service model.Service
+}
+
+func (r *fakeServiceRepository) GetByID(ID uint) (*model.Service, error) {
+	service := r.service
+	return &service, nil
+}
+
+type fakeAppointmentCredit struct {
+	AppointmentCredit
+}
+
+func (l *fakeAppointmentCredit) FindCredit(patientID, serviceID uint, date string) (*model.PackageCredit, error) {
+	return nil, nil
+}
+
+type fakeInsuranceLogic struct {
+	logic.InsuranceLogic
+}
+
+func (l *fakeInsuranceLogic) GetValidPolicy(patientID uint, date string) (*model.PatientPolicy, error) {
+	return nil, nil
+}
+
+type fakeAppointmentServiceID struct {
+	AppointmentServiceID
+}
+
+func (l *fakeAppointmentServiceID) IsServiceIDEXists(ID uint, policy *model.PatientPolicy) (*model.FinalServicePrice, error) {
+	return &model.FinalServicePrice{ServiceID: ID, TotalAmount: money.FromUnits(100), FinalPrice: money.FromUnits(100)}, nil
+}
+
+type fakeCurrencyLogic struct {
+	logic.CurrencyLogic
+}
+
+func (l *fakeCurrencyLogic) FromBase(amount money.Money, currency, date string) (money.Money, money.Rate, error) {
+	var rate money.Rate
+	return amount, rate, nil
+}
+
+// La cotización aplica las mismas verificaciones de servicios que la reserva
+func TestQuoteAppointmentChecksServices(t *testing.T) {
+	cardiology := uint(3)
+	doctor := &model.Doctor{Person: model.Person{ID: 8}, Specialties: []model.Specialty{{ID: 5}}}
+
+	tests := []struct {
+		name     string
+		service  model.Service
+		doctorID uint
+		err      error
+	}{
+		{name: "servicio activo sin médico elegido", service: model.Service{ID: 1, Active: true, SpecialtyID: &cardiology}},
+		{name: "servicio activo con médico habilitado", service: model.Service{ID: 1, Active: true}, doctorID: 8},
+		{name: "servicio inactivo", service: model.Service{ID: 1, Active: false}, err: response.ErrorServiceInactive},
+		{name: "médico sin la especialidad", service: model.Service{ID: 1, Active: true, SpecialtyID: &cardiology}, doctorID: 8, err: response.ErrorDoctorSpecialty},
+		{name: "médico inexistente", service: model.Service{ID: 1, Active: true}, doctorID: 9, err: response.ErrorDoctorNotFoundID},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			create := &appointmentCreate{
+				appointmentServiceID: &fakeAppointmentServiceID{},
+				logicCurrency:        &fakeCurrencyLogic{},
+				logicInsurance:       &fakeInsuranceLogic{},
+				appointmentCredit:    &fakeAppointmentCredit{},
+				appointmentServices:  NewAppointmentServices(&fakeDoctorRepository{doctor: doctor}, &fakeServiceRepository{service: test.service}, nil),
+			}
+
+			quote, err := create.QuoteAppointment(&model.Appointment{ServiceID: test.service.ID, DoctorID: test.doctorID}, "")
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("expected error %v, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if quote.FinalPrice != money.FromUnits(100) {
+				t.Errorf("quoted %s, want 100.00", quote.FinalPrice)
+			}
+		})
+	}
+}
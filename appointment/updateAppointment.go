@@ -1,9 +1,14 @@
 package appointment
 
 import (
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/calculation"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
 )
 
 type AppointmentUpdate interface {
@@ -11,20 +16,27 @@ type AppointmentUpdate interface {
 }
 
 type appointmentUpdate struct {
-	repositoryAppointment repository.Repository[model.Appointment]
-	repositoryDoctor      repository.Repository[model.Doctor]
-	repositoryService     repository.Repository[model.Service]
-	repositoryPackage     repository.Repository[model.Package]
-	repositoryPatient     repository.Repository[model.Patient]
-	repositoryPatientMain repository.PatientRepository
-	appointmentDoctor     AppointmentDoctorID
-	appointmentPackageID  AppointmentPackageID
-	appointmentServiceID  AppointmentServiceID
-	appointmentTime       AppointmentTime
+	repositoryAppointment     repository.Repository[model.Appointment]
+	repositoryAppointmentMain repository.AppointmentRepository
+	repositoryDoctor          repository.Repository[model.Doctor]
+	repositoryService         repository.Repository[model.Service]
+	repositoryPackage         repository.Repository[model.Package]
+	repositoryPatient         repository.Repository[model.Patient]
+	repositoryPatientMain     repository.PatientRepository
+	appointmentDoctor         AppointmentDoctorID
+	appointmentPackageID      AppointmentPackageID
+	appointmentServiceID      AppointmentServiceID
+	appointmentTime           AppointmentTime
+	appointmentCoupon         AppointmentCoupon
+	logicInsurance            logic.InsuranceLogic
+	appointmentCredit         AppointmentCredit
+	appointmentItems          AppointmentItems
+	appointmentServices       AppointmentServices
 }
 
 func NewAppointmentUpdate(
 	repositoryAppointment repository.Repository[model.Appointment],
+	repositoryAppointmentMain repository.AppointmentRepository,
 	repositoryDoctor repository.Repository[model.Doctor],
 	repositoryService repository.Repository[model.Service],
 	repositoryPackage repository.Repository[model.Package],
@@ -34,27 +46,42 @@ func NewAppointmentUpdate(
 	appointmentPackageID AppointmentPackageID,
 	appointmentServiceID AppointmentServiceID,
 	appointmentTime AppointmentTime,
+	appointmentCoupon AppointmentCoupon,
+	logicInsurance logic.InsuranceLogic,
+	appointmentCredit AppointmentCredit,
+	appointmentItems AppointmentItems,
+	appointmentServices AppointmentServices,
 ) AppointmentUpdate {
 	return &appointmentUpdate{
-		repositoryAppointment: repositoryAppointment,
-		repositoryDoctor:      repositoryDoctor,
-		repositoryService:     repositoryService,
-		repositoryPackage:     repositoryPackage,
-		repositoryPatient:     repositoryPatient,
-		repositoryPatientMain: repositoryPatientMain,
-		appointmentDoctor:     appointmentDoctor,
-		appointmentPackageID:  appointmentPackageID,
-		appointmentServiceID:  appointmentServiceID,
-		appointmentTime:       appointmentTime,
+		repositoryAppointment:     repositoryAppointment,
+		repositoryAppointmentMain: repositoryAppointmentMain,
+		repositoryDoctor:          repositoryDoctor,
+		repositoryService:         repositoryService,
+		repositoryPackage:         repositoryPackage,
+		repositoryPatient:         repositoryPatient,
+		repositoryPatientMain:     repositoryPatientMain,
+		appointmentDoctor:         appointmentDoctor,
+		appointmentPackageID:      appointmentPackageID,
+		appointmentServiceID:      appointmentServiceID,
+		appointmentTime:           appointmentTime,
+		appointmentCoupon:         appointmentCoupon,
+		logicInsurance:            logicInsurance,
+		appointmentCredit:         appointmentCredit,
+		appointmentItems:          appointmentItems,
+		appointmentServices:       appointmentServices,
 	}
 }
 
 func (l *appointmentUpdate) UpdateAppointment(ID uint, updatedAppointment *model.Appointment) (model.PriceDetails, error) {
-	existingAppointment, err := l.repositoryAppointment.GetByID(ID)
+	existingAppointment, err := l.repositoryAppointmentMain.GetByID(ID)
 	if err != nil {
 		return nil, response.ErrorAppointmentNotFound
 	}
 
+	if existingAppointment.Status != model.AppointmentScheduled {
+		return nil, response.ErrorAppointmentNotScheduled
+	}
+
 	if !l.appointmentDoctor.IsDoctorExists(updatedAppointment.DoctorID) {
 		return nil, response.ErrorDoctorNotFoundID
 	}
@@ -69,15 +96,39 @@ func (l *appointmentUpdate) UpdateAppointment(ID uint, updatedAppointment *model
 		return nil, err
 	}
 
-	priceDetails, err := l.getPriceDetails(updatedAppointment, patientFound)
+	err = normalizeItems(updatedAppointment)
+	if err != nil {
+		return nil, err
+	}
+
+	var priceDetails model.PriceDetails
+	if existingAppointment.CreditID != nil {
+		priceDetails, err = l.getCreditPriceDetails(existingAppointment, updatedAppointment, patientFound)
+	} else {
+		priceDetails, err = l.getPriceDetails(existingAppointment, updatedAppointment, patientFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.appointmentServices.CheckServices(updatedAppointment, existingAppointment)
 	if err != nil {
 		return nil, err
 	}
 
+	// El cupón canjeado al reservar se mantiene y su descuento se recalcula con el nuevo precio
+	if existingAppointment.CouponID != nil {
+		_, err = l.appointmentCoupon.ReapplyCoupon(*existingAppointment.CouponID, updatedAppointment, priceDetails)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Construir la cita actualizada
 	updatedAppointmentData := l.buildUpdatedAppointment(existingAppointment, updatedAppointment, patientFound, priceDetails)
 
-	err = l.repositoryAppointment.Update(updatedAppointmentData)
+	err = l.repositoryAppointmentMain.UpdateWithItems(updatedAppointmentData, existingAppointment)
 	if err != nil {
 		return nil, err
 	}
@@ -94,47 +145,109 @@ func (l *appointmentUpdate) isPatientDNIExists(DNI string) (*model.Patient, erro
 	return patient, nil
 }
 
-func (l *appointmentUpdate) getPriceDetails(appointment *model.Appointment, patient *model.Patient) (model.PriceDetails, error) {
+// La cita cubierta por un crédito de paquete puede reprogramarse mientras el crédito siga vigente,
+// pero conserva su servicio y su paciente; la sesión ya se consumió al reservar
+func (l *appointmentUpdate) getCreditPriceDetails(existingAppointment, updatedAppointment *model.Appointment, patient *model.Patient) (model.PriceDetails, error) {
+	if updatedAppointment.ServiceID != existingAppointment.ServiceID || patient.ID != existingAppointment.PatientID {
+		return nil, response.ErrorCreditServiceChange
+	}
+
+	err := l.appointmentCredit.CheckCredit(*existingAppointment.CreditID, updatedAppointment.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	finalServicePrice, err := l.appointmentServiceID.IsServiceIDEXists(updatedAppointment.ServiceID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	finalServicePrice.ApplyPackageCredit(*existingAppointment.CreditID)
+	updatedAppointment.PolicyID = nil
+
+	return finalServicePrice, nil
+}
+
+// El precio se reparte entre la aseguradora y el paciente según la póliza vigente en la fecha de la cita;
+// una cita con varias líneas suma el precio de cada una. Mientras la póliza no cambie, los paquetes que
+// la cita ya tenía conservan el precio de la reserva aunque el paquete se haya actualizado después.
+func (l *appointmentUpdate) getPriceDetails(existingAppointment, appointment *model.Appointment, patient *model.Patient) (model.PriceDetails, error) {
+	date := appointment.Date
+	if date == "" {
+		date = validate.FormatDate(time.Now())
+	}
+
+	policy, err := l.logicInsurance.GetValidPolicy(patient.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	//la póliza usada queda registrada en la cita
+	appointment.PolicyID = nil
+	if policy != nil {
+		appointment.PolicyID = &policy.ID
+	}
+
+	var booked []model.AppointmentItem
+	if samePolicy(existingAppointment.PolicyID, appointment.PolicyID) {
+		booked = existingAppointment.Items
+	}
+
+	if len(appointment.Items) > 0 {
+		return l.appointmentItems.PriceItems(appointment.Items, policy, booked)
+	}
+
 	if appointment.ServiceID != 0 {
-		finalServicePrice, err := l.appointmentServiceID.IsServiceIDEXists(appointment.ServiceID, patient.Insurance)
+		finalServicePrice, err := l.appointmentServiceID.IsServiceIDEXists(appointment.ServiceID, policy)
 		if err != nil {
 			return nil, err
 		}
 
-		return &model.FinalServicePrice{
-			TotalAmount:       finalServicePrice.TotalAmount,
-			InsuranceDiscount: finalServicePrice.InsuranceDiscount,
-			FinalPrice:        finalServicePrice.FinalPrice,
-		}, nil
+		return finalServicePrice, nil
 	}
 
-	finalPkgPrice, err := l.appointmentPackageID.IsPackageIDExists(appointment.PackageID, patient.Insurance)
+	bookedItem, found := bookedPackageItem(booked, appointment.PackageID)
+	if found {
+		return calculation.BookedPackagePrice(bookedItem, policy), nil
+	}
+
+	finalPkgPrice, err := l.appointmentPackageID.IsPackageIDExists(appointment.PackageID, policy)
 	if err != nil {
 		return nil, err
 	}
 
-	return &model.FinalPackagePriceWithInsegurance{
-		InsuranceDiscount: finalPkgPrice.InsuranceDiscount,
-		FinalPackagePrice: model.FinalPackagePrice{
-			TotalAmount:     finalPkgPrice.TotalAmount,
-			DiscountPackage: finalPkgPrice.DiscountPackage,
-			FinalPrice:      finalPkgPrice.FinalPrice,
-		},
-	}, nil
+	return finalPkgPrice, nil
+}
+
+func samePolicy(bookedPolicyID, policyID *uint) bool {
+	if bookedPolicyID == nil || policyID == nil {
+		return bookedPolicyID == policyID
+	}
+
+	return *bookedPolicyID == *policyID
 }
 
 // Método para construir la cita actualizada
 func (l *appointmentUpdate) buildUpdatedAppointment(existingAppointment, updatedAppointment *model.Appointment, patient *model.Patient, priceDetails model.PriceDetails) *model.Appointment {
 	return &model.Appointment{
-		ID:          existingAppointment.ID,
-		PatientID:   patient.ID,
-		DoctorID:    updatedAppointment.DoctorID,
-		ServiceID:   updatedAppointment.ServiceID,
-		PackageID:   updatedAppointment.PackageID,
-		Date:        updatedAppointment.Date,
-		StartTime:   updatedAppointment.StartTime,
-		EndTime:     updatedAppointment.EndTime,
-		Paid:        existingAppointment.Paid,
-		TotalAmount: priceDetails.GetFinalPrice(),
+		ID:             existingAppointment.ID,
+		PatientID:      patient.ID,
+		DoctorID:       updatedAppointment.DoctorID,
+		ServiceID:      updatedAppointment.ServiceID,
+		PackageID:      updatedAppointment.PackageID,
+		Date:           updatedAppointment.Date,
+		StartTime:      updatedAppointment.StartTime,
+		EndTime:        updatedAppointment.EndTime,
+		Paid:           existingAppointment.Paid,
+		Status:         existingAppointment.Status,
+		CouponID:       existingAppointment.CouponID,
+		CouponCode:     existingAppointment.CouponCode,
+		CouponDiscount: priceDetails.GetCouponDiscount(),
+		PolicyID:       updatedAppointment.PolicyID,
+		CreditID:       existingAppointment.CreditID,
+		TotalAmount:    priceDetails.GetFinalPrice(),
+		InsurerAmount:  priceDetails.GetInsurerAmount(),
+		PatientAmount:  priceDetails.GetPatientAmount(),
+		Items:          priceDetails.GetItems(),
 	}
 }
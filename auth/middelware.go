@@ -4,13 +4,21 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
 	"github.com/labstack/echo/v4"
 )
 
+// Claves del contexto donde se guardan los datos del usuario autenticado
+const (
+	UserEmailKey  = "user_email"
+	UserRoleKey   = "user_role"
+	UserDoctorKey = "user_doctor"
+)
+
 func ValidateJWT(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		_, err := validateToken(c)
+		user, err := validateToken(c)
 		if err != nil {
 			log.Printf("Invalid token: %v", err)
 			return response.WriteError(&response.WriteResponse{
@@ -21,6 +29,44 @@ func ValidateJWT(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
+		c.Set(UserEmailKey, user.Email)
+		c.Set(UserRoleKey, user.Role)
+		c.Set(UserDoctorKey, user.DoctorID)
+
 		return next(c)
 	}
 }
+
+func GetUserEmail(c echo.Context) string {
+	email, _ := c.Get(UserEmailKey).(string)
+	return email
+}
+
+// Usuario autenticado con su rol y, si es médico, el médico con el que está vinculado
+func GetUser(c echo.Context) model.User {
+	role, _ := c.Get(UserRoleKey).(model.UserRole)
+	doctorID, _ := c.Get(UserDoctorKey).(*uint)
+
+	return model.User{Email: GetUserEmail(c), Role: role, DoctorID: doctorID}
+}
+
+// Restringe el endpoint a los roles indicados; debe usarse dentro de ValidateJWT
+func RequireRole(next echo.HandlerFunc, roles ...model.UserRole) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		role, _ := c.Get(UserRoleKey).(model.UserRole)
+
+		for _, allowed := range roles {
+			if role == allowed {
+				return next(c)
+			}
+		}
+
+		log.Printf("Forbidden role %q for %s", role, c.Path())
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: response.ErrorForbiddenRole.Error(),
+			Status:  http.StatusForbidden,
+			Data:    nil,
+		})
+	}
+}
@@ -16,7 +16,9 @@ import (
 )
 
 type Claims struct {
-	Email string `json:"email"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	DoctorID uint   `json:"doctor_id,omitempty"`
 	jwt.StandardClaims
 }
 
@@ -36,8 +38,15 @@ func generateToken(user *model.User) (string, error) {
 		return "", fmt.Errorf("invalid JWT_EXP value: %v", err)
 	}
 
+	var doctorID uint
+	if user.DoctorID != nil {
+		doctorID = *user.DoctorID
+	}
+
 	claims := Claims{
-		Email: user.Email,
+		Email:    user.Email,
+		Role:     string(user.Role),
+		DoctorID: doctorID,
 		StandardClaims: jwt.StandardClaims{
 			IssuedAt:  iat,
 			ExpiresAt: time.Now().Add(time.Second * time.Duration(exp)).Unix(),
@@ -99,8 +108,18 @@ func validateToken(c echo.Context) (model.User, error) {
 		return model.User{}, fmt.Errorf("email field is missing or invalid in token claims")
 	}
 
+	role, _ := userData["role"].(string)
+
 	response := model.User{
 		Email: userData["email"].(string),
+		Role:  model.UserRole(role),
+	}
+
+	//los números de los claims se decodifican como float64
+	doctorID, ok := userData["doctor_id"].(float64)
+	if ok && doctorID > 0 {
+		ID := uint(doctorID)
+		response.DoctorID = &ID
 	}
 
 	return response, nil
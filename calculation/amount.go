@@ -1,72 +1,224 @@
 package calculation
 
-import "github.com/IsraelTeo/clinic-backend-hackacode-app/model"
-
-func TotalServiceAmount(servicePrice float64, hasInsurance bool) *model.FinalServicePrice {
-	var discount float64 = 0.0
-
-	if hasInsurance {
-		discount = servicePrice * 0.20
+import (
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Los descuentos se aplican en cascada: categoría del servicio y paquete; el precio final se reparte
+// luego entre la aseguradora y el paciente según la póliza vigente (nil si el paciente no tiene seguro).
+// Las reglas recibidas deben ser las vigentes a la fecha del cálculo.
+// Cada descuento se redondea al céntimo y se resta del monto anterior, así no se pierden ni se crean céntimos.
+
+func TotalServiceAmount(service model.Service, rules []model.PricingRule, policy *model.PatientPolicy) *model.FinalServicePrice {
+	servicePrice := service.Price
+	appliedRules := []model.AppliedPricingRule{}
+
+	var categoryDiscount money.Money
+	categoryRule := SelectCategoryRule(rules, service.Category)
+	if categoryRule != nil {
+		categoryDiscount = servicePrice.Percentage(categoryRule.Percentage)
+		appliedRules = append(appliedRules, appliedRule(categoryRule, categoryDiscount))
 	}
 
-	finalPrice := servicePrice - discount
+	finalPrice := servicePrice - categoryDiscount
 
 	return &model.FinalServicePrice{
-		TotalAmount:       servicePrice,
-		InsuranceDiscount: discount,
-		FinalPrice:        finalPrice,
+		ServiceID:        service.ID,
+		Name:             service.Name,
+		TotalAmount:      servicePrice,
+		CategoryDiscount: categoryDiscount,
+		FinalPrice:       finalPrice,
+		CoverageShares:   SplitCoverage([]model.Service{service}, finalPrice, policy),
+		AppliedRules:     appliedRules,
 	}
 }
 
-func TotalServicePackageAmount(services []model.Service) *model.FinalPackagePrice {
+func TotalServicePackageAmount(packageID uint, services []model.Service, sessions map[uint]int, rules []model.PricingRule) *model.FinalPackagePrice {
+	finalPricePkg := TotalServicePackageAmountToAppointment(packageID, services, sessions, rules, nil)
+
+	return &finalPricePkg.FinalPackagePrice
+}
+
+// Cada servicio del paquete cuenta tantas veces como sesiones incluye (ver PackageSessions)
+func TotalServicePackageAmountToAppointment(packageID uint, services []model.Service, sessions map[uint]int, rules []model.PricingRule, policy *model.PatientPolicy) *model.FinalPackagePriceWithInsegurance {
 	if len(services) == 0 {
-		return &model.FinalPackagePrice{}
+		return &model.FinalPackagePriceWithInsegurance{}
 	}
 
-	var totalAmount float64
-	//calcula el precio total
+	services = sessionServices(services, sessions)
+
+	appliedRules := []model.AppliedPricingRule{}
+
+	var totalAmount money.Money
+	var categoryDiscount money.Money
+
+	//calcula el precio total y los descuentos por categoría de cada servicio
 	for _, service := range services {
 		totalAmount += service.Price
+
+		categoryRule := SelectCategoryRule(rules, service.Category)
+		if categoryRule != nil {
+			discount := service.Price.Percentage(categoryRule.Percentage)
+			categoryDiscount += discount
+			appliedRules = append(appliedRules, appliedRule(categoryRule, discount))
+		}
 	}
 
-	discountPackage := totalAmount * 0.15       //descuento por paquete
-	finalPrice := totalAmount - discountPackage //precio total con descuento de paquete
+	priceAfterCategoryDiscount := totalAmount - categoryDiscount
 
-	return &model.FinalPackagePrice{
-		TotalAmount:     totalAmount,
-		DiscountPackage: discountPackage,
-		FinalPrice:      finalPrice,
+	var discountPackage money.Money
+	packageRule := SelectPackageRule(rules, packageID)
+	if packageRule != nil {
+		discountPackage = priceAfterCategoryDiscount.Percentage(packageRule.Percentage) //descuento por paquete
+		appliedRules = append(appliedRules, appliedRule(packageRule, discountPackage))
+	}
+
+	finalPrice := priceAfterCategoryDiscount - discountPackage //precio total con descuento de paquete
+
+	return &model.FinalPackagePriceWithInsegurance{
+		FinalPackagePrice: model.FinalPackagePrice{
+			PackageID:        packageID,
+			TotalAmount:      totalAmount,
+			CategoryDiscount: categoryDiscount,
+			DiscountPackage:  discountPackage,
+			FinalPrice:       finalPrice,
+		},
+		CoverageShares: SplitCoverage(services, finalPrice, policy), //parte de la aseguradora y del paciente
+		AppliedRules:   appliedRules,
 	}
 }
 
-func TotalServicePackageAmountToAppointment(services []model.Service, hasInsurance bool) *model.FinalPackagePriceWithInsegurance {
-	if len(services) == 0 {
-		return &model.FinalPackagePriceWithInsegurance{}
+// Servicios con el precio de todas sus sesiones; un servicio sin sesiones indicadas cuenta una vez
+func sessionServices(services []model.Service, sessions map[uint]int) []model.Service {
+	weighted := make([]model.Service, len(services))
+	for i, service := range services {
+		if count := sessions[service.ID]; count > 1 {
+			service.Price = service.Price.Times(count)
+		}
+
+		weighted[i] = service
 	}
 
-	var totalAmount float64
+	return weighted
+}
 
-	//calcula el precio total
-	for _, service := range services {
-		totalAmount += service.Price
+func appliedRule(rule *model.PricingRule, amount money.Money) model.AppliedPricingRule {
+	return model.AppliedPricingRule{
+		RuleID:     rule.ID,
+		Name:       rule.Name,
+		Scope:      rule.Scope,
+		Percentage: rule.Percentage,
+		Amount:     amount,
+	}
+}
+
+// Multiplica los montos de una línea por su cantidad; el precio unitario se conserva
+func ItemTimes(item model.AppointmentItem, quantity int) model.AppointmentItem {
+	item.Quantity = quantity
+	item.Discount = item.Discount.Times(quantity)
+	item.CouponDiscount = item.CouponDiscount.Times(quantity)
+	item.PrepaidDiscount = item.PrepaidDiscount.Times(quantity)
+	item.FinalPrice = item.FinalPrice.Times(quantity)
+	item.InsurerAmount = item.InsurerAmount.Times(quantity)
+	item.PatientAmount = item.PatientAmount.Times(quantity)
+	item.AppliedRules = scaleRules(item.AppliedRules, func(amount money.Money) money.Money { return amount.Times(quantity) })
+
+	return item
+}
+
+// Copia de las reglas con cada monto transformado, para no tocar las de la línea original
+func scaleRules(rules []model.AppliedPricingRule, scale func(money.Money) money.Money) []model.AppliedPricingRule {
+	scaled := make([]model.AppliedPricingRule, len(rules))
+	for i, rule := range rules {
+		rule.Amount = scale(rule.Amount)
+		scaled[i] = rule
+	}
+
+	return scaled
+}
+
+// Identifica una regla aplicada al agruparlas en la cita. Los descuentos propios de los paquetes
+// (ver PackageRules) no tienen ID, así que se distinguen también por ámbito, nombre y porcentaje
+type appliedRuleKey struct {
+	ruleID     uint
+	scope      model.PricingRuleScope
+	name       string
+	percentage float64
+}
+
+// Suma las líneas de la cita; cada línea ya trae su reparto entre la aseguradora y el paciente.
+// Las reglas aplicadas se agrupan por regla, en el orden en que aparecen, con la suma de sus montos.
+func TotalAppointmentAmount(items []model.AppointmentItem, policy *model.PatientPolicy) *model.FinalAppointmentPrice {
+	finalPrice := &model.FinalAppointmentPrice{Items: items, AppliedRules: []model.AppliedPricingRule{}}
+	ruleIndex := map[appliedRuleKey]int{}
+
+	for _, item := range items {
+		finalPrice.TotalAmount += item.UnitPrice.Times(item.Quantity)
+		finalPrice.Discount += item.Discount
+		finalPrice.FinalPrice += item.FinalPrice
+		finalPrice.InsurerAmount += item.InsurerAmount
+		finalPrice.PatientAmount += item.PatientAmount
+
+		for _, rule := range item.AppliedRules {
+			key := appliedRuleKey{ruleID: rule.RuleID, scope: rule.Scope, name: rule.Name, percentage: rule.Percentage}
+
+			i, found := ruleIndex[key]
+			if !found {
+				ruleIndex[key] = len(finalPrice.AppliedRules)
+				finalPrice.AppliedRules = append(finalPrice.AppliedRules, rule)
+				continue
+			}
+
+			finalPrice.AppliedRules[i].Amount += rule.Amount
+		}
 	}
 
-	discountPackage := totalAmount * 0.15                      //descuento por paquete
-	priceAfterPackageDiscount := totalAmount - discountPackage //precio total con descuento de paquete
+	if policy != nil && policy.Plan != nil {
+		finalPrice.PolicyNumber = policy.PolicyNumber
+	}
+
+	return finalPrice
+}
 
-	insuranceDiscount := 0.0
-	if hasInsurance {
-		insuranceDiscount = priceAfterPackageDiscount * 0.20 //descuento por seguro médico
+// Línea reservada por unidad y sin el cupón, que se vuelve a aplicar al recalcular la cita.
+// Los montos de la línea son múltiplos de su cantidad salvo el cupón, que se suma antes de dividir.
+func BookedUnitItem(item model.AppointmentItem) model.AppointmentItem {
+	quantity := money.Money(item.Quantity)
+	if quantity < 1 {
+		quantity = 1
 	}
 
-	finalPrice := priceAfterPackageDiscount - insuranceDiscount //precio total con descuento de seguro médico
+	item.FinalPrice = (item.FinalPrice + item.CouponDiscount) / quantity
+	item.PatientAmount = (item.PatientAmount + item.CouponDiscount) / quantity
+	item.InsurerAmount /= quantity
+	item.Discount /= quantity
+	item.PrepaidDiscount /= quantity
+	item.AppliedRules = scaleRules(item.AppliedRules, func(amount money.Money) money.Money { return amount / quantity })
+	item.CouponDiscount = 0
+	item.Quantity = 1
+
+	return item
+}
+
+// Precio del paquete tal como se reservó en la cita, para no recalcularlo con el paquete actualizado
+func BookedPackagePrice(item model.AppointmentItem, policy *model.PatientPolicy) *model.FinalPackagePriceWithInsegurance {
+	unit := BookedUnitItem(item)
+
+	shares := model.CoverageShares{InsurerAmount: unit.InsurerAmount, PatientAmount: unit.PatientAmount}
+	if policy != nil && policy.Plan != nil {
+		shares.PolicyNumber = policy.PolicyNumber
+	}
 
 	return &model.FinalPackagePriceWithInsegurance{
-		InsuranceDiscount: insuranceDiscount,
 		FinalPackagePrice: model.FinalPackagePrice{
-			TotalAmount:     totalAmount,
-			DiscountPackage: discountPackage,
-			FinalPrice:      finalPrice,
+			PackageID:       unit.PackageID,
+			Name:            unit.Name,
+			TotalAmount:     unit.UnitPrice,
+			DiscountPackage: unit.Discount,
+			FinalPrice:      unit.FinalPrice,
 		},
+		CoverageShares: shares,
+		AppliedRules:   unit.AppliedRules,
 	}
 }
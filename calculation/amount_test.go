@@ -0,0 +1,288 @@
+package calculation
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+func packageRule(ID uint, percentage float64) model.PricingRule {
+	return model.PricingRule{ID: ID, Name: "Paquete", Scope: model.ScopePackage, Percentage: percentage, Active: true}
+}
+
+func categoryRule(ID uint, category string, percentage float64) model.PricingRule {
+	return model.PricingRule{ID: ID, Name: category, Scope: model.ScopeServiceCategory, ServiceCategory: category, Percentage: percentage, Active: true}
+}
+
+func policyWith(coverage float64, copay money.Money, coverages ...model.PlanCoverage) *model.PatientPolicy {
+	return &model.PatientPolicy{
+		PolicyNumber: "POL-1",
+		Active:       true,
+		Plan:         &model.InsurancePlan{DefaultCoverage: coverage, DefaultCopay: copay, Active: true, Coverages: coverages},
+	}
+}
+
+func TestTotalServicePackageAmountToAppointment(t *testing.T) {
+	consultation := model.Service{ID: 1, Name: "Consulta", Category: "Cardiología", Price: money.FromUnits(100)}
+	ekg := model.Service{ID: 2, Name: "Electrocardiograma", Category: "Diagnóstico", Price: 4550}
+
+	tests := []struct {
+		name     string
+		services []model.Service
+		sessions map[uint]int
+		rules    []model.PricingRule
+		policy   *model.PatientPolicy
+		want     model.FinalPackagePrice
+		insurer  money.Money
+	}{
+		{
+			name:     "sin reglas ni seguro",
+			services: []model.Service{consultation, ekg},
+			want:     model.FinalPackagePrice{PackageID: 9, TotalAmount: 14550, FinalPrice: 14550},
+		},
+		{
+			name:     "categoría y luego paquete",
+			services: []model.Service{consultation, ekg},
+			rules:    []model.PricingRule{categoryRule(1, "cardiología", 10), packageRule(2, 15)},
+			// 145.50 - 10.00 = 135.50; 15% de 135.50 = 20.325 -> 20.33
+			want: model.FinalPackagePrice{PackageID: 9, TotalAmount: 14550, CategoryDiscount: 1000, DiscountPackage: 2033, FinalPrice: 11517},
+		},
+		{
+			name:     "sesiones multiplican el precio",
+			services: []model.Service{consultation, ekg},
+			sessions: map[uint]int{1: 3},
+			rules:    []model.PricingRule{packageRule(2, 10)},
+			want:     model.FinalPackagePrice{PackageID: 9, TotalAmount: 34550, DiscountPackage: 3455, FinalPrice: 31095},
+		},
+		{
+			name:     "seguro con copago",
+			services: []model.Service{consultation},
+			policy:   policyWith(80, money.FromUnits(20)),
+			// (100.00 - 20.00) * 80% = 64.00
+			want:    model.FinalPackagePrice{PackageID: 9, TotalAmount: 10000, FinalPrice: 10000},
+			insurer: 6400,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := TotalServicePackageAmountToAppointment(9, test.services, test.sessions, test.rules, test.policy)
+
+			if got.FinalPackagePrice != test.want {
+				t.Errorf("got %+v, want %+v", got.FinalPackagePrice, test.want)
+			}
+
+			if got.InsurerAmount != test.insurer {
+				t.Errorf("insurer amount = %s, want %s", got.InsurerAmount, test.insurer)
+			}
+
+			assertPackageInvariants(t, test.services, test.sessions, got)
+		})
+	}
+}
+
+// Para cualquier combinación de servicios, reglas y póliza no se pierden ni se crean céntimos
+func TestTotalServicePackageAmountInvariants(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	categories := []string{"Cardiología", "Diagnóstico", "Laboratorio", ""}
+
+	for i := 0; i < 5000; i++ {
+		services := make([]model.Service, 1+random.Intn(5))
+		sessions := map[uint]int{}
+		for j := range services {
+			services[j] = model.Service{
+				ID:       uint(j + 1),
+				Category: categories[random.Intn(len(categories))],
+				Price:    money.Money(random.Int63n(500_000)),
+			}
+			sessions[services[j].ID] = 1 + random.Intn(10)
+		}
+
+		var rules []model.PricingRule
+		for j, category := range categories[:3] {
+			if random.Intn(2) == 0 {
+				rules = append(rules, categoryRule(uint(j+1), category, randomPercentage(random)))
+			}
+		}
+
+		if random.Intn(2) == 0 {
+			rules = append(rules, packageRule(10, randomPercentage(random)))
+		}
+
+		var policy *model.PatientPolicy
+		if random.Intn(2) == 0 {
+			policy = policyWith(
+				randomPercentage(random),
+				money.Money(random.Int63n(5_000)),
+				model.PlanCoverage{ServiceCategory: "Laboratorio", Percentage: randomPercentage(random), Copay: money.Money(random.Int63n(5_000))},
+			)
+		}
+
+		got := TotalServicePackageAmountToAppointment(9, services, sessions, rules, policy)
+		assertPackageInvariants(t, services, sessions, got)
+
+		if t.Failed() {
+			t.Fatalf("services %+v, sessions %v, rules %+v", services, sessions, rules)
+		}
+	}
+}
+
+func randomPercentage(random *rand.Rand) float64 {
+	return float64(1+random.Intn(10000)) / 100
+}
+
+func assertPackageInvariants(t *testing.T, services []model.Service, sessions map[uint]int, got *model.FinalPackagePriceWithInsegurance) {
+	t.Helper()
+
+	var listPrice money.Money
+	for _, service := range services {
+		count := sessions[service.ID]
+		if count < 1 {
+			count = 1
+		}
+
+		listPrice += service.Price.Times(count)
+	}
+
+	if got.TotalAmount != listPrice {
+		t.Errorf("total amount = %s, want %s", got.TotalAmount, listPrice)
+	}
+
+	// precio final + descuentos == total original
+	if got.FinalPackagePrice.FinalPrice+got.CategoryDiscount+got.DiscountPackage != got.TotalAmount {
+		t.Errorf("final %s + category %s + package %s != total %s",
+			got.FinalPackagePrice.FinalPrice, got.CategoryDiscount, got.DiscountPackage, got.TotalAmount)
+	}
+
+	var applied money.Money
+	for _, rule := range got.AppliedRules {
+		applied += rule.Amount
+	}
+
+	if applied != got.CategoryDiscount+got.DiscountPackage {
+		t.Errorf("applied rules sum to %s, want %s", applied, got.CategoryDiscount+got.DiscountPackage)
+	}
+
+	// aseguradora + paciente == precio final
+	if got.InsurerAmount+got.PatientAmount != got.FinalPackagePrice.FinalPrice {
+		t.Errorf("insurer %s + patient %s != final %s", got.InsurerAmount, got.PatientAmount, got.FinalPackagePrice.FinalPrice)
+	}
+
+	if got.FinalPackagePrice.FinalPrice < 0 || got.InsurerAmount < 0 || got.PatientAmount < 0 {
+		t.Errorf("negative amount in %+v", got)
+	}
+}
+
+func TestTotalAppointmentAmountSumsLines(t *testing.T) {
+	policy := policyWith(70, 500)
+	lines := []*model.FinalPackagePriceWithInsegurance{
+		TotalServicePackageAmountToAppointment(1, []model.Service{{ID: 1, Price: 12345}}, nil, []model.PricingRule{packageRule(1, 12.5)}, policy),
+		TotalServicePackageAmountToAppointment(2, []model.Service{{ID: 2, Price: 9999}, {ID: 3, Price: 1}}, nil, nil, policy),
+	}
+
+	var items []model.AppointmentItem
+	for _, line := range lines {
+		items = append(items, ItemTimes(line.GetItems()[0], 3))
+	}
+
+	got := TotalAppointmentAmount(items, policy)
+
+	if got.FinalPrice+got.Discount != got.TotalAmount {
+		t.Errorf("final %s + discount %s != total %s", got.FinalPrice, got.Discount, got.TotalAmount)
+	}
+
+	if got.InsurerAmount+got.PatientAmount != got.FinalPrice {
+		t.Errorf("insurer %s + patient %s != final %s", got.InsurerAmount, got.PatientAmount, got.FinalPrice)
+	}
+
+	if got.PolicyNumber != policy.PolicyNumber {
+		t.Errorf("policy number = %q, want %q", got.PolicyNumber, policy.PolicyNumber)
+	}
+}
+
+// Las reglas de cada línea se multiplican con la cantidad y la cita las agrupa por regla
+func TestTotalAppointmentAmountAggregatesRules(t *testing.T) {
+	consultation := model.Service{ID: 1, Name: "Consulta", Category: "Cardiología", Price: money.FromUnits(100)}
+	cardiology := categoryRule(5, "Cardiología", 10)
+
+	service := TotalServiceAmount(consultation, []model.PricingRule{cardiology}, nil)
+	pkg := TotalServicePackageAmountToAppointment(2, []model.Service{consultation}, map[uint]int{1: 2}, []model.PricingRule{cardiology, packageRule(9, 5)}, nil)
+
+	items := []model.AppointmentItem{
+		ItemTimes(service.GetItems()[0], 3),
+		ItemTimes(pkg.GetItems()[0], 1),
+	}
+
+	if len(items[0].AppliedRules) != 1 || items[0].AppliedRules[0].Amount != money.FromUnits(30) {
+		t.Fatalf("service line rules = %+v, want 30.00 of rule 5", items[0].AppliedRules)
+	}
+
+	if service.AppliedRules[0].Amount != money.FromUnits(10) {
+		t.Errorf("ItemTimes changed the rules of the original price: %+v", service.AppliedRules)
+	}
+
+	got := TotalAppointmentAmount(items, nil)
+
+	// 3 x 10.00 de la línea del servicio + 20.00 de las dos sesiones del paquete; el 5% del paquete sobre 180.00
+	want := []model.AppliedPricingRule{
+		{RuleID: 5, Name: "Cardiología", Scope: model.ScopeServiceCategory, Percentage: 10, Amount: money.FromUnits(50)},
+		{RuleID: 9, Name: "Paquete", Scope: model.ScopePackage, Percentage: 5, Amount: money.FromUnits(9)},
+	}
+
+	if len(got.AppliedRules) != len(want) {
+		t.Fatalf("applied rules = %+v, want %+v", got.AppliedRules, want)
+	}
+
+	var rulesTotal money.Money
+	for i, rule := range got.AppliedRules {
+		if rule != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rule, want[i])
+		}
+
+		rulesTotal += rule.Amount
+	}
+
+	if rulesTotal != got.Discount {
+		t.Errorf("rules sum %s, discount %s", rulesTotal, got.Discount)
+	}
+
+	// La línea reservada vuelve a la unidad con sus reglas divididas por la cantidad
+	unit := BookedUnitItem(items[0])
+	if len(unit.AppliedRules) != 1 || unit.AppliedRules[0].Amount != money.FromUnits(10) {
+		t.Errorf("booked unit rules = %+v, want 10.00", unit.AppliedRules)
+	}
+}
+
+// Los descuentos propios de dos paquetes no tienen ID de regla y no se mezclan al agruparlos en la cita
+func TestTotalAppointmentAmountKeepsPackageDiscountsApart(t *testing.T) {
+	first, second := 10.0, 20.0
+	packages := []model.Package{
+		{ID: 1, Name: "Chequeo", Services: []model.Service{{ID: 1, Price: money.FromUnits(100)}}, DiscountPercentage: &first},
+		{ID: 2, Name: "Fisioterapia", Services: []model.Service{{ID: 2, Price: money.FromUnits(200)}}, DiscountPercentage: &second},
+	}
+
+	var items []model.AppointmentItem
+	for _, pkg := range packages {
+		price := TotalServicePackageAmountToAppointment(pkg.ID, pkg.Services, PackageSessions(pkg), PackageRules(pkg, nil), nil)
+		items = append(items, price.GetItems()[0])
+	}
+
+	got := TotalAppointmentAmount(items, nil)
+
+	want := []model.AppliedPricingRule{
+		{Name: "Descuento del paquete Chequeo", Scope: model.ScopePackage, Percentage: 10, Amount: money.FromUnits(10)},
+		{Name: "Descuento del paquete Fisioterapia", Scope: model.ScopePackage, Percentage: 20, Amount: money.FromUnits(40)},
+	}
+
+	if len(got.AppliedRules) != len(want) {
+		t.Fatalf("applied rules = %+v, want %+v", got.AppliedRules, want)
+	}
+
+	for i, rule := range got.AppliedRules {
+		if rule != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rule, want[i])
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package calculation
+
+import (
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Indica si el cupón está activo y dentro de su periodo de validez en la fecha indicada (AAAA-MM-DD)
+func IsCouponValidOn(coupon model.Coupon, date string) bool {
+	if !coupon.Active {
+		return false
+	}
+
+	if coupon.ValidFrom != "" && date < coupon.ValidFrom {
+		return false
+	}
+
+	if coupon.ValidTo != "" && date > coupon.ValidTo {
+		return false
+	}
+
+	return true
+}
+
+// Un cupón sin servicios ni paquetes elegibles aplica a cualquier cita
+func IsCouponEligible(coupon model.Coupon, serviceID, packageID uint) bool {
+	if len(coupon.Services) == 0 && len(coupon.Packages) == 0 {
+		return true
+	}
+
+	if serviceID != 0 {
+		for _, service := range coupon.Services {
+			if service.ID == serviceID {
+				return true
+			}
+		}
+	}
+
+	if packageID != 0 {
+		for _, pkg := range coupon.Packages {
+			if pkg.ID == packageID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// El cupón se aplica sobre la parte que paga el paciente; el descuento nunca supera ese monto
+func CouponDiscount(coupon model.Coupon, price money.Money) money.Money {
+	var discount money.Money
+
+	switch coupon.Type {
+	case model.CouponPercentage:
+		discount = price.Percentage(coupon.Percentage)
+	case model.CouponFixed:
+		discount = coupon.Amount
+	}
+
+	if discount > price {
+		return price
+	}
+
+	return discount
+}
+
+// Descuento del cupón por línea de la cita: se calcula sobre la parte que paga el paciente en las
+// líneas elegibles y se reparte entre ellas en proporción a esa parte. Devuelve false si ninguna línea es elegible.
+func CouponItemDiscounts(coupon model.Coupon, items []model.AppointmentItem) ([]money.Money, bool) {
+	discounts := make([]money.Money, len(items))
+
+	eligible := []int{}
+	weights := []money.Money{}
+	var eligibleAmount money.Money
+	for i, item := range items {
+		if IsCouponEligible(coupon, item.ServiceID, item.PackageID) {
+			eligible = append(eligible, i)
+			weights = append(weights, item.PatientAmount)
+			eligibleAmount += item.PatientAmount
+		}
+	}
+
+	if len(eligible) == 0 {
+		return discounts, false
+	}
+
+	for j, part := range CouponDiscount(coupon, eligibleAmount).Allocate(weights) {
+		discounts[eligible[j]] = part
+	}
+
+	return discounts, true
+}
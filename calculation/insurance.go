@@ -0,0 +1,70 @@
+package calculation
+
+import (
+	"strings"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Indica si la póliza, su plan y la aseguradora están activos y la póliza está vigente en la fecha indicada (AAAA-MM-DD)
+func IsPolicyValidOn(policy model.PatientPolicy, date string) bool {
+	if !policy.Active || policy.Plan == nil || !policy.Plan.Active {
+		return false
+	}
+
+	if policy.Plan.Provider != nil && !policy.Plan.Provider.Active {
+		return false
+	}
+
+	if date < policy.ValidFrom {
+		return false
+	}
+
+	if policy.ValidTo != "" && date > policy.ValidTo {
+		return false
+	}
+
+	return true
+}
+
+// Cobertura del plan para la categoría; sin cobertura propia se usa la cobertura por defecto del plan
+func SelectCoverage(plan model.InsurancePlan, category string) (float64, money.Money) {
+	for _, coverage := range plan.Coverages {
+		if category != "" && strings.EqualFold(coverage.ServiceCategory, category) {
+			return coverage.Percentage, coverage.Copay
+		}
+	}
+
+	return plan.DefaultCoverage, plan.DefaultCopay
+}
+
+// Reparte el precio final entre la aseguradora y el paciente.
+// El precio final se distribuye entre los servicios en proporción a su precio de lista y a cada parte
+// se le aplica la cobertura de la categoría del servicio: el paciente paga el copago y la aseguradora
+// el porcentaje cubierto del resto. Sin póliza el paciente paga todo.
+func SplitCoverage(services []model.Service, finalPrice money.Money, policy *model.PatientPolicy) model.CoverageShares {
+	shares := model.CoverageShares{PatientAmount: finalPrice}
+	if policy == nil || policy.Plan == nil || len(services) == 0 {
+		return shares
+	}
+
+	weights := make([]money.Money, 0, len(services))
+	for _, service := range services {
+		weights = append(weights, service.Price)
+	}
+
+	var insurerAmount money.Money
+	for i, part := range finalPrice.Allocate(weights) {
+		percentage, copay := SelectCoverage(*policy.Plan, services[i].Category)
+		if part > copay {
+			insurerAmount += (part - copay).Percentage(percentage)
+		}
+	}
+
+	shares.PolicyNumber = policy.PolicyNumber
+	shares.InsurerAmount = insurerAmount
+	shares.PatientAmount = finalPrice - insurerAmount
+
+	return shares
+}
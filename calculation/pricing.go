@@ -0,0 +1,133 @@
+package calculation
+
+import (
+	"strings"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+)
+
+// Indica si la regla está activa y dentro de su periodo de validez en la fecha indicada (AAAA-MM-DD)
+func IsRuleValidOn(rule model.PricingRule, date string) bool {
+	if !rule.Active {
+		return false
+	}
+
+	if rule.ValidFrom != "" && date < rule.ValidFrom {
+		return false
+	}
+
+	if rule.ValidTo != "" && date > rule.ValidTo {
+		return false
+	}
+
+	return true
+}
+
+// Una regla para un paquete específico tiene prioridad sobre una regla general de paquetes
+func SelectPackageRule(rules []model.PricingRule, packageID uint) *model.PricingRule {
+	var general, specific *model.PricingRule
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Scope != model.ScopePackage {
+			continue
+		}
+
+		if rule.PackageID == nil {
+			general = higherRule(general, rule)
+			continue
+		}
+
+		if packageID != 0 && *rule.PackageID == packageID {
+			specific = higherRule(specific, rule)
+		}
+	}
+
+	if specific != nil {
+		return specific
+	}
+
+	return general
+}
+
+// Un paquete con descuento propio reemplaza las reglas de paquetes; con descuento cero se vende sin descuento
+func PackageRules(pkg model.Package, rules []model.PricingRule) []model.PricingRule {
+	if pkg.DiscountPercentage == nil {
+		return rules
+	}
+
+	packageRules := make([]model.PricingRule, 0, len(rules)+1)
+	for _, rule := range rules {
+		if rule.Scope != model.ScopePackage {
+			packageRules = append(packageRules, rule)
+		}
+	}
+
+	if *pkg.DiscountPercentage > 0 {
+		packageRules = append(packageRules, model.PricingRule{
+			Name:       "Descuento del paquete " + pkg.Name,
+			Scope:      model.ScopePackage,
+			Percentage: *pkg.DiscountPercentage,
+			Active:     true,
+		})
+	}
+
+	return packageRules
+}
+
+// Sesiones que otorga el paquete por servicio; un servicio sin sesiones indicadas otorga una
+func PackageSessions(pkg model.Package) map[uint]int {
+	sessions := map[uint]int{}
+	for _, service := range pkg.Services {
+		sessions[service.ID] = 1
+	}
+
+	for _, session := range pkg.Sessions {
+		sessions[session.ServiceID] = session.Sessions
+	}
+
+	return sessions
+}
+
+// Indica si el paquete está activo y dentro de su periodo de venta en la fecha indicada (AAAA-MM-DD)
+func IsPackageOnSale(pkg model.Package, date string) bool {
+	if !pkg.Active {
+		return false
+	}
+
+	if pkg.SaleFrom != "" && date < pkg.SaleFrom {
+		return false
+	}
+
+	if pkg.SaleTo != "" && date > pkg.SaleTo {
+		return false
+	}
+
+	return true
+}
+
+func SelectCategoryRule(rules []model.PricingRule, category string) *model.PricingRule {
+	if category == "" {
+		return nil
+	}
+
+	var selected *model.PricingRule
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Scope == model.ScopeServiceCategory && strings.EqualFold(rule.ServiceCategory, category) {
+			selected = higherRule(selected, rule)
+		}
+	}
+
+	return selected
+}
+
+// Entre reglas del mismo ámbito se aplica la de mayor porcentaje
+func higherRule(current, candidate *model.PricingRule) *model.PricingRule {
+	if current == nil || candidate.Percentage > current.Percentage {
+		return candidate
+	}
+
+	return current
+}
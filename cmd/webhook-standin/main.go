@@ -0,0 +1,55 @@
+// Receptor de webhooks para probar los envíos en local. Muestra cada evento recibido y si su firma
+// es válida para WEBHOOK_SECRET. Con WEBHOOK_STANDIN_STATUS se fuerza el código de respuesta, p. ej.
+// 500 para ver los reintentos en el registro de envíos.
+//
+//	WEBHOOK_SECRET=whsec_... go run ./cmd/webhook-standin
+//
+// y registrar el webhook con la URL http://localhost:9090/webhook
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/webhook"
+)
+
+func main() {
+	port := os.Getenv("WEBHOOK_STANDIN_PORT")
+	if port == "" {
+		port = "9090"
+	}
+
+	status, err := strconv.Atoi(os.Getenv("WEBHOOK_STANDIN_STATUS"))
+	if err != nil {
+		status = http.StatusOK
+	}
+
+	secret := os.Getenv("WEBHOOK_SECRET")
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		valid := webhook.Verify(secret, r.Header.Get(webhook.HeaderTimestamp), body, r.Header.Get(webhook.HeaderSignature))
+
+		log.Printf("%s %s event=%s id=%s signature_valid=%t\n%s",
+			r.Method, r.URL.Path, r.Header.Get(webhook.HeaderEvent), r.Header.Get(webhook.HeaderEventID), valid, body)
+
+		if secret != "" && !valid {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(status)
+	})
+
+	log.Printf("webhook stand-in listening on :%s", port)
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
@@ -20,6 +23,39 @@ type Config struct {
 	DBName                string
 	JWTExpirationInSecond int64
 	JWTSecret             string
+	BaseCurrency          string
+	StorageDriver         string
+	StorageDir            string
+	MaxUploadBytes        int64
+	S3Endpoint            string
+	S3Region              string
+	S3Bucket              string
+	S3AccessKey           string
+	S3SecretKey           string
+	ReceiptRetentionDays  int
+	ReceiptSecret         string
+	DocumentSecret        string
+	DefaultLanguage       string
+	ClinicName            string
+	ClinicHeader          string
+	ClinicFooter          string
+	ClinicLogoPath        string
+	PDFFontPath           string
+	PDFFontBoldPath       string
+	PDFTemplatesPath      string
+	EmailDriver           string
+	SMTPHost              string
+	SMTPPort              string
+	SMTPUsername          string
+	SMTPPassword          string
+	SMTPFrom              string
+	SMSDriver             string
+	SMSAPIURL             string
+	SMSAPIKey             string
+	SMSSender             string
+	JobPollSeconds        int
+	StaleBookingHours     int
+	WebhookTimeoutSeconds int
 }
 
 var Envs = InitConfig()
@@ -31,6 +67,92 @@ func InitConfig() *Config {
 		jwtExp = 3600
 	}
 
+	baseCurrency := strings.ToUpper(os.Getenv("BASE_CURRENCY"))
+	if baseCurrency == "" {
+		baseCurrency = "PEN"
+	}
+
+	// Almacenamiento de documentos, recibos y QR: "local" (por defecto) o "s3" para un servicio compatible con S3
+	storageDriver := strings.ToLower(os.Getenv("STORAGE_DRIVER"))
+	if storageDriver == "" {
+		storageDriver = "local"
+	}
+
+	storageDir := resolveStorageDir(os.Getenv("STORAGE_DIR"), executableDir())
+
+	maxUploadMB, err := strconv.ParseInt(os.Getenv("MAX_UPLOAD_MB"), 10, 64)
+	if err != nil || maxUploadMB <= 0 {
+		maxUploadMB = 10
+	}
+
+	s3Region := os.Getenv("S3_REGION")
+	if s3Region == "" {
+		s3Region = "us-east-1"
+	}
+
+	// Días que se conservan las copias guardadas de recibos y QR; con 0 no se guardan y se generan en cada descarga
+	receiptRetentionDays, err := strconv.Atoi(os.Getenv("RECEIPT_RETENTION_DAYS"))
+	if err != nil || receiptRetentionDays < 0 {
+		receiptRetentionDays = 30
+	}
+
+	// Clave con que se firman los QR de los recibos; si no se indica se usa la del JWT
+	receiptSecret := os.Getenv("RECEIPT_SECRET")
+	if receiptSecret == "" {
+		receiptSecret = os.Getenv("API_SECRET")
+	}
+
+	// Clave con que se firman los enlaces de descarga de documentos; si no se indica se usa la del JWT
+	documentSecret := os.Getenv("DOCUMENT_SECRET")
+	if documentSecret == "" {
+		documentSecret = os.Getenv("API_SECRET")
+	}
+
+	// Idioma por defecto de recibos, comprobantes y confirmaciones: "es" o "en"
+	defaultLanguage := strings.ToLower(os.Getenv("DEFAULT_LANGUAGE"))
+	if defaultLanguage == "" {
+		defaultLanguage = "es"
+	}
+
+	clinicName := os.Getenv("CLINIC_NAME")
+	if clinicName == "" {
+		clinicName = "Clínica Médica"
+	}
+
+	// Canales de notificación: "smtp" / "http" envían de verdad; "fake" (por defecto) solo registra los mensajes
+	emailDriver := strings.ToLower(os.Getenv("NOTIFICATION_EMAIL_DRIVER"))
+	if emailDriver == "" {
+		emailDriver = "fake"
+	}
+
+	smtpPort := os.Getenv("SMTP_PORT")
+	if smtpPort == "" {
+		smtpPort = "587"
+	}
+
+	smsDriver := strings.ToLower(os.Getenv("NOTIFICATION_SMS_DRIVER"))
+	if smsDriver == "" {
+		smsDriver = "fake"
+	}
+
+	// Cada cuántos segundos se revisan los trabajos en segundo plano pendientes
+	jobPollSeconds, err := strconv.Atoi(os.Getenv("JOB_POLL_SECONDS"))
+	if err != nil || jobPollSeconds <= 0 {
+		jobPollSeconds = 15
+	}
+
+	// Horas después del inicio de una cita impaga para marcarla como vencida
+	staleBookingHours, err := strconv.Atoi(os.Getenv("STALE_BOOKING_HOURS"))
+	if err != nil || staleBookingHours <= 0 {
+		staleBookingHours = 24
+	}
+
+	// Segundos que se espera la respuesta de un receptor de webhooks antes de contar el intento como fallido
+	webhookTimeoutSeconds, err := strconv.Atoi(os.Getenv("WEBHOOK_TIMEOUT_SECONDS"))
+	if err != nil || webhookTimeoutSeconds <= 0 {
+		webhookTimeoutSeconds = 10
+	}
+
 	return &Config{
 		PublicHost:            os.Getenv("PUBLIC_HOST"),
 		Port:                  os.Getenv("PORT"),
@@ -41,7 +163,93 @@ func InitConfig() *Config {
 		DBName:                os.Getenv("DB_NAME"),
 		JWTExpirationInSecond: jwtExp,
 		JWTSecret:             os.Getenv("API_SECRET"),
+		BaseCurrency:          baseCurrency,
+		StorageDriver:         storageDriver,
+		StorageDir:            storageDir,
+		MaxUploadBytes:        maxUploadMB << 20,
+		S3Endpoint:            os.Getenv("S3_ENDPOINT"),
+		S3Region:              s3Region,
+		S3Bucket:              os.Getenv("S3_BUCKET"),
+		S3AccessKey:           os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:           os.Getenv("S3_SECRET_KEY"),
+		ReceiptRetentionDays:  receiptRetentionDays,
+		ReceiptSecret:         receiptSecret,
+		DocumentSecret:        documentSecret,
+		DefaultLanguage:       defaultLanguage,
+		ClinicName:            clinicName,
+		ClinicHeader:          os.Getenv("CLINIC_HEADER"),
+		ClinicFooter:          os.Getenv("CLINIC_FOOTER"),
+		ClinicLogoPath:        os.Getenv("CLINIC_LOGO_PATH"),
+		PDFFontPath:           os.Getenv("PDF_FONT_PATH"),
+		PDFFontBoldPath:       os.Getenv("PDF_FONT_BOLD_PATH"),
+		PDFTemplatesPath:      os.Getenv("PDF_TEMPLATES_PATH"),
+		EmailDriver:           emailDriver,
+		SMTPHost:              os.Getenv("SMTP_HOST"),
+		SMTPPort:              smtpPort,
+		SMTPUsername:          os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:          os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:              os.Getenv("SMTP_FROM"),
+		SMSDriver:             smsDriver,
+		SMSAPIURL:             os.Getenv("SMS_API_URL"),
+		SMSAPIKey:             os.Getenv("SMS_API_KEY"),
+		SMSSender:             os.Getenv("SMS_SENDER"),
+		JobPollSeconds:        jobPollSeconds,
+		StaleBookingHours:     staleBookingHours,
+		WebhookTimeoutSeconds: webhookTimeoutSeconds,
+	}
+}
+
+// Comprueba al iniciar lo que la API no puede suplir con un valor por defecto: sin clave, los QR de los
+// recibos y los enlaces de descarga se firmarían con una clave vacía que cualquiera puede reproducir
+func (c *Config) Validate() error {
+	if c.JWTSecret == "" {
+		return errors.New("API_SECRET is required")
+	}
+
+	if c.ReceiptSecret == "" {
+		return errors.New("RECEIPT_SECRET or API_SECRET is required to sign receipts")
+	}
+
+	if c.DocumentSecret == "" {
+		return errors.New("DOCUMENT_SECRET or API_SECRET is required to sign document download links")
 	}
+
+	return nil
+}
+
+// Un STORAGE_DIR relativo (por defecto "storage") se toma desde la carpeta del ejecutable y no desde el
+// directorio de trabajo, así los archivos guardados no cambian de lugar según desde dónde se inicie la API
+func resolveStorageDir(dir, baseDir string) string {
+	if dir == "" {
+		dir = "storage"
+	}
+
+	if filepath.IsAbs(dir) {
+		return filepath.Clean(dir)
+	}
+
+	return filepath.Join(baseDir, dir)
+}
+
+// Carpeta del ejecutable; si no se puede determinar se usa el directorio de trabajo
+func executableDir() string {
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+
+	if err != nil {
+		log.Printf("Error resolving the executable path: %v. STORAGE_DIR will be relative to the working directory", err)
+
+		workingDir, err := os.Getwd()
+		if err != nil {
+			return "."
+		}
+
+		return workingDir
+	}
+
+	return filepath.Dir(executable)
 }
 
 func StartServer(e *echo.Echo, port string) error {
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateRequiresSigningSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{name: "todas las claves", config: Config{JWTSecret: "jwt", ReceiptSecret: "receipt", DocumentSecret: "document"}},
+		{name: "sin API_SECRET", config: Config{ReceiptSecret: "receipt", DocumentSecret: "document"}, wantErr: true},
+		{name: "sin clave de recibos", config: Config{JWTSecret: "jwt", DocumentSecret: "document"}, wantErr: true},
+		{name: "sin clave de documentos", config: Config{JWTSecret: "jwt", ReceiptSecret: "receipt"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Validate()
+			if (err != nil) != test.wantErr {
+				t.Errorf("Validate() = %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSigningSecretsFallBackToAPISecret(t *testing.T) {
+	t.Setenv("API_SECRET", "api-secret")
+	t.Setenv("RECEIPT_SECRET", "")
+	t.Setenv("DOCUMENT_SECRET", "document-secret")
+
+	cfg := InitConfig()
+
+	if cfg.ReceiptSecret != "api-secret" || cfg.DocumentSecret != "document-secret" {
+		t.Errorf("receipt secret %q and document secret %q", cfg.ReceiptSecret, cfg.DocumentSecret)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	t.Setenv("API_SECRET", "")
+	t.Setenv("DOCUMENT_SECRET", "")
+
+	if err := InitConfig().Validate(); err == nil {
+		t.Error("Validate() accepted empty signing secrets")
+	}
+}
+
+func TestResolveStorageDir(t *testing.T) {
+	baseDir := filepath.Join(string(filepath.Separator), "opt", "clinic")
+
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{name: "por defecto junto al ejecutable", dir: "", want: filepath.Join(baseDir, "storage")},
+		{name: "relativa al ejecutable", dir: filepath.Join("data", "files"), want: filepath.Join(baseDir, "data", "files")},
+		{name: "absoluta", dir: filepath.Join(string(filepath.Separator), "var", "lib", "clinic", ".."), want: filepath.Join(string(filepath.Separator), "var", "lib")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := resolveStorageDir(test.dir, baseDir)
+			if got != test.want {
+				t.Errorf("resolveStorageDir(%q) = %q, want %q", test.dir, got, test.want)
+			}
+
+			if !filepath.IsAbs(got) {
+				t.Errorf("resolveStorageDir(%q) = %q is not absolute", test.dir, got)
+			}
+		})
+	}
+}
@@ -32,18 +32,78 @@ func Connection(cfg *config.Config) error {
 }
 
 func MigrateDB() error {
+	// Las reglas de precios iniciales se crean junto con su tabla y nunca después
+	newPricingRules := !GDB.Migrator().HasTable(&model.PricingRule{})
+
 	err := GDB.AutoMigrate(
+		&model.Specialty{},
 		&model.Service{},
+		&model.ServiceCategory{},
 		&model.Package{},
 		&model.Patient{},
 		&model.User{},
 		&model.Appointment{},
 		&model.Doctor{},
+		&model.Payment{},
+		&model.CashSession{},
+		&model.CashSessionCount{},
+		&model.PricingRule{},
+		&model.ExchangeRate{},
+		&model.Coupon{},
+		&model.CouponRedemption{},
+		&model.InsuranceProvider{},
+		&model.InsurancePlan{},
+		&model.PlanCoverage{},
+		&model.PatientPolicy{},
+		&model.InsuranceClaim{},
+		&model.ClaimItem{},
+		&model.PackageSession{},
+		&model.PackagePurchase{},
+		&model.PackageCredit{},
+		&model.PriceHistory{},
+		&model.AppointmentItem{},
+		&model.Encounter{},
+		&model.EncounterRevision{},
+		&model.PatientAllergy{},
+		&model.ChronicCondition{},
+		&model.PatientMedication{},
+		&model.FamilyHistory{},
+		&model.Prescription{},
+		&model.PrescriptionItem{},
+		&model.Document{},
+		&model.NotificationPreference{},
+		&model.NotificationDelivery{},
+		&model.Job{},
+		&model.OutboxEvent{},
+		&model.WebhookSubscription{},
+		&model.WebhookDelivery{},
 	)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	err = migrateLegacyInsurance()
+	if err != nil {
+		return err
+	}
+
+	if newPricingRules {
+		err = seedPricingRules()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = seedServiceCategories()
+	if err != nil {
+		return err
+	}
+
+	err = migrateLegacySpecialties()
+	if err != nil {
+		return err
+	}
+
+	return seedPriceHistory()
 }
@@ -0,0 +1,283 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+)
+
+// Ámbito de las reglas de descuento por seguro usadas antes de registrar pólizas
+// y fecha de inicio de vigencia de las pólizas migradas
+const (
+	legacyInsuranceScope = "seguro"
+	legacyPolicyStart    = "2000-01-01"
+)
+
+// Registra el descuento que antes estaba fijo en el cálculo (15% por paquete). Se llama solo cuando
+// se crea la tabla de reglas, así no reaparece si el administrador lo elimina
+func seedPricingRules() error {
+	defaultRules := []model.PricingRule{
+		{Name: "Descuento por paquete", Scope: model.ScopePackage, Percentage: 15, Active: true},
+	}
+
+	return GDB.Create(&defaultRules).Error
+}
+
+// Registra en el catálogo las categorías que los servicios ya usaban como texto libre
+func seedServiceCategories() error {
+	var names []string
+
+	err := GDB.
+		Model(&model.Service{}).
+		Where("category <> ''").
+		Where("category NOT IN (?)", GDB.Model(&model.ServiceCategory{}).Select("name")).
+		Distinct().
+		Pluck("category", &names).
+		Error
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		category := model.ServiceCategory{Name: name}
+		err = GDB.Where(model.ServiceCategory{Name: name}).FirstOrCreate(&category).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Convierte las especialidades escritas como texto libre en médicos y servicios en especialidades del catálogo.
+// Los textos que solo difieren en mayúsculas, tildes o espacios se unifican en una misma especialidad
+// con el nombre de su primera aparición; luego se eliminan las columnas de texto. Los nombres distintos
+// de una misma especialidad (p. ej. "Cardio" y "Cardiología") se unen después con POST /specialties/:id/merge.
+func migrateLegacySpecialties() error {
+	hasDoctorColumn := GDB.Migrator().HasColumn(&model.Doctor{}, "especialty")
+	hasServiceColumn := GDB.Migrator().HasColumn(&model.Service{}, "specialty")
+	if !hasDoctorColumn && !hasServiceColumn {
+		return nil
+	}
+
+	var specialties []model.Specialty
+	err := GDB.Find(&specialties).Error
+	if err != nil {
+		return err
+	}
+
+	catalog := map[string]model.Specialty{}
+	for _, specialty := range specialties {
+		catalog[model.SpecialtyKey(specialty.Name)] = specialty
+	}
+
+	findOrCreate := func(name string) (*model.Specialty, error) {
+		key := model.SpecialtyKey(name)
+		if key == "" {
+			return nil, nil
+		}
+
+		specialty, exists := catalog[key]
+		if !exists {
+			specialty = model.Specialty{Name: strings.TrimSpace(name)}
+			err := GDB.Create(&specialty).Error
+			if err != nil {
+				return nil, err
+			}
+
+			catalog[key] = specialty
+		}
+
+		return &specialty, nil
+	}
+
+	type legacySpecialty struct {
+		ID   uint
+		Name string
+	}
+
+	if hasDoctorColumn {
+		var doctors []legacySpecialty
+		err = GDB.Table("doctors").Select("id, especialty AS name").Order("id").Scan(&doctors).Error
+		if err != nil {
+			return err
+		}
+
+		for _, doctor := range doctors {
+			specialty, err := findOrCreate(doctor.Name)
+			if err != nil {
+				return err
+			}
+
+			if specialty == nil {
+				continue
+			}
+
+			err = GDB.Exec("INSERT IGNORE INTO doctor_specialties (doctor_id, specialty_id) VALUES (?, ?)", doctor.ID, specialty.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = GDB.Migrator().DropColumn(&model.Doctor{}, "especialty")
+		if err != nil {
+			return err
+		}
+	}
+
+	if hasServiceColumn {
+		var services []legacySpecialty
+		err = GDB.Table("services").Select("id, specialty AS name").Order("id").Scan(&services).Error
+		if err != nil {
+			return err
+		}
+
+		for _, service := range services {
+			specialty, err := findOrCreate(service.Name)
+			if err != nil {
+				return err
+			}
+
+			if specialty == nil {
+				continue
+			}
+
+			err = GDB.Model(&model.Service{}).Where("id = ?", service.ID).Update("specialty_id", specialty.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = GDB.Migrator().DropColumn(&model.Service{}, "specialty")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Convierte el antiguo indicador de seguro del paciente en pólizas de un plan genérico cuya cobertura
+// es el porcentaje de la regla de descuento por seguro (20% si no había regla), y elimina esas reglas.
+// Las citas ya registradas conservan su monto y se consideran pagadas por completo por el paciente.
+func migrateLegacyInsurance() error {
+	if !GDB.Migrator().HasColumn(&model.Patient{}, "insurance") {
+		return nil
+	}
+
+	err := GDB.Exec("UPDATE appointments SET patient_amount = total_amount WHERE patient_amount = 0 AND insurer_amount = 0").Error
+	if err != nil {
+		return err
+	}
+
+	var legacyRule model.PricingRule
+	coverage := 20.0
+
+	result := GDB.Where("scope = ? AND active = ?", legacyInsuranceScope, true).Order("percentage DESC").Limit(1).Find(&legacyRule)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		coverage = legacyRule.Percentage
+	}
+
+	var patientIDs []uint
+	err = GDB.Table("patients").Where("insurance = ?", true).Pluck("id", &patientIDs).Error
+	if err != nil {
+		return err
+	}
+
+	if len(patientIDs) > 0 {
+		provider := model.InsuranceProvider{Name: "Seguro genérico (migrado)", Active: true}
+		err = GDB.Where(model.InsuranceProvider{Name: provider.Name}).FirstOrCreate(&provider).Error
+		if err != nil {
+			return err
+		}
+
+		plan := model.InsurancePlan{ProviderID: provider.ID, Name: "Plan genérico", DefaultCoverage: coverage, Active: true}
+		err = GDB.Create(&plan).Error
+		if err != nil {
+			return err
+		}
+
+		policies := []model.PatientPolicy{}
+		for _, patientID := range patientIDs {
+			policies = append(policies, model.PatientPolicy{
+				PatientID:    patientID,
+				PlanID:       plan.ID,
+				PolicyNumber: fmt.Sprintf("MIGRADO-%d", patientID),
+				ValidFrom:    legacyPolicyStart,
+				Active:       true,
+			})
+		}
+
+		err = GDB.Create(&policies).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	err = GDB.Where("scope = ?", legacyInsuranceScope).Delete(&model.PricingRule{}).Error
+	if err != nil {
+		return err
+	}
+
+	return GDB.Migrator().DropColumn(&model.Patient{}, "insurance")
+}
+
+// Registra el precio actual de los servicios y paquetes que aún no tienen historial de precios,
+// vigente desde la activación del historial
+func seedPriceHistory() error {
+	now := time.Now()
+	entries := []model.PriceHistory{}
+
+	var services []model.Service
+	err := GDB.
+		Where("id NOT IN (?)", GDB.Model(&model.PriceHistory{}).Select("item_id").Where("item_type = ?", model.CatalogService)).
+		Find(&services).
+		Error
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		entries = append(entries, model.PriceHistory{
+			ItemType:      model.CatalogService,
+			ItemID:        service.ID,
+			Name:          service.Name,
+			Price:         service.Price,
+			Currency:      service.Currency,
+			EffectiveFrom: now,
+		})
+	}
+
+	var packages []model.Package
+	err = GDB.
+		Where("id NOT IN (?)", GDB.Model(&model.PriceHistory{}).Select("item_id").Where("item_type = ?", model.CatalogPackage)).
+		Find(&packages).
+		Error
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range packages {
+		entries = append(entries, model.PriceHistory{
+			ItemType:      model.CatalogPackage,
+			ItemID:        pkg.ID,
+			Name:          pkg.Name,
+			Price:         pkg.Price,
+			Currency:      config.Envs.BaseCurrency,
+			EffectiveFrom: now,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return GDB.Create(&entries).Error
+}
@@ -0,0 +1,111 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/jobs"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+)
+
+// Trabajo periódico que reparte los eventos del outbox
+const JobDispatchEvents = "despachar_eventos"
+
+// Eventos que se reparten en cada vuelta
+const batchSize = 100
+
+// Recibe un evento del outbox; un error hace que se reintente solo para este suscriptor
+type Subscriber func(event *model.OutboxEvent) error
+
+type subscription struct {
+	name    string
+	jobName string
+}
+
+// Payload del trabajo que entrega un evento a un suscriptor
+type delivery struct {
+	EventID uint `json:"event_id"`
+}
+
+// Entrega los eventos del outbox a los suscriptores del proceso. Cada entrega es un trabajo del
+// runner con clave por evento y suscriptor, así que hereda sus reintentos y un suscriptor que falla
+// no afecta a los demás. La entrega es al menos una vez: un suscriptor puede recibir el mismo evento
+// de nuevo si falla después de hacer su efecto.
+type Dispatcher struct {
+	repositoryOutbox repository.OutboxRepository
+	runner           *jobs.Runner
+	subscriptions    map[model.EventType][]subscription
+}
+
+// Crea el dispatcher y registra en el runner el reparto periódico cada interval
+func NewDispatcher(repositoryOutbox repository.OutboxRepository, runner *jobs.Runner, interval time.Duration) *Dispatcher {
+	dispatcher := &Dispatcher{
+		repositoryOutbox: repositoryOutbox,
+		runner:           runner,
+		subscriptions:    map[model.EventType][]subscription{},
+	}
+
+	runner.Every(JobDispatchEvents, interval, func(string) error { return dispatcher.Dispatch() })
+
+	return dispatcher
+}
+
+// Suscribe subscriber a los eventos de eventType. name identifica al suscriptor en los trabajos y
+// debe ser único por suscriptor.
+func (d *Dispatcher) Subscribe(eventType model.EventType, name string, subscriber Subscriber) {
+	jobName := "evento_" + name
+
+	d.runner.Handle(jobName, func(payload string) error {
+		var job delivery
+
+		err := json.Unmarshal([]byte(payload), &job)
+		if err != nil {
+			return err
+		}
+
+		event, err := d.repositoryOutbox.GetByID(job.EventID)
+		if err != nil {
+			return err
+		}
+
+		return subscriber(event)
+	})
+
+	d.subscriptions[eventType] = append(d.subscriptions[eventType], subscription{name: name, jobName: jobName})
+}
+
+// Encola una entrega por suscriptor de cada evento pendiente y marca el evento como despachado. Si
+// el proceso se cae antes de marcarlo, la vuelta siguiente lo vuelve a tomar y la clave del trabajo
+// evita entregas repetidas.
+func (d *Dispatcher) Dispatch() error {
+	events, err := d.repositoryOutbox.GetPending(batchSize)
+	if err != nil {
+		log.Printf("events: Error fetching pending events: %v", err)
+		return err
+	}
+
+	for _, event := range events {
+		now := time.Now()
+
+		for _, subscription := range d.subscriptions[event.EventType] {
+			key := fmt.Sprintf("evento:%d:%s", event.ID, subscription.name)
+
+			_, err := d.runner.Enqueue(subscription.jobName, key, delivery{EventID: event.ID}, now)
+			if err != nil {
+				log.Printf("events: Error enqueuing event ID %d for %s: %v", event.ID, subscription.name, err)
+				return err
+			}
+		}
+
+		err := d.repositoryOutbox.MarkDispatched(event.ID, now)
+		if err != nil {
+			log.Printf("events: Error marking event ID %d as dispatched: %v", event.ID, err)
+			return err
+		}
+	}
+
+	return nil
+}
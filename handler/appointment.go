@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/appointment"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
@@ -14,10 +15,11 @@ import (
 
 type AppointmentHandler struct {
 	logicAppointment appointment.AppointmentLogic
+	logicHistory     logic.MedicalHistoryLogic
 }
 
-func NewAppointmentHandler(logicAppointment appointment.AppointmentLogic) *AppointmentHandler {
-	return &AppointmentHandler{logicAppointment: logicAppointment}
+func NewAppointmentHandler(logicAppointment appointment.AppointmentLogic, logicHistory logic.MedicalHistoryLogic) *AppointmentHandler {
+	return &AppointmentHandler{logicAppointment: logicAppointment, logicHistory: logicHistory}
 }
 
 func (h *AppointmentHandler) GetAppointmentByID(c echo.Context) error {
@@ -115,11 +117,51 @@ func (h *AppointmentHandler) CreateAppointment(c echo.Context) error {
 		})
 	}
 
-	return response.WriteSuccess(&response.WriteResponse{
+	//la cita ya quedó registrada; las alergias del paciente se advierten a quien la reservó
+	allergies, err := h.logicHistory.GetAllergyWarnings(appointment.PatientDNI)
+	if err != nil {
+		log.Printf("appointment-handler: Error fetching allergy warnings: %v", err)
+		allergies = []model.PatientAllergy{}
+	}
+
+	return response.WriteSuccessAllergyWarnings(&response.WriteResponse{
 		C:       c,
 		Message: response.SuccessAppointmentCreated,
 		Status:  http.StatusCreated,
 		Data:    finalPrice,
+	}, allergies)
+}
+
+func (h *AppointmentHandler) QuoteAppointment(c echo.Context) error {
+	log.Println("appointment-handler: request received in QuoteAppointment")
+
+	appointment := model.Appointment{}
+
+	err := c.Bind(&appointment)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: response.ErrorBadRequest.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	quote, err := h.logicAppointment.QuoteAppointment(&appointment, c.QueryParam("currency"))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessAppointmentQuoted,
+		Status:  http.StatusOK,
+		Data:    quote,
 	})
 }
 
@@ -166,6 +208,59 @@ func (h *AppointmentHandler) UpdateAppointment(c echo.Context) error {
 	})
 }
 
+func (h *AppointmentHandler) UpdateAppointmentStatus(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: response.ErrorInvalidID.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("appointment-handler: request received in UpdateAppointmentStatus with ID: %d", ID)
+
+	request := model.AppointmentStatusRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: response.ErrorBadRequest.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logicAppointment.UpdateAppointmentStatus(ID, request.Status)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessAppointmentStatus,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
 func (h *AppointmentHandler) DeleteAppointment(c echo.Context) error {
 	ID, err := validate.ParseID(c)
 	if err != nil {
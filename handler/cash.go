@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/auth"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type CashSessionHandler struct {
+	logic logic.CashSessionLogic
+}
+
+func NewCashSessionHandler(logic logic.CashSessionLogic) *CashSessionHandler {
+	return &CashSessionHandler{logic: logic}
+}
+
+func (h *CashSessionHandler) GetCashSessionByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("cash-handler: cash session fetching with ID: %d", ID)
+
+	session, err := h.logic.GetCashSessionByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCashSessionFound,
+		Status:  http.StatusOK,
+		Data:    session,
+	})
+}
+
+func (h *CashSessionHandler) GetAllCashSessions(c echo.Context) error {
+	log.Println("cash-handler: request received in GetAllCashSessions")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	sessions, err := h.logic.GetAllCashSessions(limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(sessions) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessCashSessionsEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.CashSession{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCashSessionsFound,
+		Status:  http.StatusOK,
+		Data:    sessions,
+	})
+}
+
+func (h *CashSessionHandler) OpenCashSession(c echo.Context) error {
+	log.Println("cash-handler: request received in OpenCashSession")
+
+	request := model.OpenCashSessionRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	session, err := h.logic.OpenCashSession(&request, auth.GetUserEmail(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusConflict,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCashSessionOpened,
+		Status:  http.StatusCreated,
+		Data:    session,
+	})
+}
+
+func (h *CashSessionHandler) CloseCashSession(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("cash-handler: request received in CloseCashSession with ID: %d", ID)
+
+	request := model.CloseCashSessionRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	report, err := h.logic.CloseCashSession(ID, &request, auth.GetUserEmail(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCashSessionClosed,
+		Status:  http.StatusOK,
+		Data:    report,
+	})
+}
+
+func (h *CashSessionHandler) GetCashSessionReport(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("cash-handler: request received in GetCashSessionReport with ID: %d", ID)
+
+	report, err := h.logic.GetCashSessionReport(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCashSessionReport,
+		Status:  http.StatusOK,
+		Data:    report,
+	})
+}
+
+func (h *CashSessionHandler) GetCashSessionReportPDF(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("cash-handler: request received in GetCashSessionReportPDF with ID: %d", ID)
+
+	pdfBytes, err := h.logic.GenerateCashSessionReportPDF(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=cierre_caja_%d.pdf", ID))
+
+	return c.Blob(http.StatusOK, "application/pdf", pdfBytes)
+}
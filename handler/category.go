@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type ServiceCategoryHandler struct {
+	logic logic.ServiceCategoryLogic
+}
+
+func NewServiceCategoryHandler(logic logic.ServiceCategoryLogic) *ServiceCategoryHandler {
+	return &ServiceCategoryHandler{logic: logic}
+}
+
+func (h *ServiceCategoryHandler) GetCategoryByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("category-handler: service category fetching with ID: %d", ID)
+
+	category, err := h.logic.GetCategoryByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessServiceCategoryFound,
+		Status:  http.StatusOK,
+		Data:    category,
+	})
+}
+
+func (h *ServiceCategoryHandler) GetAllCategories(c echo.Context) error {
+	log.Println("category-handler: request received in GetAllCategories")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	categories, err := h.logic.GetAllCategories(limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(categories) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessServiceCategoriesEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.ServiceCategory{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessServiceCategoriesFound,
+		Status:  http.StatusOK,
+		Data:    categories,
+	})
+}
+
+func (h *ServiceCategoryHandler) CreateCategory(c echo.Context) error {
+	log.Println("category-handler: request received in CreateCategory")
+
+	category := model.ServiceCategory{}
+
+	err := c.Bind(&category)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&category)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.CreateCategory(&category)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessServiceCategoryCreated,
+		Status:  http.StatusCreated,
+		Data:    category,
+	})
+}
+
+func (h *ServiceCategoryHandler) UpdateCategory(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("category-handler: request received in UpdateCategory with ID: %d", ID)
+
+	category := model.ServiceCategory{}
+
+	err = c.Bind(&category)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&category)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.UpdateCategory(ID, &category)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessServiceCategoryUpdated,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func (h *ServiceCategoryHandler) DeleteCategory(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("category-handler: request received in DeleteCategory with ID: %d", ID)
+
+	err = h.logic.DeleteCategory(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessServiceCategoryDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/auth"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type ClaimHandler struct {
+	logic logic.ClaimLogic
+}
+
+func NewClaimHandler(logic logic.ClaimLogic) *ClaimHandler {
+	return &ClaimHandler{logic: logic}
+}
+
+func (h *ClaimHandler) GetClaimByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("claim-handler: claim fetching with ID: %d", ID)
+
+	claim, err := h.logic.GetClaimByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessClaimFound,
+		Status:  http.StatusOK,
+		Data:    claim,
+	})
+}
+
+// Admite los filtros opcionales provider_id y status
+func (h *ClaimHandler) GetAllClaims(c echo.Context) error {
+	log.Println("claim-handler: request received in GetAllClaims")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	providerID, err := strconv.ParseUint(c.QueryParam("provider_id"), 10, 64)
+	if err != nil {
+		providerID = 0
+	}
+
+	status := model.ClaimStatus(c.QueryParam("status"))
+
+	claims, err := h.logic.GetAllClaims(uint(providerID), status, limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(claims) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessClaimsEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.InsuranceClaim{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessClaimsFound,
+		Status:  http.StatusOK,
+		Data:    claims,
+	})
+}
+
+func (h *ClaimHandler) CreateClaim(c echo.Context) error {
+	log.Println("claim-handler: request received in CreateClaim")
+
+	request := model.CreateClaimRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	claim, err := h.logic.CreateClaim(&request, auth.GetUserEmail(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessClaimCreated,
+		Status:  http.StatusCreated,
+		Data:    claim,
+	})
+}
+
+func (h *ClaimHandler) UpdateClaimStatus(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("claim-handler: request received in UpdateClaimStatus with ID: %d", ID)
+
+	request := model.ClaimStatusRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	claim, err := h.logic.UpdateClaimStatus(ID, &request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessClaimStatus,
+		Status:  http.StatusOK,
+		Data:    claim,
+	})
+}
+
+func (h *ClaimHandler) RegisterClaimPayment(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("claim-handler: request received in RegisterClaimPayment with ID: %d", ID)
+
+	request := model.ClaimPaymentRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	claim, err := h.logic.RegisterClaimPayment(ID, &request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessClaimPayment,
+		Status:  http.StatusCreated,
+		Data:    claim,
+	})
+}
+
+func (h *ClaimHandler) GetClaimCSV(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("claim-handler: request received in GetClaimCSV with ID: %d", ID)
+
+	csvBytes, err := h.logic.GenerateClaimCSV(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=reclamo_%d.csv", ID))
+
+	return c.Blob(http.StatusOK, "text/csv", csvBytes)
+}
+
+func (h *ClaimHandler) GetClaimBatch(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("claim-handler: request received in GetClaimBatch with ID: %d", ID)
+
+	batch, err := h.logic.GetClaimBatch(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessClaimBatch,
+		Status:  http.StatusOK,
+		Data:    batch,
+	})
+}
+
+func (h *ClaimHandler) GetReceivables(c echo.Context) error {
+	log.Println("claim-handler: request received in GetReceivables")
+
+	receivables, err := h.logic.GetReceivables()
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+
+	if len(receivables) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessClaimReceivablesEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.ClaimReceivable{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessClaimReceivables,
+		Status:  http.StatusOK,
+		Data:    receivables,
+	})
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type ConfirmationHandler struct {
+	logic logic.ConfirmationLogic
+}
+
+func NewConfirmationHandler(logic logic.ConfirmationLogic) *ConfirmationHandler {
+	return &ConfirmationHandler{logic: logic}
+}
+
+// El idioma de la confirmación se elige con ?lang=es|en; sin él se usa DEFAULT_LANGUAGE
+func (h *ConfirmationHandler) GetAppointmentConfirmation(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("confirmation-handler: request received in GetAppointmentConfirmation with ID: %d", ID)
+
+	confirmation, err := h.logic.GenerateAppointmentConfirmation(ID, c.QueryParam("lang"))
+	if err != nil {
+		status := uint(http.StatusInternalServerError)
+		if errors.Is(err, response.ErrorAppointmentNotFound) {
+			status = http.StatusNotFound
+		}
+
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  status,
+			Data:    nil,
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=confirmacion_cita_%d.pdf", ID))
+
+	return c.Blob(http.StatusOK, "application/pdf", confirmation)
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type CouponHandler struct {
+	logic logic.CouponLogic
+}
+
+func NewCouponHandler(logic logic.CouponLogic) *CouponHandler {
+	return &CouponHandler{logic: logic}
+}
+
+func (h *CouponHandler) GetCouponByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("coupon-handler: coupon fetching with ID: %d", ID)
+
+	coupon, err := h.logic.GetCouponByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCouponFound,
+		Status:  http.StatusOK,
+		Data:    coupon,
+	})
+}
+
+func (h *CouponHandler) GetAllCoupons(c echo.Context) error {
+	log.Println("coupon-handler: request received in GetAllCoupons")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	coupons, err := h.logic.GetAllCoupons(limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(coupons) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessCouponsEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.Coupon{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCouponsFound,
+		Status:  http.StatusOK,
+		Data:    coupons,
+	})
+}
+
+func (h *CouponHandler) CreateCoupon(c echo.Context) error {
+	log.Println("coupon-handler: request received in CreateCoupon")
+
+	request := model.CouponRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	coupon, err := h.logic.CreateCoupon(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCouponCreated,
+		Status:  http.StatusCreated,
+		Data:    coupon,
+	})
+}
+
+func (h *CouponHandler) UpdateCoupon(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("coupon-handler: request received in UpdateCoupon with ID: %d", ID)
+
+	request := model.CouponRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.UpdateCoupon(ID, &request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCouponUpdated,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func (h *CouponHandler) DeleteCoupon(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("coupon-handler: request received in DeleteCoupon with ID: %d", ID)
+
+	err = h.logic.DeleteCoupon(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessCouponDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
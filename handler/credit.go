@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type PackageCreditHandler struct {
+	logic logic.PackageCreditLogic
+}
+
+func NewPackageCreditHandler(logic logic.PackageCreditLogic) *PackageCreditHandler {
+	return &PackageCreditHandler{logic: logic}
+}
+
+func (h *PackageCreditHandler) GetPackagePurchaseByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("credit-handler: package purchase fetching with ID: %d", ID)
+
+	purchase, err := h.logic.GetPurchaseByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPackagePurchaseFound,
+		Status:  http.StatusOK,
+		Data:    purchase,
+	})
+}
+
+func (h *PackageCreditHandler) PurchasePackage(c echo.Context) error {
+	log.Println("credit-handler: request received in PurchasePackage")
+
+	request := model.PackagePurchaseRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	purchase, err := h.logic.PurchasePackage(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPackagePurchased,
+		Status:  http.StatusCreated,
+		Data:    purchase,
+	})
+}
+
+func (h *PackageCreditHandler) GetPatientCredits(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("credit-handler: package credits fetching for patient ID: %d", ID)
+
+	credits, err := h.logic.GetPatientCredits(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(credits) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessPackageCreditsEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.PackageCredit{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPackageCreditsFound,
+		Status:  http.StatusOK,
+		Data:    credits,
+	})
+}
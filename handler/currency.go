@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type ExchangeRateHandler struct {
+	logic logic.CurrencyLogic
+}
+
+func NewExchangeRateHandler(logic logic.CurrencyLogic) *ExchangeRateHandler {
+	return &ExchangeRateHandler{logic: logic}
+}
+
+func (h *ExchangeRateHandler) GetExchangeRateByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("currency-handler: exchange rate fetching with ID: %d", ID)
+
+	rate, err := h.logic.GetExchangeRateByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessExchangeRateFound,
+		Status:  http.StatusOK,
+		Data:    rate,
+	})
+}
+
+func (h *ExchangeRateHandler) GetAllExchangeRates(c echo.Context) error {
+	log.Println("currency-handler: request received in GetAllExchangeRates")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	rates, err := h.logic.GetAllExchangeRates(c.QueryParam("currency"), limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(rates) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessExchangeRatesEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.ExchangeRate{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessExchangeRatesFound,
+		Status:  http.StatusOK,
+		Data:    rates,
+	})
+}
+
+func (h *ExchangeRateHandler) CreateExchangeRate(c echo.Context) error {
+	log.Println("currency-handler: request received in CreateExchangeRate")
+
+	rate := model.ExchangeRate{}
+
+	err := c.Bind(&rate)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&rate)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.CreateExchangeRate(&rate)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessExchangeRateCreated,
+		Status:  http.StatusCreated,
+		Data:    rate,
+	})
+}
+
+func (h *ExchangeRateHandler) DeleteExchangeRate(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("currency-handler: request received in DeleteExchangeRate with ID: %d", ID)
+
+	err = h.logic.DeleteExchangeRate(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessExchangeRateDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
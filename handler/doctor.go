@@ -94,7 +94,12 @@ func (h *DoctorHandler) GetAllDoctors(c echo.Context) error {
 		offset = 0
 	}
 
-	doctors, err := h.logic.GetAllDoctors(limit, offset)
+	specialtyID, err := strconv.ParseUint(c.QueryParam("specialty_id"), 10, 64)
+	if err != nil {
+		specialtyID = 0
+	}
+
+	doctors, err := h.logic.GetAllDoctors(uint(specialtyID), limit, offset)
 	if len(doctors) == 0 {
 		return response.WriteError(&response.WriteResponse{
 			C:       c,
@@ -121,6 +126,42 @@ func (h *DoctorHandler) GetAllDoctors(c echo.Context) error {
 	})
 }
 
+// Requiere la fecha (date) y admite el filtro opcional specialty_id
+func (h *DoctorHandler) GetAvailability(c echo.Context) error {
+	log.Println("handler: request received in GetAvailability")
+
+	specialtyID, err := strconv.ParseUint(c.QueryParam("specialty_id"), 10, 64)
+	if err != nil {
+		specialtyID = 0
+	}
+
+	availability, err := h.logic.GetAvailability(c.QueryParam("date"), uint(specialtyID))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	if len(availability) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessAvailabilityEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.DoctorAvailability{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessAvailabilityFound,
+		Status:  http.StatusOK,
+		Data:    availability,
+	})
+}
+
 func (h *DoctorHandler) CreateDoctor(c echo.Context) error {
 	log.Println("handler: request received in CreateDoctor")
 
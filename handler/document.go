@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/auth"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type DocumentHandler struct {
+	logic logic.DocumentLogic
+}
+
+func NewDocumentHandler(logic logic.DocumentLogic) *DocumentHandler {
+	return &DocumentHandler{logic: logic}
+}
+
+func (h *DocumentHandler) GetDocumentByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("document-handler: document fetching with ID: %d", ID)
+
+	document, err := h.logic.GetDocumentByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessDocumentFound,
+		Status:  http.StatusOK,
+		Data:    document,
+	})
+}
+
+func (h *DocumentHandler) GetPatientDocuments(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("document-handler: documents fetching for patient ID: %d", patientID)
+
+	documents, err := h.logic.GetPatientDocuments(patientID)
+
+	return writeDocuments(c, documents, err)
+}
+
+func (h *DocumentHandler) GetAppointmentDocuments(c echo.Context) error {
+	appointmentID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("document-handler: documents fetching for appointment ID: %d", appointmentID)
+
+	documents, err := h.logic.GetAppointmentDocuments(appointmentID)
+
+	return writeDocuments(c, documents, err)
+}
+
+// Subida multipart: el archivo va en el campo "file" y la categoría en "category"
+func (h *DocumentHandler) UploadPatientDocument(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("document-handler: request received in UploadPatientDocument for patient ID: %d", patientID)
+
+	return h.upload(c, func(category, fileName string, file io.Reader) (*model.Document, error) {
+		return h.logic.UploadPatientDocument(patientID, category, fileName, file, auth.GetUser(c))
+	})
+}
+
+func (h *DocumentHandler) UploadAppointmentDocument(c echo.Context) error {
+	appointmentID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("document-handler: request received in UploadAppointmentDocument for appointment ID: %d", appointmentID)
+
+	return h.upload(c, func(category, fileName string, file io.Reader) (*model.Document, error) {
+		return h.logic.UploadAppointmentDocument(appointmentID, category, fileName, file, auth.GetUser(c))
+	})
+}
+
+func (h *DocumentHandler) upload(c echo.Context, save func(category, fileName string, file io.Reader) (*model.Document, error)) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: response.ErrorDocumentFileRequired.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	if fileHeader.Size > config.Envs.MaxUploadBytes {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: response.ErrorDocumentTooLarge.Error(),
+			Status:  http.StatusRequestEntityTooLarge,
+			Data:    nil,
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: response.ErrorToUploadDocument.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+	defer file.Close()
+
+	document, err := save(c.FormValue("category"), fileHeader.Filename, file)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  documentErrorStatus(err, http.StatusBadRequest),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessDocumentUploaded,
+		Status:  http.StatusCreated,
+		Data:    document,
+	})
+}
+
+func (h *DocumentHandler) GetDocumentDownloadURL(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("document-handler: request received in GetDocumentDownloadURL with ID: %d", ID)
+
+	downloadURL, err := h.logic.GetDownloadURL(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessDocumentURLCreated,
+		Status:  http.StatusOK,
+		Data:    downloadURL,
+	})
+}
+
+// Descarga con el enlace firmado; no requiere token
+func (h *DocumentHandler) DownloadDocument(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("document-handler: request received in DownloadDocument with ID: %d", ID)
+
+	document, file, err := h.logic.OpenDocument(ID, c.QueryParam("expires"), c.QueryParam("signature"))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  documentErrorStatus(err, http.StatusInternalServerError),
+			Data:    nil,
+		})
+	}
+	defer file.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", document.FileName))
+	c.Response().Header().Set("X-Checksum-SHA256", document.Checksum)
+
+	return c.Stream(http.StatusOK, document.ContentType, file)
+}
+
+func (h *DocumentHandler) DeleteDocument(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("document-handler: request received in DeleteDocument with ID: %d", ID)
+
+	err = h.logic.DeleteDocument(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  documentErrorStatus(err, http.StatusInternalServerError),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessDocumentDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func writeDocuments(c echo.Context, documents []model.Document, err error) error {
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  documentErrorStatus(err, http.StatusInternalServerError),
+			Data:    nil,
+		})
+	}
+
+	if len(documents) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessDocumentsEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.Document{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessDocumentsFound,
+		Status:  http.StatusOK,
+		Data:    documents,
+	})
+}
+
+func documentErrorStatus(err error, defaultStatus uint) uint {
+	switch {
+	case errors.Is(err, response.ErrorDocumentNotFound), errors.Is(err, response.ErrorPatientNotFoundID), errors.Is(err, response.ErrorAppointmentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, response.ErrorDocumentLinkInvalid):
+		return http.StatusForbidden
+	case errors.Is(err, response.ErrorDocumentTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, response.ErrorDocumentContentType):
+		return http.StatusUnsupportedMediaType
+	}
+
+	return defaultStatus
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/auth"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type EncounterHandler struct {
+	logic logic.EncounterLogic
+}
+
+func NewEncounterHandler(logic logic.EncounterLogic) *EncounterHandler {
+	return &EncounterHandler{logic: logic}
+}
+
+func (h *EncounterHandler) GetEncounterByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("encounter-handler: encounter fetching with ID: %d", ID)
+
+	encounter, err := h.logic.GetEncounterByID(ID, auth.GetUser(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  encounterErrorStatus(err, http.StatusNotFound),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessEncounterFound,
+		Status:  http.StatusOK,
+		Data:    encounter,
+	})
+}
+
+func (h *EncounterHandler) GetAppointmentEncounter(c echo.Context) error {
+	appointmentID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("encounter-handler: encounter fetching for appointment ID: %d", appointmentID)
+
+	encounter, err := h.logic.GetEncounterByAppointment(appointmentID, auth.GetUser(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  encounterErrorStatus(err, http.StatusNotFound),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessEncounterFound,
+		Status:  http.StatusOK,
+		Data:    encounter,
+	})
+}
+
+func (h *EncounterHandler) CreateEncounter(c echo.Context) error {
+	appointmentID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("encounter-handler: request received in CreateEncounter for appointment ID: %d", appointmentID)
+
+	request := model.EncounterRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	encounter, err := h.logic.CreateEncounter(appointmentID, &request, auth.GetUser(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  encounterErrorStatus(err, http.StatusBadRequest),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessEncounterCreated,
+		Status:  http.StatusCreated,
+		Data:    encounter,
+	})
+}
+
+func (h *EncounterHandler) UpdateEncounter(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("encounter-handler: request received in UpdateEncounter with ID: %d", ID)
+
+	request := model.EncounterRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	encounter, err := h.logic.UpdateEncounter(ID, &request, auth.GetUser(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  encounterErrorStatus(err, http.StatusBadRequest),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessEncounterUpdated,
+		Status:  http.StatusOK,
+		Data:    encounter,
+	})
+}
+
+func (h *EncounterHandler) SignEncounter(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("encounter-handler: request received in SignEncounter with ID: %d", ID)
+
+	encounter, err := h.logic.SignEncounter(ID, auth.GetUser(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  encounterErrorStatus(err, http.StatusBadRequest),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessEncounterSigned,
+		Status:  http.StatusOK,
+		Data:    encounter,
+	})
+}
+
+// El acceso denegado y el registro bloqueado se distinguen del resto de errores
+func encounterErrorStatus(err error, defaultStatus uint) uint {
+	switch {
+	case errors.Is(err, response.ErrorEncounterForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, response.ErrorEncounterLocked), errors.Is(err, response.ErrorEncounterExists):
+		return http.StatusConflict
+	case errors.Is(err, response.ErrorEncounterNotFound), errors.Is(err, response.ErrorAppointmentNotFound):
+		return http.StatusNotFound
+	}
+
+	return defaultStatus
+}
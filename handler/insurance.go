@@ -0,0 +1,621 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type InsuranceHandler struct {
+	logic logic.InsuranceLogic
+}
+
+func NewInsuranceHandler(logic logic.InsuranceLogic) *InsuranceHandler {
+	return &InsuranceHandler{logic: logic}
+}
+
+func (h *InsuranceHandler) GetInsuranceProviderByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: insurance provider fetching with ID: %d", ID)
+
+	provider, err := h.logic.GetProviderByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsuranceProviderFound,
+		Status:  http.StatusOK,
+		Data:    provider,
+	})
+}
+
+func (h *InsuranceHandler) GetAllInsuranceProviders(c echo.Context) error {
+	log.Println("insurance-handler: request received in GetAllInsuranceProviders")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	providers, err := h.logic.GetAllProviders(limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(providers) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessInsuranceProvidersEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.InsuranceProvider{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsuranceProvidersFound,
+		Status:  http.StatusOK,
+		Data:    providers,
+	})
+}
+
+func (h *InsuranceHandler) CreateInsuranceProvider(c echo.Context) error {
+	log.Println("insurance-handler: request received in CreateInsuranceProvider")
+
+	provider := model.InsuranceProvider{}
+
+	err := c.Bind(&provider)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&provider)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.CreateProvider(&provider)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsuranceProviderCreated,
+		Status:  http.StatusCreated,
+		Data:    provider,
+	})
+}
+
+func (h *InsuranceHandler) UpdateInsuranceProvider(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: request received in UpdateInsuranceProvider with ID: %d", ID)
+
+	provider := model.InsuranceProvider{}
+
+	err = c.Bind(&provider)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&provider)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.UpdateProvider(ID, &provider)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsuranceProviderUpdated,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func (h *InsuranceHandler) DeleteInsuranceProvider(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: request received in DeleteInsuranceProvider with ID: %d", ID)
+
+	err = h.logic.DeleteProvider(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsuranceProviderDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func (h *InsuranceHandler) GetInsurancePlanByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: insurance plan fetching with ID: %d", ID)
+
+	plan, err := h.logic.GetPlanByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsurancePlanFound,
+		Status:  http.StatusOK,
+		Data:    plan,
+	})
+}
+
+// Admite el filtro opcional provider_id
+func (h *InsuranceHandler) GetAllInsurancePlans(c echo.Context) error {
+	log.Println("insurance-handler: request received in GetAllInsurancePlans")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	providerID, err := strconv.ParseUint(c.QueryParam("provider_id"), 10, 64)
+	if err != nil {
+		providerID = 0
+	}
+
+	plans, err := h.logic.GetAllPlans(uint(providerID), limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(plans) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessInsurancePlansEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.InsurancePlan{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsurancePlansFound,
+		Status:  http.StatusOK,
+		Data:    plans,
+	})
+}
+
+func (h *InsuranceHandler) CreateInsurancePlan(c echo.Context) error {
+	log.Println("insurance-handler: request received in CreateInsurancePlan")
+
+	plan := model.InsurancePlan{}
+
+	err := c.Bind(&plan)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&plan)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.CreatePlan(&plan)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsurancePlanCreated,
+		Status:  http.StatusCreated,
+		Data:    plan,
+	})
+}
+
+func (h *InsuranceHandler) UpdateInsurancePlan(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: request received in UpdateInsurancePlan with ID: %d", ID)
+
+	plan := model.InsurancePlan{}
+
+	err = c.Bind(&plan)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&plan)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.UpdatePlan(ID, &plan)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsurancePlanUpdated,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func (h *InsuranceHandler) DeleteInsurancePlan(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: request received in DeleteInsurancePlan with ID: %d", ID)
+
+	err = h.logic.DeletePlan(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessInsurancePlanDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func (h *InsuranceHandler) GetPatientPolicyByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: patient policy fetching with ID: %d", ID)
+
+	policy, err := h.logic.GetPolicyByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPatientPolicyFound,
+		Status:  http.StatusOK,
+		Data:    policy,
+	})
+}
+
+func (h *InsuranceHandler) GetPatientPolicies(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: policies fetching for patient ID: %d", ID)
+
+	policies, err := h.logic.GetPatientPolicies(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(policies) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessPatientPoliciesEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.PatientPolicy{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPatientPoliciesFound,
+		Status:  http.StatusOK,
+		Data:    policies,
+	})
+}
+
+func (h *InsuranceHandler) CreatePatientPolicy(c echo.Context) error {
+	log.Println("insurance-handler: request received in CreatePatientPolicy")
+
+	policy := model.PatientPolicy{}
+
+	err := c.Bind(&policy)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&policy)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.CreatePolicy(&policy)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPatientPolicyCreated,
+		Status:  http.StatusCreated,
+		Data:    policy,
+	})
+}
+
+func (h *InsuranceHandler) UpdatePatientPolicy(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: request received in UpdatePatientPolicy with ID: %d", ID)
+
+	policy := model.PatientPolicy{}
+
+	err = c.Bind(&policy)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&policy)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.UpdatePolicy(ID, &policy)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPatientPolicyUpdated,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func (h *InsuranceHandler) DeletePatientPolicy(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("insurance-handler: request received in DeletePatientPolicy with ID: %d", ID)
+
+	err = h.logic.DeletePolicy(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPatientPolicyDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
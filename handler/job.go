@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type JobHandler struct {
+	logic logic.JobLogic
+}
+
+func NewJobHandler(logic logic.JobLogic) *JobHandler {
+	return &JobHandler{logic: logic}
+}
+
+func (h *JobHandler) GetJobByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("job-handler: job fetching with ID: %d", ID)
+
+	job, err := h.logic.GetJobByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessJobFound,
+		Status:  http.StatusOK,
+		Data:    job,
+	})
+}
+
+// Lista de trabajos; ?status=pendiente|en_curso|completado|fallido filtra por estado
+func (h *JobHandler) GetJobs(c echo.Context) error {
+	log.Println("job-handler: request received in GetJobs")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	jobs, err := h.logic.GetJobs(model.JobStatus(c.QueryParam("status")), limit, offset)
+	if err != nil {
+		status := uint(http.StatusInternalServerError)
+		if errors.Is(err, response.ErrorInvalidJobStatus) {
+			status = http.StatusBadRequest
+		}
+
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  status,
+			Data:    nil,
+		})
+	}
+
+	if len(jobs) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessJobsEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.Job{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessJobsFound,
+		Status:  http.StatusOK,
+		Data:    jobs,
+	})
+}
+
+func (h *JobHandler) RetryJob(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("job-handler: request received in RetryJob with ID: %d", ID)
+
+	job, err := h.logic.RetryJob(ID)
+	if err != nil {
+		status := uint(http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, response.ErrorJobNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, response.ErrorJobNotFailed):
+			status = http.StatusConflict
+		}
+
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  status,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessJobRetried,
+		Status:  http.StatusOK,
+		Data:    job,
+	})
+}
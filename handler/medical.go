@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+// Parámetro de la ruta con el ID del registro dentro del historial del paciente
+const recordIDParam = "record_id"
+
+// Administra una sección del historial médico del paciente (alergias, enfermedades crónicas,
+// medicamentos o antecedentes familiares); name se usa solo en los logs
+type PatientRecordHandler[T any] struct {
+	logic logic.PatientRecordLogic[T]
+	name  string
+}
+
+func NewPatientRecordHandler[T any](logic logic.PatientRecordLogic[T], name string) *PatientRecordHandler[T] {
+	return &PatientRecordHandler[T]{logic: logic, name: name}
+}
+
+func (h *PatientRecordHandler[T]) GetRecords(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("medical-handler: %s fetching for patient ID: %d", h.name, patientID)
+
+	records, err := h.logic.GetRecords(patientID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(records) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessPatientRecordsEmpty,
+			Status:  http.StatusOK,
+			Data:    []T{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPatientRecordsFound,
+		Status:  http.StatusOK,
+		Data:    records,
+	})
+}
+
+func (h *PatientRecordHandler[T]) CreateRecord(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("medical-handler: request received in CreateRecord (%s) for patient ID: %d", h.name, patientID)
+
+	record := new(T)
+
+	err = c.Bind(record)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(record)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.CreateRecord(patientID, record)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPatientRecordCreated,
+		Status:  http.StatusCreated,
+		Data:    record,
+	})
+}
+
+func (h *PatientRecordHandler[T]) UpdateRecord(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	ID, err := validate.ParseParamID(c, recordIDParam)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("medical-handler: request received in UpdateRecord (%s) with ID: %d", h.name, ID)
+
+	record := new(T)
+
+	err = c.Bind(record)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(record)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.UpdateRecord(patientID, ID, record)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPatientRecordUpdated,
+		Status:  http.StatusOK,
+		Data:    record,
+	})
+}
+
+func (h *PatientRecordHandler[T]) DeleteRecord(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	ID, err := validate.ParseParamID(c, recordIDParam)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("medical-handler: request received in DeleteRecord (%s) with ID: %d", h.name, ID)
+
+	err = h.logic.DeleteRecord(patientID, ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPatientRecordDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+type MedicalHistoryHandler struct {
+	logic logic.MedicalHistoryLogic
+}
+
+func NewMedicalHistoryHandler(logic logic.MedicalHistoryLogic) *MedicalHistoryHandler {
+	return &MedicalHistoryHandler{logic: logic}
+}
+
+func (h *MedicalHistoryHandler) GetMedicalSummary(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("medical-handler: medical summary fetching for patient ID: %d", patientID)
+
+	summary, err := h.logic.GetMedicalSummary(patientID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessMedicalSummaryFound,
+		Status:  http.StatusOK,
+		Data:    summary,
+	})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	logic logic.NotificationLogic
+}
+
+func NewNotificationHandler(logic logic.NotificationLogic) *NotificationHandler {
+	return &NotificationHandler{logic: logic}
+}
+
+func (h *NotificationHandler) GetNotificationPreference(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("notification-handler: notification preference fetching for patient ID: %d", patientID)
+
+	preference, err := h.logic.GetPreference(patientID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  notificationErrorStatus(err),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessNotificationPreferenceFound,
+		Status:  http.StatusOK,
+		Data:    preference,
+	})
+}
+
+func (h *NotificationHandler) UpdateNotificationPreference(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("notification-handler: request received in UpdateNotificationPreference for patient ID: %d", patientID)
+
+	request := model.NotificationPreferenceRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: response.ErrorBadRequest.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	preference, err := h.logic.UpdatePreference(patientID, &request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  notificationErrorStatus(err),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessNotificationPreferenceUpdated,
+		Status:  http.StatusOK,
+		Data:    preference,
+	})
+}
+
+// Registro de avisos del paciente, del más reciente al más antiguo
+func (h *NotificationHandler) GetPatientNotifications(c echo.Context) error {
+	patientID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("notification-handler: notifications fetching for patient ID: %d", patientID)
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	deliveries, err := h.logic.GetDeliveries(patientID, limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  notificationErrorStatus(err),
+			Data:    nil,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessNotificationsEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.NotificationDelivery{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessNotificationsFound,
+		Status:  http.StatusOK,
+		Data:    deliveries,
+	})
+}
+
+func notificationErrorStatus(err error) uint {
+	if errors.Is(err, response.ErrorPatientNotFoundID) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
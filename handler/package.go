@@ -52,6 +52,7 @@ func (h *PackageHandler) GetPackageByID(c echo.Context) error {
 	})
 }
 
+// Admite el filtro opcional active=true para listar solo los paquetes que no están retirados
 func (h *PackageHandler) GetAllPackages(c echo.Context) error {
 	log.Println("package-handler: request received in GetAllPackages")
 
@@ -65,7 +66,12 @@ func (h *PackageHandler) GetAllPackages(c echo.Context) error {
 		offset = 0
 	}
 
-	packageServices, err := h.logicPkg.GetAllPackages(limit, offset)
+	onlyActive, err := strconv.ParseBool(c.QueryParam("active"))
+	if err != nil {
+		onlyActive = false
+	}
+
+	packageServices, err := h.logicPkg.GetAllPackages(onlyActive, limit, offset)
 	if len(packageServices) == 0 {
 		return response.WriteError(&response.WriteResponse{
 			C:       c,
@@ -190,7 +196,7 @@ func (h *PackageHandler) DeletePackage(c echo.Context) error {
 
 	log.Printf("package-handler: request received in DeletePackage with ID: %d", ID)
 
-	err = h.logicPkg.DeletePackage(uint(ID))
+	retired, err := h.logicPkg.DeletePackage(uint(ID))
 	if err != nil {
 		return response.WriteError(&response.WriteResponse{
 			C:       c,
@@ -200,10 +206,99 @@ func (h *PackageHandler) DeletePackage(c echo.Context) error {
 		})
 	}
 
+	message := response.SuccessPackageDeleted
+	if retired {
+		message = response.SuccessPackageRetired
+	}
+
 	return response.WriteSuccess(&response.WriteResponse{
 		C:       c,
-		Message: response.SuccessPackageDeleted,
+		Message: message,
 		Status:  http.StatusOK,
 		Data:    nil,
 	})
 }
+
+func (h *PackageHandler) UpdatePackageStatus(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("package-handler: request received in UpdatePackageStatus with ID: %d", ID)
+
+	request := model.PackageStatusRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	pkg, err := h.logicPkg.UpdatePackageStatus(ID, *request.Active)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPackageStatus,
+		Status:  http.StatusOK,
+		Data:    pkg,
+	})
+}
+
+func (h *PackageHandler) GetPackagePriceHistory(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("package-handler: price history fetching for package ID: %d", ID)
+
+	history, err := h.logicPkg.GetPriceHistory(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPriceHistoryFound,
+		Status:  http.StatusOK,
+		Data:    history,
+	})
+}
@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/auth"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
 	"github.com/labstack/echo/v4"
 )
 
@@ -37,12 +43,26 @@ func (h *PaymentHandler) PaymentRegister(c echo.Context) error {
 	if err != nil {
 		return response.WriteError(&response.WriteResponse{
 			C:       c,
-			Message: response.ErrorProcessingPayment.Error(),
+			Message: err.Error(),
 			Status:  http.StatusInternalServerError,
 			Data:    nil,
 		})
 	}
 
+	// Con "Accept: application/pdf" se responde directamente el recibo
+	if strings.Contains(c.Request().Header.Get(echo.HeaderAccept), "application/pdf") {
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=recibo_%d.pdf", paymentResponse.PaymentID))
+		c.Response().Header().Set(echo.HeaderLocation, paymentResponse.PDFReceipt)
+
+		return c.Blob(http.StatusCreated, "application/pdf", paymentResponse.ReceiptPDF)
+	}
+
+	// Con ?inline_pdf=true el recibo se incluye en base64 en la respuesta JSON
+	inline, err := strconv.ParseBool(c.QueryParam("inline_pdf"))
+	if err != nil || !inline {
+		paymentResponse.ReceiptPDF = nil
+	}
+
 	return response.WriteSuccess(&response.WriteResponse{
 		C:       c,
 		Message: response.SuccessPaymentRegister,
@@ -50,3 +70,166 @@ func (h *PaymentHandler) PaymentRegister(c echo.Context) error {
 		Data:    paymentResponse,
 	})
 }
+
+// El idioma del recibo se elige con ?lang=es|en; sin él se usa DEFAULT_LANGUAGE
+func (h *PaymentHandler) GetPaymentReceipt(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("handler: request received in GetPaymentReceipt with ID: %d", ID)
+
+	receipt, err := h.logic.GetPaymentReceipt(ID, c.QueryParam("lang"))
+	if err != nil {
+		return writeReceiptError(c, err)
+	}
+	defer receipt.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=recibo_%d.pdf", ID))
+
+	return c.Stream(http.StatusOK, "application/pdf", receipt)
+}
+
+func (h *PaymentHandler) GetPaymentInvoice(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("handler: request received in GetPaymentInvoice with ID: %d", ID)
+
+	invoice, err := h.logic.GetPaymentInvoice(ID, c.QueryParam("lang"))
+	if err != nil {
+		return writeReceiptError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=comprobante_%d.pdf", ID))
+
+	return c.Blob(http.StatusOK, "application/pdf", invoice)
+}
+
+func (h *PaymentHandler) GetPaymentQRCode(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("handler: request received in GetPaymentQRCode with ID: %d", ID)
+
+	qrCode, err := h.logic.GetPaymentQRCode(ID)
+	if err != nil {
+		return writeReceiptError(c, err)
+	}
+	defer qrCode.Close()
+
+	return c.Stream(http.StatusOK, "image/png", qrCode)
+}
+
+func (h *PaymentHandler) RefundPayment(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("handler: request received in RefundPayment with ID: %d", ID)
+
+	request := model.RefundRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	payment, err := h.logic.RefundPayment(ID, &request, auth.GetUser(c))
+	if err != nil {
+		if errors.Is(err, response.ErrorPaymentRefunded) {
+			return response.WriteError(&response.WriteResponse{
+				C:       c,
+				Message: err.Error(),
+				Status:  http.StatusConflict,
+				Data:    nil,
+			})
+		}
+
+		return writeReceiptError(c, err)
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPaymentRefunded,
+		Status:  http.StatusOK,
+		Data:    payment,
+	})
+}
+
+// Endpoint público al que apunta el QR del recibo
+func (h *PaymentHandler) VerifyReceipt(c echo.Context) error {
+	log.Println("handler: request received in VerifyReceipt")
+
+	verification, err := h.logic.VerifyReceipt(c.Param("token"))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessReceiptVerified,
+		Status:  http.StatusOK,
+		Data:    verification,
+	})
+}
+
+func writeReceiptError(c echo.Context, err error) error {
+	status := uint(http.StatusInternalServerError)
+	if errors.Is(err, response.ErrorPaymentNotFound) || errors.Is(err, response.ErrorPaymentWithoutReceipt) || errors.Is(err, response.ErrorAppointmentNotFound) {
+		status = http.StatusNotFound
+	}
+
+	return response.WriteError(&response.WriteResponse{
+		C:       c,
+		Message: err.Error(),
+		Status:  status,
+		Data:    nil,
+	})
+}
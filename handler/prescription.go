@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/auth"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type PrescriptionHandler struct {
+	logic logic.PrescriptionLogic
+}
+
+func NewPrescriptionHandler(logic logic.PrescriptionLogic) *PrescriptionHandler {
+	return &PrescriptionHandler{logic: logic}
+}
+
+func (h *PrescriptionHandler) GetPrescriptionByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("prescription-handler: prescription fetching with ID: %d", ID)
+
+	prescription, err := h.logic.GetPrescriptionByID(ID, auth.GetUser(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  prescriptionErrorStatus(err, http.StatusNotFound),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPrescriptionFound,
+		Status:  http.StatusOK,
+		Data:    prescription,
+	})
+}
+
+func (h *PrescriptionHandler) GetAppointmentPrescriptions(c echo.Context) error {
+	appointmentID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("prescription-handler: prescriptions fetching for appointment ID: %d", appointmentID)
+
+	prescriptions, err := h.logic.GetPrescriptionsByAppointment(appointmentID, auth.GetUser(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  prescriptionErrorStatus(err, http.StatusInternalServerError),
+			Data:    nil,
+		})
+	}
+
+	if len(prescriptions) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessPrescriptionsEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.Prescription{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPrescriptionsFound,
+		Status:  http.StatusOK,
+		Data:    prescriptions,
+	})
+}
+
+func (h *PrescriptionHandler) CreatePrescription(c echo.Context) error {
+	appointmentID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("prescription-handler: request received in CreatePrescription for appointment ID: %d", appointmentID)
+
+	request := model.CreatePrescriptionRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	prescription, err := h.logic.CreatePrescription(appointmentID, &request, auth.GetUser(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  prescriptionErrorStatus(err, http.StatusBadRequest),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPrescriptionCreated,
+		Status:  http.StatusCreated,
+		Data:    prescription,
+	})
+}
+
+func (h *PrescriptionHandler) GetPrescriptionPDF(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("prescription-handler: request received in GetPrescriptionPDF with ID: %d", ID)
+
+	pdfBytes, err := h.logic.GeneratePrescriptionPDF(ID, auth.GetUser(c))
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  prescriptionErrorStatus(err, http.StatusInternalServerError),
+			Data:    nil,
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=receta_%d.pdf", ID))
+
+	return c.Blob(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// Endpoint público al que apunta el QR de la receta
+func (h *PrescriptionHandler) VerifyPrescription(c echo.Context) error {
+	code := c.Param("code")
+
+	log.Printf("prescription-handler: request received in VerifyPrescription")
+
+	verification, err := h.logic.VerifyPrescription(code)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPrescriptionVerified,
+		Status:  http.StatusOK,
+		Data:    verification,
+	})
+}
+
+func prescriptionErrorStatus(err error, defaultStatus uint) uint {
+	switch {
+	case errors.Is(err, response.ErrorPrescriptionForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, response.ErrorPrescriptionNotFound), errors.Is(err, response.ErrorAppointmentNotFound):
+		return http.StatusNotFound
+	}
+
+	return defaultStatus
+}
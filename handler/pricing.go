@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type PricingRuleHandler struct {
+	logic logic.PricingRuleLogic
+}
+
+func NewPricingRuleHandler(logic logic.PricingRuleLogic) *PricingRuleHandler {
+	return &PricingRuleHandler{logic: logic}
+}
+
+func (h *PricingRuleHandler) GetPricingRuleByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("pricing-handler: pricing rule fetching with ID: %d", ID)
+
+	rule, err := h.logic.GetPricingRuleByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPricingRuleFound,
+		Status:  http.StatusOK,
+		Data:    rule,
+	})
+}
+
+func (h *PricingRuleHandler) GetAllPricingRules(c echo.Context) error {
+	log.Println("pricing-handler: request received in GetAllPricingRules")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	rules, err := h.logic.GetAllPricingRules(limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(rules) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessPricingRulesEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.PricingRule{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPricingRulesFound,
+		Status:  http.StatusOK,
+		Data:    rules,
+	})
+}
+
+func (h *PricingRuleHandler) CreatePricingRule(c echo.Context) error {
+	log.Println("pricing-handler: request received in CreatePricingRule")
+
+	rule := model.PricingRule{}
+
+	err := c.Bind(&rule)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&rule)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.CreatePricingRule(&rule)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPricingRuleCreated,
+		Status:  http.StatusCreated,
+		Data:    rule,
+	})
+}
+
+func (h *PricingRuleHandler) UpdatePricingRule(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("pricing-handler: request received in UpdatePricingRule with ID: %d", ID)
+
+	rule := model.PricingRule{}
+
+	err = c.Bind(&rule)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&rule)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.UpdatePricingRule(ID, &rule)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPricingRuleUpdated,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func (h *PricingRuleHandler) DeletePricingRule(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("pricing-handler: request received in DeletePricingRule with ID: %d", ID)
+
+	err = h.logic.DeletePricingRule(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPricingRuleDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
@@ -51,6 +51,7 @@ func (h *ServiceHandler) GetServiceByID(c echo.Context) error {
 	})
 }
 
+// Admite los filtros opcionales category y active=true para listar solo los servicios que se pueden reservar
 func (h *ServiceHandler) GetAllServices(c echo.Context) error {
 	log.Println("service-handler: request received in GetAllServices")
 
@@ -64,7 +65,12 @@ func (h *ServiceHandler) GetAllServices(c echo.Context) error {
 		offset = 0
 	}
 
-	services, err := h.logic.GetAllServices(limit, offset)
+	onlyActive, err := strconv.ParseBool(c.QueryParam("active"))
+	if err != nil {
+		onlyActive = false
+	}
+
+	services, err := h.logic.GetAllServices(c.QueryParam("category"), onlyActive, limit, offset)
 	if len(services) == 0 {
 		return response.WriteSuccess(&response.WriteResponse{
 			C:       c,
@@ -167,6 +173,59 @@ func (h *ServiceHandler) UpdateService(c echo.Context) error {
 	})
 }
 
+func (h *ServiceHandler) UpdateServiceStatus(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("service-handler: request received in UpdateServiceStatus with ID: %d", ID)
+
+	request := model.ServiceStatusRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	service, err := h.logic.UpdateServiceStatus(ID, *request.Active)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessServiceStatus,
+		Status:  http.StatusOK,
+		Data:    service,
+	})
+}
+
 func (h *ServiceHandler) DeleteService(c echo.Context) error {
 	ID, err := validate.ParseID(c)
 	if err != nil {
@@ -197,3 +256,34 @@ func (h *ServiceHandler) DeleteService(c echo.Context) error {
 		Data:    nil,
 	})
 }
+
+func (h *ServiceHandler) GetServicePriceHistory(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("service-handler: price history fetching for service ID: %d", ID)
+
+	history, err := h.logic.GetPriceHistory(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessPriceHistoryFound,
+		Status:  http.StatusOK,
+		Data:    history,
+	})
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type SpecialtyHandler struct {
+	logic logic.SpecialtyLogic
+}
+
+func NewSpecialtyHandler(logic logic.SpecialtyLogic) *SpecialtyHandler {
+	return &SpecialtyHandler{logic: logic}
+}
+
+func (h *SpecialtyHandler) GetSpecialtyByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("specialty-handler: specialty fetching with ID: %d", ID)
+
+	specialty, err := h.logic.GetSpecialtyByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessSpecialtyFound,
+		Status:  http.StatusOK,
+		Data:    specialty,
+	})
+}
+
+func (h *SpecialtyHandler) GetAllSpecialties(c echo.Context) error {
+	log.Println("specialty-handler: request received in GetAllSpecialties")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	specialties, err := h.logic.GetAllSpecialties(limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	if len(specialties) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessSpecialtiesEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.Specialty{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessSpecialtiesFound,
+		Status:  http.StatusOK,
+		Data:    specialties,
+	})
+}
+
+func (h *SpecialtyHandler) CreateSpecialty(c echo.Context) error {
+	log.Println("specialty-handler: request received in CreateSpecialty")
+
+	specialty := model.Specialty{}
+
+	err := c.Bind(&specialty)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&specialty)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.CreateSpecialty(&specialty)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessSpecialtyCreated,
+		Status:  http.StatusCreated,
+		Data:    specialty,
+	})
+}
+
+func (h *SpecialtyHandler) UpdateSpecialty(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("specialty-handler: request received in UpdateSpecialty with ID: %d", ID)
+
+	specialty := model.Specialty{}
+
+	err = c.Bind(&specialty)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&specialty)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = h.logic.UpdateSpecialty(ID, &specialty)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessSpecialtyUpdated,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+func (h *SpecialtyHandler) DeleteSpecialty(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("specialty-handler: request received in DeleteSpecialty with ID: %d", ID)
+
+	err = h.logic.DeleteSpecialty(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessSpecialtyDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+// Une la especialidad del path con la indicada en el cuerpo y devuelve la que queda
+func (h *SpecialtyHandler) MergeSpecialty(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("specialty-handler: request received in MergeSpecialty with ID: %d", ID)
+
+	request := model.MergeSpecialtyRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	specialty, err := h.logic.MergeSpecialty(ID, &request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  specialtyErrorStatus(err),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessSpecialtyMerged,
+		Status:  http.StatusOK,
+		Data:    specialty,
+	})
+}
+
+func specialtyErrorStatus(err error) uint {
+	switch {
+	case errors.Is(err, response.ErrorSpecialtyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, response.ErrorSpecialtyMergeSame):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	logic logic.WebhookLogic
+}
+
+func NewWebhookHandler(logic logic.WebhookLogic) *WebhookHandler {
+	return &WebhookHandler{logic: logic}
+}
+
+func (h *WebhookHandler) GetWebhookByID(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("webhook-handler: webhook fetching with ID: %d", ID)
+
+	subscription, err := h.logic.GetWebhookByID(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusNotFound,
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessWebhookFound,
+		Status:  http.StatusOK,
+		Data:    subscription,
+	})
+}
+
+func (h *WebhookHandler) GetAllWebhooks(c echo.Context) error {
+	log.Println("webhook-handler: request received in GetAllWebhooks")
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	subscriptions, err := h.logic.GetAllWebhooks(limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusInternalServerError,
+			Data:    nil,
+		})
+	}
+
+	if len(subscriptions) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessWebhooksEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.WebhookSubscription{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessWebhooksFound,
+		Status:  http.StatusOK,
+		Data:    subscriptions,
+	})
+}
+
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	log.Println("webhook-handler: request received in CreateWebhook")
+
+	request := model.WebhookRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	subscription, err := h.logic.CreateWebhook(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  webhookErrorStatus(err),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessWebhookCreated,
+		Status:  http.StatusCreated,
+		Data:    subscription,
+	})
+}
+
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("webhook-handler: request received in UpdateWebhook with ID: %d", ID)
+
+	request := model.WebhookRequest{}
+
+	err = c.Bind(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	subscription, err := h.logic.UpdateWebhook(ID, &request)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  webhookErrorStatus(err),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessWebhookUpdated,
+		Status:  http.StatusOK,
+		Data:    subscription,
+	})
+}
+
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("webhook-handler: request received in DeleteWebhook with ID: %d", ID)
+
+	err = h.logic.DeleteWebhook(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  webhookErrorStatus(err),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessWebhookDeleted,
+		Status:  http.StatusOK,
+		Data:    nil,
+	})
+}
+
+// Registro de intentos de envío del webhook, los más recientes primero
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("webhook-handler: request received in GetDeliveries with ID: %d", ID)
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	deliveries, err := h.logic.GetDeliveries(ID, limit, offset)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  webhookErrorStatus(err),
+			Data:    nil,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return response.WriteSuccess(&response.WriteResponse{
+			C:       c,
+			Message: response.SuccessWebhookDeliveriesEmpty,
+			Status:  http.StatusOK,
+			Data:    []model.WebhookDelivery{},
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessWebhookDeliveriesFound,
+		Status:  http.StatusOK,
+		Data:    deliveries,
+	})
+}
+
+// Reenvía el evento de un envío registrado; la respuesta es el nuevo intento, entregado o fallido
+func (h *WebhookHandler) ReplayDelivery(c echo.Context) error {
+	ID, err := validate.ParseID(c)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+			Data:    nil,
+		})
+	}
+
+	log.Printf("webhook-handler: request received in ReplayDelivery with ID: %d", ID)
+
+	delivery, err := h.logic.ReplayDelivery(ID)
+	if err != nil {
+		return response.WriteError(&response.WriteResponse{
+			C:       c,
+			Message: err.Error(),
+			Status:  webhookErrorStatus(err),
+			Data:    nil,
+		})
+	}
+
+	return response.WriteSuccess(&response.WriteResponse{
+		C:       c,
+		Message: response.SuccessWebhookReplayed,
+		Status:  http.StatusOK,
+		Data:    delivery,
+	})
+}
+
+func webhookErrorStatus(err error) uint {
+	switch {
+	case errors.Is(err, response.ErrorWebhookNotFound),
+		errors.Is(err, response.ErrorWebhookDeliveryNotFound),
+		errors.Is(err, response.ErrorWebhookEventNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, response.ErrorInvalidWebhookURL),
+		errors.Is(err, response.ErrorInvalidWebhookEvent):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
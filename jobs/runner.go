@@ -0,0 +1,247 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+)
+
+const (
+	// Intentos antes de dar un trabajo por fallido
+	defaultMaxAttempts = 5
+	// Tiempo que una instancia retiene un trabajo; si se cae, otra lo retoma al vencer
+	lockDuration = 5 * time.Minute
+	// Trabajos que se toman en cada revisión
+	batchSize = 10
+)
+
+// Ejecuta un trabajo con el payload con que se encoló; un error hace que se reintente
+type Handler func(payload string) error
+
+// Cuántas veces se intenta un trabajo y cuánto se espera antes de cada reintento
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     func(attempts int) time.Duration
+}
+
+// Espera que se duplica en cada intento: base, 2*base, 4*base... sin pasar de max
+func ExponentialBackoff(base, max time.Duration) func(attempts int) time.Duration {
+	return func(attempts int) time.Duration {
+		wait := base
+		for i := 1; i < attempts && wait < max; i++ {
+			wait *= 2
+		}
+
+		if wait > max {
+			wait = max
+		}
+
+		return wait
+	}
+}
+
+type recurringJob struct {
+	name     string
+	interval time.Duration
+}
+
+// Ejecuta en segundo plano los trabajos guardados en la tabla jobs. Varias instancias de la API
+// pueden correr a la vez: cada trabajo lo toma una sola (ver JobRepository.Claim).
+type Runner struct {
+	repositoryJob repository.JobRepository
+	handlers      map[string]Handler
+	policies      map[string]RetryPolicy
+	recurring     []recurringJob
+	owner         string
+	pollInterval  time.Duration
+}
+
+func NewRunner(repositoryJob repository.JobRepository, pollInterval time.Duration) *Runner {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "api"
+	}
+
+	return &Runner{
+		repositoryJob: repositoryJob,
+		handlers:      map[string]Handler{},
+		policies:      map[string]RetryPolicy{},
+		owner:         fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		pollInterval:  pollInterval,
+	}
+}
+
+// Registra el handler de los trabajos llamados name
+func (r *Runner) Handle(name string, handler Handler) {
+	r.handlers[name] = handler
+}
+
+// Cambia los reintentos de los trabajos llamados name; debe llamarse antes de encolarlos
+func (r *Runner) SetRetryPolicy(name string, policy RetryPolicy) {
+	r.policies[name] = policy
+}
+
+// Registra un trabajo periódico; se guarda una sola fila por nombre que vuelve a quedar pendiente
+// cada interval
+func (r *Runner) Every(name string, interval time.Duration, handler Handler) {
+	r.Handle(name, handler)
+	r.recurring = append(r.recurring, recurringJob{name: name, interval: interval})
+}
+
+// Encola un trabajo para runAt; si ya existe otro con la misma clave no se vuelve a encolar
+func (r *Runner) Enqueue(name, key string, payload interface{}, runAt time.Time) (bool, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return false, err
+	}
+
+	maxAttempts := defaultMaxAttempts
+	if policy, exists := r.policies[name]; exists && policy.MaxAttempts > 0 {
+		maxAttempts = policy.MaxAttempts
+	}
+
+	return r.repositoryJob.Enqueue(&model.Job{
+		Key:         key,
+		Name:        name,
+		Payload:     string(data),
+		Status:      model.JobPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt,
+	})
+}
+
+// Crea las filas de los trabajos periódicos y revisa la tabla cada pollInterval
+func (r *Runner) Start() {
+	now := time.Now()
+
+	for _, recurring := range r.recurring {
+		_, err := r.repositoryJob.Enqueue(&model.Job{
+			Key:         recurring.name,
+			Name:        recurring.name,
+			Payload:     "{}",
+			Status:      model.JobPending,
+			MaxAttempts: defaultMaxAttempts,
+			Interval:    int64(recurring.interval / time.Second),
+			RunAt:       now,
+		})
+		if err != nil {
+			log.Printf("jobs: Error registering recurring job %s: %v", recurring.name, err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(r.pollInterval)
+		defer ticker.Stop()
+
+		for ; ; <-ticker.C {
+			r.runDue()
+		}
+	}()
+}
+
+func (r *Runner) runDue() {
+	now := time.Now()
+
+	jobs, err := r.repositoryJob.GetDue(now, batchSize)
+	if err != nil {
+		log.Printf("jobs: Error fetching due jobs: %v", err)
+		return
+	}
+
+	for i := range jobs {
+		claimed, err := r.repositoryJob.Claim(jobs[i].ID, r.owner, now, now.Add(lockDuration))
+		if err != nil {
+			log.Printf("jobs: Error claiming job ID %d: %v", jobs[i].ID, err)
+			continue
+		}
+
+		// Otra instancia lo tomó primero
+		if !claimed {
+			continue
+		}
+
+		jobs[i].Attempts++
+		r.run(&jobs[i])
+	}
+}
+
+func (r *Runner) run(job *model.Job) {
+	err := r.execute(job)
+	now := time.Now()
+
+	values := map[string]interface{}{"last_error": ""}
+
+	switch {
+	case err == nil && job.Interval > 0:
+		values["status"] = model.JobPending
+		values["attempts"] = 0
+		values["run_at"] = now.Add(time.Duration(job.Interval) * time.Second)
+	case err == nil:
+		values["status"] = model.JobCompleted
+	case job.Attempts < job.MaxAttempts:
+		values["status"] = model.JobPending
+		values["run_at"] = now.Add(r.backoff(job))
+		values["last_error"] = limitError(err)
+	case job.Interval > 0:
+		// Un trabajo periódico agotó sus intentos: se deja registrado el error y se espera a la siguiente vuelta
+		values["status"] = model.JobPending
+		values["attempts"] = 0
+		values["run_at"] = now.Add(time.Duration(job.Interval) * time.Second)
+		values["last_error"] = limitError(err)
+	default:
+		values["status"] = model.JobFailed
+		values["last_error"] = limitError(err)
+	}
+
+	if err != nil {
+		log.Printf("jobs: Error running job %s (ID %d, attempt %d of %d): %v", job.Name, job.ID, job.Attempts, job.MaxAttempts, err)
+	}
+
+	err = r.repositoryJob.Release(job.ID, r.owner, values)
+	if err != nil {
+		log.Printf("jobs: Error saving result of job ID %d: %v", job.ID, err)
+	}
+}
+
+// Espera antes del siguiente intento; por defecto crece 1, 4, 9, 16... minutos
+func (r *Runner) backoff(job *model.Job) time.Duration {
+	policy, exists := r.policies[job.Name]
+	if exists && policy.Backoff != nil {
+		return policy.Backoff(job.Attempts)
+	}
+
+	return time.Duration(job.Attempts*job.Attempts) * time.Minute
+}
+
+// Ejecuta el handler; un panic se trata como error para no tumbar el proceso
+func (r *Runner) execute(job *model.Job) (err error) {
+	handler, exists := r.handlers[job.Name]
+	if !exists {
+		return errors.New("no handler registered for job " + job.Name)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return handler(job.Payload)
+}
+
+// El error se recorta al tamaño de la columna sin dejar un carácter a medias
+func limitError(err error) string {
+	message := err.Error()
+	if len(message) > 255 {
+		message = strings.ToValidUTF8(message[:255], "")
+	}
+
+	return message
+}
@@ -0,0 +1,231 @@
+package jobs
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+)
+
+// Tabla jobs en memoria con las mismas condiciones que JobRepository
+type fakeJobRepository struct {
+	jobs map[uint]*model.Job
+}
+
+func newFakeJobRepository() *fakeJobRepository {
+	return &fakeJobRepository{jobs: map[uint]*model.Job{}}
+}
+
+func (r *fakeJobRepository) Enqueue(job *model.Job) (bool, error) {
+	for _, existing := range r.jobs {
+		if existing.Key == job.Key {
+			return false, nil
+		}
+	}
+
+	job.ID = uint(len(r.jobs) + 1)
+	stored := *job
+	r.jobs[job.ID] = &stored
+
+	return true, nil
+}
+
+func (r *fakeJobRepository) GetDue(now time.Time, limit int) ([]model.Job, error) {
+	due := []model.Job{}
+	for _, job := range r.jobs {
+		if !job.RunAt.After(now) && r.claimable(job, now) {
+			due = append(due, *job)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+
+	return due, nil
+}
+
+func (r *fakeJobRepository) Claim(ID uint, owner string, now, lockedUntil time.Time) (bool, error) {
+	job := r.jobs[ID]
+	if job == nil || !r.claimable(job, now) || job.RunAt.After(now) {
+		return false, nil
+	}
+
+	job.Status = model.JobRunning
+	job.LockedBy = owner
+	job.LockedUntil = &lockedUntil
+	job.Attempts++
+
+	return true, nil
+}
+
+func (r *fakeJobRepository) claimable(job *model.Job, now time.Time) bool {
+	return job.Status == model.JobPending || (job.Status == model.JobRunning && job.LockedUntil.Before(now))
+}
+
+func (r *fakeJobRepository) Release(ID uint, owner string, values map[string]interface{}) error {
+	job := r.jobs[ID]
+	if job == nil || job.LockedBy != owner {
+		return nil
+	}
+
+	job.LockedBy = ""
+	job.LockedUntil = nil
+
+	for name, value := range values {
+		switch name {
+		case "status":
+			job.Status = value.(model.JobStatus)
+		case "attempts":
+			job.Attempts = value.(int)
+		case "run_at":
+			job.RunAt = value.(time.Time)
+		case "last_error":
+			job.LastError = value.(string)
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeJobRepository) GetAll(status model.JobStatus, limit, offset int) ([]model.Job, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeJobRepository) Retry(ID uint, now time.Time) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Minute, 2*time.Hour)
+
+	want := []time.Duration{
+		time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute,
+		32 * time.Minute, 64 * time.Minute, 2 * time.Hour, 2 * time.Hour, 2 * time.Hour,
+	}
+
+	for i, wait := range want {
+		if got := backoff(i + 1); got != wait {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, wait)
+		}
+	}
+
+	if got := backoff(1000); got != 2*time.Hour {
+		t.Errorf("backoff(1000) = %s, want the 2h cap", got)
+	}
+}
+
+// Un trabajo que siempre falla se reintenta con la espera de su política y queda fallido al agotar
+// sus intentos
+func TestRunnerGivesUpAfterMaxAttempts(t *testing.T) {
+	repository := newFakeJobRepository()
+	runner := NewRunner(repository, time.Second)
+
+	calls := 0
+	runner.SetRetryPolicy("enviar", RetryPolicy{MaxAttempts: 8, Backoff: ExponentialBackoff(time.Minute, 2*time.Hour)})
+	runner.Handle("enviar", func(payload string) error {
+		calls++
+		return errors.New("receiver responded with status 500")
+	})
+
+	_, err := runner.Enqueue("enviar", "enviar:1", map[string]int{"id": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	job := repository.jobs[1]
+	if job.MaxAttempts != 8 {
+		t.Fatalf("MaxAttempts = %d, want 8", job.MaxAttempts)
+	}
+
+	var waits []time.Duration
+	for i := 0; i < 20 && job.Status != model.JobFailed; i++ {
+		before := time.Now()
+		runner.runDue()
+
+		if job.Status == model.JobPending {
+			waits = append(waits, job.RunAt.Sub(before).Round(time.Minute))
+			// se adelanta el reloj hasta el siguiente intento
+			job.RunAt = time.Now().Add(-time.Second)
+		}
+	}
+
+	if job.Status != model.JobFailed || calls != 8 || job.Attempts != 8 {
+		t.Fatalf("status %s after %d calls (%d attempts), want fallido after 8", job.Status, calls, job.Attempts)
+	}
+
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute, 32 * time.Minute, 64 * time.Minute}
+	if len(waits) != len(want) {
+		t.Fatalf("waits = %v, want %v", waits, want)
+	}
+
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("wait before attempt %d = %s, want %s", i+2, waits[i], want[i])
+		}
+	}
+
+	if job.LastError != "receiver responded with status 500" {
+		t.Errorf("last error = %q", job.LastError)
+	}
+
+	// Ya fallido, no se vuelve a ejecutar
+	runner.runDue()
+	if calls != 8 {
+		t.Errorf("a failed job ran again (%d calls)", calls)
+	}
+}
+
+func TestRunnerDefaultPolicy(t *testing.T) {
+	repository := newFakeJobRepository()
+	runner := NewRunner(repository, time.Second)
+	runner.Handle("recordar", func(payload string) error { return errors.New("smtp down") })
+
+	_, err := runner.Enqueue("recordar", "recordar:1", nil, time.Now())
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	job := repository.jobs[1]
+	if job.MaxAttempts != defaultMaxAttempts {
+		t.Errorf("MaxAttempts = %d, want %d", job.MaxAttempts, defaultMaxAttempts)
+	}
+
+	for attempts, want := range map[int]time.Duration{1: time.Minute, 2: 4 * time.Minute, 3: 9 * time.Minute} {
+		if got := runner.backoff(&model.Job{Name: "recordar", Attempts: attempts}); got != want {
+			t.Errorf("backoff after %d attempts = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestRunnerRecoversPanics(t *testing.T) {
+	repository := newFakeJobRepository()
+	runner := NewRunner(repository, time.Second)
+	runner.Handle("roto", func(payload string) error { panic("nil map") })
+
+	_, err := runner.Enqueue("roto", "roto:1", nil, time.Now())
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	runner.runDue()
+
+	job := repository.jobs[1]
+	if job.Status != model.JobPending || job.LastError != "panic: nil map" {
+		t.Errorf("status %s, last error %q", job.Status, job.LastError)
+	}
+}
+
+func TestEnqueueDeduplicatesByKey(t *testing.T) {
+	runner := NewRunner(newFakeJobRepository(), time.Second)
+
+	first, err := runner.Enqueue("enviar", "enviar:1", nil, time.Now())
+	if err != nil || !first {
+		t.Fatalf("first Enqueue = %t, %v", first, err)
+	}
+
+	second, err := runner.Enqueue("enviar", "enviar:1", nil, time.Now())
+	if err != nil || second {
+		t.Errorf("second Enqueue = %t, %v, want a duplicate", second, err)
+	}
+}
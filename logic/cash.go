@@ -0,0 +1,372 @@
+package logic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+	"github.com/jung-kurt/gofpdf"
+)
+
+type CashSessionLogic interface {
+	GetCashSessionByID(ID uint) (*model.CashSession, error)
+	GetAllCashSessions(limit, offset int) ([]model.CashSession, error)
+	OpenCashSession(request *model.OpenCashSessionRequest, openedBy string) (*model.CashSession, error)
+	CloseCashSession(ID uint, request *model.CloseCashSessionRequest, closedBy string) (*model.CashSessionReport, error)
+	GetCashSessionReport(ID uint) (*model.CashSessionReport, error)
+	GenerateCashSessionReportPDF(ID uint) ([]byte, error)
+}
+
+type cashSessionLogic struct {
+	repositoryCashSessionMain repository.CashSessionRepository
+	repositoryPayment         repository.PaymentRepository
+	logicCurrency             CurrencyLogic
+}
+
+// Cada línea del cierre corresponde a un método de pago en una moneda
+type cashLineKey struct {
+	paymentType model.PaymentType
+	currency    string
+}
+
+func NewCashSessionLogic(
+	repositoryCashSessionMain repository.CashSessionRepository,
+	repositoryPayment repository.PaymentRepository,
+	logicCurrency CurrencyLogic,
+) CashSessionLogic {
+	return &cashSessionLogic{
+		repositoryCashSessionMain: repositoryCashSessionMain,
+		repositoryPayment:         repositoryPayment,
+		logicCurrency:             logicCurrency,
+	}
+}
+
+func (l *cashSessionLogic) GetCashSessionByID(ID uint) (*model.CashSession, error) {
+	session, err := l.repositoryCashSessionMain.GetByID(ID)
+	if err != nil {
+		log.Printf("cash-logic: Error fetching cash session with ID %d: %v", ID, err)
+		return nil, response.ErrorCashSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (l *cashSessionLogic) GetAllCashSessions(limit, offset int) ([]model.CashSession, error) {
+	sessions, err := l.repositoryCashSessionMain.GetAll(limit, offset)
+	if err != nil {
+		log.Printf("cash-logic: Error fetching cash sessions: %v", err)
+		return nil, response.ErrorCashSessionsNotFound
+	}
+
+	return sessions, nil
+}
+
+func (l *cashSessionLogic) OpenCashSession(request *model.OpenCashSessionRequest, openedBy string) (*model.CashSession, error) {
+	_, err := l.repositoryCashSessionMain.GetOpen()
+	if err == nil {
+		return nil, response.ErrorCashSessionAlreadyOpen
+	}
+
+	session := model.CashSession{
+		OpenedBy:     openedBy,
+		OpeningFloat: request.OpeningFloat,
+		Status:       model.CashSessionOpen,
+		OpenedAt:     time.Now(),
+		Notes:        request.Notes,
+	}
+
+	// La consulta anterior no basta con dos aperturas simultáneas; el repositorio las rechaza con un índice único
+	err = l.repositoryCashSessionMain.Open(&session)
+	if err != nil {
+		if errors.Is(err, response.ErrorCashSessionAlreadyOpen) {
+			return nil, err
+		}
+
+		log.Printf("cash-logic: Error opening cash session: %v", err)
+		return nil, response.ErrorToOpenCashSession
+	}
+
+	return &session, nil
+}
+
+func (l *cashSessionLogic) CloseCashSession(ID uint, request *model.CloseCashSessionRequest, closedBy string) (*model.CashSessionReport, error) {
+	session, err := l.GetCashSessionByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status == model.CashSessionClosed {
+		return nil, response.ErrorCashSessionAlreadyClosed
+	}
+
+	closedAt := time.Now()
+	closeDate := validate.FormatDate(closedAt)
+
+	// Lo contado en otra moneda se valoriza con el tipo de cambio vigente al cierre
+	counted := map[cashLineKey]money.Money{}
+	rates := map[string]money.Rate{}
+	for _, count := range request.Counts {
+		if !isValidPaymentType(count.PaymentType) {
+			return nil, response.ErrorInvalidPaymentType
+		}
+
+		key := cashLineKey{paymentType: count.PaymentType, currency: NormalizeCurrency(count.Currency)}
+		if _, exists := counted[key]; exists {
+			return nil, response.ErrorDuplicatedCount
+		}
+
+		if _, exists := rates[key.currency]; !exists {
+			rate, err := l.logicCurrency.GetEffectiveRate(key.currency, closeDate)
+			if err != nil {
+				return nil, err
+			}
+
+			rates[key.currency] = rate
+		}
+
+		counted[key] = count.Counted
+	}
+
+	payments, err := l.repositoryPayment.GetByCashSession(ID)
+	if err != nil {
+		log.Printf("cash-logic: Error fetching payments for cash session ID %d: %v", ID, err)
+		return nil, response.ErrorFetchingCashPayments
+	}
+
+	session.Counts = buildCashSessionLines(session, payments, counted, rates)
+	session.Status = model.CashSessionClosed
+	session.ClosedBy = closedBy
+	session.ClosedAt = &closedAt
+	if request.Notes != "" {
+		session.Notes = request.Notes
+	}
+
+	err = l.repositoryCashSessionMain.Close(session)
+	if err != nil {
+		if errors.Is(err, response.ErrorCashSessionAlreadyClosed) {
+			return nil, err
+		}
+
+		log.Printf("cash-logic: Error closing cash session with ID %d: %v", ID, err)
+		return nil, response.ErrorToCloseCashSession
+	}
+
+	return buildCashSessionReport(session, session.Counts, payments), nil
+}
+
+func (l *cashSessionLogic) GetCashSessionReport(ID uint) (*model.CashSessionReport, error) {
+	session, err := l.GetCashSessionByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := l.repositoryPayment.GetByCashSession(ID)
+	if err != nil {
+		log.Printf("cash-logic: Error fetching payments for cash session ID %d: %v", ID, err)
+		return nil, response.ErrorFetchingCashPayments
+	}
+
+	// Una sesión cerrada ya tiene sus conteos registrados, una abierta se calcula al momento
+	lines := session.Counts
+	if session.Status == model.CashSessionOpen {
+		lines = buildCashSessionLines(session, payments, map[cashLineKey]money.Money{}, map[string]money.Rate{})
+	}
+
+	return buildCashSessionReport(session, lines, payments), nil
+}
+
+func (l *cashSessionLogic) GenerateCashSessionReportPDF(ID uint) ([]byte, error) {
+	report, err := l.GetCashSessionReport(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	pdfBytes, err := GenerateCashSessionPDF(report)
+	if err != nil {
+		log.Printf("cash-logic: Error generating PDF report for cash session ID %d: %v", ID, err)
+		return nil, response.ErrorGeneratingCashReport
+	}
+
+	return pdfBytes, nil
+}
+
+// Calcula lo esperado por método de pago y moneda; el efectivo esperado en moneda base incluye el fondo de apertura.
+// Lo esperado en moneda base es la suma de los montos convertidos al momento de cada pago. Los pagos
+// reembolsados no se esperan: su dinero se devolvió al paciente.
+func buildCashSessionLines(session *model.CashSession, payments []model.Payment, counted map[cashLineKey]money.Money, rates map[string]money.Rate) []model.CashSessionCount {
+	baseCurrency := config.Envs.BaseCurrency
+
+	expected := map[cashLineKey]money.Money{
+		{paymentType: model.Cash, currency: baseCurrency}: session.OpeningFloat,
+	}
+	expectedBase := map[cashLineKey]money.Money{
+		{paymentType: model.Cash, currency: baseCurrency}: session.OpeningFloat,
+	}
+
+	currencies := map[string]bool{baseCurrency: true}
+	keys := map[cashLineKey]bool{}
+
+	for _, payment := range payments {
+		if payment.RefundedAt != nil {
+			continue
+		}
+
+		key := cashLineKey{paymentType: payment.PaymentType, currency: NormalizeCurrency(payment.Currency)}
+		expected[key] += payment.TotalAmount
+		expectedBase[key] += payment.BaseAmount
+		currencies[key.currency] = true
+		keys[key] = true
+	}
+
+	for key := range counted {
+		currencies[key.currency] = true
+		keys[key] = true
+	}
+
+	sortedCurrencies := []string{}
+	for currency := range currencies {
+		if currency != baseCurrency {
+			sortedCurrencies = append(sortedCurrencies, currency)
+		}
+	}
+	sort.Strings(sortedCurrencies)
+	sortedCurrencies = append([]string{baseCurrency}, sortedCurrencies...)
+
+	lines := []model.CashSessionCount{}
+	for _, paymentType := range model.PaymentTypes {
+		for _, currency := range sortedCurrencies {
+			key := cashLineKey{paymentType: paymentType, currency: currency}
+
+			// La moneda base siempre aparece; las demás solo si tuvieron movimiento o conteo
+			if currency != baseCurrency && !keys[key] {
+				continue
+			}
+
+			rate, exists := rates[currency]
+			if !exists {
+				rate = money.OneRate
+			}
+
+			countedBase := counted[key].ToBase(rate)
+
+			lines = append(lines, model.CashSessionCount{
+				CashSessionID:   session.ID,
+				PaymentType:     paymentType,
+				Currency:        currency,
+				Expected:        expected[key],
+				Counted:         counted[key],
+				Discrepancy:     counted[key] - expected[key],
+				ExpectedBase:    expectedBase[key],
+				CountedBase:     countedBase,
+				DiscrepancyBase: countedBase - expectedBase[key],
+			})
+		}
+	}
+
+	return lines
+}
+
+func buildCashSessionReport(session *model.CashSession, lines []model.CashSessionCount, payments []model.Payment) *model.CashSessionReport {
+	report := model.CashSessionReport{
+		BaseCurrency: config.Envs.BaseCurrency,
+		Session:      *session,
+		Lines:        lines,
+		Payments:     payments,
+	}
+
+	for _, line := range lines {
+		report.TotalExpected += line.ExpectedBase
+		report.TotalCounted += line.CountedBase
+		report.TotalDiscrepancy += line.DiscrepancyBase
+	}
+
+	return &report
+}
+
+func GenerateCashSessionPDF(report *model.CashSessionReport) ([]byte, error) {
+	session := report.Session
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, tr("Cierre de Caja"))
+	pdf.Ln(12)
+
+	// Información de la sesión
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(0, 10, tr(fmt.Sprintf("Sesión: %d (%s)", session.ID, session.Status)))
+	pdf.Ln(8)
+	pdf.Cell(0, 10, tr(fmt.Sprintf("Apertura: %s por %s", session.OpenedAt.Format("2006-01-02 15:04"), session.OpenedBy)))
+	pdf.Ln(8)
+	if session.ClosedAt != nil {
+		pdf.Cell(0, 10, tr(fmt.Sprintf("Cierre: %s por %s", session.ClosedAt.Format("2006-01-02 15:04"), session.ClosedBy)))
+		pdf.Ln(8)
+	}
+	pdf.Cell(0, 10, tr(fmt.Sprintf("Fondo de apertura: %s %s", session.OpeningFloat, report.BaseCurrency)))
+	pdf.Ln(12)
+
+	// Esperado vs contado por método de pago
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(40, 8, tr("Método de pago"), "1", 0, "", false, 0, "")
+	pdf.CellFormat(20, 8, "Moneda", "1", 0, "", false, 0, "")
+	pdf.CellFormat(40, 8, "Esperado", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(40, 8, "Contado", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(40, 8, "Diferencia", "1", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 12)
+	for _, line := range report.Lines {
+		pdf.CellFormat(40, 8, tr(string(line.PaymentType)), "1", 0, "", false, 0, "")
+		pdf.CellFormat(20, 8, line.Currency, "1", 0, "", false, 0, "")
+		pdf.CellFormat(40, 8, fmt.Sprint(line.Expected), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(40, 8, fmt.Sprint(line.Counted), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(40, 8, fmt.Sprint(line.Discrepancy), "1", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(40, 8, "Total", "1", 0, "", false, 0, "")
+	pdf.CellFormat(20, 8, report.BaseCurrency, "1", 0, "", false, 0, "")
+	pdf.CellFormat(40, 8, fmt.Sprint(report.TotalExpected), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(40, 8, fmt.Sprint(report.TotalCounted), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(40, 8, fmt.Sprint(report.TotalDiscrepancy), "1", 1, "R", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(0, 10, tr(fmt.Sprintf("Pagos registrados: %d", len(report.Payments))))
+	pdf.Ln(8)
+	if refunded := refundedPayments(report.Payments); refunded > 0 {
+		pdf.Cell(0, 10, tr(fmt.Sprintf("Pagos reembolsados (no esperados en caja): %d", refunded)))
+		pdf.Ln(8)
+	}
+	if session.Notes != "" {
+		pdf.MultiCell(0, 8, tr(fmt.Sprintf("Observaciones: %s", session.Notes)), "", "", false)
+	}
+
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func refundedPayments(payments []model.Payment) int {
+	refunded := 0
+	for _, payment := range payments {
+		if payment.RefundedAt != nil {
+			refunded++
+		}
+	}
+
+	return refunded
+}
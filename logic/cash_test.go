@@ -0,0 +1,105 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+// Caja que otra petición abre o cierra entre la consulta de la lógica y la escritura del repositorio
+type racingCashSessionRepository struct {
+	repository.CashSessionRepository
+}
+
+func (r *racingCashSessionRepository) GetOpen() (*model.CashSession, error) {
+	return nil, response.ErrorCashSessionNotOpen
+}
+
+func (r *racingCashSessionRepository) Open(session *model.CashSession) error {
+	return response.ErrorCashSessionAlreadyOpen
+}
+
+func (r *racingCashSessionRepository) GetByID(ID uint) (*model.CashSession, error) {
+	return &model.CashSession{ID: ID, Status: model.CashSessionOpen}, nil
+}
+
+func (r *racingCashSessionRepository) Close(session *model.CashSession) error {
+	return response.ErrorCashSessionAlreadyClosed
+}
+
+type fakeCashPaymentRepository struct {
+	repository.PaymentRepository
+}
+
+func (r *fakeCashPaymentRepository) GetByCashSession(cashSessionID uint) ([]model.Payment, error) {
+	return nil, nil
+}
+
+// Las carreras que solo detecta el repositorio llegan como conflicto y no como error interno
+func TestCashSessionConcurrentOpenAndClose(t *testing.T) {
+	logic := NewCashSessionLogic(&racingCashSessionRepository{}, &fakeCashPaymentRepository{}, &fakeCurrencyLogic{})
+
+	_, err := logic.OpenCashSession(&model.OpenCashSessionRequest{OpeningFloat: money.FromUnits(100)}, "caja@clinica.pe")
+	if !errors.Is(err, response.ErrorCashSessionAlreadyOpen) {
+		t.Errorf("open: expected %v, got %v", response.ErrorCashSessionAlreadyOpen, err)
+	}
+
+	_, err = logic.CloseCashSession(1, &model.CloseCashSessionRequest{}, "caja@clinica.pe")
+	if !errors.Is(err, response.ErrorCashSessionAlreadyClosed) {
+		t.Errorf("close: expected %v, got %v", response.ErrorCashSessionAlreadyClosed, err)
+	}
+}
+
+// Un pago reembolsado no suma a lo esperado: su dinero ya salió de la caja
+func TestCashSessionLinesExcludeRefunds(t *testing.T) {
+	previous := config.Envs.BaseCurrency
+	config.Envs.BaseCurrency = "PEN"
+	t.Cleanup(func() { config.Envs.BaseCurrency = previous })
+
+	refundedAt := time.Date(2026, time.March, 14, 12, 0, 0, 0, time.UTC)
+	session := &model.CashSession{ID: 1, OpeningFloat: money.FromUnits(100), Status: model.CashSessionOpen}
+	payments := []model.Payment{
+		{ID: 1, PaymentType: model.Cash, Currency: "PEN", TotalAmount: money.FromUnits(80), BaseAmount: money.FromUnits(80)},
+		{ID: 2, PaymentType: model.Cash, Currency: "PEN", TotalAmount: money.FromUnits(50), BaseAmount: money.FromUnits(50), RefundedAt: &refundedAt},
+		{ID: 3, PaymentType: model.Card, Currency: "PEN", TotalAmount: money.FromUnits(120), BaseAmount: money.FromUnits(120), RefundedAt: &refundedAt},
+		{ID: 4, PaymentType: model.Cash, Currency: "USD", TotalAmount: money.FromUnits(20), BaseAmount: money.FromUnits(75), RefundedAt: &refundedAt},
+	}
+
+	counted := map[cashLineKey]money.Money{
+		{paymentType: model.Cash, currency: "PEN"}: money.FromUnits(180),
+	}
+
+	lines := buildCashSessionLines(session, payments, counted, map[string]money.Rate{})
+
+	expected := map[cashLineKey]money.Money{}
+	for _, line := range lines {
+		expected[cashLineKey{paymentType: line.PaymentType, currency: line.Currency}] = line.Expected
+
+		if line.Currency == "USD" {
+			t.Errorf("a refunded USD payment produced a line: %+v", line)
+		}
+	}
+
+	if got := expected[cashLineKey{paymentType: model.Cash, currency: "PEN"}]; got != money.FromUnits(180) {
+		t.Errorf("expected cash = %s, want 180.00 (float + the payment that was not refunded)", got)
+	}
+
+	if got := expected[cashLineKey{paymentType: model.Card, currency: "PEN"}]; got != 0 {
+		t.Errorf("expected card = %s, want 0.00", got)
+	}
+
+	report := buildCashSessionReport(session, lines, payments)
+	if report.TotalExpected != money.FromUnits(180) || report.TotalDiscrepancy != 0 {
+		t.Errorf("report expected %s with discrepancy %s, want 180.00 and 0.00", report.TotalExpected, report.TotalDiscrepancy)
+	}
+
+	if len(report.Payments) != 4 {
+		t.Errorf("report lists %d payments, want all 4", len(report.Payments))
+	}
+}
@@ -0,0 +1,136 @@
+package logic
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+type ServiceCategoryLogic interface {
+	GetCategoryByID(ID uint) (*model.ServiceCategory, error)
+	GetAllCategories(limit, offset int) ([]model.ServiceCategory, error)
+	CreateCategory(category *model.ServiceCategory) error
+	UpdateCategory(ID uint, category *model.ServiceCategory) error
+	DeleteCategory(ID uint) error
+}
+
+type serviceCategoryLogic struct {
+	repositoryCategory     repository.Repository[model.ServiceCategory]
+	repositoryCategoryMain repository.ServiceCategoryRepository
+}
+
+func NewServiceCategoryLogic(repositoryCategory repository.Repository[model.ServiceCategory], repositoryCategoryMain repository.ServiceCategoryRepository) ServiceCategoryLogic {
+	return &serviceCategoryLogic{repositoryCategory: repositoryCategory, repositoryCategoryMain: repositoryCategoryMain}
+}
+
+func (l *serviceCategoryLogic) GetCategoryByID(ID uint) (*model.ServiceCategory, error) {
+	category, err := l.repositoryCategory.GetByID(ID)
+	if err != nil {
+		log.Printf("category-logic: Error fetching service category with ID %d: %v", ID, err)
+		return nil, response.ErrorServiceCategoryNotFound
+	}
+
+	return category, nil
+}
+
+func (l *serviceCategoryLogic) GetAllCategories(limit, offset int) ([]model.ServiceCategory, error) {
+	categories, err := l.repositoryCategory.GetAll(limit, offset)
+	if err != nil {
+		log.Printf("category-logic: Error fetching service categories: %v", err)
+		return nil, response.ErrorServiceCategoriesNotFound
+	}
+
+	return categories, nil
+}
+
+func (l *serviceCategoryLogic) CreateCategory(category *model.ServiceCategory) error {
+	category.Name = strings.TrimSpace(category.Name)
+
+	err := l.checkNameAvailable(category.Name, 0)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryCategory.Create(category)
+	if err != nil {
+		log.Printf("category-logic: Error saving service category: %v", err)
+		return response.ErrorToCreatedServiceCategory
+	}
+
+	return nil
+}
+
+// Al renombrar la categoría se actualizan los servicios, las reglas de precios y las coberturas que la usan
+func (l *serviceCategoryLogic) UpdateCategory(ID uint, category *model.ServiceCategory) error {
+	categoryUpdate, err := l.GetCategoryByID(ID)
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSpace(category.Name)
+
+	err = l.checkNameAvailable(name, ID)
+	if err != nil {
+		return err
+	}
+
+	oldName := categoryUpdate.Name
+	categoryUpdate.Name = name
+	categoryUpdate.Description = category.Description
+
+	err = l.repositoryCategoryMain.Rename(categoryUpdate, oldName)
+	if err != nil {
+		log.Printf("category-logic: Error updating service category with ID %d: %v", ID, err)
+		return response.ErrorToUpdatedServiceCategory
+	}
+
+	return nil
+}
+
+func (l *serviceCategoryLogic) DeleteCategory(ID uint) error {
+	category, err := l.GetCategoryByID(ID)
+	if err != nil {
+		return err
+	}
+
+	count, err := l.repositoryCategoryMain.CountServices(category.Name)
+	if err != nil {
+		log.Printf("category-logic: Error counting services of category with ID %d: %v", ID, err)
+		return response.ErrorToDeletedServiceCategory
+	}
+
+	if count > 0 {
+		return response.ErrorServiceCategoryInUse
+	}
+
+	err = l.repositoryCategory.Delete(ID)
+	if err != nil {
+		log.Printf("category-logic: Error deleting service category with ID %d: %v", ID, err)
+		return response.ErrorToDeletedServiceCategory
+	}
+
+	return nil
+}
+
+// El nombre no puede repetirse en otra categoría
+func (l *serviceCategoryLogic) checkNameAvailable(name string, ID uint) error {
+	existing, err := l.repositoryCategoryMain.GetByName(name)
+	if err != nil {
+		if errors.Is(err, response.ErrorServiceCategoryNotFound) {
+			return nil
+		}
+
+		log.Printf("category-logic: Error fetching service category %s: %v", name, err)
+		return response.ErrorToCreatedServiceCategory
+	}
+
+	if existing.ID != ID {
+		return response.ErrorServiceCategoryExists
+	}
+
+	return nil
+}
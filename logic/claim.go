@@ -0,0 +1,321 @@
+package logic
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+type ClaimLogic interface {
+	GetClaimByID(ID uint) (*model.InsuranceClaim, error)
+	GetAllClaims(providerID uint, status model.ClaimStatus, limit, offset int) ([]model.InsuranceClaim, error)
+	CreateClaim(request *model.CreateClaimRequest, createdBy string) (*model.InsuranceClaim, error)
+	UpdateClaimStatus(ID uint, request *model.ClaimStatusRequest) (*model.InsuranceClaim, error)
+	RegisterClaimPayment(ID uint, request *model.ClaimPaymentRequest) (*model.InsuranceClaim, error)
+	GenerateClaimCSV(ID uint) ([]byte, error)
+	GetClaimBatch(ID uint) (*model.ClaimBatch, error)
+	GetReceivables() ([]model.ClaimReceivable, error)
+}
+
+type claimLogic struct {
+	repositoryClaimMain       repository.ClaimRepository
+	repositoryInsuranceMain   repository.InsuranceRepository
+	repositoryCashSessionMain repository.CashSessionRepository
+	logicCurrency             CurrencyLogic
+}
+
+func NewClaimLogic(
+	repositoryClaimMain repository.ClaimRepository,
+	repositoryInsuranceMain repository.InsuranceRepository,
+	repositoryCashSessionMain repository.CashSessionRepository,
+	logicCurrency CurrencyLogic,
+) ClaimLogic {
+	return &claimLogic{
+		repositoryClaimMain:       repositoryClaimMain,
+		repositoryInsuranceMain:   repositoryInsuranceMain,
+		repositoryCashSessionMain: repositoryCashSessionMain,
+		logicCurrency:             logicCurrency,
+	}
+}
+
+func (l *claimLogic) GetClaimByID(ID uint) (*model.InsuranceClaim, error) {
+	claim, err := l.repositoryClaimMain.GetByID(ID)
+	if err != nil {
+		log.Printf("claim-logic: Error fetching claim with ID %d: %v", ID, err)
+		return nil, response.ErrorClaimNotFound
+	}
+
+	return claim, nil
+}
+
+func (l *claimLogic) GetAllClaims(providerID uint, status model.ClaimStatus, limit, offset int) ([]model.InsuranceClaim, error) {
+	claims, err := l.repositoryClaimMain.GetAll(providerID, status, limit, offset)
+	if err != nil {
+		log.Printf("claim-logic: Error fetching claims: %v", err)
+		return nil, response.ErrorClaimsNotFound
+	}
+
+	return claims, nil
+}
+
+// Agrupa en un reclamo la parte cubierta de las citas completadas del periodo que aún no se reclamaron
+func (l *claimLogic) CreateClaim(request *model.CreateClaimRequest, createdBy string) (*model.InsuranceClaim, error) {
+	_, err := l.repositoryInsuranceMain.GetProviderByID(request.ProviderID)
+	if err != nil {
+		return nil, response.ErrorInsuranceProviderNotFound
+	}
+
+	_, err = validate.ParseDate(request.PeriodFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = validate.ParseDate(request.PeriodTo)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.PeriodFrom > request.PeriodTo {
+		return nil, response.ErrorClaimPeriod
+	}
+
+	claim := model.InsuranceClaim{
+		ProviderID: request.ProviderID,
+		PeriodFrom: request.PeriodFrom,
+		PeriodTo:   request.PeriodTo,
+		Status:     model.ClaimSubmitted,
+		Notes:      request.Notes,
+		CreatedBy:  createdBy,
+	}
+
+	err = l.repositoryClaimMain.CreateClaim(&claim)
+	if err != nil {
+		if errors.Is(err, response.ErrorClaimNoAppointments) {
+			return nil, err
+		}
+
+		log.Printf("claim-logic: Error saving claim for insurance provider with ID %d: %v", request.ProviderID, err)
+		return nil, response.ErrorToCreatedClaim
+	}
+
+	return l.GetClaimByID(claim.ID)
+}
+
+// La aseguradora aprueba (total o parcialmente) o rechaza un reclamo presentado
+func (l *claimLogic) UpdateClaimStatus(ID uint, request *model.ClaimStatusRequest) (*model.InsuranceClaim, error) {
+	claim, err := l.GetClaimByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status != model.ClaimApproved && request.Status != model.ClaimRejected {
+		return nil, response.ErrorInvalidClaimStatus
+	}
+
+	if claim.Status != model.ClaimSubmitted {
+		return nil, response.ErrorClaimNotSubmitted
+	}
+
+	claim.Status = request.Status
+	claim.ApprovedAmount = 0
+	if request.Status == model.ClaimApproved {
+		claim.ApprovedAmount = request.ApprovedAmount
+		if claim.ApprovedAmount == 0 {
+			claim.ApprovedAmount = claim.ClaimedAmount
+		}
+
+		if claim.ApprovedAmount > claim.ClaimedAmount {
+			return nil, response.ErrorClaimApprovedAmount
+		}
+	}
+
+	if request.Notes != "" {
+		claim.Notes = request.Notes
+	}
+
+	err = l.repositoryClaimMain.UpdateStatus(claim)
+	if err != nil {
+		log.Printf("claim-logic: Error updating status of claim with ID %d: %v", ID, err)
+		return nil, response.ErrorToUpdatedClaim
+	}
+
+	return claim, nil
+}
+
+// El pago de la aseguradora se registra en el libro de pagos, convertido a moneda base con el tipo de cambio del día
+func (l *claimLogic) RegisterClaimPayment(ID uint, request *model.ClaimPaymentRequest) (*model.InsuranceClaim, error) {
+	if request.Amount <= 0 {
+		return nil, response.ErrorClaimPaymentAmount
+	}
+
+	if !isValidPaymentType(request.PaymentType) {
+		return nil, response.ErrorInvalidPaymentType
+	}
+
+	payment := model.Payment{
+		Paid:        true,
+		TotalAmount: request.Amount,
+		Currency:    NormalizeCurrency(request.Currency),
+		PaymentType: request.PaymentType,
+	}
+
+	baseAmount, rate, err := l.logicCurrency.ToBase(payment.TotalAmount, payment.Currency, validate.FormatDate(time.Now()))
+	if err != nil {
+		log.Printf("claim-logic: Error converting claim payment to base currency: %v", err)
+		return nil, err
+	}
+
+	payment.ExchangeRate = rate
+	payment.BaseAmount = baseAmount
+
+	// La aseguradora paga fuera del mostrador: solo el efectivo entra en la caja abierta
+	if payment.PaymentType == model.Cash {
+		session, err := l.repositoryCashSessionMain.GetOpen()
+		if err != nil {
+			log.Printf("claim-logic: Error no open cash session for cash payment: %v", err)
+			return nil, response.ErrorCashSessionNotOpen
+		}
+
+		payment.CashSessionID = &session.ID
+	}
+
+	claim, err := l.repositoryClaimMain.RegisterPayment(ID, &payment)
+	if err != nil {
+		if errors.Is(err, response.ErrorClaimNotFound) || errors.Is(err, response.ErrorClaimNotPayable) || errors.Is(err, response.ErrorClaimOverpaid) {
+			return nil, err
+		}
+
+		log.Printf("claim-logic: Error saving payment for claim with ID %d: %v", ID, err)
+		return nil, response.ErrorToSaveClaimPayment
+	}
+
+	return claim, nil
+}
+
+// Exporta el detalle del reclamo en CSV, una fila por cita
+func (l *claimLogic) GenerateClaimCSV(ID uint) ([]byte, error) {
+	claim, err := l.GetClaimByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := bytes.Buffer{}
+	writer := csv.NewWriter(&buffer)
+
+	rows := [][]string{{"reclamo", "aseguradora", "ruc", "periodo_desde", "periodo_hasta", "cita", "fecha", "paciente", "dni", "poliza", "monto", "moneda"}}
+	for _, item := range claim.Items {
+		rows = append(rows, []string{
+			strconv.FormatUint(uint64(claim.ID), 10),
+			claim.Provider.Name,
+			claim.Provider.TaxID,
+			claim.PeriodFrom,
+			claim.PeriodTo,
+			strconv.FormatUint(uint64(item.AppointmentID), 10),
+			item.Date,
+			item.PatientName,
+			item.PatientDNI,
+			item.PolicyNumber,
+			item.Amount.String(),
+			config.Envs.BaseCurrency,
+		})
+	}
+
+	err = writer.WriteAll(rows)
+	if err != nil {
+		log.Printf("claim-logic: Error writing csv for claim with ID %d: %v", ID, err)
+		return nil, response.ErrorGeneratingClaimCSV
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (l *claimLogic) GetClaimBatch(ID uint) (*model.ClaimBatch, error) {
+	claim, err := l.GetClaimByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ClaimBatch{
+		ClaimID:       claim.ID,
+		ProviderName:  claim.Provider.Name,
+		ProviderTaxID: claim.Provider.TaxID,
+		PeriodFrom:    claim.PeriodFrom,
+		PeriodTo:      claim.PeriodTo,
+		Currency:      config.Envs.BaseCurrency,
+		ItemCount:     len(claim.Items),
+		TotalAmount:   claim.ClaimedAmount,
+		Items:         claim.Items,
+	}, nil
+}
+
+// Saldo por cobrar a cada aseguradora: citas aún no reclamadas más lo pendiente de los reclamos abiertos.
+// Un reclamo presentado cuenta por lo reclamado; uno aprobado, por lo aprobado menos lo ya pagado.
+func (l *claimLogic) GetReceivables() ([]model.ClaimReceivable, error) {
+	unclaimed, err := l.repositoryClaimMain.GetUnclaimedByProvider()
+	if err != nil {
+		log.Printf("claim-logic: Error fetching unclaimed insurer amounts: %v", err)
+		return nil, response.ErrorFetchingReceivables
+	}
+
+	claims, err := l.repositoryClaimMain.GetOpen()
+	if err != nil {
+		log.Printf("claim-logic: Error fetching open claims: %v", err)
+		return nil, response.ErrorFetchingReceivables
+	}
+
+	providers, err := l.repositoryInsuranceMain.GetAllProviders(0, 0)
+	if err != nil {
+		log.Printf("claim-logic: Error fetching insurance providers: %v", err)
+		return nil, response.ErrorFetchingReceivables
+	}
+
+	receivables := map[uint]*model.ClaimReceivable{}
+	for providerID, amount := range unclaimed {
+		receivables[providerID] = &model.ClaimReceivable{ProviderID: providerID, Unclaimed: amount, Outstanding: amount}
+	}
+
+	for _, claim := range claims {
+		receivable, exists := receivables[claim.ProviderID]
+		if !exists {
+			receivable = &model.ClaimReceivable{ProviderID: claim.ProviderID}
+			receivables[claim.ProviderID] = receivable
+		}
+
+		var balance money.Money
+		if claim.Status == model.ClaimSubmitted {
+			balance = claim.ClaimedAmount
+		} else {
+			balance = claim.ApprovedAmount - claim.PaidAmount
+		}
+
+		receivable.Claimed += claim.ClaimedAmount
+		receivable.Approved += claim.ApprovedAmount
+		receivable.Paid += claim.PaidAmount
+		receivable.Outstanding += balance
+		receivable.OpenClaims++
+	}
+
+	// Las aseguradoras se listan en orden alfabético
+	result := []model.ClaimReceivable{}
+	for _, provider := range providers {
+		receivable, exists := receivables[provider.ID]
+		if !exists {
+			continue
+		}
+
+		receivable.ProviderName = provider.Name
+		result = append(result, *receivable)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+type fakeClaimRepository struct {
+	repository.ClaimRepository
+	payment *model.Payment
+}
+
+func (r *fakeClaimRepository) RegisterPayment(claimID uint, payment *model.Payment) (*model.InsuranceClaim, error) {
+	r.payment = payment
+	return &model.InsuranceClaim{ID: claimID}, nil
+}
+
+type fakeCashSessionRepository struct {
+	repository.CashSessionRepository
+}
+
+func (r *fakeCashSessionRepository) GetOpen() (*model.CashSession, error) {
+	return &model.CashSession{ID: 3}, nil
+}
+
+type closedCashSessionRepository struct {
+	repository.CashSessionRepository
+}
+
+func (r *closedCashSessionRepository) GetOpen() (*model.CashSession, error) {
+	return nil, errors.New("record not found")
+}
+
+// Todo se cobra en moneda base
+type fakeCurrencyLogic struct {
+	CurrencyLogic
+}
+
+func (l *fakeCurrencyLogic) ToBase(amount money.Money, currency, date string) (money.Money, money.Rate, error) {
+	var rate money.Rate
+	return amount, rate, nil
+}
+
+func (l *fakeCurrencyLogic) ServicesToBase(services []model.Service, date string) ([]model.Service, error) {
+	return services, nil
+}
+
+// Solo el efectivo de la aseguradora entra en la caja; las transferencias no dependen de que haya una abierta
+func TestRegisterClaimPaymentCashSession(t *testing.T) {
+	tests := []struct {
+		name        string
+		paymentType model.PaymentType
+		sessions    repository.CashSessionRepository
+		inSession   bool
+		err         error
+	}{
+		{name: "transferencia con caja abierta", paymentType: model.Transfer, sessions: &fakeCashSessionRepository{}},
+		{name: "transferencia sin caja", paymentType: model.Transfer, sessions: &closedCashSessionRepository{}},
+		{name: "efectivo con caja abierta", paymentType: model.Cash, sessions: &fakeCashSessionRepository{}, inSession: true},
+		{name: "efectivo sin caja", paymentType: model.Cash, sessions: &closedCashSessionRepository{}, err: response.ErrorCashSessionNotOpen},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims := &fakeClaimRepository{}
+			logic := NewClaimLogic(claims, nil, test.sessions, &fakeCurrencyLogic{})
+
+			_, err := logic.RegisterClaimPayment(1, &model.ClaimPaymentRequest{Amount: money.FromUnits(150), PaymentType: test.paymentType})
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("expected error %v, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := claims.payment.CashSessionID
+			if (got != nil) != test.inSession {
+				t.Fatalf("cash session = %v, want set: %t", got, test.inSession)
+			}
+
+			if got != nil && *got != 3 {
+				t.Errorf("cash session = %d, want the open one (3)", *got)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"log"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/pdftemplate"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+type ConfirmationLogic interface {
+	GenerateAppointmentConfirmation(ID uint, lang string) ([]byte, error)
+}
+
+type confirmationLogic struct {
+	repositoryAppointmentMain repository.AppointmentRepository
+	repositoryDoctor          repository.Repository[model.Doctor]
+	renderer                  *pdftemplate.Renderer
+}
+
+func NewConfirmationLogic(
+	repositoryAppointmentMain repository.AppointmentRepository,
+	repositoryDoctor repository.Repository[model.Doctor],
+	renderer *pdftemplate.Renderer,
+) ConfirmationLogic {
+	return &confirmationLogic{
+		repositoryAppointmentMain: repositoryAppointmentMain,
+		repositoryDoctor:          repositoryDoctor,
+		renderer:                  renderer,
+	}
+}
+
+// Confirmación de la cita para el paciente con el médico, el horario y el monto a pagar
+func (l *confirmationLogic) GenerateAppointmentConfirmation(ID uint, lang string) ([]byte, error) {
+	appointment, err := l.repositoryAppointmentMain.GetByID(ID)
+	if err != nil {
+		log.Printf("confirmation: Error fetching appointment with ID %d: %v", ID, err)
+		return nil, response.ErrorAppointmentNotFound
+	}
+
+	doc := appointmentDocument(pdftemplate.KindConfirmation, l.renderer.Language(lang), appointment)
+
+	// La fecha de la cita fija la fecha del PDF, así la misma cita siempre genera el mismo archivo
+	issuedAt, err := validate.ParseDate(appointment.Date)
+	if err == nil {
+		doc.IssuedAt = issuedAt
+	}
+
+	doctor, err := l.repositoryDoctor.GetByID(appointment.DoctorID)
+	if err == nil {
+		doc.Fields["doctor"] = doctor.Name + " " + doctor.LastName
+	}
+
+	doc.Totals = append(doc.Totals, pdftemplate.Field{Key: "total", Value: appointment.PatientAmount.String() + " " + config.Envs.BaseCurrency})
+
+	confirmation, err := l.renderer.Render(doc)
+	if err != nil {
+		log.Printf("confirmation: Error generating PDF confirmation for appointment ID %d: %v", ID, err)
+		return nil, response.ErrorGeneratingConfirmation
+	}
+
+	return confirmation, nil
+}
@@ -0,0 +1,226 @@
+package logic
+
+import (
+	"log"
+	"strings"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+type CouponLogic interface {
+	GetCouponByID(ID uint) (*model.Coupon, error)
+	GetAllCoupons(limit, offset int) ([]model.Coupon, error)
+	CreateCoupon(request *model.CouponRequest) (*model.Coupon, error)
+	UpdateCoupon(ID uint, request *model.CouponRequest) error
+	DeleteCoupon(ID uint) error
+}
+
+type couponLogic struct {
+	repositoryCoupon     repository.Repository[model.Coupon]
+	repositoryCouponMain repository.CouponRepository
+	repositoryService    repository.Repository[model.Service]
+	repositoryPackage    repository.Repository[model.Package]
+}
+
+func NewCouponLogic(
+	repositoryCoupon repository.Repository[model.Coupon],
+	repositoryCouponMain repository.CouponRepository,
+	repositoryService repository.Repository[model.Service],
+	repositoryPackage repository.Repository[model.Package],
+) CouponLogic {
+	return &couponLogic{
+		repositoryCoupon:     repositoryCoupon,
+		repositoryCouponMain: repositoryCouponMain,
+		repositoryService:    repositoryService,
+		repositoryPackage:    repositoryPackage,
+	}
+}
+
+func (l *couponLogic) GetCouponByID(ID uint) (*model.Coupon, error) {
+	coupon, err := l.repositoryCouponMain.GetByID(ID)
+	if err != nil {
+		log.Printf("coupon-logic: Error fetching coupon with ID %d: %v", ID, err)
+		return nil, response.ErrorCouponNotFound
+	}
+
+	return coupon, nil
+}
+
+func (l *couponLogic) GetAllCoupons(limit, offset int) ([]model.Coupon, error) {
+	coupons, err := l.repositoryCouponMain.GetAll(limit, offset)
+	if err != nil {
+		log.Printf("coupon-logic: Error fetching coupons: %v", err)
+		return nil, response.ErrorCouponsNotFound
+	}
+
+	return coupons, nil
+}
+
+func (l *couponLogic) CreateCoupon(request *model.CouponRequest) (*model.Coupon, error) {
+	err := validateCouponRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = l.repositoryCouponMain.GetByCode(request.Code)
+	if err == nil {
+		return nil, response.ErrorCouponCodeExists
+	}
+
+	coupon := model.Coupon{}
+	err = l.fillCoupon(&coupon, request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.repositoryCoupon.Create(&coupon)
+	if err != nil {
+		log.Printf("coupon-logic: Error saving coupon: %v", err)
+		return nil, response.ErrorToCreatedCoupon
+	}
+
+	return &coupon, nil
+}
+
+func (l *couponLogic) UpdateCoupon(ID uint, request *model.CouponRequest) error {
+	coupon, err := l.GetCouponByID(ID)
+	if err != nil {
+		return err
+	}
+
+	err = validateCouponRequest(request)
+	if err != nil {
+		return err
+	}
+
+	existing, err := l.repositoryCouponMain.GetByCode(request.Code)
+	if err == nil && existing.ID != ID {
+		return response.ErrorCouponCodeExists
+	}
+
+	err = l.fillCoupon(coupon, request)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryCouponMain.ClearEligibility(ID)
+	if err != nil {
+		log.Printf("coupon-logic: Error clearing eligibility for coupon ID %d: %v", ID, err)
+		return response.ErrorClearingCouponTarget
+	}
+
+	err = l.repositoryCoupon.Update(coupon)
+	if err != nil {
+		log.Printf("coupon-logic: Error updating coupon with ID %d: %v", ID, err)
+		return response.ErrorToUpdatedCoupon
+	}
+
+	return nil
+}
+
+// Un cupón ya canjeado se conserva para no perder el historial de citas; se debe desactivar
+func (l *couponLogic) DeleteCoupon(ID uint) error {
+	_, err := l.GetCouponByID(ID)
+	if err != nil {
+		return err
+	}
+
+	used, err := l.repositoryCouponMain.CountRedemptions(ID)
+	if err != nil {
+		log.Printf("coupon-logic: Error counting redemptions for coupon ID %d: %v", ID, err)
+		return response.ErrorFetchingCouponUsage
+	}
+
+	if used > 0 {
+		return response.ErrorCouponInUse
+	}
+
+	err = l.repositoryCouponMain.Delete(ID)
+	if err != nil {
+		log.Printf("coupon-logic: Error deleting coupon with ID %d: %v", ID, err)
+		return response.ErrorToDeletedCoupon
+	}
+
+	return nil
+}
+
+func (l *couponLogic) fillCoupon(coupon *model.Coupon, request *model.CouponRequest) error {
+	services := []model.Service{}
+	for _, serviceID := range request.ServiceIDs {
+		service, err := l.repositoryService.GetByID(serviceID)
+		if err != nil {
+			log.Printf("coupon-logic: Error fetching service with ID %d: %v", serviceID, err)
+			return response.ErrorServiceNotFound
+		}
+
+		services = append(services, *service)
+	}
+
+	packages := []model.Package{}
+	for _, packageID := range request.PackageIDs {
+		pkg, err := l.repositoryPackage.GetByID(packageID)
+		if err != nil {
+			log.Printf("coupon-logic: Error fetching package with ID %d: %v", packageID, err)
+			return response.ErrorPackageNotFound
+		}
+
+		packages = append(packages, *pkg)
+	}
+
+	coupon.Code = request.Code
+	coupon.Description = request.Description
+	coupon.Type = request.Type
+	coupon.Percentage = request.Percentage
+	coupon.Amount = request.Amount
+	coupon.ValidFrom = request.ValidFrom
+	coupon.ValidTo = request.ValidTo
+	coupon.MaxUses = request.MaxUses
+	coupon.MaxUsesPerPatient = request.MaxUsesPerPatient
+	coupon.Active = request.Active
+	coupon.Services = services
+	coupon.Packages = packages
+
+	return nil
+}
+
+func validateCouponRequest(request *model.CouponRequest) error {
+	request.Code = strings.ToUpper(strings.TrimSpace(request.Code))
+
+	switch request.Type {
+	case model.CouponPercentage:
+		request.Amount = 0
+		if request.Percentage <= 0 {
+			return response.ErrorCouponValue
+		}
+	case model.CouponFixed:
+		request.Percentage = 0
+		if request.Amount <= 0 {
+			return response.ErrorCouponValue
+		}
+	default:
+		return response.ErrorInvalidCouponType
+	}
+
+	if request.ValidFrom != "" {
+		_, err := validate.ParseDate(request.ValidFrom)
+		if err != nil {
+			return err
+		}
+	}
+
+	if request.ValidTo != "" {
+		_, err := validate.ParseDate(request.ValidTo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if request.ValidFrom != "" && request.ValidTo != "" && request.ValidFrom > request.ValidTo {
+		return response.ErrorCouponValidity
+	}
+
+	return nil
+}
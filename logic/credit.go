@@ -0,0 +1,192 @@
+package logic
+
+import (
+	"log"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/calculation"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+type PackageCreditLogic interface {
+	GetPurchaseByID(ID uint) (*model.PackagePurchase, error)
+	PurchasePackage(request *model.PackagePurchaseRequest) (*model.PackagePurchase, error)
+	GetPatientCredits(patientID uint) ([]model.PackageCredit, error)
+}
+
+type packageCreditLogic struct {
+	repositoryCreditMain      repository.PackageCreditRepository
+	repositoryPkgMain         repository.PackageRepository
+	repositoryPatient         repository.Repository[model.Patient]
+	repositoryPatientMain     repository.PatientRepository
+	repositoryPricing         repository.PricingRuleRepository
+	repositoryCashSessionMain repository.CashSessionRepository
+	logicCurrency             CurrencyLogic
+}
+
+func NewPackageCreditLogic(
+	repositoryCreditMain repository.PackageCreditRepository,
+	repositoryPkgMain repository.PackageRepository,
+	repositoryPatient repository.Repository[model.Patient],
+	repositoryPatientMain repository.PatientRepository,
+	repositoryPricing repository.PricingRuleRepository,
+	repositoryCashSessionMain repository.CashSessionRepository,
+	logicCurrency CurrencyLogic,
+) PackageCreditLogic {
+	return &packageCreditLogic{
+		repositoryCreditMain:      repositoryCreditMain,
+		repositoryPkgMain:         repositoryPkgMain,
+		repositoryPatient:         repositoryPatient,
+		repositoryPatientMain:     repositoryPatientMain,
+		repositoryPricing:         repositoryPricing,
+		repositoryCashSessionMain: repositoryCashSessionMain,
+		logicCurrency:             logicCurrency,
+	}
+}
+
+func (l *packageCreditLogic) GetPurchaseByID(ID uint) (*model.PackagePurchase, error) {
+	purchase, err := l.repositoryCreditMain.GetPurchaseByID(ID)
+	if err != nil {
+		log.Printf("credit-logic: Error fetching package purchase with ID %d: %v", ID, err)
+		return nil, response.ErrorPackagePurchaseNotFound
+	}
+
+	today := validate.FormatDate(time.Now())
+	for i := range purchase.Credits {
+		setCreditBalance(&purchase.Credits[i], today)
+	}
+
+	return purchase, nil
+}
+
+// El paquete se paga al venderse y otorga al paciente un crédito por cada servicio incluido
+func (l *packageCreditLogic) PurchasePackage(request *model.PackagePurchaseRequest) (*model.PackagePurchase, error) {
+	pkg, err := l.repositoryPkgMain.GetByID(request.PackageID)
+	if err != nil {
+		return nil, response.ErrorPackageNotFound
+	}
+
+	if len(pkg.Services) == 0 {
+		return nil, response.ErrorNoServicesProvided
+	}
+
+	now := time.Now()
+	today := validate.FormatDate(now)
+
+	//un paquete retirado o fuera de su periodo de venta no se puede vender
+	if !calculation.IsPackageOnSale(*pkg, today) {
+		return nil, response.ErrorPackageNotOnSale
+	}
+
+	patient, err := l.repositoryPatientMain.GetPatientByDNI(request.PatientDNI)
+	if err != nil {
+		return nil, response.ErrorPatientNotFoundDNI
+	}
+
+	rules, err := l.repositoryPricing.GetActiveRules(today)
+	if err != nil {
+		log.Printf("credit-logic: Error fetching pricing rules: %v", err)
+		return nil, response.ErrorFetchingPricingRules
+	}
+
+	//el precio de venta se calcula en moneda base con las reglas vigentes
+	services, err := l.logicCurrency.ServicesToBase(pkg.Services, today)
+	if err != nil {
+		return nil, err
+	}
+
+	//cada servicio se cobra por todas las sesiones que otorga el paquete
+	sessions := calculation.PackageSessions(*pkg)
+	price := calculation.TotalServicePackageAmount(pkg.ID, services, sessions, calculation.PackageRules(*pkg, rules)).FinalPrice
+
+	if !isValidPaymentType(request.PaymentType) {
+		return nil, response.ErrorInvalidPaymentType
+	}
+
+	payment := model.Payment{
+		Paid:        true,
+		TotalAmount: request.TotalAmount,
+		Currency:    NormalizeCurrency(request.Currency),
+		PaymentType: request.PaymentType,
+	}
+
+	baseAmount, rate, err := l.logicCurrency.ToBase(payment.TotalAmount, payment.Currency, today)
+	if err != nil {
+		log.Printf("credit-logic: Error converting package payment to base currency: %v", err)
+		return nil, err
+	}
+
+	payment.ExchangeRate = rate
+	payment.BaseAmount = baseAmount
+
+	if payment.BaseAmount < price {
+		return nil, response.ErrorTotalAmountBadRequest
+	}
+
+	session, err := l.repositoryCashSessionMain.GetOpen()
+	if err == nil {
+		payment.CashSessionID = &session.ID
+	} else if payment.PaymentType == model.Cash {
+		log.Printf("credit-logic: Error no open cash session for cash payment: %v", err)
+		return nil, response.ErrorCashSessionNotOpen
+	}
+
+	expiresOn := ""
+	if pkg.ValidityDays > 0 {
+		expiresOn = validate.FormatDate(now.AddDate(0, 0, pkg.ValidityDays))
+	}
+
+	purchase := model.PackagePurchase{
+		PackageID:    pkg.ID,
+		PatientID:    patient.ID,
+		PurchaseDate: today,
+		ExpiresOn:    expiresOn,
+		Price:        price,
+	}
+
+	for _, service := range pkg.Services {
+		purchase.Credits = append(purchase.Credits, model.PackageCredit{
+			PatientID: patient.ID,
+			ServiceID: service.ID,
+			Total:     sessions[service.ID],
+			ExpiresOn: expiresOn,
+		})
+	}
+
+	err = l.repositoryCreditMain.CreatePurchase(&purchase, &payment)
+	if err != nil {
+		log.Printf("credit-logic: Error saving purchase of package with ID %d: %v", pkg.ID, err)
+		return nil, response.ErrorToPurchasePackage
+	}
+
+	return l.GetPurchaseByID(purchase.ID)
+}
+
+// Saldo de sesiones del paciente, incluidos los créditos agotados o vencidos
+func (l *packageCreditLogic) GetPatientCredits(patientID uint) ([]model.PackageCredit, error) {
+	_, err := l.repositoryPatient.GetByID(patientID)
+	if err != nil {
+		return nil, response.ErrorPatientNotFoundID
+	}
+
+	credits, err := l.repositoryCreditMain.GetCreditsByPatient(patientID)
+	if err != nil {
+		log.Printf("credit-logic: Error fetching package credits of patient with ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingPackageCredits
+	}
+
+	today := validate.FormatDate(time.Now())
+	for i := range credits {
+		setCreditBalance(&credits[i], today)
+	}
+
+	return credits, nil
+}
+
+func setCreditBalance(credit *model.PackageCredit, today string) {
+	credit.Remaining = credit.Total - credit.Used
+	credit.Expired = credit.ExpiresOn != "" && credit.ExpiresOn < today
+}
@@ -0,0 +1,128 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+// Dobles de los repositorios; las interfaces embebidas hacen fallar cualquier método que la prueba no use
+type fakeCreditRepository struct {
+	repository.PackageCreditRepository
+	purchase *model.PackagePurchase
+	payment  *model.Payment
+}
+
+func (r *fakeCreditRepository) CreatePurchase(purchase *model.PackagePurchase, payment *model.Payment) error {
+	purchase.ID = 1
+	r.purchase = purchase
+	r.payment = payment
+
+	return nil
+}
+
+func (r *fakeCreditRepository) GetPurchaseByID(ID uint) (*model.PackagePurchase, error) {
+	return r.purchase, nil
+}
+
+type fakePackageRepository struct {
+	repository.PackageRepository
+	pkg *model.Package
+}
+
+func (r *fakePackageRepository) GetByID(ID uint) (*model.Package, error) {
+	return r.pkg, nil
+}
+
+type fakePatientRepository struct {
+	repository.PatientRepository
+}
+
+func (r *fakePatientRepository) GetPatientByDNI(DNI string) (*model.Patient, error) {
+	return &model.Patient{Person: model.Person{ID: 7, DNI: DNI}}, nil
+}
+
+type fakePricingRepository struct {
+	rules []model.PricingRule
+}
+
+func (r *fakePricingRepository) GetActiveRules(date string) ([]model.PricingRule, error) {
+	return r.rules, nil
+}
+
+func TestPurchasePackageChargesEverySession(t *testing.T) {
+	discount := 10.0
+	physiotherapy := model.Service{ID: 4, Name: "Fisioterapia", Price: money.FromUnits(80), Active: true}
+
+	pkg := &model.Package{
+		ID:                 2,
+		Name:               "5 sesiones de fisioterapia",
+		Services:           []model.Service{physiotherapy},
+		Sessions:           []model.PackageSession{{ServiceID: physiotherapy.ID, Sessions: 5}},
+		DiscountPercentage: &discount,
+		Active:             true,
+	}
+
+	// 5 x 80.00 = 400.00 menos el 10% del paquete
+	expected := money.FromUnits(360)
+
+	tests := []struct {
+		name   string
+		amount money.Money
+		err    error
+	}{
+		{name: "pago exacto", amount: expected},
+		{name: "pago de una sola sesión", amount: money.FromUnits(72), err: response.ErrorTotalAmountBadRequest},
+		{name: "falta un céntimo", amount: expected - 1, err: response.ErrorTotalAmountBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			credits := &fakeCreditRepository{}
+			logic := NewPackageCreditLogic(
+				credits,
+				&fakePackageRepository{pkg: pkg},
+				nil,
+				&fakePatientRepository{},
+				&fakePricingRepository{},
+				&fakeCashSessionRepository{},
+				&fakeCurrencyLogic{},
+			)
+
+			purchase, err := logic.PurchasePackage(&model.PackagePurchaseRequest{
+				PackageID:   pkg.ID,
+				PatientDNI:  "12345678",
+				TotalAmount: test.amount,
+				PaymentType: model.Card,
+			})
+
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("expected error %v, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if purchase.Price != expected {
+				t.Errorf("charged %s, expected %s", purchase.Price, expected)
+			}
+
+			if len(purchase.Credits) != 1 || purchase.Credits[0].Total != 5 {
+				t.Errorf("expected one credit of 5 sessions, got %+v", purchase.Credits)
+			}
+
+			if credits.payment.BaseAmount != expected {
+				t.Errorf("payment recorded %s, expected %s", credits.payment.BaseAmount, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package logic
+
+import (
+	"log"
+	"strings"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+type CurrencyLogic interface {
+	GetExchangeRateByID(ID uint) (*model.ExchangeRate, error)
+	GetAllExchangeRates(currency string, limit, offset int) ([]model.ExchangeRate, error)
+	CreateExchangeRate(rate *model.ExchangeRate) error
+	DeleteExchangeRate(ID uint) error
+	GetEffectiveRate(currency, date string) (money.Rate, error)
+	ToBase(amount money.Money, currency, date string) (money.Money, money.Rate, error)
+	FromBase(amount money.Money, currency, date string) (money.Money, money.Rate, error)
+	ServicesToBase(services []model.Service, date string) ([]model.Service, error)
+}
+
+type currencyLogic struct {
+	repositoryExchangeRate     repository.Repository[model.ExchangeRate]
+	repositoryExchangeRateMain repository.ExchangeRateRepository
+}
+
+func NewCurrencyLogic(repositoryExchangeRate repository.Repository[model.ExchangeRate], repositoryExchangeRateMain repository.ExchangeRateRepository) CurrencyLogic {
+	return &currencyLogic{repositoryExchangeRate: repositoryExchangeRate, repositoryExchangeRateMain: repositoryExchangeRateMain}
+}
+
+// Normaliza el código de moneda; si está vacío se usa la moneda base
+func NormalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return config.Envs.BaseCurrency
+	}
+
+	return currency
+}
+
+func (l *currencyLogic) GetExchangeRateByID(ID uint) (*model.ExchangeRate, error) {
+	rate, err := l.repositoryExchangeRate.GetByID(ID)
+	if err != nil {
+		log.Printf("currency-logic: Error fetching exchange rate with ID %d: %v", ID, err)
+		return nil, response.ErrorExchangeRateNotFound
+	}
+
+	return rate, nil
+}
+
+func (l *currencyLogic) GetAllExchangeRates(currency string, limit, offset int) ([]model.ExchangeRate, error) {
+	if currency != "" {
+		currency = NormalizeCurrency(currency)
+	}
+
+	rates, err := l.repositoryExchangeRateMain.GetAll(currency, limit, offset)
+	if err != nil {
+		log.Printf("currency-logic: Error fetching exchange rates: %v", err)
+		return nil, response.ErrorExchangeRatesNotFound
+	}
+
+	return rates, nil
+}
+
+func (l *currencyLogic) CreateExchangeRate(rate *model.ExchangeRate) error {
+	rate.Currency = NormalizeCurrency(rate.Currency)
+	if rate.Currency == config.Envs.BaseCurrency {
+		return response.ErrorExchangeRateBaseCurrency
+	}
+
+	if rate.Rate <= 0 {
+		return money.ErrInvalidRate
+	}
+
+	_, err := validate.ParseDate(rate.EffectiveFrom)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryExchangeRate.Create(rate)
+	if err != nil {
+		log.Printf("currency-logic: Error saving exchange rate: %v", err)
+		return response.ErrorToCreatedExchangeRate
+	}
+
+	return nil
+}
+
+func (l *currencyLogic) DeleteExchangeRate(ID uint) error {
+	_, err := l.GetExchangeRateByID(ID)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryExchangeRate.Delete(ID)
+	if err != nil {
+		log.Printf("currency-logic: Error deleting exchange rate with ID %d: %v", ID, err)
+		return response.ErrorToDeletedExchangeRate
+	}
+
+	return nil
+}
+
+func (l *currencyLogic) GetEffectiveRate(currency, date string) (money.Rate, error) {
+	currency = NormalizeCurrency(currency)
+	if currency == config.Envs.BaseCurrency {
+		return money.OneRate, nil
+	}
+
+	rate, err := l.repositoryExchangeRateMain.GetEffective(currency, date)
+	if err != nil {
+		log.Printf("currency-logic: Error fetching exchange rate for %s on %s: %v", currency, date, err)
+		return 0, response.ErrorExchangeRateNotFound
+	}
+
+	return rate.Rate, nil
+}
+
+func (l *currencyLogic) ToBase(amount money.Money, currency, date string) (money.Money, money.Rate, error) {
+	rate, err := l.GetEffectiveRate(currency, date)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return amount.ToBase(rate), rate, nil
+}
+
+func (l *currencyLogic) FromBase(amount money.Money, currency, date string) (money.Money, money.Rate, error) {
+	rate, err := l.GetEffectiveRate(currency, date)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return amount.FromBase(rate), rate, nil
+}
+
+// Devuelve una copia de los servicios con sus precios expresados en moneda base
+func (l *currencyLogic) ServicesToBase(services []model.Service, date string) ([]model.Service, error) {
+	converted := make([]model.Service, 0, len(services))
+
+	for _, service := range services {
+		price, _, err := l.ToBase(service.Price, service.Currency, date)
+		if err != nil {
+			return nil, err
+		}
+
+		service.Price = price
+		service.Currency = config.Envs.BaseCurrency
+		converted = append(converted, service)
+	}
+
+	return converted, nil
+}
@@ -3,7 +3,9 @@ package logic
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
@@ -14,23 +16,36 @@ import (
 type DoctorLogic interface {
 	GetDoctorByID(ID uint) (*model.Doctor, error)
 	GetDoctorByDNI(DNI string) (*model.Doctor, error)
-	GetAllDoctors(limit, offset int) ([]model.Doctor, error)
+	GetAllDoctors(specialtyID uint, limit, offset int) ([]model.Doctor, error)
+	GetAvailability(date string, specialtyID uint) ([]model.DoctorAvailability, error)
 	CreateDoctor(doctor *model.Doctor) error
 	UpdateDoctor(ID uint, doctor *model.Doctor) error
 	DeleteDoctor(ID uint) error
 }
 
 type doctorLogic struct {
-	repositoryDoctor     repository.Repository[model.Doctor]
-	repositoryDoctorMain repository.DoctorRepository
+	repositoryDoctor          repository.Repository[model.Doctor]
+	repositoryDoctorMain      repository.DoctorRepository
+	repositorySpecialtyMain   repository.SpecialtyRepository
+	repositoryAppointmentMain repository.AppointmentRepository
 }
 
-func NewDoctorLogic(repositoryDoctor repository.Repository[model.Doctor], repositoryDoctorMain repository.DoctorRepository) DoctorLogic {
-	return &doctorLogic{repositoryDoctor: repositoryDoctor, repositoryDoctorMain: repositoryDoctorMain}
+func NewDoctorLogic(
+	repositoryDoctor repository.Repository[model.Doctor],
+	repositoryDoctorMain repository.DoctorRepository,
+	repositorySpecialtyMain repository.SpecialtyRepository,
+	repositoryAppointmentMain repository.AppointmentRepository,
+) DoctorLogic {
+	return &doctorLogic{
+		repositoryDoctor:          repositoryDoctor,
+		repositoryDoctorMain:      repositoryDoctorMain,
+		repositorySpecialtyMain:   repositorySpecialtyMain,
+		repositoryAppointmentMain: repositoryAppointmentMain,
+	}
 }
 
 func (l *doctorLogic) GetDoctorByID(ID uint) (*model.Doctor, error) {
-	doctor, err := l.repositoryDoctor.GetByID(ID)
+	doctor, err := l.repositoryDoctorMain.GetByID(ID)
 	if err != nil {
 		log.Printf("doctor-logic: Error fetching doctor with ID %d: %v", ID, err)
 		return nil, response.ErrorDoctorNotFoundID
@@ -49,8 +64,9 @@ func (l *doctorLogic) GetDoctorByDNI(DNI string) (*model.Doctor, error) {
 	return patient, nil
 }
 
-func (l *doctorLogic) GetAllDoctors(limit, offset int) ([]model.Doctor, error) {
-	doctors, err := l.repositoryDoctor.GetAll(limit, offset)
+// Con specialtyID distinto de 0 solo se listan los médicos de esa especialidad
+func (l *doctorLogic) GetAllDoctors(specialtyID uint, limit, offset int) ([]model.Doctor, error) {
+	doctors, err := l.repositoryDoctorMain.GetAll(specialtyID, limit, offset)
 	if err != nil {
 		log.Printf("doctor-logic: Error fetching doctors: %v", err)
 		return nil, response.ErrorDoctorsNotFound
@@ -72,6 +88,11 @@ func (l *doctorLogic) CreateDoctor(doctor *model.Doctor) error {
 
 	doctor.Days = normalizedDays
 
+	specialties, err := l.getSpecialties(doctor.SpecialtyIDs)
+	if err != nil {
+		return err
+	}
+
 	newDoctor := model.Doctor{
 		Person: model.Person{
 			Name:        doctor.Name,
@@ -82,11 +103,11 @@ func (l *doctorLogic) CreateDoctor(doctor *model.Doctor) error {
 			PhoneNumber: doctor.PhoneNumber,
 			Address:     doctor.Address,
 		},
-		Especialty: doctor.Especialty,
-		Days:       normalizedDays,
-		StartTime:  doctor.StartTime,
-		EndTime:    doctor.EndTime,
-		Salary:     doctor.Salary,
+		Specialties: specialties,
+		Days:        normalizedDays,
+		StartTime:   doctor.StartTime,
+		EndTime:     doctor.EndTime,
+		Salary:      doctor.Salary,
 	}
 
 	err = l.repositoryDoctor.Create(&newDoctor)
@@ -117,9 +138,14 @@ func (l *doctorLogic) UpdateDoctor(ID uint, doctor *model.Doctor) error {
 
 	doctor.Days = normalizedDays
 
+	specialties, err := l.getSpecialties(doctor.SpecialtyIDs)
+	if err != nil {
+		return err
+	}
+
 	doctorUpdate.Name = doctor.Name
 	doctorUpdate.LastName = doctor.LastName
-	doctorUpdate.Especialty = doctor.Especialty
+	doctorUpdate.Specialties = specialties
 	doctorUpdate.Salary = doctor.Salary
 	doctorUpdate.StartTime = doctor.StartTime
 	doctorUpdate.EndTime = doctor.EndTime
@@ -128,7 +154,7 @@ func (l *doctorLogic) UpdateDoctor(ID uint, doctor *model.Doctor) error {
 	doctorUpdate.Email = doctor.Email
 	doctorUpdate.Address = doctor.Address
 
-	err = l.repositoryDoctor.Update(doctorUpdate)
+	err = l.repositoryDoctorMain.UpdateWithSpecialties(doctorUpdate)
 	if err != nil {
 		log.Printf("doctor-logic:: Error updating doctor with ID %d: %v", ID, err)
 		return response.ErrorToUpdatedDoctor
@@ -153,6 +179,110 @@ func (l *doctorLogic) DeleteDoctor(ID uint) error {
 	return nil
 }
 
+// Tramos libres de cada médico que trabaja en la fecha indicada (AAAA-MM-DD); con specialtyID
+// distinto de 0 solo se consideran los médicos de esa especialidad
+func (l *doctorLogic) GetAvailability(date string, specialtyID uint) ([]model.DoctorAvailability, error) {
+	availabilityDate, err := validate.ParseDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	doctors, err := l.repositoryDoctorMain.GetAll(specialtyID, 0, 0)
+	if err != nil {
+		log.Printf("doctor-logic: Error fetching doctors: %v", err)
+		return nil, response.ErrorFetchingAvailability
+	}
+
+	weekDay := validate.DayToGolang[availabilityDate.Weekday()]
+	availability := []model.DoctorAvailability{}
+
+	for _, doctor := range doctors {
+		if !validate.IsDayAvailable(weekDay, strings.Split(doctor.Days, ",")) {
+			continue
+		}
+
+		shiftStart, err := validate.ParseTime(doctor.StartTime)
+		if err != nil {
+			continue
+		}
+
+		shiftEnd, err := validate.ParseTime(doctor.EndTime)
+		if err != nil {
+			continue
+		}
+
+		appointments, err := l.repositoryAppointmentMain.GetAppointmentsByDoctorAndDate(doctor.ID, availabilityDate)
+		if err != nil {
+			log.Printf("doctor-logic: Error fetching appointments of doctor with ID %d: %v", doctor.ID, err)
+			return nil, response.ErrorFetchingAvailability
+		}
+
+		availability = append(availability, model.DoctorAvailability{
+			Doctor:    doctor,
+			Date:      validate.FormatDate(availabilityDate),
+			FreeSlots: freeSlots(shiftStart, shiftEnd, appointments),
+		})
+	}
+
+	return availability, nil
+}
+
+// Tramos del turno que no ocupan las citas; las citas con horario inválido se ignoran
+func freeSlots(shiftStart, shiftEnd time.Time, appointments []model.Appointment) []model.TimeSlot {
+	//el formato HH:MM permite ordenar las citas por su hora de inicio como texto
+	sort.Slice(appointments, func(i, j int) bool {
+		return appointments[i].StartTime < appointments[j].StartTime
+	})
+
+	slots := []model.TimeSlot{}
+	cursor := shiftStart
+
+	for _, appointment := range appointments {
+		start, err := validate.ParseTime(appointment.StartTime)
+		if err != nil {
+			continue
+		}
+
+		end, err := validate.ParseTime(appointment.EndTime)
+		if err != nil {
+			continue
+		}
+
+		if start.After(shiftEnd) {
+			start = shiftEnd
+		}
+
+		if start.After(cursor) {
+			slots = append(slots, model.TimeSlot{StartTime: cursor.Format("15:04"), EndTime: start.Format("15:04")})
+		}
+
+		if end.After(cursor) {
+			cursor = end
+		}
+	}
+
+	if shiftEnd.After(cursor) {
+		slots = append(slots, model.TimeSlot{StartTime: cursor.Format("15:04"), EndTime: shiftEnd.Format("15:04")})
+	}
+
+	return slots
+}
+
+// Especialidades indicadas para el médico; todas deben existir en el catálogo
+func (l *doctorLogic) getSpecialties(IDs []uint) ([]model.Specialty, error) {
+	if len(IDs) == 0 {
+		return nil, response.ErrorSpecialtyNotFound
+	}
+
+	specialties, err := l.repositorySpecialtyMain.GetByIDs(IDs)
+	if err != nil {
+		log.Printf("doctor-logic: Error fetching specialties %v: %v", IDs, err)
+		return nil, response.ErrorSpecialtyNotFound
+	}
+
+	return specialties, nil
+}
+
 func normalizeDays(days string) (string, error) {
 	validDays := map[string]string{
 		"lunes":     string(model.Moonday),
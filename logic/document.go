@@ -0,0 +1,328 @@
+package logic
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/storage"
+)
+
+// Vigencia de los enlaces de descarga firmados
+const documentURLExpiration = 10 * time.Minute
+
+// Tipos de archivo aceptados (detectados por contenido) y la extensión con que se guardan
+var documentContentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+type DocumentLogic interface {
+	GetDocumentByID(ID uint) (*model.Document, error)
+	GetPatientDocuments(patientID uint) ([]model.Document, error)
+	GetAppointmentDocuments(appointmentID uint) ([]model.Document, error)
+	UploadPatientDocument(patientID uint, category, fileName string, file io.Reader, user model.User) (*model.Document, error)
+	UploadAppointmentDocument(appointmentID uint, category, fileName string, file io.Reader, user model.User) (*model.Document, error)
+	GetDownloadURL(ID uint) (*model.DocumentDownloadURL, error)
+	OpenDocument(ID uint, expires, signature string) (*model.Document, io.ReadCloser, error)
+	DeleteDocument(ID uint) error
+}
+
+type documentLogic struct {
+	repositoryDocument        repository.Repository[model.Document]
+	repositoryDocumentMain    repository.DocumentRepository
+	repositoryPatient         repository.Repository[model.Patient]
+	repositoryAppointmentMain repository.AppointmentRepository
+	fileStore                 storage.FileStore
+}
+
+func NewDocumentLogic(
+	repositoryDocument repository.Repository[model.Document],
+	repositoryDocumentMain repository.DocumentRepository,
+	repositoryPatient repository.Repository[model.Patient],
+	repositoryAppointmentMain repository.AppointmentRepository,
+	fileStore storage.FileStore,
+) DocumentLogic {
+	return &documentLogic{
+		repositoryDocument:        repositoryDocument,
+		repositoryDocumentMain:    repositoryDocumentMain,
+		repositoryPatient:         repositoryPatient,
+		repositoryAppointmentMain: repositoryAppointmentMain,
+		fileStore:                 fileStore,
+	}
+}
+
+func (l *documentLogic) GetDocumentByID(ID uint) (*model.Document, error) {
+	document, err := l.repositoryDocument.GetByID(ID)
+	if err != nil {
+		log.Printf("document-logic: Error fetching document with ID %d: %v", ID, err)
+		return nil, response.ErrorDocumentNotFound
+	}
+
+	return document, nil
+}
+
+func (l *documentLogic) GetPatientDocuments(patientID uint) ([]model.Document, error) {
+	_, err := l.repositoryPatient.GetByID(patientID)
+	if err != nil {
+		log.Printf("document-logic: Error fetching patient with ID %d: %v", patientID, err)
+		return nil, response.ErrorPatientNotFoundID
+	}
+
+	documents, err := l.repositoryDocumentMain.GetByPatient(patientID)
+	if err != nil {
+		log.Printf("document-logic: Error fetching documents of patient ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingDocuments
+	}
+
+	return documents, nil
+}
+
+func (l *documentLogic) GetAppointmentDocuments(appointmentID uint) ([]model.Document, error) {
+	_, err := l.repositoryAppointmentMain.GetByID(appointmentID)
+	if err != nil {
+		log.Printf("document-logic: Error fetching appointment with ID %d: %v", appointmentID, err)
+		return nil, response.ErrorAppointmentNotFound
+	}
+
+	documents, err := l.repositoryDocumentMain.GetByAppointment(appointmentID)
+	if err != nil {
+		log.Printf("document-logic: Error fetching documents of appointment ID %d: %v", appointmentID, err)
+		return nil, response.ErrorFetchingDocuments
+	}
+
+	return documents, nil
+}
+
+func (l *documentLogic) UploadPatientDocument(patientID uint, category, fileName string, file io.Reader, user model.User) (*model.Document, error) {
+	_, err := l.repositoryPatient.GetByID(patientID)
+	if err != nil {
+		log.Printf("document-logic: Error fetching patient with ID %d: %v", patientID, err)
+		return nil, response.ErrorPatientNotFoundID
+	}
+
+	return l.upload(patientID, nil, category, fileName, file, user)
+}
+
+// El documento de una cita también queda en el expediente de su paciente
+func (l *documentLogic) UploadAppointmentDocument(appointmentID uint, category, fileName string, file io.Reader, user model.User) (*model.Document, error) {
+	appointment, err := l.repositoryAppointmentMain.GetByID(appointmentID)
+	if err != nil {
+		log.Printf("document-logic: Error fetching appointment with ID %d: %v", appointmentID, err)
+		return nil, response.ErrorAppointmentNotFound
+	}
+
+	if appointment.PatientID == 0 {
+		return nil, response.ErrorPatientNotFoundID
+	}
+
+	return l.upload(appointment.PatientID, &appointmentID, category, fileName, file, user)
+}
+
+// Valida tamaño y tipo real del archivo, guarda el contenido y luego registra el documento con su SHA-256
+func (l *documentLogic) upload(patientID uint, appointmentID *uint, category, fileName string, file io.Reader, user model.User) (*model.Document, error) {
+	documentCategory, err := parseDocumentCategory(category)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, config.Envs.MaxUploadBytes+1))
+	if err != nil {
+		log.Printf("document-logic: Error reading uploaded file %q: %v", fileName, err)
+		return nil, response.ErrorToUploadDocument
+	}
+
+	if int64(len(data)) > config.Envs.MaxUploadBytes {
+		return nil, response.ErrorDocumentTooLarge
+	}
+
+	if len(data) == 0 {
+		return nil, response.ErrorDocumentEmpty
+	}
+
+	contentType := strings.Split(http.DetectContentType(data), ";")[0]
+
+	extension, ok := documentContentTypes[contentType]
+	if !ok {
+		return nil, response.ErrorDocumentContentType
+	}
+
+	code, err := newRandomCode()
+	if err != nil {
+		log.Printf("document-logic: Error generating storage key: %v", err)
+		return nil, response.ErrorToUploadDocument
+	}
+
+	checksum := sha256.Sum256(data)
+
+	document := model.Document{
+		PatientID:     patientID,
+		AppointmentID: appointmentID,
+		Category:      documentCategory,
+		FileName:      cleanFileName(fileName, extension),
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+		Checksum:      hex.EncodeToString(checksum[:]),
+		StorageKey:    fmt.Sprintf("patients/%d/%s%s", patientID, code, extension),
+		UploadedBy:    user.Email,
+	}
+
+	err = l.fileStore.Put(document.StorageKey, data, contentType)
+	if err != nil {
+		log.Printf("document-logic: Error storing file for patient ID %d: %v", patientID, err)
+		return nil, response.ErrorToUploadDocument
+	}
+
+	err = l.repositoryDocument.Create(&document)
+	if err != nil {
+		log.Printf("document-logic: Error saving document for patient ID %d: %v", patientID, err)
+
+		err = l.fileStore.Delete(document.StorageKey)
+		if err != nil {
+			log.Printf("document-logic: Error removing orphan file %s: %v", document.StorageKey, err)
+		}
+
+		return nil, response.ErrorToUploadDocument
+	}
+
+	return &document, nil
+}
+
+// Enlace que permite descargar el archivo sin token durante documentURLExpiration
+func (l *documentLogic) GetDownloadURL(ID uint) (*model.DocumentDownloadURL, error) {
+	document, err := l.GetDocumentByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(documentURLExpiration).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", signDocumentURL(document.ID, expires))
+
+	return &model.DocumentDownloadURL{
+		URL:       fmt.Sprintf("%s/api/v1/documents/%d/download?%s", strings.TrimRight(config.Envs.PublicHost, "/"), document.ID, query.Encode()),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Comprueba la firma y la vigencia del enlace antes de abrir el archivo y que su contenido coincida con
+// el checksum registrado al subirlo; quien lo abre debe cerrarlo
+func (l *documentLogic) OpenDocument(ID uint, expires, signature string) (*model.Document, io.ReadCloser, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, nil, response.ErrorDocumentLinkInvalid
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(signDocumentURL(ID, expires))) {
+		return nil, nil, response.ErrorDocumentLinkInvalid
+	}
+
+	document, err := l.GetDocumentByID(ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := l.fileStore.Get(document.StorageKey)
+	if err != nil {
+		log.Printf("document-logic: Error reading file %s of document ID %d: %v", document.StorageKey, ID, err)
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, nil, response.ErrorDocumentNotFound
+		}
+
+		return nil, nil, response.ErrorToReadDocument
+	}
+	defer file.Close()
+
+	// Se lee completo para comprobar que el archivo guardado es el que se subió antes de enviarlo
+	data, err := io.ReadAll(io.LimitReader(file, document.Size+1))
+	if err != nil {
+		log.Printf("document-logic: Error reading file %s of document ID %d: %v", document.StorageKey, ID, err)
+		return nil, nil, response.ErrorToReadDocument
+	}
+
+	checksum := sha256.Sum256(data)
+	if hex.EncodeToString(checksum[:]) != document.Checksum {
+		log.Printf("document-logic: Error checksum mismatch for file %s of document ID %d", document.StorageKey, ID)
+		return nil, nil, response.ErrorDocumentChecksum
+	}
+
+	return document, io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (l *documentLogic) DeleteDocument(ID uint) error {
+	document, err := l.GetDocumentByID(ID)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryDocument.Delete(ID)
+	if err != nil {
+		log.Printf("document-logic: Error deleting document with ID %d: %v", ID, err)
+		return response.ErrorToDeletedDocument
+	}
+
+	// El registro ya no existe; un archivo que no se pudo borrar solo queda huérfano en el almacenamiento
+	err = l.fileStore.Delete(document.StorageKey)
+	if err != nil {
+		log.Printf("document-logic: Error removing file %s of document ID %d: %v", document.StorageKey, ID, err)
+	}
+
+	return nil
+}
+
+func parseDocumentCategory(category string) (model.DocumentCategory, error) {
+	if category == "" {
+		return model.DocumentOther, nil
+	}
+
+	for _, documentCategory := range model.DocumentCategories {
+		if model.DocumentCategory(strings.ToLower(category)) == documentCategory {
+			return documentCategory, nil
+		}
+	}
+
+	return "", response.ErrorInvalidDocumentCategory
+}
+
+// Conserva solo el nombre base del archivo subido y le asigna la extensión de su tipo real si no la tiene
+func cleanFileName(fileName, extension string) string {
+	name := filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		name = "documento"
+	}
+
+	if runes := []rune(name); len(runes) > 200 {
+		name = string(runes[:200])
+	}
+
+	if filepath.Ext(name) == "" {
+		name += extension
+	}
+
+	return name
+}
+
+func signDocumentURL(ID uint, expires string) string {
+	mac := hmac.New(sha256.New, []byte(config.Envs.DocumentSecret))
+	fmt.Fprintf(mac, "document:%d:%s", ID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,81 @@
+package logic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/storage"
+)
+
+type fakeDocumentRepository struct {
+	repository.Repository[model.Document]
+	document *model.Document
+}
+
+func (r *fakeDocumentRepository) GetByID(ID uint) (*model.Document, error) {
+	return r.document, nil
+}
+
+func TestOpenDocumentVerifiesChecksum(t *testing.T) {
+	content := []byte("%PDF-1.4 resultado de laboratorio")
+	checksum := sha256.Sum256(content)
+
+	tests := []struct {
+		name   string
+		stored []byte
+		err    error
+	}{
+		{name: "archivo íntegro", stored: content},
+		{name: "archivo modificado", stored: []byte("%PDF-1.4 resultado de laboratorio alterado"), err: response.ErrorDocumentChecksum},
+		{name: "archivo truncado", stored: content[:10], err: response.ErrorDocumentChecksum},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := &model.Document{
+				ID:          5,
+				ContentType: "application/pdf",
+				Size:        int64(len(content)),
+				Checksum:    hex.EncodeToString(checksum[:]),
+				StorageKey:  "patients/1/abc.pdf",
+			}
+
+			fileStore := storage.NewLocalStore(t.TempDir())
+			err := fileStore.Put(document.StorageKey, test.stored, document.ContentType)
+			if err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			logic := NewDocumentLogic(&fakeDocumentRepository{document: document}, nil, nil, nil, fileStore)
+
+			expires := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+			_, file, err := logic.OpenDocument(document.ID, expires, signDocumentURL(document.ID, expires))
+
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("expected error %v, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer file.Close()
+
+			got, err := io.ReadAll(file)
+			if err != nil || string(got) != string(content) {
+				t.Errorf("read %q, %v, want %q", got, err, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package logic
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+// Plazo tras el cual el registro clínico queda bloqueado aunque no se haya firmado
+const encounterLockAfter = 24 * time.Hour
+
+type EncounterLogic interface {
+	GetEncounterByID(ID uint, user model.User) (*model.Encounter, error)
+	GetEncounterByAppointment(appointmentID uint, user model.User) (*model.Encounter, error)
+	CreateEncounter(appointmentID uint, request *model.EncounterRequest, user model.User) (*model.Encounter, error)
+	UpdateEncounter(ID uint, request *model.EncounterRequest, user model.User) (*model.Encounter, error)
+	SignEncounter(ID uint, user model.User) (*model.Encounter, error)
+}
+
+type encounterLogic struct {
+	repositoryEncounterMain   repository.EncounterRepository
+	repositoryAppointmentMain repository.AppointmentRepository
+}
+
+func NewEncounterLogic(repositoryEncounterMain repository.EncounterRepository, repositoryAppointmentMain repository.AppointmentRepository) EncounterLogic {
+	return &encounterLogic{repositoryEncounterMain: repositoryEncounterMain, repositoryAppointmentMain: repositoryAppointmentMain}
+}
+
+// Solo el médico que atendió la cita y los administradores pueden ver el registro clínico
+func (l *encounterLogic) GetEncounterByID(ID uint, user model.User) (*model.Encounter, error) {
+	encounter, err := l.repositoryEncounterMain.GetByID(ID)
+	if err != nil {
+		log.Printf("encounter-logic: Error fetching encounter with ID %d: %v", ID, err)
+		return nil, response.ErrorEncounterNotFound
+	}
+
+	if user.Role != model.RoleAdmin && !isTreatingDoctor(user, encounter.DoctorID) {
+		return nil, response.ErrorEncounterForbidden
+	}
+
+	encounter.Locked = isEncounterLocked(encounter, time.Now())
+
+	return encounter, nil
+}
+
+func (l *encounterLogic) GetEncounterByAppointment(appointmentID uint, user model.User) (*model.Encounter, error) {
+	encounter, err := l.repositoryEncounterMain.GetByAppointmentID(appointmentID)
+	if err != nil {
+		log.Printf("encounter-logic: Error fetching encounter of appointment ID %d: %v", appointmentID, err)
+		return nil, response.ErrorEncounterNotFound
+	}
+
+	if user.Role != model.RoleAdmin && !isTreatingDoctor(user, encounter.DoctorID) {
+		return nil, response.ErrorEncounterForbidden
+	}
+
+	encounter.Locked = isEncounterLocked(encounter, time.Now())
+
+	return encounter, nil
+}
+
+// El registro lo escribe el médico que atendió la cita una vez completada; hay uno por cita
+func (l *encounterLogic) CreateEncounter(appointmentID uint, request *model.EncounterRequest, user model.User) (*model.Encounter, error) {
+	appointment, err := l.repositoryAppointmentMain.GetByID(appointmentID)
+	if err != nil {
+		log.Printf("encounter-logic: Error fetching appointment with ID %d: %v", appointmentID, err)
+		return nil, response.ErrorAppointmentNotFound
+	}
+
+	if !isTreatingDoctor(user, appointment.DoctorID) {
+		return nil, response.ErrorEncounterForbidden
+	}
+
+	if appointment.Status != model.AppointmentCompleted {
+		return nil, response.ErrorEncounterNotCompleted
+	}
+
+	_, err = l.repositoryEncounterMain.GetByAppointmentID(appointmentID)
+	if err == nil {
+		return nil, response.ErrorEncounterExists
+	}
+
+	if !errors.Is(err, response.ErrorEncounterNotFound) {
+		log.Printf("encounter-logic: Error fetching encounter of appointment ID %d: %v", appointmentID, err)
+		return nil, response.ErrorToCreatedEncounter
+	}
+
+	encounter := model.Encounter{
+		AppointmentID:  appointmentID,
+		DoctorID:       appointment.DoctorID,
+		Reason:         strings.TrimSpace(request.Reason),
+		Findings:       request.Findings,
+		DiagnosisCodes: normalizeDiagnosisCodes(request.DiagnosisCodes),
+		Plan:           request.Plan,
+		Notes:          request.Notes,
+		AuthoredBy:     user.Email,
+	}
+
+	err = l.repositoryEncounterMain.Create(&encounter)
+	if err != nil {
+		log.Printf("encounter-logic: Error saving encounter for appointment ID %d: %v", appointmentID, err)
+		return nil, response.ErrorToCreatedEncounter
+	}
+
+	return &encounter, nil
+}
+
+// Cada edición guarda el contenido anterior como revisión; un registro bloqueado ya no se edita
+func (l *encounterLogic) UpdateEncounter(ID uint, request *model.EncounterRequest, user model.User) (*model.Encounter, error) {
+	encounter, err := l.getEditableEncounter(ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := model.EncounterRevision{
+		EncounterID:    encounter.ID,
+		Reason:         encounter.Reason,
+		Findings:       encounter.Findings,
+		DiagnosisCodes: encounter.DiagnosisCodes,
+		Plan:           encounter.Plan,
+		Notes:          encounter.Notes,
+		EditedBy:       user.Email,
+		EditedAt:       time.Now(),
+	}
+
+	encounter.Reason = strings.TrimSpace(request.Reason)
+	encounter.Findings = request.Findings
+	encounter.DiagnosisCodes = normalizeDiagnosisCodes(request.DiagnosisCodes)
+	encounter.Plan = request.Plan
+	encounter.Notes = request.Notes
+
+	err = l.repositoryEncounterMain.UpdateWithRevision(encounter, &revision)
+	if err != nil {
+		log.Printf("encounter-logic: Error updating encounter with ID %d: %v", ID, err)
+		return nil, response.ErrorToUpdatedEncounter
+	}
+
+	encounter.Revisions = append(encounter.Revisions, revision)
+
+	return encounter, nil
+}
+
+// Al firmarse el registro queda bloqueado
+func (l *encounterLogic) SignEncounter(ID uint, user model.User) (*model.Encounter, error) {
+	encounter, err := l.getEditableEncounter(ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	encounter.SignedAt = &now
+
+	err = l.repositoryEncounterMain.Sign(encounter)
+	if err != nil {
+		log.Printf("encounter-logic: Error signing encounter with ID %d: %v", ID, err)
+		return nil, response.ErrorToSignEncounter
+	}
+
+	encounter.Locked = true
+
+	return encounter, nil
+}
+
+// Solo el médico que atendió la cita edita o firma el registro mientras no esté bloqueado
+func (l *encounterLogic) getEditableEncounter(ID uint, user model.User) (*model.Encounter, error) {
+	encounter, err := l.repositoryEncounterMain.GetByID(ID)
+	if err != nil {
+		log.Printf("encounter-logic: Error fetching encounter with ID %d: %v", ID, err)
+		return nil, response.ErrorEncounterNotFound
+	}
+
+	if !isTreatingDoctor(user, encounter.DoctorID) {
+		return nil, response.ErrorEncounterForbidden
+	}
+
+	if isEncounterLocked(encounter, time.Now()) {
+		return nil, response.ErrorEncounterLocked
+	}
+
+	return encounter, nil
+}
+
+func isTreatingDoctor(user model.User, doctorID uint) bool {
+	return user.Role == model.RoleDoctor && user.DoctorID != nil && *user.DoctorID == doctorID
+}
+
+func isEncounterLocked(encounter *model.Encounter, now time.Time) bool {
+	return encounter.SignedAt != nil || now.Sub(encounter.CreatedAt) >= encounterLockAfter
+}
+
+// Los códigos de diagnóstico se guardan en mayúsculas y sin repetir
+func normalizeDiagnosisCodes(codes []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+
+	return normalized
+}
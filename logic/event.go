@@ -0,0 +1,89 @@
+package logic
+
+import (
+	"errors"
+	"io"
+	"log"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/events"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+// Avisos al paciente que salen de los eventos del outbox
+func SubscribeNotifications(
+	dispatcher *events.Dispatcher,
+	logicNotification NotificationLogic,
+	logicPayment PaymentLogic,
+	repositoryAppointmentMain repository.AppointmentRepository,
+	repositoryPayment repository.PaymentRepository,
+) {
+	dispatcher.Subscribe(model.EventAppointmentBooked, "aviso_cita_reservada", notifyAppointmentEvent(logicNotification, model.NotificationAppointmentBooked))
+	dispatcher.Subscribe(model.EventAppointmentCancelled, "aviso_cita_cancelada", notifyAppointmentEvent(logicNotification, model.NotificationAppointmentCancelled))
+	dispatcher.Subscribe(model.EventAppointmentRescheduled, "aviso_cita_reprogramada", notifyAppointmentEvent(logicNotification, model.NotificationAppointmentRescheduled))
+
+	dispatcher.Subscribe(model.EventPaymentRegistered, "aviso_pago_recibido", func(event *model.OutboxEvent) error {
+		var paymentEvent model.PaymentEvent
+
+		err := event.Decode(&paymentEvent)
+		if err != nil {
+			return err
+		}
+
+		payment, err := repositoryPayment.GetByID(paymentEvent.PaymentID)
+		if err != nil {
+			return err
+		}
+
+		appointment, err := repositoryAppointmentMain.GetByID(paymentEvent.AppointmentID)
+		if err != nil {
+			if errors.Is(err, response.ErrorAppointmentNotFound) {
+				log.Printf("events: Appointment ID %d no longer exists, payment notice discarded", paymentEvent.AppointmentID)
+				return nil
+			}
+
+			return err
+		}
+
+		return logicNotification.NotifyPaymentReceived(appointment, payment, paymentReceipt(logicPayment, payment.ID))
+	})
+}
+
+// El aviso de la cita usa los datos guardados en el evento, así llega aunque la cita ya se haya eliminado;
+// en la reprogramación el evento trae también el horario anterior
+func notifyAppointmentEvent(logicNotification NotificationLogic, notificationEvent model.NotificationEvent) events.Subscriber {
+	return func(event *model.OutboxEvent) error {
+		var appointmentEvent model.AppointmentEvent
+
+		err := event.Decode(&appointmentEvent)
+		if err != nil {
+			return err
+		}
+
+		// Citas cuyo paciente se eliminó
+		if appointmentEvent.PatientID == 0 {
+			return nil
+		}
+
+		return logicNotification.NotifyAppointment(notificationEvent, appointmentEvent.Appointment(), appointmentEvent.Previous())
+	}
+}
+
+// Recibo PDF para adjuntar al aviso; si no se puede generar el aviso sale sin adjunto
+func paymentReceipt(logicPayment PaymentLogic, paymentID uint) []byte {
+	file, err := logicPayment.GetPaymentReceipt(paymentID, "")
+	if err != nil {
+		log.Printf("events: Error fetching receipt of payment ID %d for notice: %v", paymentID, err)
+		return nil
+	}
+	defer file.Close()
+
+	receipt, err := io.ReadAll(file)
+	if err != nil {
+		log.Printf("events: Error reading receipt of payment ID %d for notice: %v", paymentID, err)
+		return nil
+	}
+
+	return receipt
+}
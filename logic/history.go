@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"log"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+// Registra el precio vigente de un servicio o paquete solo si cambió respecto del último registrado
+func recordPrice(repositoryHistory repository.PriceHistoryRepository, entry model.PriceHistory) error {
+	latest, err := repositoryHistory.GetLatest(entry.ItemType, entry.ItemID)
+	if err != nil {
+		log.Printf("history-logic: Error fetching price history of %s with ID %d: %v", entry.ItemType, entry.ItemID, err)
+		return response.ErrorRecordingPriceHistory
+	}
+
+	if latest != nil && latest.Price == entry.Price && latest.Currency == entry.Currency {
+		return nil
+	}
+
+	entry.EffectiveFrom = time.Now()
+
+	err = repositoryHistory.Create(&entry)
+	if err != nil {
+		log.Printf("history-logic: Error saving price history of %s with ID %d: %v", entry.ItemType, entry.ItemID, err)
+		return response.ErrorRecordingPriceHistory
+	}
+
+	return nil
+}
+
+// Devuelve la evolución del precio; cada precio es vigente hasta el inicio del siguiente
+func getPriceHistory(repositoryHistory repository.PriceHistoryRepository, itemType model.CatalogItemType, itemID uint) ([]model.PriceHistory, error) {
+	entries, err := repositoryHistory.GetByItem(itemType, itemID)
+	if err != nil {
+		log.Printf("history-logic: Error fetching price history of %s with ID %d: %v", itemType, itemID, err)
+		return nil, response.ErrorFetchingPriceHistory
+	}
+
+	for i := 0; i < len(entries)-1; i++ {
+		effectiveTo := entries[i+1].EffectiveFrom
+		entries[i].EffectiveTo = &effectiveTo
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,370 @@
+package logic
+
+import (
+	"log"
+	"strings"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/calculation"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+type InsuranceLogic interface {
+	GetProviderByID(ID uint) (*model.InsuranceProvider, error)
+	GetAllProviders(limit, offset int) ([]model.InsuranceProvider, error)
+	CreateProvider(provider *model.InsuranceProvider) error
+	UpdateProvider(ID uint, provider *model.InsuranceProvider) error
+	DeleteProvider(ID uint) error
+	GetPlanByID(ID uint) (*model.InsurancePlan, error)
+	GetAllPlans(providerID uint, limit, offset int) ([]model.InsurancePlan, error)
+	CreatePlan(plan *model.InsurancePlan) error
+	UpdatePlan(ID uint, plan *model.InsurancePlan) error
+	DeletePlan(ID uint) error
+	GetPolicyByID(ID uint) (*model.PatientPolicy, error)
+	GetPatientPolicies(patientID uint) ([]model.PatientPolicy, error)
+	CreatePolicy(policy *model.PatientPolicy) error
+	UpdatePolicy(ID uint, policy *model.PatientPolicy) error
+	DeletePolicy(ID uint) error
+	GetValidPolicy(patientID uint, date string) (*model.PatientPolicy, error)
+}
+
+type insuranceLogic struct {
+	repositoryProvider      repository.Repository[model.InsuranceProvider]
+	repositoryPlan          repository.Repository[model.InsurancePlan]
+	repositoryPolicy        repository.Repository[model.PatientPolicy]
+	repositoryInsuranceMain repository.InsuranceRepository
+	repositoryPatient       repository.Repository[model.Patient]
+}
+
+func NewInsuranceLogic(
+	repositoryProvider repository.Repository[model.InsuranceProvider],
+	repositoryPlan repository.Repository[model.InsurancePlan],
+	repositoryPolicy repository.Repository[model.PatientPolicy],
+	repositoryInsuranceMain repository.InsuranceRepository,
+	repositoryPatient repository.Repository[model.Patient],
+) InsuranceLogic {
+	return &insuranceLogic{
+		repositoryProvider:      repositoryProvider,
+		repositoryPlan:          repositoryPlan,
+		repositoryPolicy:        repositoryPolicy,
+		repositoryInsuranceMain: repositoryInsuranceMain,
+		repositoryPatient:       repositoryPatient,
+	}
+}
+
+func (l *insuranceLogic) GetProviderByID(ID uint) (*model.InsuranceProvider, error) {
+	provider, err := l.repositoryInsuranceMain.GetProviderByID(ID)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching insurance provider with ID %d: %v", ID, err)
+		return nil, response.ErrorInsuranceProviderNotFound
+	}
+
+	return provider, nil
+}
+
+func (l *insuranceLogic) GetAllProviders(limit, offset int) ([]model.InsuranceProvider, error) {
+	providers, err := l.repositoryInsuranceMain.GetAllProviders(limit, offset)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching insurance providers: %v", err)
+		return nil, response.ErrorInsuranceProvidersNotFound
+	}
+
+	return providers, nil
+}
+
+func (l *insuranceLogic) CreateProvider(provider *model.InsuranceProvider) error {
+	provider.Plans = nil
+
+	err := l.repositoryProvider.Create(provider)
+	if err != nil {
+		log.Printf("insurance-logic: Error saving insurance provider: %v", err)
+		return response.ErrorToCreatedInsuranceProvider
+	}
+
+	return nil
+}
+
+func (l *insuranceLogic) UpdateProvider(ID uint, provider *model.InsuranceProvider) error {
+	providerUpdate, err := l.repositoryProvider.GetByID(ID)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching insurance provider with ID %d: %v to update", ID, err)
+		return response.ErrorInsuranceProviderNotFound
+	}
+
+	providerUpdate.Name = provider.Name
+	providerUpdate.TaxID = provider.TaxID
+	providerUpdate.Phone = provider.Phone
+	providerUpdate.Email = provider.Email
+	providerUpdate.Active = provider.Active
+
+	err = l.repositoryProvider.Update(providerUpdate)
+	if err != nil {
+		log.Printf("insurance-logic: Error updating insurance provider with ID %d: %v", ID, err)
+		return response.ErrorToUpdatedInsuranceProvider
+	}
+
+	return nil
+}
+
+func (l *insuranceLogic) DeleteProvider(ID uint) error {
+	_, err := l.GetProviderByID(ID)
+	if err != nil {
+		return err
+	}
+
+	plans, err := l.repositoryInsuranceMain.CountProviderPlans(ID)
+	if err != nil || plans > 0 {
+		return response.ErrorInsuranceProviderHasPlans
+	}
+
+	err = l.repositoryProvider.Delete(ID)
+	if err != nil {
+		log.Printf("insurance-logic: Error deleting insurance provider with ID %d: %v", ID, err)
+		return response.ErrorToDeletedInsuranceProvider
+	}
+
+	return nil
+}
+
+func (l *insuranceLogic) GetPlanByID(ID uint) (*model.InsurancePlan, error) {
+	plan, err := l.repositoryInsuranceMain.GetPlanByID(ID)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching insurance plan with ID %d: %v", ID, err)
+		return nil, response.ErrorInsurancePlanNotFound
+	}
+
+	return plan, nil
+}
+
+func (l *insuranceLogic) GetAllPlans(providerID uint, limit, offset int) ([]model.InsurancePlan, error) {
+	plans, err := l.repositoryInsuranceMain.GetAllPlans(providerID, limit, offset)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching insurance plans: %v", err)
+		return nil, response.ErrorInsurancePlansNotFound
+	}
+
+	return plans, nil
+}
+
+func (l *insuranceLogic) CreatePlan(plan *model.InsurancePlan) error {
+	err := l.validatePlan(plan)
+	if err != nil {
+		return err
+	}
+
+	plan.Provider = nil
+
+	err = l.repositoryPlan.Create(plan)
+	if err != nil {
+		log.Printf("insurance-logic: Error saving insurance plan: %v", err)
+		return response.ErrorToCreatedInsurancePlan
+	}
+
+	return nil
+}
+
+func (l *insuranceLogic) UpdatePlan(ID uint, plan *model.InsurancePlan) error {
+	planUpdate, err := l.GetPlanByID(ID)
+	if err != nil {
+		return err
+	}
+
+	err = l.validatePlan(plan)
+	if err != nil {
+		return err
+	}
+
+	planUpdate.ProviderID = plan.ProviderID
+	planUpdate.Name = plan.Name
+	planUpdate.DefaultCoverage = plan.DefaultCoverage
+	planUpdate.DefaultCopay = plan.DefaultCopay
+	planUpdate.Active = plan.Active
+	planUpdate.Coverages = plan.Coverages
+
+	err = l.repositoryInsuranceMain.UpdatePlan(planUpdate)
+	if err != nil {
+		log.Printf("insurance-logic: Error updating insurance plan with ID %d: %v", ID, err)
+		return response.ErrorToUpdatedInsurancePlan
+	}
+
+	return nil
+}
+
+func (l *insuranceLogic) DeletePlan(ID uint) error {
+	_, err := l.GetPlanByID(ID)
+	if err != nil {
+		return err
+	}
+
+	policies, err := l.repositoryInsuranceMain.CountPlanPolicies(ID)
+	if err != nil || policies > 0 {
+		return response.ErrorInsurancePlanHasPolicies
+	}
+
+	err = l.repositoryPlan.Delete(ID)
+	if err != nil {
+		log.Printf("insurance-logic: Error deleting insurance plan with ID %d: %v", ID, err)
+		return response.ErrorToDeletedInsurancePlan
+	}
+
+	return nil
+}
+
+func (l *insuranceLogic) GetPolicyByID(ID uint) (*model.PatientPolicy, error) {
+	policy, err := l.repositoryInsuranceMain.GetPolicyByID(ID)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching patient policy with ID %d: %v", ID, err)
+		return nil, response.ErrorPatientPolicyNotFound
+	}
+
+	return policy, nil
+}
+
+func (l *insuranceLogic) GetPatientPolicies(patientID uint) ([]model.PatientPolicy, error) {
+	_, err := l.repositoryPatient.GetByID(patientID)
+	if err != nil {
+		return nil, response.ErrorPatientNotFoundID
+	}
+
+	policies, err := l.repositoryInsuranceMain.GetPoliciesByPatient(patientID)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching policies for patient ID %d: %v", patientID, err)
+		return nil, response.ErrorPatientPoliciesNotFound
+	}
+
+	return policies, nil
+}
+
+func (l *insuranceLogic) CreatePolicy(policy *model.PatientPolicy) error {
+	err := l.validatePolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	policy.Plan = nil
+
+	err = l.repositoryPolicy.Create(policy)
+	if err != nil {
+		log.Printf("insurance-logic: Error saving patient policy: %v", err)
+		return response.ErrorToCreatedPatientPolicy
+	}
+
+	return nil
+}
+
+func (l *insuranceLogic) UpdatePolicy(ID uint, policy *model.PatientPolicy) error {
+	policyUpdate, err := l.repositoryPolicy.GetByID(ID)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching patient policy with ID %d: %v to update", ID, err)
+		return response.ErrorPatientPolicyNotFound
+	}
+
+	err = l.validatePolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	policyUpdate.PatientID = policy.PatientID
+	policyUpdate.PlanID = policy.PlanID
+	policyUpdate.PolicyNumber = policy.PolicyNumber
+	policyUpdate.ValidFrom = policy.ValidFrom
+	policyUpdate.ValidTo = policy.ValidTo
+	policyUpdate.Active = policy.Active
+
+	err = l.repositoryPolicy.Update(policyUpdate)
+	if err != nil {
+		log.Printf("insurance-logic: Error updating patient policy with ID %d: %v", ID, err)
+		return response.ErrorToUpdatedPatientPolicy
+	}
+
+	return nil
+}
+
+func (l *insuranceLogic) DeletePolicy(ID uint) error {
+	_, err := l.repositoryPolicy.GetByID(ID)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching patient policy with ID %d: %v to deleting", ID, err)
+		return response.ErrorPatientPolicyNotFound
+	}
+
+	err = l.repositoryPolicy.Delete(ID)
+	if err != nil {
+		log.Printf("insurance-logic: Error deleting patient policy with ID %d: %v", ID, err)
+		return response.ErrorToDeletedPatientPolicy
+	}
+
+	return nil
+}
+
+// Póliza vigente del paciente en la fecha indicada (AAAA-MM-DD); nil si el paciente no tiene seguro vigente.
+// Con varias pólizas vigentes se usa la de inicio de vigencia más reciente.
+func (l *insuranceLogic) GetValidPolicy(patientID uint, date string) (*model.PatientPolicy, error) {
+	if patientID == 0 {
+		return nil, nil
+	}
+
+	policies, err := l.repositoryInsuranceMain.GetPoliciesByPatient(patientID)
+	if err != nil {
+		log.Printf("insurance-logic: Error fetching policies for patient ID %d: %v", patientID, err)
+		return nil, response.ErrorPatientPoliciesNotFound
+	}
+
+	for i := range policies {
+		if calculation.IsPolicyValidOn(policies[i], date) {
+			return &policies[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (l *insuranceLogic) validatePlan(plan *model.InsurancePlan) error {
+	_, err := l.repositoryProvider.GetByID(plan.ProviderID)
+	if err != nil {
+		return response.ErrorInsuranceProviderNotFound
+	}
+
+	categories := map[string]bool{}
+	for _, coverage := range plan.Coverages {
+		category := strings.ToLower(coverage.ServiceCategory)
+		if categories[category] {
+			return response.ErrorDuplicatedCoverage
+		}
+
+		categories[category] = true
+	}
+
+	return nil
+}
+
+func (l *insuranceLogic) validatePolicy(policy *model.PatientPolicy) error {
+	_, err := l.repositoryPatient.GetByID(policy.PatientID)
+	if err != nil {
+		return response.ErrorPatientNotFoundID
+	}
+
+	_, err = l.repositoryPlan.GetByID(policy.PlanID)
+	if err != nil {
+		return response.ErrorInsurancePlanNotFound
+	}
+
+	_, err = validate.ParseDate(policy.ValidFrom)
+	if err != nil {
+		return err
+	}
+
+	if policy.ValidTo != "" {
+		_, err = validate.ParseDate(policy.ValidTo)
+		if err != nil {
+			return err
+		}
+
+		if policy.ValidFrom > policy.ValidTo {
+			return response.ErrorPatientPolicyValidity
+		}
+	}
+
+	return nil
+}
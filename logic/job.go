@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"log"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+type JobLogic interface {
+	GetJobByID(ID uint) (*model.Job, error)
+	GetJobs(status model.JobStatus, limit, offset int) ([]model.Job, error)
+	RetryJob(ID uint) (*model.Job, error)
+}
+
+type jobLogic struct {
+	repositoryJob     repository.Repository[model.Job]
+	repositoryJobMain repository.JobRepository
+}
+
+func NewJobLogic(repositoryJob repository.Repository[model.Job], repositoryJobMain repository.JobRepository) JobLogic {
+	return &jobLogic{
+		repositoryJob:     repositoryJob,
+		repositoryJobMain: repositoryJobMain,
+	}
+}
+
+func (l *jobLogic) GetJobByID(ID uint) (*model.Job, error) {
+	job, err := l.repositoryJob.GetByID(ID)
+	if err != nil {
+		log.Printf("job-logic: Error fetching job with ID %d: %v", ID, err)
+		return nil, response.ErrorJobNotFound
+	}
+
+	return job, nil
+}
+
+// Trabajos más recientes primero; status vacío devuelve todos
+func (l *jobLogic) GetJobs(status model.JobStatus, limit, offset int) ([]model.Job, error) {
+	switch status {
+	case "", model.JobPending, model.JobRunning, model.JobCompleted, model.JobFailed:
+	default:
+		return nil, response.ErrorInvalidJobStatus
+	}
+
+	jobs, err := l.repositoryJobMain.GetAll(status, limit, offset)
+	if err != nil {
+		log.Printf("job-logic: Error fetching jobs: %v", err)
+		return nil, response.ErrorFetchingJobs
+	}
+
+	return jobs, nil
+}
+
+// Vuelve a encolar un trabajo que agotó sus intentos, p. ej. después de corregir la configuración del SMTP
+func (l *jobLogic) RetryJob(ID uint) (*model.Job, error) {
+	_, err := l.GetJobByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	retried, err := l.repositoryJobMain.Retry(ID, time.Now())
+	if err != nil {
+		log.Printf("job-logic: Error retrying job with ID %d: %v", ID, err)
+		return nil, response.ErrorToRetryJob
+	}
+
+	if !retried {
+		return nil, response.ErrorJobNotFailed
+	}
+
+	return l.GetJobByID(ID)
+}
@@ -0,0 +1,309 @@
+package logic
+
+import (
+	"log"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+// Registros del historial médico que se administran bajo /patients/:id
+type PatientRecordLogic[T any] interface {
+	GetRecords(patientID uint) ([]T, error)
+	CreateRecord(patientID uint, record *T) error
+	UpdateRecord(patientID, ID uint, record *T) error
+	DeleteRecord(patientID, ID uint) error
+}
+
+type patientRecord[T any] interface {
+	*T
+	model.PatientRecord
+}
+
+type patientRecordLogic[T any, PT patientRecord[T]] struct {
+	repositoryRecord     repository.Repository[T]
+	repositoryRecordMain repository.PatientRecordRepository[T]
+	repositoryPatient    repository.Repository[model.Patient]
+	check                func(record *T) error
+}
+
+func NewAllergyLogic(
+	repositoryRecord repository.Repository[model.PatientAllergy],
+	repositoryRecordMain repository.PatientRecordRepository[model.PatientAllergy],
+	repositoryPatient repository.Repository[model.Patient],
+) PatientRecordLogic[model.PatientAllergy] {
+	return newPatientRecordLogic(repositoryRecord, repositoryRecordMain, repositoryPatient, checkAllergy)
+}
+
+func NewChronicConditionLogic(
+	repositoryRecord repository.Repository[model.ChronicCondition],
+	repositoryRecordMain repository.PatientRecordRepository[model.ChronicCondition],
+	repositoryPatient repository.Repository[model.Patient],
+) PatientRecordLogic[model.ChronicCondition] {
+	return newPatientRecordLogic(repositoryRecord, repositoryRecordMain, repositoryPatient, checkChronicCondition)
+}
+
+func NewMedicationLogic(
+	repositoryRecord repository.Repository[model.PatientMedication],
+	repositoryRecordMain repository.PatientRecordRepository[model.PatientMedication],
+	repositoryPatient repository.Repository[model.Patient],
+) PatientRecordLogic[model.PatientMedication] {
+	return newPatientRecordLogic(repositoryRecord, repositoryRecordMain, repositoryPatient, checkMedication)
+}
+
+func NewFamilyHistoryLogic(
+	repositoryRecord repository.Repository[model.FamilyHistory],
+	repositoryRecordMain repository.PatientRecordRepository[model.FamilyHistory],
+	repositoryPatient repository.Repository[model.Patient],
+) PatientRecordLogic[model.FamilyHistory] {
+	return newPatientRecordLogic(repositoryRecord, repositoryRecordMain, repositoryPatient, func(*model.FamilyHistory) error { return nil })
+}
+
+func newPatientRecordLogic[T any, PT patientRecord[T]](
+	repositoryRecord repository.Repository[T],
+	repositoryRecordMain repository.PatientRecordRepository[T],
+	repositoryPatient repository.Repository[model.Patient],
+	check func(record *T) error,
+) PatientRecordLogic[T] {
+	return &patientRecordLogic[T, PT]{
+		repositoryRecord:     repositoryRecord,
+		repositoryRecordMain: repositoryRecordMain,
+		repositoryPatient:    repositoryPatient,
+		check:                check,
+	}
+}
+
+func (l *patientRecordLogic[T, PT]) GetRecords(patientID uint) ([]T, error) {
+	err := l.checkPatient(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := l.repositoryRecordMain.GetByPatient(patientID)
+	if err != nil {
+		log.Printf("medical-logic: Error fetching records of patient ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingPatientRecords
+	}
+
+	return records, nil
+}
+
+func (l *patientRecordLogic[T, PT]) CreateRecord(patientID uint, record *T) error {
+	err := l.checkPatient(patientID)
+	if err != nil {
+		return err
+	}
+
+	PT(record).SetID(0)
+	PT(record).SetPatientID(patientID)
+
+	err = l.check(record)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryRecord.Create(record)
+	if err != nil {
+		log.Printf("medical-logic: Error saving record for patient ID %d: %v", patientID, err)
+		return response.ErrorToCreatedPatientRecord
+	}
+
+	return nil
+}
+
+func (l *patientRecordLogic[T, PT]) UpdateRecord(patientID, ID uint, record *T) error {
+	_, err := l.getRecord(patientID, ID)
+	if err != nil {
+		return err
+	}
+
+	PT(record).SetID(ID)
+	PT(record).SetPatientID(patientID)
+
+	err = l.check(record)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryRecord.Update(record)
+	if err != nil {
+		log.Printf("medical-logic: Error updating record with ID %d: %v", ID, err)
+		return response.ErrorToUpdatedPatientRecord
+	}
+
+	return nil
+}
+
+func (l *patientRecordLogic[T, PT]) DeleteRecord(patientID, ID uint) error {
+	_, err := l.getRecord(patientID, ID)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryRecord.Delete(ID)
+	if err != nil {
+		log.Printf("medical-logic: Error deleting record with ID %d: %v", ID, err)
+		return response.ErrorToDeletedPatientRecord
+	}
+
+	return nil
+}
+
+func (l *patientRecordLogic[T, PT]) checkPatient(patientID uint) error {
+	_, err := l.repositoryPatient.GetByID(patientID)
+	if err != nil {
+		log.Printf("medical-logic: Error fetching patient with ID %d: %v", patientID, err)
+		return response.ErrorPatientNotFoundID
+	}
+
+	return nil
+}
+
+// Un registro de otro paciente se trata como inexistente
+func (l *patientRecordLogic[T, PT]) getRecord(patientID, ID uint) (*T, error) {
+	record, err := l.repositoryRecord.GetByID(ID)
+	if err != nil || PT(record).GetPatientID() != patientID {
+		log.Printf("medical-logic: Error fetching record with ID %d of patient ID %d: %v", ID, patientID, err)
+		return nil, response.ErrorPatientRecordNotFound
+	}
+
+	return record, nil
+}
+
+func checkAllergy(allergy *model.PatientAllergy) error {
+	for _, severity := range model.AllergySeverities {
+		if allergy.Severity == severity {
+			return nil
+		}
+	}
+
+	return response.ErrorInvalidAllergySeverity
+}
+
+func checkChronicCondition(condition *model.ChronicCondition) error {
+	return checkRecordDates(condition.DiagnosedOn, "")
+}
+
+func checkMedication(medication *model.PatientMedication) error {
+	return checkRecordDates(medication.StartDate, medication.EndDate)
+}
+
+// Las fechas son opcionales (AAAA-MM-DD); si se indican ambas, la de inicio no puede ser posterior a la de fin
+func checkRecordDates(from, to string) error {
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+
+		_, err := validate.ParseDate(date)
+		if err != nil {
+			return err
+		}
+	}
+
+	if from != "" && to != "" && from > to {
+		return response.ErrorInvalidRecordDates
+	}
+
+	return nil
+}
+
+type MedicalHistoryLogic interface {
+	GetMedicalSummary(patientID uint) (*model.MedicalSummary, error)
+	GetAllergyWarnings(patientDNI string) ([]model.PatientAllergy, error)
+}
+
+type medicalHistoryLogic struct {
+	repositoryPatient        repository.Repository[model.Patient]
+	repositoryPatientMain    repository.PatientRepository
+	repositoryAllergyMain    repository.PatientRecordRepository[model.PatientAllergy]
+	repositoryConditionMain  repository.PatientRecordRepository[model.ChronicCondition]
+	repositoryMedicationMain repository.PatientRecordRepository[model.PatientMedication]
+	repositoryFamilyMain     repository.PatientRecordRepository[model.FamilyHistory]
+}
+
+func NewMedicalHistoryLogic(
+	repositoryPatient repository.Repository[model.Patient],
+	repositoryPatientMain repository.PatientRepository,
+	repositoryAllergyMain repository.PatientRecordRepository[model.PatientAllergy],
+	repositoryConditionMain repository.PatientRecordRepository[model.ChronicCondition],
+	repositoryMedicationMain repository.PatientRecordRepository[model.PatientMedication],
+	repositoryFamilyMain repository.PatientRecordRepository[model.FamilyHistory],
+) MedicalHistoryLogic {
+	return &medicalHistoryLogic{
+		repositoryPatient:        repositoryPatient,
+		repositoryPatientMain:    repositoryPatientMain,
+		repositoryAllergyMain:    repositoryAllergyMain,
+		repositoryConditionMain:  repositoryConditionMain,
+		repositoryMedicationMain: repositoryMedicationMain,
+		repositoryFamilyMain:     repositoryFamilyMain,
+	}
+}
+
+func (l *medicalHistoryLogic) GetMedicalSummary(patientID uint) (*model.MedicalSummary, error) {
+	patient, err := l.repositoryPatient.GetByID(patientID)
+	if err != nil {
+		log.Printf("medical-logic: Error fetching patient with ID %d: %v", patientID, err)
+		return nil, response.ErrorPatientNotFoundID
+	}
+
+	allergies, err := l.repositoryAllergyMain.GetByPatient(patientID)
+	if err != nil {
+		log.Printf("medical-logic: Error fetching allergies of patient ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingPatientRecords
+	}
+
+	conditions, err := l.repositoryConditionMain.GetByPatient(patientID)
+	if err != nil {
+		log.Printf("medical-logic: Error fetching chronic conditions of patient ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingPatientRecords
+	}
+
+	medications, err := l.repositoryMedicationMain.GetByPatient(patientID)
+	if err != nil {
+		log.Printf("medical-logic: Error fetching medications of patient ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingPatientRecords
+	}
+
+	family, err := l.repositoryFamilyMain.GetByPatient(patientID)
+	if err != nil {
+		log.Printf("medical-logic: Error fetching family history of patient ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingPatientRecords
+	}
+
+	today := validate.FormatDate(time.Now())
+
+	current := []model.PatientMedication{}
+	for _, medication := range medications {
+		if medication.IsCurrentOn(today) {
+			current = append(current, medication)
+		}
+	}
+
+	return &model.MedicalSummary{
+		Patient:           patient,
+		Allergies:         allergies,
+		ChronicConditions: conditions,
+		Medications:       current,
+		FamilyHistory:     family,
+	}, nil
+}
+
+// Alergias que se advierten al reservar una cita al paciente
+func (l *medicalHistoryLogic) GetAllergyWarnings(patientDNI string) ([]model.PatientAllergy, error) {
+	patient, err := l.repositoryPatientMain.GetPatientByDNI(patientDNI)
+	if err != nil {
+		return nil, response.ErrorPatientNotFoundDNI
+	}
+
+	allergies, err := l.repositoryAllergyMain.GetByPatient(patient.ID)
+	if err != nil {
+		log.Printf("medical-logic: Error fetching allergies of patient ID %d: %v", patient.ID, err)
+		return nil, response.ErrorFetchingPatientRecords
+	}
+
+	return allergies, nil
+}
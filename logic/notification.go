@@ -0,0 +1,252 @@
+package logic
+
+import (
+	"fmt"
+	"log"
+	"unicode/utf8"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/notification"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+type NotificationLogic interface {
+	NotifyAppointment(event model.NotificationEvent, appointment *model.Appointment, previous *model.Appointment) error
+	NotifyPaymentReceived(appointment *model.Appointment, payment *model.Payment, receipt []byte) error
+	RemindAppointment(appointment *model.Appointment, hoursBefore int) error
+	GetPreference(patientID uint) (*model.NotificationPreference, error)
+	UpdatePreference(patientID uint, request *model.NotificationPreferenceRequest) (*model.NotificationPreference, error)
+	GetDeliveries(patientID uint, limit, offset int) ([]model.NotificationDelivery, error)
+}
+
+type notificationLogic struct {
+	repositoryNotificationMain repository.NotificationRepository
+	repositoryPatient          repository.Repository[model.Patient]
+	repositoryDoctor           repository.Repository[model.Doctor]
+	emailChannel               notification.Channel
+	smsChannel                 notification.Channel
+}
+
+func NewNotificationLogic(
+	repositoryNotificationMain repository.NotificationRepository,
+	repositoryPatient repository.Repository[model.Patient],
+	repositoryDoctor repository.Repository[model.Doctor],
+	emailChannel notification.Channel,
+	smsChannel notification.Channel,
+) NotificationLogic {
+	return &notificationLogic{
+		repositoryNotificationMain: repositoryNotificationMain,
+		repositoryPatient:          repositoryPatient,
+		repositoryDoctor:           repositoryDoctor,
+		emailChannel:               emailChannel,
+		smsChannel:                 smsChannel,
+	}
+}
+
+// Avisa al paciente de la reserva, reprogramación o cancelación de su cita; previous es la cita
+// antes de reprogramarla. Los avisos se envían en el momento y devuelven el primer error de envío,
+// para que el suscriptor o trabajo que los envía pueda reintentarlos.
+func (l *notificationLogic) NotifyAppointment(event model.NotificationEvent, appointment *model.Appointment, previous *model.Appointment) error {
+	data := l.appointmentData(appointment)
+
+	if previous != nil {
+		data.PreviousDate = previous.Date
+		data.PreviousStartTime = previous.StartTime
+	}
+
+	if event == model.NotificationAppointmentBooked && appointment.PatientAmount > 0 {
+		data.Amount = fmt.Sprintf("%s %s", appointment.PatientAmount, config.Envs.BaseCurrency)
+	}
+
+	return l.send(event, appointment.PatientID, appointment.ID, nil, data, nil)
+}
+
+// Avisa del pago registrado; por correo se adjunta el recibo
+func (l *notificationLogic) NotifyPaymentReceived(appointment *model.Appointment, payment *model.Payment, receipt []byte) error {
+	data := l.appointmentData(appointment)
+	data.InvoiceNumber = payment.InvoiceNumber()
+	data.Amount = fmt.Sprintf("%s %s", payment.TotalAmount, payment.Currency)
+
+	var attachments []notification.Attachment
+	if receipt != nil {
+		attachments = append(attachments, notification.Attachment{
+			FileName:    fmt.Sprintf("recibo_%d.pdf", payment.ID),
+			ContentType: "application/pdf",
+			Data:        receipt,
+		})
+	}
+
+	return l.send(model.NotificationPaymentReceived, appointment.PatientID, appointment.ID, &payment.ID, data, attachments)
+}
+
+// Recordatorio previo a la cita
+func (l *notificationLogic) RemindAppointment(appointment *model.Appointment, hoursBefore int) error {
+	data := l.appointmentData(appointment)
+	data.HoursBefore = hoursBefore
+
+	return l.send(model.NotificationAppointmentReminder, appointment.PatientID, appointment.ID, nil, data, nil)
+}
+
+func (l *notificationLogic) GetPreference(patientID uint) (*model.NotificationPreference, error) {
+	_, err := l.repositoryPatient.GetByID(patientID)
+	if err != nil {
+		log.Printf("notification-logic: Error fetching patient with ID %d: %v", patientID, err)
+		return nil, response.ErrorPatientNotFoundID
+	}
+
+	preference, err := l.repositoryNotificationMain.GetPreference(patientID)
+	if err != nil {
+		log.Printf("notification-logic: Error fetching notification preference of patient ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingNotificationPreference
+	}
+
+	return preference, nil
+}
+
+func (l *notificationLogic) UpdatePreference(patientID uint, request *model.NotificationPreferenceRequest) (*model.NotificationPreference, error) {
+	preference, err := l.GetPreference(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	preference.Email = request.Email
+	preference.SMS = request.SMS
+
+	err = l.repositoryNotificationMain.SavePreference(preference)
+	if err != nil {
+		log.Printf("notification-logic: Error saving notification preference of patient ID %d: %v", patientID, err)
+		return nil, response.ErrorToSaveNotificationPreference
+	}
+
+	return preference, nil
+}
+
+func (l *notificationLogic) GetDeliveries(patientID uint, limit, offset int) ([]model.NotificationDelivery, error) {
+	_, err := l.repositoryPatient.GetByID(patientID)
+	if err != nil {
+		log.Printf("notification-logic: Error fetching patient with ID %d: %v", patientID, err)
+		return nil, response.ErrorPatientNotFoundID
+	}
+
+	deliveries, err := l.repositoryNotificationMain.GetDeliveries(patientID, limit, offset)
+	if err != nil {
+		log.Printf("notification-logic: Error fetching notifications of patient ID %d: %v", patientID, err)
+		return nil, response.ErrorFetchingNotifications
+	}
+
+	return deliveries, nil
+}
+
+// Datos de la cita comunes a todos los avisos
+func (l *notificationLogic) appointmentData(appointment *model.Appointment) *notification.Data {
+	data := &notification.Data{
+		ClinicName:    config.Envs.ClinicName,
+		AppointmentID: appointment.ID,
+		Date:          appointment.Date,
+		StartTime:     appointment.StartTime,
+		EndTime:       appointment.EndTime,
+	}
+
+	doctor, err := l.repositoryDoctor.GetByID(appointment.DoctorID)
+	if err == nil {
+		data.DoctorName = doctor.Name + " " + doctor.LastName
+	}
+
+	return data
+}
+
+// Envía el aviso por los canales que eligió el paciente y deja registro de cada envío; devuelve el
+// primer error de envío
+func (l *notificationLogic) send(event model.NotificationEvent, patientID, appointmentID uint, paymentID *uint, data *notification.Data, attachments []notification.Attachment) error {
+	patient, err := l.repositoryPatient.GetByID(patientID)
+	if err != nil {
+		log.Printf("notification-logic: Error fetching patient with ID %d for %s: %v", patientID, event, err)
+		return err
+	}
+
+	preference, err := l.repositoryNotificationMain.GetPreference(patientID)
+	if err != nil {
+		log.Printf("notification-logic: Error fetching notification preference of patient ID %d: %v", patientID, err)
+		return err
+	}
+
+	data.PatientName = patient.Name + " " + patient.LastName
+
+	message, err := notification.Render(event, config.Envs.DefaultLanguage, data)
+	if err != nil {
+		log.Printf("notification-logic: Error rendering %s notification: %v", event, err)
+		return err
+	}
+
+	delivery := model.NotificationDelivery{
+		PatientID:     patientID,
+		AppointmentID: appointmentID,
+		PaymentID:     paymentID,
+		Event:         event,
+		Subject:       limitText(message.Subject, 200),
+	}
+
+	var sendErr error
+
+	if preference.Email {
+		sendErr = l.deliver(delivery, model.ChannelEmail, l.emailChannel, &notification.Message{
+			To:          patient.Email,
+			Subject:     message.Subject,
+			Body:        message.Body,
+			Attachments: attachments,
+		})
+	}
+
+	if preference.SMS {
+		err = l.deliver(delivery, model.ChannelSMS, l.smsChannel, &notification.Message{
+			To:   patient.PhoneNumber,
+			Body: message.SMS,
+		})
+		if sendErr == nil {
+			sendErr = err
+		}
+	}
+
+	return sendErr
+}
+
+func (l *notificationLogic) deliver(delivery model.NotificationDelivery, channelName model.NotificationChannel, channel notification.Channel, message *notification.Message) error {
+	delivery.Channel = channelName
+	delivery.Recipient = message.To
+	delivery.Status = model.NotificationSent
+
+	var sendErr error
+
+	if message.To == "" {
+		delivery.Status = model.NotificationSkipped
+	} else {
+		sendErr = channel.Send(message)
+		if sendErr != nil {
+			log.Printf("notification-logic: Error sending %s %s to patient ID %d: %v", delivery.Event, channelName, delivery.PatientID, sendErr)
+			delivery.Status = model.NotificationFailed
+			delivery.Error = limitText(sendErr.Error(), 255)
+		}
+	}
+
+	err := l.repositoryNotificationMain.CreateDelivery(&delivery)
+	if err != nil {
+		log.Printf("notification-logic: Error saving %s delivery for patient ID %d: %v", channelName, delivery.PatientID, err)
+	}
+
+	return sendErr
+}
+
+// Recorta el texto a max bytes sin partir un carácter, para que quepa en su columna
+func limitText(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+
+	for max > 0 && !utf8.RuneStart(text[max]) {
+		max--
+	}
+
+	return text[:max]
+}
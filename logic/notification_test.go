@@ -0,0 +1,425 @@
+package logic
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/notification"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+)
+
+type fakeNotificationRepository struct {
+	repository.NotificationRepository
+	preference model.NotificationPreference
+	deliveries []model.NotificationDelivery
+}
+
+func (r *fakeNotificationRepository) GetPreference(patientID uint) (*model.NotificationPreference, error) {
+	preference := r.preference
+	preference.PatientID = patientID
+	return &preference, nil
+}
+
+func (r *fakeNotificationRepository) CreateDelivery(delivery *model.NotificationDelivery) error {
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+type fakePatientStore struct {
+	repository.Repository[model.Patient]
+	patient model.Patient
+}
+
+func (r *fakePatientStore) GetByID(ID uint) (*model.Patient, error) {
+	patient := r.patient
+	return &patient, nil
+}
+
+type fakeDoctorStore struct {
+	repository.Repository[model.Doctor]
+}
+
+func (r *fakeDoctorStore) GetByID(ID uint) (*model.Doctor, error) {
+	return &model.Doctor{Person: model.Person{ID: ID, Name: "José", LastName: "Núñez"}}, nil
+}
+
+type failingChannel struct{}
+
+func (c failingChannel) Send(message *notification.Message) error {
+	return errors.New("smtp: connection refused")
+}
+
+// Usa el idioma y el nombre de la clínica indicados mientras dura la prueba
+func withNotificationConfig(t *testing.T, lang string) {
+	t.Helper()
+
+	previousLanguage, previousClinic, previousCurrency := config.Envs.DefaultLanguage, config.Envs.ClinicName, config.Envs.BaseCurrency
+	config.Envs.DefaultLanguage = lang
+	config.Envs.ClinicName = "Clínica Hackacode"
+	config.Envs.BaseCurrency = "PEN"
+
+	t.Cleanup(func() {
+		config.Envs.DefaultLanguage, config.Envs.ClinicName, config.Envs.BaseCurrency = previousLanguage, previousClinic, previousCurrency
+	})
+}
+
+func testNotificationAppointment() *model.Appointment {
+	return &model.Appointment{
+		ID:            42,
+		PatientID:     3,
+		DoctorID:      8,
+		Date:          "2026-03-20",
+		StartTime:     "10:00",
+		EndTime:       "10:30",
+		PatientAmount: money.FromUnits(90),
+	}
+}
+
+func TestNotificationMessagesPerChannelAndLanguage(t *testing.T) {
+	payment := &model.Payment{ID: 7, TotalAmount: money.FromUnits(90), Currency: "PEN"}
+	receipt := []byte("%PDF-1.4 recibo")
+
+	type expected struct {
+		subject string
+		body    []string
+		sms     string
+	}
+
+	tests := []struct {
+		name        string
+		lang        string
+		send        func(l NotificationLogic) error
+		event       model.NotificationEvent
+		attachments int
+		want        expected
+	}{
+		{
+			name:  "reserva en español",
+			lang:  "es",
+			event: model.NotificationAppointmentBooked,
+			send: func(l NotificationLogic) error {
+				return l.NotifyAppointment(model.NotificationAppointmentBooked, testNotificationAppointment(), nil)
+			},
+			want: expected{
+				subject: "Cita reservada para el 2026-03-20",
+				body:    []string{"Hola María Peña", "Su cita N.° 42 quedó reservada para el 2026-03-20 de 10:00 a 10:30 con José Núñez.", "Monto a pagar: 90.00 PEN", "Clínica Hackacode"},
+				sms:     "Clínica Hackacode: su cita quedó reservada para el 2026-03-20 a las 10:00.",
+			},
+		},
+		{
+			name:  "reserva en inglés",
+			lang:  "en",
+			event: model.NotificationAppointmentBooked,
+			send: func(l NotificationLogic) error {
+				return l.NotifyAppointment(model.NotificationAppointmentBooked, testNotificationAppointment(), nil)
+			},
+			want: expected{
+				subject: "Appointment booked for 2026-03-20",
+				body:    []string{"Hello María Peña", "Your appointment #42 is booked for 2026-03-20 from 10:00 to 10:30 with José Núñez.", "Amount due: 90.00 PEN"},
+				sms:     "Clínica Hackacode: your appointment is booked for 2026-03-20 at 10:00.",
+			},
+		},
+		{
+			name:  "reprogramación en español",
+			lang:  "es",
+			event: model.NotificationAppointmentRescheduled,
+			send: func(l NotificationLogic) error {
+				previous := testNotificationAppointment()
+				previous.Date, previous.StartTime = "2026-03-18", "09:00"
+				return l.NotifyAppointment(model.NotificationAppointmentRescheduled, testNotificationAppointment(), previous)
+			},
+			want: expected{
+				subject: "Cita reprogramada para el 2026-03-20",
+				body:    []string{"Su cita N.° 42 del 2026-03-18 a las 09:00 se reprogramó para el 2026-03-20 de 10:00 a 10:30"},
+				sms:     "Clínica Hackacode: su cita se reprogramó para el 2026-03-20 a las 10:00.",
+			},
+		},
+		{
+			name:        "pago en español con recibo",
+			lang:        "es",
+			event:       model.NotificationPaymentReceived,
+			attachments: 1,
+			send: func(l NotificationLogic) error {
+				return l.NotifyPaymentReceived(testNotificationAppointment(), payment, receipt)
+			},
+			want: expected{
+				subject: "Pago recibido - " + payment.InvoiceNumber(),
+				body:    []string{"Recibimos su pago de 90.00 PEN por la cita N.° 42 del 2026-03-20. Adjuntamos el recibo " + payment.InvoiceNumber() + "."},
+				sms:     "Clínica Hackacode: recibimos su pago de 90.00 PEN (" + payment.InvoiceNumber() + ").",
+			},
+		},
+		{
+			name:        "pago en inglés con recibo",
+			lang:        "en",
+			event:       model.NotificationPaymentReceived,
+			attachments: 1,
+			send: func(l NotificationLogic) error {
+				return l.NotifyPaymentReceived(testNotificationAppointment(), payment, receipt)
+			},
+			want: expected{
+				subject: "Payment received - " + payment.InvoiceNumber(),
+				body:    []string{"We received your payment of 90.00 PEN for appointment #42 on 2026-03-20."},
+				sms:     "Clínica Hackacode: we received your payment of 90.00 PEN (" + payment.InvoiceNumber() + ").",
+			},
+		},
+		{
+			name:  "recordatorio en español",
+			lang:  "es",
+			event: model.NotificationAppointmentReminder,
+			send: func(l NotificationLogic) error {
+				return l.RemindAppointment(testNotificationAppointment(), 24)
+			},
+			want: expected{
+				subject: "Recordatorio: su cita es el 2026-03-20 a las 10:00",
+				body:    []string{"su cita N.° 42 es en 24 horas: el 2026-03-20 de 10:00 a 10:30 con José Núñez."},
+				sms:     "Clínica Hackacode: recuerde su cita del 2026-03-20 a las 10:00.",
+			},
+		},
+		{
+			name:  "recordatorio en inglés",
+			lang:  "en",
+			event: model.NotificationAppointmentReminder,
+			send: func(l NotificationLogic) error {
+				return l.RemindAppointment(testNotificationAppointment(), 2)
+			},
+			want: expected{
+				subject: "Reminder: your appointment is on 2026-03-20 at 10:00",
+				body:    []string{"your appointment #42 is in 2 hours: 2026-03-20 from 10:00 to 10:30 with José Núñez."},
+				sms:     "Clínica Hackacode: reminder of your appointment on 2026-03-20 at 10:00.",
+			},
+		},
+		{
+			name:  "idioma sin plantilla usa el español",
+			lang:  "pt",
+			event: model.NotificationAppointmentCancelled,
+			send: func(l NotificationLogic) error {
+				return l.NotifyAppointment(model.NotificationAppointmentCancelled, testNotificationAppointment(), nil)
+			},
+			want: expected{
+				subject: "Cita del 2026-03-20 cancelada",
+				body:    []string{"Su cita N.° 42 del 2026-03-20 a las 10:00 fue cancelada."},
+				sms:     "Clínica Hackacode: su cita del 2026-03-20 a las 10:00 fue cancelada.",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withNotificationConfig(t, test.lang)
+
+			email := notification.NewFakeChannel("email")
+			sms := notification.NewFakeChannel("sms")
+			notifications := &fakeNotificationRepository{preference: model.NotificationPreference{Email: true, SMS: true}}
+			patients := &fakePatientStore{patient: model.Patient{Person: model.Person{ID: 3, Name: "María", LastName: "Peña", Email: "maria@example.com", PhoneNumber: "+51999888777"}}}
+
+			logic := NewNotificationLogic(notifications, patients, &fakeDoctorStore{}, email, sms)
+
+			err := test.send(logic)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			emails := email.Messages()
+			if len(emails) != 1 {
+				t.Fatalf("sent %d emails, want 1", len(emails))
+			}
+
+			if emails[0].To != "maria@example.com" || emails[0].Subject != test.want.subject {
+				t.Errorf("email to %s with subject %q, want maria@example.com with %q", emails[0].To, emails[0].Subject, test.want.subject)
+			}
+
+			for _, text := range test.want.body {
+				if !strings.Contains(emails[0].Body, text) {
+					t.Errorf("email body does not contain %q:\n%s", text, emails[0].Body)
+				}
+			}
+
+			if len(emails[0].Attachments) != test.attachments {
+				t.Errorf("email has %d attachments, want %d", len(emails[0].Attachments), test.attachments)
+			}
+
+			texts := sms.Messages()
+			if len(texts) != 1 {
+				t.Fatalf("sent %d SMS, want 1", len(texts))
+			}
+
+			if texts[0].To != "+51999888777" || texts[0].Body != test.want.sms || texts[0].Subject != "" || len(texts[0].Attachments) != 0 {
+				t.Errorf("SMS to %s %q, want +51999888777 %q without subject or attachments", texts[0].To, texts[0].Body, test.want.sms)
+			}
+
+			if len(notifications.deliveries) != 2 {
+				t.Fatalf("recorded %d deliveries, want 2", len(notifications.deliveries))
+			}
+
+			for i, channel := range []model.NotificationChannel{model.ChannelEmail, model.ChannelSMS} {
+				delivery := notifications.deliveries[i]
+				if delivery.Channel != channel || delivery.Event != test.event || delivery.Status != model.NotificationSent || delivery.AppointmentID != 42 {
+					t.Errorf("delivery %d = %+v, want a sent %s %s", i, delivery, channel, test.event)
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationRespectsPreferences(t *testing.T) {
+	withNotificationConfig(t, "es")
+
+	tests := []struct {
+		name       string
+		preference model.NotificationPreference
+		phone      string
+		emails     int
+		sms        int
+		statuses   []model.NotificationStatus
+	}{
+		{name: "solo correo", preference: model.NotificationPreference{Email: true}, phone: "+51999888777", emails: 1, statuses: []model.NotificationStatus{model.NotificationSent}},
+		{name: "solo SMS", preference: model.NotificationPreference{SMS: true}, phone: "+51999888777", sms: 1, statuses: []model.NotificationStatus{model.NotificationSent}},
+		{name: "ninguno", preference: model.NotificationPreference{}, phone: "+51999888777"},
+		{name: "sin teléfono", preference: model.NotificationPreference{Email: true, SMS: true}, emails: 1, statuses: []model.NotificationStatus{model.NotificationSent, model.NotificationSkipped}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			email := notification.NewFakeChannel("email")
+			sms := notification.NewFakeChannel("sms")
+			notifications := &fakeNotificationRepository{preference: test.preference}
+			patients := &fakePatientStore{patient: model.Patient{Person: model.Person{ID: 3, Name: "María", Email: "maria@example.com", PhoneNumber: test.phone}}}
+
+			err := NewNotificationLogic(notifications, patients, &fakeDoctorStore{}, email, sms).RemindAppointment(testNotificationAppointment(), 24)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(email.Messages()) != test.emails || len(sms.Messages()) != test.sms {
+				t.Errorf("sent %d emails and %d SMS, want %d and %d", len(email.Messages()), len(sms.Messages()), test.emails, test.sms)
+			}
+
+			if len(notifications.deliveries) != len(test.statuses) {
+				t.Fatalf("recorded %d deliveries, want %d", len(notifications.deliveries), len(test.statuses))
+			}
+
+			for i, status := range test.statuses {
+				if notifications.deliveries[i].Status != status {
+					t.Errorf("delivery %d status = %s, want %s", i, notifications.deliveries[i].Status, status)
+				}
+			}
+		})
+	}
+}
+
+// Un canal caído se registra como fallido y su error se devuelve para reintentar, sin frenar al otro canal
+func TestNotificationChannelFailure(t *testing.T) {
+	withNotificationConfig(t, "es")
+
+	sms := notification.NewFakeChannel("sms")
+	notifications := &fakeNotificationRepository{preference: model.NotificationPreference{Email: true, SMS: true}}
+	patients := &fakePatientStore{patient: model.Patient{Person: model.Person{ID: 3, Email: "maria@example.com", PhoneNumber: "+51999888777"}}}
+
+	err := NewNotificationLogic(notifications, patients, &fakeDoctorStore{}, failingChannel{}, sms).
+		NotifyAppointment(model.NotificationAppointmentBooked, testNotificationAppointment(), nil)
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("got %v, want the email error", err)
+	}
+
+	if len(sms.Messages()) != 1 {
+		t.Errorf("sent %d SMS, want 1", len(sms.Messages()))
+	}
+
+	if notifications.deliveries[0].Status != model.NotificationFailed || notifications.deliveries[0].Error != "smtp: connection refused" {
+		t.Errorf("email delivery = %+v, want failed", notifications.deliveries[0])
+	}
+
+	if notifications.deliveries[1].Status != model.NotificationSent {
+		t.Errorf("SMS delivery = %+v, want sent", notifications.deliveries[1])
+	}
+}
+
+// El suscriptor de AppointmentBooked arma el aviso con los datos guardados en el evento
+func TestAppointmentBookedEventNotifies(t *testing.T) {
+	withNotificationConfig(t, "en")
+
+	email := notification.NewFakeChannel("email")
+	sms := notification.NewFakeChannel("sms")
+	notifications := &fakeNotificationRepository{preference: model.NotificationPreference{Email: true, SMS: true}}
+	patients := &fakePatientStore{patient: model.Patient{Person: model.Person{ID: 3, Name: "María", LastName: "Peña", Email: "maria@example.com", PhoneNumber: "+51999888777"}}}
+	logic := NewNotificationLogic(notifications, patients, &fakeDoctorStore{}, email, sms)
+
+	event := &model.OutboxEvent{
+		ID:        1,
+		EventType: model.EventAppointmentBooked,
+		Payload:   `{"appointment_id":42,"patient_id":3,"doctor_id":8,"date":"2026-03-20","start_time":"10:00","end_time":"10:30","status":"programada","paid":false,"total_amount":100.00,"patient_amount":90.00}`,
+	}
+
+	err := notifyAppointmentEvent(logic, model.NotificationAppointmentBooked)(event)
+	if err != nil {
+		t.Fatalf("subscriber: %v", err)
+	}
+
+	if len(email.Messages()) != 1 || email.Messages()[0].Subject != "Appointment booked for 2026-03-20" {
+		t.Errorf("emails = %+v", email.Messages())
+	}
+
+	if len(sms.Messages()) != 1 || sms.Messages()[0].Body != "Clínica Hackacode: your appointment is booked for 2026-03-20 at 10:00." {
+		t.Errorf("SMS = %+v", sms.Messages())
+	}
+
+	// Sin paciente no hay a quién avisar
+	event.Payload = `{"appointment_id":43,"patient_id":0,"date":"2026-03-20"}`
+
+	err = notifyAppointmentEvent(logic, model.NotificationAppointmentBooked)(event)
+	if err != nil || len(email.Messages()) != 1 {
+		t.Errorf("appointment without patient: err %v, %d emails", err, len(email.Messages()))
+	}
+}
+
+// AppointmentRescheduled viaja con las líneas y el horario anterior, y el aviso menciona ambos horarios
+func TestAppointmentRescheduledEventNotifies(t *testing.T) {
+	withNotificationConfig(t, "es")
+
+	email := notification.NewFakeChannel("email")
+	notifications := &fakeNotificationRepository{preference: model.NotificationPreference{Email: true}}
+	patients := &fakePatientStore{patient: model.Patient{Person: model.Person{ID: 3, Name: "María", LastName: "Peña", Email: "maria@example.com"}}}
+	logic := NewNotificationLogic(notifications, patients, &fakeDoctorStore{}, email, notification.NewFakeChannel("sms"))
+
+	appointment := testNotificationAppointment()
+	appointment.Items = []model.AppointmentItem{{ItemType: model.CatalogService, ServiceID: 4, Name: "Consulta", Quantity: 1, UnitPrice: money.FromUnits(100), FinalPrice: money.FromUnits(90), PatientAmount: money.FromUnits(90)}}
+
+	previous := testNotificationAppointment()
+	previous.Date, previous.StartTime, previous.EndTime = "2026-03-18", "09:00", "09:30"
+
+	payload, err := json.Marshal(model.NewRescheduledEvent(appointment, previous))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	event := &model.OutboxEvent{ID: 1, EventType: model.EventAppointmentRescheduled, Payload: string(payload)}
+
+	var decoded model.AppointmentEvent
+
+	err = event.Decode(&decoded)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if len(decoded.Items) != 1 || decoded.Items[0].ServiceID != 4 || decoded.Items[0].FinalPrice != money.FromUnits(90) {
+		t.Errorf("items = %+v, want %+v", decoded.Items, appointment.Items)
+	}
+
+	if got := decoded.Previous(); got == nil || got.Date != "2026-03-18" || got.StartTime != "09:00" || got.DoctorID != 8 {
+		t.Errorf("previous = %+v", got)
+	}
+
+	err = notifyAppointmentEvent(logic, model.NotificationAppointmentRescheduled)(event)
+	if err != nil {
+		t.Fatalf("subscriber: %v", err)
+	}
+
+	if len(email.Messages()) != 1 || !strings.Contains(email.Messages()[0].Body, "Su cita N.° 42 del 2026-03-18 a las 09:00 se reprogramó para el 2026-03-20 de 10:00 a 10:30") {
+		t.Errorf("emails = %+v", email.Messages())
+	}
+}
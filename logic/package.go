@@ -2,19 +2,24 @@ package logic
 
 import (
 	"log"
+	"time"
 
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/calculation"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
 )
 
 type PackageLogic interface {
 	GetPackageByID(ID uint) (*model.Package, error)
-	GetAllPackages(limit, offset int) ([]model.Package, error)
+	GetAllPackages(onlyActive bool, limit, offset int) ([]model.Package, error)
 	CreatePackage(packageServices *model.CreatePackageRequest) error
 	UpdatePackage(ID uint, packageServices *model.CreatePackageRequest) error
-	DeletePackage(ID uint) error
+	UpdatePackageStatus(ID uint, active bool) (*model.Package, error)
+	DeletePackage(ID uint) (bool, error)
+	GetPriceHistory(ID uint) ([]model.PriceHistory, error)
 }
 
 type packageLogic struct {
@@ -22,6 +27,9 @@ type packageLogic struct {
 	repositoryPkgMain  repository.PackageRepository
 	repositoryServ     repository.Repository[model.Service]
 	repositoryServMain repository.ServiceRepository
+	repositoryPricing  repository.PricingRuleRepository
+	logicCurrency      CurrencyLogic
+	repositoryHistory  repository.PriceHistoryRepository
 }
 
 func NewPackageLogic(
@@ -29,12 +37,18 @@ func NewPackageLogic(
 	repositoryPkgMain repository.PackageRepository,
 	repositoryServ repository.Repository[model.Service],
 	repositoryServMain repository.ServiceRepository,
+	repositoryPricing repository.PricingRuleRepository,
+	logicCurrency CurrencyLogic,
+	repositoryHistory repository.PriceHistoryRepository,
 ) PackageLogic {
 	return &packageLogic{
 		repositoryPkg:      repositoryPkg,
 		repositoryPkgMain:  repositoryPkgMain,
 		repositoryServ:     repositoryServ,
 		repositoryServMain: repositoryServMain,
+		repositoryPricing:  repositoryPricing,
+		logicCurrency:      logicCurrency,
+		repositoryHistory:  repositoryHistory,
 	}
 }
 
@@ -48,8 +62,8 @@ func (l *packageLogic) GetPackageByID(ID uint) (*model.Package, error) {
 	return packageService, nil
 }
 
-func (l *packageLogic) GetAllPackages(limit, offset int) ([]model.Package, error) {
-	packageServices, err := l.repositoryPkgMain.GetAll(limit, offset)
+func (l *packageLogic) GetAllPackages(onlyActive bool, limit, offset int) ([]model.Package, error) {
+	packageServices, err := l.repositoryPkgMain.GetAll(onlyActive, limit, offset)
 	if err != nil {
 		log.Printf("package-logic: Error fetching packages: %v", err)
 		return nil, response.ErrorPackageNotFound
@@ -79,24 +93,58 @@ func (l *packageLogic) CreatePackage(pkg *model.CreatePackageRequest) error {
 			return response.ErrorServiceNotFound
 		}
 
+		if !serviceFound.Active {
+			return response.ErrorServiceInactive
+		}
+
 		selectedServices = append(selectedServices, *serviceFound)
 	}
 
-	finalPkgPrice := calculation.TotalServicePackageAmount(selectedServices)
+	today := validate.FormatDate(time.Now())
+
+	rules, err := l.repositoryPricing.GetActiveRules(today)
+	if err != nil {
+		log.Printf("package: Error fetching pricing rules: %v", err)
+		return response.ErrorFetchingPricingRules
+	}
+
+	//el precio del paquete se guarda en moneda base
+	baseServices, err := l.logicCurrency.ServicesToBase(selectedServices, today)
+	if err != nil {
+		log.Printf("package: Error converting service prices to base currency: %v", err)
+		return err
+	}
+
+	err = validatePackageSessions(pkg)
+	if err != nil {
+		return err
+	}
+
+	err = validatePackageSale(pkg)
+	if err != nil {
+		return err
+	}
 
 	pkgCreated := model.Package{
-		Name:     pkg.Name,
-		Services: selectedServices,
-		Price:    finalPkgPrice.FinalPrice,
+		Name:               pkg.Name,
+		Services:           selectedServices,
+		Sessions:           pkg.Sessions,
+		ValidityDays:       pkg.ValidityDays,
+		DiscountPercentage: pkg.DiscountPercentage,
+		SaleFrom:           pkg.SaleFrom,
+		SaleTo:             pkg.SaleTo,
 	}
 
+	finalPkgPrice := calculation.TotalServicePackageAmount(0, baseServices, calculation.PackageSessions(pkgCreated), calculation.PackageRules(pkgCreated, rules))
+	pkgCreated.Price = finalPkgPrice.FinalPrice
+
 	err = l.repositoryPkg.Create(&pkgCreated)
 	if err != nil {
 		log.Printf("package: Error saving package: %v", err)
 		return response.ErrorToCreatedPackage
 	}
 
-	return nil
+	return recordPrice(l.repositoryHistory, packagePriceEntry(&pkgCreated))
 }
 
 func (l *packageLogic) UpdatePackage(ID uint, packageServices *model.CreatePackageRequest) error {
@@ -106,10 +154,14 @@ func (l *packageLogic) UpdatePackage(ID uint, packageServices *model.CreatePacka
 		return response.ErrorPackageNotFound
 	}
 
-	err = l.repositoryPkgMain.ClearServices(ID)
+	err = validatePackageSessions(packageServices)
 	if err != nil {
-		log.Printf("package: Error clearing services for package ID %d: %v", ID, err)
-		return response.ErrorClearingServices
+		return err
+	}
+
+	err = validatePackageSale(packageServices)
+	if err != nil {
+		return err
 	}
 
 	selectedServices := []model.Service{}
@@ -121,13 +173,52 @@ func (l *packageLogic) UpdatePackage(ID uint, packageServices *model.CreatePacka
 			return response.ErrorServiceNotFound
 		}
 
+		//un servicio retirado solo se conserva si el paquete ya lo incluía
+		if !serviceFound.Active && !includesService(existingPackage.Services, serviceID) {
+			return response.ErrorServiceInactive
+		}
+
 		selectedServices = append(selectedServices, *serviceFound)
 	}
 
-	finalPkgPrice := calculation.TotalServicePackageAmount(selectedServices)
+	today := validate.FormatDate(time.Now())
+
+	rules, err := l.repositoryPricing.GetActiveRules(today)
+	if err != nil {
+		log.Printf("package: Error fetching pricing rules: %v", err)
+		return response.ErrorFetchingPricingRules
+	}
+
+	//el precio del paquete se guarda en moneda base
+	baseServices, err := l.logicCurrency.ServicesToBase(selectedServices, today)
+	if err != nil {
+		log.Printf("package: Error converting service prices to base currency: %v", err)
+		return err
+	}
+
+	//los servicios y sesiones se reemplazan solo cuando la solicitud ya fue validada
+	err = l.repositoryPkgMain.ClearServices(ID)
+	if err != nil {
+		log.Printf("package: Error clearing services for package ID %d: %v", ID, err)
+		return response.ErrorClearingServices
+	}
+
+	err = l.repositoryPkgMain.ClearSessions(ID)
+	if err != nil {
+		log.Printf("package: Error clearing sessions for package ID %d: %v", ID, err)
+		return response.ErrorClearingSessions
+	}
 
 	existingPackage.Name = packageServices.Name
 	existingPackage.Services = selectedServices
+	existingPackage.Sessions = packageServices.Sessions
+	existingPackage.ValidityDays = packageServices.ValidityDays
+	existingPackage.DiscountPercentage = packageServices.DiscountPercentage
+	existingPackage.SaleFrom = packageServices.SaleFrom
+	existingPackage.SaleTo = packageServices.SaleTo
+
+	//las citas ya reservadas conservan el precio de su reserva; el nuevo precio aplica a las siguientes
+	finalPkgPrice := calculation.TotalServicePackageAmount(ID, baseServices, calculation.PackageSessions(*existingPackage), calculation.PackageRules(*existingPackage, rules))
 	existingPackage.Price = finalPkgPrice.FinalPrice
 
 	err = l.repositoryPkg.Update(existingPackage)
@@ -136,21 +227,138 @@ func (l *packageLogic) UpdatePackage(ID uint, packageServices *model.CreatePacka
 		return response.ErrorToUpdatedPackage
 	}
 
-	return nil
+	return recordPrice(l.repositoryHistory, packagePriceEntry(existingPackage))
+}
+
+func (l *packageLogic) UpdatePackageStatus(ID uint, active bool) (*model.Package, error) {
+	pkg, err := l.GetPackageByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.Active = active
+
+	err = l.repositoryPkg.Update(pkg)
+	if err != nil {
+		log.Printf("package-logic: Error updating status of package with ID %d: %v", ID, err)
+		return nil, response.ErrorToUpdatedPackage
+	}
+
+	return pkg, nil
 }
 
-func (l *packageLogic) DeletePackage(ID uint) error {
-	_, err := l.repositoryPkg.GetByID(ID)
+// Un paquete con citas o ventas registradas no se elimina para conservar su historial: se retira.
+// Devuelve true cuando el paquete se retiró en lugar de eliminarse
+func (l *packageLogic) DeletePackage(ID uint) (bool, error) {
+	pkg, err := l.repositoryPkg.GetByID(ID)
 	if err != nil {
 		log.Printf("package-logic: Error fetching package with ID %d: %v", ID, err)
-		return response.ErrorPackageNotFound
+		return false, response.ErrorPackageNotFound
+	}
+
+	count, err := l.repositoryPkgMain.CountReferences(ID)
+	if err != nil {
+		log.Printf("package-logic: Error counting references of package with ID %d: %v", ID, err)
+		return false, response.ErrorToDeletedPackage
+	}
+
+	if count > 0 {
+		pkg.Active = false
+
+		err = l.repositoryPkg.Update(pkg)
+		if err != nil {
+			log.Printf("package-logic: Error retiring package with ID %d: %v", ID, err)
+			return false, response.ErrorToDeletedPackage
+		}
+
+		return true, nil
 	}
 
 	err = l.repositoryPkgMain.Delete(ID)
 	if err != nil {
 		log.Printf("package: Error deleting package with ID %d: %v", ID, err)
-		return response.ErrorToDeletedPackage
+		return false, response.ErrorToDeletedPackage
+	}
+
+	return false, nil
+}
+
+func (l *packageLogic) GetPriceHistory(ID uint) ([]model.PriceHistory, error) {
+	_, err := l.GetPackageByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return getPriceHistory(l.repositoryHistory, model.CatalogPackage, ID)
+}
+
+// El precio del paquete se guarda en moneda base
+func packagePriceEntry(pkg *model.Package) model.PriceHistory {
+	return model.PriceHistory{
+		ItemType: model.CatalogPackage,
+		ItemID:   pkg.ID,
+		Name:     pkg.Name,
+		Price:    pkg.Price,
+		Currency: config.Envs.BaseCurrency,
+	}
+}
+
+// Las sesiones solo pueden indicarse para servicios incluidos en el paquete y una vez por servicio
+func validatePackageSessions(pkg *model.CreatePackageRequest) error {
+	included := map[uint]bool{}
+	for _, serviceID := range pkg.ServiceIDs {
+		included[serviceID] = true
+	}
+
+	seen := map[uint]bool{}
+	for _, session := range pkg.Sessions {
+		if !included[session.ServiceID] {
+			return response.ErrorSessionServiceNotIncluded
+		}
+
+		if seen[session.ServiceID] {
+			return response.ErrorDuplicatedSession
+		}
+
+		seen[session.ServiceID] = true
 	}
 
 	return nil
 }
+
+// El descuento propio va de 0 a 100 y el periodo de venta es opcional en cada extremo (AAAA-MM-DD)
+func validatePackageSale(pkg *model.CreatePackageRequest) error {
+	if pkg.DiscountPercentage != nil && (*pkg.DiscountPercentage < 0 || *pkg.DiscountPercentage > 100) {
+		return response.ErrorPackageDiscount
+	}
+
+	if pkg.SaleFrom != "" {
+		_, err := validate.ParseDate(pkg.SaleFrom)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pkg.SaleTo != "" {
+		_, err := validate.ParseDate(pkg.SaleTo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pkg.SaleFrom != "" && pkg.SaleTo != "" && pkg.SaleFrom > pkg.SaleTo {
+		return response.ErrorPackageSaleWindow
+	}
+
+	return nil
+}
+
+func includesService(services []model.Service, serviceID uint) bool {
+	for _, service := range services {
+		if service.ID == serviceID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -22,16 +22,22 @@ type patientLogic struct {
 	repositoryPatient         repository.Repository[model.Patient]
 	repositoryPatientMain     repository.PatientRepository
 	repositoryAppointmentMain repository.AppointmentRepository
+	repositoryInsuranceMain   repository.InsuranceRepository
+	repositoryHistoryMain     repository.MedicalHistoryRepository
 }
 
 func NewPatientLogic(repositoryPatient repository.Repository[model.Patient],
 	repositoryPatientMain repository.PatientRepository,
 	repositoryAppointmentMain repository.AppointmentRepository,
+	repositoryInsuranceMain repository.InsuranceRepository,
+	repositoryHistoryMain repository.MedicalHistoryRepository,
 ) PatientLogic {
 	return &patientLogic{
 		repositoryPatient:         repositoryPatient,
 		repositoryPatientMain:     repositoryPatientMain,
 		repositoryAppointmentMain: repositoryAppointmentMain,
+		repositoryInsuranceMain:   repositoryInsuranceMain,
+		repositoryHistoryMain:     repositoryHistoryMain,
 	}
 }
 
@@ -102,9 +108,8 @@ func (l *patientLogic) UpdatePatient(ID uint, updatePatient *model.Patient) erro
 	existingPatient.Email = updatePatient.Email
 	existingPatient.PhoneNumber = updatePatient.PhoneNumber
 	existingPatient.Address = updatePatient.Address
-	existingPatient.Insurance = updatePatient.Insurance
 
-	err = l.repositoryPatient.Update(existingPatient)
+	err = l.repositoryPatientMain.Update(existingPatient)
 	if err != nil {
 		log.Printf("patient-logic: Error updating patient with ID %d: %v", ID, err)
 		return response.ErrorToUpdatedPatient
@@ -126,6 +131,18 @@ func (l *patientLogic) DeletePatient(ID uint) error {
 		return response.ErrorUnlinkingAppointments
 	}
 
+	err = l.repositoryInsuranceMain.DeletePatientPolicies(ID)
+	if err != nil {
+		log.Printf("patient-logic: Error deleting policies for patient with ID %d: %v", ID, err)
+		return response.ErrorDeletingPatientPolicies
+	}
+
+	err = l.repositoryHistoryMain.DeletePatientHistory(ID)
+	if err != nil {
+		log.Printf("patient-logic: Error deleting medical history for patient with ID %d: %v", ID, err)
+		return response.ErrorDeletingPatientHistory
+	}
+
 	err = l.repositoryPatient.Delete(ID)
 	if err != nil {
 		log.Printf("patient-logic: Error deleting patient with ID %d: %v", ID, err)
@@ -1,29 +1,59 @@
 package logic
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"strings"
+	"time"
 
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/jobs"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/pdftemplate"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
-	"github.com/jung-kurt/gofpdf"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/storage"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
 	"github.com/skip2/go-qrcode"
 )
 
 type PaymentLogic interface {
 	PaymentRegister(payment *model.Payment) (*model.PaymentResponse, error)
+	GetPaymentReceipt(ID uint, lang string) (io.ReadCloser, error)
+	GetPaymentInvoice(ID uint, lang string) ([]byte, error)
+	GetPaymentQRCode(ID uint) (io.ReadCloser, error)
+	PurgeExpiredReceipts() (int, error)
+	VerifyReceipt(token string) (*model.ReceiptVerification, error)
+	RefundPayment(ID uint, request *model.RefundRequest, user model.User) (*model.Payment, error)
 }
 
 type paymentLogic struct {
 	repositoryAppointmentMain repository.AppointmentRepository
+	repositoryPayment         repository.PaymentRepository
+	repositoryCashSessionMain repository.CashSessionRepository
+	logicCurrency             CurrencyLogic
+	fileStore                 storage.FileStore
+	renderer                  *pdftemplate.Renderer
 }
 
 func NewPaymentLogic(repositoryAppointmentMain repository.AppointmentRepository,
+	repositoryPayment repository.PaymentRepository,
+	repositoryCashSessionMain repository.CashSessionRepository,
+	logicCurrency CurrencyLogic,
+	fileStore storage.FileStore,
+	renderer *pdftemplate.Renderer,
 ) PaymentLogic {
 	return &paymentLogic{
 		repositoryAppointmentMain: repositoryAppointmentMain,
+		repositoryPayment:         repositoryPayment,
+		repositoryCashSessionMain: repositoryCashSessionMain,
+		logicCurrency:             logicCurrency,
+		fileStore:                 fileStore,
+		renderer:                  renderer,
 	}
 }
 
@@ -34,125 +64,459 @@ func (l paymentLogic) PaymentRegister(payment *model.Payment) (*model.PaymentRes
 		return nil, response.ErrorAppointmentNotFound
 	}
 
+	if appointment.Paid {
+		return nil, response.ErrorAppointmentPaid
+	}
+
+	// Una cita cancelada o vencida ya no se cobra
+	if !appointment.Status.Payable() {
+		return nil, response.ErrorAppointmentNotPayable
+	}
+
 	if !payment.Paid {
 		return nil, response.ErrorPaidNotTrue
 	}
 
-	if payment.TotalAmount == 0 {
+	// Cuando la aseguradora cubre todo, el paciente confirma la cita con un pago de cero
+	if payment.TotalAmount == 0 && appointment.PatientAmount > 0 {
 		return nil, response.ErrorTotalAmountEmpty
 	}
 
-	if payment.TotalAmount < appointment.TotalAmount {
+	// El pago se convierte a moneda base con el tipo de cambio del día y queda registrado en el libro
+	payment.Currency = NormalizeCurrency(payment.Currency)
+
+	baseAmount, rate, err := l.logicCurrency.ToBase(payment.TotalAmount, payment.Currency, validate.FormatDate(time.Now()))
+	if err != nil {
+		log.Printf("payment: Error converting payment to base currency: %v", err)
+		return nil, err
+	}
+
+	payment.ExchangeRate = rate
+	payment.BaseAmount = baseAmount
+
+	// El paciente paga solo su parte; la parte de la aseguradora se cobra por reclamo
+	if payment.BaseAmount < appointment.PatientAmount {
 		return nil, response.ErrorTotalAmountBadRequest
 	}
 
-	//métodos de pago
-	validPaymentTypes := map[model.PaymentType]bool{
-		"tarjeta":     true,
-		"applicativo": true,
-		"efectivo":    true,
+	if !isValidPaymentType(payment.PaymentType) {
+		log.Println("payment: Error invalid payment type")
+		return nil, response.ErrorInvalidPaymentType
 	}
 
-	// Variable para almacenar si el método de pago de pago existe
-	isValid := false
+	// El pago queda asociado a la caja abierta; el efectivo no se recibe sin caja abierta
+	session, err := l.repositoryCashSessionMain.GetOpen()
+	if err == nil {
+		payment.CashSessionID = &session.ID
+	} else if payment.PaymentType == model.Cash {
+		log.Printf("payment: Error no open cash session for cash payment: %v", err)
+		return nil, response.ErrorCashSessionNotOpen
+	}
 
-	// Recorrer el mapa para verificar si el tipo de pago existe
-	for key, value := range validPaymentTypes {
-		if value == validPaymentTypes[key] {
-			isValid = true
-			break
+	// El aviso al paciente y demás efectos salen del evento PaymentRegistered que se guarda con el pago
+	err = l.repositoryPayment.Register(payment, appointment.PatientID)
+	if err != nil {
+		if errors.Is(err, response.ErrorAppointmentPaid) || errors.Is(err, response.ErrorAppointmentNotPayable) {
+			return nil, err
 		}
+
+		log.Printf("payment: Error saving payment for appointment ID %d: %v", payment.AppoimentID, err)
+		return nil, response.ErrorToSavePayment
 	}
 
-	if !isValid {
-		log.Println("payment: Error invalid payment type")
-		return nil, response.ErrorInvalidPaymentType
+	// El pago ya quedó registrado: si el recibo no se puede generar ahora se responde sin él y se
+	// vuelve a generar cuando se pida por su URL
+	qrCode, receipt, err := l.generateReceiptFiles(appointment, payment, l.renderer.DefaultLanguage())
+	if err == nil {
+		l.persistReceiptFiles(payment.ID, qrCode, receipt)
 	}
 
-	err = l.repositoryAppointmentMain.UpdatePaid(payment.AppoimentID)
+	paymentURL := fmt.Sprintf("%s/api/v1/payments/%d", strings.TrimRight(config.Envs.PublicHost, "/"), payment.ID)
+
+	paymentResponse := model.PaymentResponse{
+		PaymentID:     payment.ID,
+		InvoiceNumber: payment.InvoiceNumber(),
+		QRCode:        paymentURL + "/qr",
+		PDFReceipt:    paymentURL + "/receipt",
+		ReceiptPDF:    receipt,
+	}
+
+	return &paymentResponse, nil
+}
+
+// Recibo PDF del pago en el idioma pedido. Solo se guarda copia del recibo en el idioma por defecto;
+// si no está en el almacenamiento o se pide otro idioma se vuelve a generar
+func (l paymentLogic) GetPaymentReceipt(ID uint, lang string) (io.ReadCloser, error) {
+	lang = l.renderer.Language(lang)
+
+	return l.getReceiptFile(ID, receiptKey(ID), lang, func(_, receipt []byte) []byte { return receipt })
+}
+
+// QR del pago; si el archivo no está en el almacenamiento se vuelve a generar
+func (l paymentLogic) GetPaymentQRCode(ID uint) (io.ReadCloser, error) {
+	return l.getReceiptFile(ID, qrCodeKey(ID), l.renderer.DefaultLanguage(), func(qrCode, _ []byte) []byte { return qrCode })
+}
+
+// Comprobante de pago con los datos de facturación; se genera siempre, no se guarda copia
+func (l paymentLogic) GetPaymentInvoice(ID uint, lang string) ([]byte, error) {
+	payment, appointment, err := l.getReceiptPayment(ID)
 	if err != nil {
-		log.Printf("payment: Error updating paid status for appointment ID %d: %v", payment.AppoimentID, err)
-		return nil, response.ErrorToUpdatePaid
+		return nil, err
 	}
 
-	qrCodePath, err := GenerateQRCode(appointment, payment)
+	qrCode, err := GenerateQRCode(payment)
 	if err != nil {
 		log.Printf("payment: Error generating QR code for appointment ID %d: %v", payment.AppoimentID, err)
 		return nil, response.ErrorGeneratingQRCode
 	}
 
-	pdfReceiptPath, err := GeneratePDFReceipt(appointment, payment, qrCodePath)
+	invoice, err := l.renderer.Render(paymentDocument(pdftemplate.KindInvoice, l.renderer.Language(lang), appointment, payment, qrCode))
 	if err != nil {
-		log.Printf("payment: Error generating PDF receipt for appointment ID %d: %v", payment.AppoimentID, err)
+		log.Printf("payment: Error generating PDF invoice for payment ID %d: %v", ID, err)
 		return nil, response.ErrorGeneratingPDF
 	}
 
-	paymentResponse := model.PaymentResponse{
-		QRCode:     qrCodePath,
-		PDFReceipt: pdfReceiptPath,
+	return invoice, nil
+}
+
+func (l paymentLogic) getReceiptFile(ID uint, key, lang string, pick func(qrCode, receipt []byte) []byte) (io.ReadCloser, error) {
+	storedLanguage := lang == l.renderer.DefaultLanguage()
+
+	if storedLanguage && config.Envs.ReceiptRetentionDays > 0 {
+		file, err := l.fileStore.Get(key)
+		if err == nil {
+			return file, nil
+		}
+
+		if !errors.Is(err, storage.ErrObjectNotFound) {
+			log.Printf("payment: Error reading %s: %v", key, err)
+			return nil, response.ErrorReadingReceipt
+		}
 	}
 
-	return &paymentResponse, nil
+	payment, appointment, err := l.getReceiptPayment(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	qrCode, receipt, err := l.generateReceiptFiles(appointment, payment, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	if storedLanguage {
+		l.persistReceiptFiles(payment.ID, qrCode, receipt)
+	}
+
+	return io.NopCloser(bytes.NewReader(pick(qrCode, receipt))), nil
 }
 
-func GenerateQRCode(appointment *model.Appointment, payment *model.Payment) (string, error) {
-	qrData := fmt.Sprintf(
-		"Appointment ID: %d\nPatient: %s %s\nDate: %s\nStart Time: %s\nEnd Time: %s\nPaid: %v",
-		appointment.ID, appointment.Patient.Name, appointment.Patient.LastName,
-		appointment.Date, appointment.StartTime, appointment.EndTime, payment.Paid,
-	)
+// Pago con recibo y la cita que se pagó
+func (l paymentLogic) getReceiptPayment(ID uint) (*model.Payment, *model.Appointment, error) {
+	payment, err := l.repositoryPayment.GetByID(ID)
+	if err != nil {
+		log.Printf("payment: Error fetching payment with ID %d: %v", ID, err)
+		return nil, nil, response.ErrorPaymentNotFound
+	}
 
-	qrCodePath := fmt.Sprintf("qrcodes/appointment_%d.png", payment.AppoimentID)
+	// Los pagos de reclamos y de paquetes no están asociados a una cita y no tienen recibo
+	if payment.AppoimentID == 0 {
+		return nil, nil, response.ErrorPaymentWithoutReceipt
+	}
 
-	err := qrcode.WriteFile(qrData, qrcode.Medium, 256, qrCodePath)
+	appointment, err := l.repositoryAppointmentMain.GetByID(payment.AppoimentID)
 	if err != nil {
-		log.Printf("Error generating QR code: %v", err)
-		return "", err
-	}
-
-	return qrCodePath, nil
-}
-
-func GeneratePDFReceipt(appointment *model.Appointment, payment *model.Payment, qrCodePath string) (string, error) {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 16)
-	pdf.Cell(40, 10, "Recibo de Cita Médica")
-	pdf.Ln(12)
-
-	// Información de la cita
-	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(0, 10, fmt.Sprintf("ID de Cita: %d", appointment.ID))
-	pdf.Ln(8)
-	pdf.Cell(0, 10, fmt.Sprintf("Paciente: %s %s", appointment.Patient.Name, appointment.Patient.LastName))
-	pdf.Ln(8)
-	pdf.Cell(0, 10, fmt.Sprintf("Fecha: %s", appointment.Date))
-	pdf.Ln(8)
-	pdf.Cell(0, 10, fmt.Sprintf("Hora de Inicio: %s", appointment.StartTime))
-	pdf.Ln(8)
-	pdf.Cell(0, 10, fmt.Sprintf("Hora de Fin: %s", appointment.EndTime))
-	pdf.Ln(8)
-	pdf.Cell(0, 10, fmt.Sprintf("Monto Total: %.2f", payment.TotalAmount))
-	pdf.Ln(12)
-
-	// Adjuntar QR
-	qrImage, err := os.Open(qrCodePath)
+		log.Printf("payment: Error fetching appointment with ID %d: %v", payment.AppoimentID, err)
+		return nil, nil, response.ErrorAppointmentNotFound
+	}
+
+	return payment, appointment, nil
+}
+
+// Lo consulta cualquiera que escanee el QR del recibo: valida la firma y devuelve el estado actual del pago
+func (l paymentLogic) VerifyReceipt(token string) (*model.ReceiptVerification, error) {
+	claims, err := parseReceiptToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := l.repositoryPayment.GetByID(claims.PaymentID)
+	if err != nil {
+		log.Printf("payment: Error fetching payment with ID %d for receipt verification: %v", claims.PaymentID, err)
+		return nil, response.ErrorReceiptTokenInvalid
+	}
+
+	verification := model.ReceiptVerification{
+		Valid:         true,
+		Status:        model.ReceiptPaid,
+		InvoiceNumber: claims.InvoiceNumber,
+		AppointmentID: claims.AppointmentID,
+		Amount:        claims.Amount,
+		Currency:      claims.Currency,
+		PaidAt:        time.Unix(claims.PaidAt, 0),
+	}
+
+	// Un recibo reembolsado sigue siendo auténtico pero ya no es válido
+	if payment.RefundedAt != nil {
+		verification.Valid = false
+		verification.Status = model.ReceiptRefunded
+		verification.RefundedAt = payment.RefundedAt
+	}
+
+	return &verification, nil
+}
+
+// Reembolsa el pago de una cita: la cita vuelve a quedar pendiente de pago y su recibo queda anulado
+func (l paymentLogic) RefundPayment(ID uint, request *model.RefundRequest, user model.User) (*model.Payment, error) {
+	payment, err := l.repositoryPayment.GetByID(ID)
+	if err != nil {
+		log.Printf("payment: Error fetching payment with ID %d: %v", ID, err)
+		return nil, response.ErrorPaymentNotFound
+	}
+
+	if payment.AppoimentID == 0 {
+		return nil, response.ErrorPaymentWithoutReceipt
+	}
+
+	if payment.RefundedAt != nil {
+		return nil, response.ErrorPaymentRefunded
+	}
+
+	now := time.Now()
+	payment.RefundedAt = &now
+	payment.RefundedBy = user.Email
+	payment.RefundReason = strings.TrimSpace(request.Reason)
+
+	// Si la cita ya no existe el evento sale sin paciente
+	var patientID uint
+	appointment, err := l.repositoryAppointmentMain.GetByID(payment.AppoimentID)
 	if err == nil {
-		imgOpts := gofpdf.ImageOptions{ImageType: "PNG", ReadDpi: false}
-		pdf.RegisterImageOptionsReader("qr", imgOpts, qrImage)
-		pdf.Image("qr", 10, pdf.GetY(), 50, 50, false, "qr", 0, "")
-		qrImage.Close()
-	} else {
-		log.Printf("Error attaching QR code to PDF: %v", err)
+		patientID = appointment.PatientID
+	}
+
+	err = l.repositoryPayment.Refund(payment, patientID)
+	if err != nil {
+		if errors.Is(err, response.ErrorPaymentRefunded) {
+			return nil, err
+		}
+
+		log.Printf("payment: Error refunding payment with ID %d: %v", ID, err)
+		return nil, response.ErrorToRefundPayment
+	}
+
+	// Las copias guardadas se regeneran con la marca de anulado en la siguiente descarga
+	for _, key := range []string{receiptKey(ID), qrCodeKey(ID)} {
+		err = l.fileStore.Delete(key)
+		if err != nil {
+			log.Printf("payment: Error deleting %s after refund: %v", key, err)
+		}
+	}
+
+	return payment, nil
+}
+
+// Elimina las copias de recibos y QR guardadas hace más de RECEIPT_RETENTION_DAYS días;
+// se vuelven a generar si se descargan de nuevo
+func (l paymentLogic) PurgeExpiredReceipts() (int, error) {
+	if config.Envs.ReceiptRetentionDays <= 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -config.Envs.ReceiptRetentionDays)
+	purged := 0
+
+	for _, prefix := range []string{"receipts/", "qrcodes/"} {
+		objects, err := l.fileStore.List(prefix)
+		if err != nil {
+			log.Printf("payment: Error listing %s: %v", prefix, err)
+			return purged, err
+		}
+
+		for _, object := range objects {
+			if !object.ModifiedAt.Before(cutoff) {
+				continue
+			}
+
+			err = l.fileStore.Delete(object.Key)
+			if err != nil {
+				log.Printf("payment: Error deleting %s: %v", object.Key, err)
+				continue
+			}
+
+			purged++
+		}
 	}
 
-	// Guardar PDF
-	pdfPath := fmt.Sprintf("receipts/receipt_%d.pdf", payment.AppoimentID)
-	err = pdf.OutputFileAndClose(pdfPath)
+	return purged, nil
+}
+
+// Trabajo periódico que elimina las copias de recibos y QR vencidas
+const JobPurgeReceipts = "purgar_recibos"
+
+func PurgeReceiptsJob(logicPayment PaymentLogic) jobs.Handler {
+	return func(string) error {
+		purged, err := logicPayment.PurgeExpiredReceipts()
+		if err != nil {
+			log.Printf("payment: Error purging expired receipts: %v", err)
+			return err
+		}
+
+		if purged > 0 {
+			log.Printf("payment: %d expired receipt files purged", purged)
+		}
+
+		return nil
+	}
+}
+
+// Genera en memoria el QR y el recibo del pago
+func (l paymentLogic) generateReceiptFiles(appointment *model.Appointment, payment *model.Payment, lang string) ([]byte, []byte, error) {
+	qrCode, err := GenerateQRCode(payment)
+	if err != nil {
+		log.Printf("payment: Error generating QR code for appointment ID %d: %v", payment.AppoimentID, err)
+		return nil, nil, response.ErrorGeneratingQRCode
+	}
+
+	receipt, err := l.renderer.Render(paymentDocument(pdftemplate.KindReceipt, lang, appointment, payment, qrCode))
 	if err != nil {
-		log.Printf("Error generating PDF: %v", err)
-		return "", err
+		log.Printf("payment: Error generating PDF receipt for appointment ID %d: %v", payment.AppoimentID, err)
+		return nil, nil, response.ErrorGeneratingPDF
+	}
+
+	return qrCode, receipt, nil
+}
+
+// Guarda una copia del QR y del recibo mientras la retención lo permita; si no se puede guardar
+// (p. ej. un contenedor de solo lectura) se regeneran en la siguiente descarga
+func (l paymentLogic) persistReceiptFiles(paymentID uint, qrCode, receipt []byte) {
+	if config.Envs.ReceiptRetentionDays <= 0 {
+		return
+	}
+
+	err := l.fileStore.Put(qrCodeKey(paymentID), qrCode, "image/png")
+	if err != nil {
+		log.Printf("payment: Error storing QR code of payment ID %d: %v", paymentID, err)
+	}
+
+	err = l.fileStore.Put(receiptKey(paymentID), receipt, "application/pdf")
+	if err != nil {
+		log.Printf("payment: Error storing PDF receipt of payment ID %d: %v", paymentID, err)
+	}
+}
+
+func receiptKey(paymentID uint) string {
+	return fmt.Sprintf("receipts/receipt_%d.pdf", paymentID)
+}
+
+func qrCodeKey(paymentID uint) string {
+	return fmt.Sprintf("qrcodes/payment_%d.png", paymentID)
+}
+
+func isValidPaymentType(paymentType model.PaymentType) bool {
+	for _, validPaymentType := range model.PaymentTypes {
+		if paymentType == validPaymentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// El QR lleva la URL de verificación con el token firmado del pago, no datos en texto plano
+func GenerateQRCode(payment *model.Payment) ([]byte, error) {
+	token, err := signReceiptToken(payment)
+	if err != nil {
+		log.Printf("Error signing receipt token: %v", err)
+		return nil, err
+	}
+
+	qrCode, err := qrcode.Encode(receiptVerifyURL(token), qrcode.Medium, 256)
+	if err != nil {
+		log.Printf("Error generating QR code: %v", err)
+		return nil, err
+	}
+
+	return qrCode, nil
+}
+
+// Datos del recibo y del comprobante; cada plantilla elige cuáles imprime
+func paymentDocument(kind pdftemplate.Kind, lang string, appointment *model.Appointment, payment *model.Payment, qrCode []byte) *pdftemplate.Document {
+	doc := appointmentDocument(kind, lang, appointment)
+	doc.IssuedAt = payment.CreatedAt
+	doc.QRCode = qrCode
+	doc.Fields["invoice_number"] = payment.InvoiceNumber()
+	doc.Fields["issued_at"] = payment.CreatedAt.Format("2006-01-02 15:04")
+	doc.Fields["payment_type"] = string(payment.PaymentType)
+
+	if payment.RefundedAt != nil {
+		doc.Notices = append(doc.Notices, pdftemplate.Field{Key: "refunded", Value: payment.RefundedAt.Format("2006-01-02")})
+	}
+
+	doc.Totals = append(doc.Totals, pdftemplate.Field{Key: "total", Value: fmt.Sprintf("%s %s", payment.TotalAmount, payment.Currency)})
+	if payment.Currency != config.Envs.BaseCurrency {
+		doc.Totals = append(doc.Totals, pdftemplate.Field{
+			Key:   "equivalent",
+			Value: fmt.Sprintf("%s %s (%s %s)", payment.BaseAmount, config.Envs.BaseCurrency, pdftemplate.Label(lang, "exchange_rate"), payment.ExchangeRate),
+		})
+	}
+
+	return doc
+}
+
+// Datos de la cita comunes al recibo, al comprobante y a la confirmación
+func appointmentDocument(kind pdftemplate.Kind, lang string, appointment *model.Appointment) *pdftemplate.Document {
+	doc := &pdftemplate.Document{
+		Kind:     kind,
+		Language: lang,
+		Fields: map[string]string{
+			"appointment": fmt.Sprint(appointment.ID),
+			"date":        appointment.Date,
+			"start_time":  appointment.StartTime,
+			"end_time":    appointment.EndTime,
+			"status":      string(appointment.Status),
+		},
+	}
+
+	// La cita puede haber quedado sin paciente si este fue eliminado
+	if appointment.Patient != nil {
+		doc.Fields["patient"] = strings.TrimSpace(appointment.Patient.Name + " " + appointment.Patient.LastName)
+		doc.Fields["patient_dni"] = appointment.Patient.DNI
+	}
+
+	currency := config.Envs.BaseCurrency
+
+	for _, item := range appointment.Items {
+		line := pdftemplate.Line{
+			Description: fmt.Sprintf("%d x %s (%s %s)", item.Quantity, item.Name, item.UnitPrice, pdftemplate.Label(lang, "each")),
+			Amount:      fmt.Sprintf("%s %s", item.FinalPrice, currency),
+		}
+
+		if item.Discount > 0 {
+			line.Details = append(line.Details, pdftemplate.Field{Key: "discount", Value: fmt.Sprintf("-%s %s", item.Discount, currency)})
+		}
+
+		if item.CouponDiscount > 0 {
+			line.Details = append(line.Details, pdftemplate.Field{Key: "coupon", Value: fmt.Sprintf("-%s %s", item.CouponDiscount, currency)})
+		}
+
+		if item.PrepaidDiscount > 0 {
+			line.Details = append(line.Details, pdftemplate.Field{Key: "prepaid", Value: fmt.Sprintf("-%s %s", item.PrepaidDiscount, currency)})
+		}
+
+		doc.Lines = append(doc.Lines, line)
+	}
+
+	if appointment.CouponCode != "" {
+		doc.Totals = append(doc.Totals, pdftemplate.Field{Key: "coupon", Value: fmt.Sprintf("%s -%s %s", appointment.CouponCode, appointment.CouponDiscount, currency)})
+	}
+
+	if appointment.CreditID != nil {
+		doc.Totals = append(doc.Totals, pdftemplate.Field{Key: "credit"})
+	}
+
+	if appointment.InsurerAmount > 0 {
+		doc.Totals = append(doc.Totals, pdftemplate.Field{Key: "insurer", Value: fmt.Sprintf("%s %s", appointment.InsurerAmount, currency)})
 	}
 
-	return pdfPath, nil
+	return doc
 }
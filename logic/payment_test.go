@@ -0,0 +1,159 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/pdftemplate"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/storage"
+)
+
+type fakePaymentRepository struct {
+	repository.PaymentRepository
+	payment         model.Payment
+	refundErr       error
+	refundPatientID uint
+	refunds         int
+	registered      *model.Payment
+	registerErr     error
+}
+
+func (r *fakePaymentRepository) Register(payment *model.Payment, patientID uint) error {
+	if r.registerErr != nil {
+		return r.registerErr
+	}
+
+	payment.ID = 12
+	r.registered = payment
+	return nil
+}
+
+func (r *fakePaymentRepository) GetByID(ID uint) (*model.Payment, error) {
+	payment := r.payment
+	return &payment, nil
+}
+
+func (r *fakePaymentRepository) Refund(payment *model.Payment, patientID uint) error {
+	r.refunds++
+	r.refundPatientID = patientID
+	return r.refundErr
+}
+
+type fakeAppointmentRepository struct {
+	repository.AppointmentRepository
+	appointment *model.Appointment
+}
+
+func (r *fakeAppointmentRepository) GetByID(ID uint) (*model.Appointment, error) {
+	if r.appointment == nil {
+		return nil, response.ErrorAppointmentNotFound
+	}
+
+	return r.appointment, nil
+}
+
+func TestRefundPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		appointment *model.Appointment
+		refundErr   error
+		patientID   uint
+		err         error
+	}{
+		{name: "reembolso con el paciente de la cita", appointment: &model.Appointment{ID: 4, PatientID: 9}, patientID: 9},
+		{name: "cita eliminada", patientID: 0},
+		// Otro reembolso del mismo pago se confirmó entre la lectura y la actualización
+		{name: "reembolso simultáneo", appointment: &model.Appointment{ID: 4, PatientID: 9}, refundErr: response.ErrorPaymentRefunded, patientID: 9, err: response.ErrorPaymentRefunded},
+		{name: "error de la base de datos", appointment: &model.Appointment{ID: 4, PatientID: 9}, refundErr: errors.New("deadlock"), patientID: 9, err: response.ErrorToRefundPayment},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			payments := &fakePaymentRepository{payment: model.Payment{ID: 12, AppoimentID: 4}, refundErr: test.refundErr}
+			logic := NewPaymentLogic(&fakeAppointmentRepository{appointment: test.appointment}, payments, nil, nil, storage.NewLocalStore(t.TempDir()), nil)
+
+			payment, err := logic.RefundPayment(12, &model.RefundRequest{Reason: " cobro duplicado "}, model.User{Email: "caja@clinica.pe"})
+
+			if payments.refunds != 1 || payments.refundPatientID != test.patientID {
+				t.Errorf("Refund called %d times with patient %d, want once with %d", payments.refunds, payments.refundPatientID, test.patientID)
+			}
+
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("expected error %v, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if payment.RefundedAt == nil || payment.RefundedBy != "caja@clinica.pe" || payment.RefundReason != "cobro duplicado" {
+				t.Errorf("unexpected refund %+v", payment)
+			}
+		})
+	}
+}
+
+// El pago ya se confirmó en la base de datos: si el recibo no se puede generar se responde igual con
+// sus URLs, que lo vuelven a generar al descargarlo
+func TestPaymentRegisterSucceedsWhenReceiptFails(t *testing.T) {
+	payments := &fakePaymentRepository{}
+	appointment := &model.Appointment{ID: 4, PatientID: 9, Status: model.AppointmentScheduled, PatientAmount: money.FromUnits(90)}
+
+	// Sin plantillas el renderizado del recibo falla
+	logic := NewPaymentLogic(&fakeAppointmentRepository{appointment: appointment}, payments, &fakeCashSessionRepository{}, &fakeCurrencyLogic{}, storage.NewLocalStore(t.TempDir()), &pdftemplate.Renderer{})
+
+	paymentResponse, err := logic.PaymentRegister(&model.Payment{AppoimentID: 4, Paid: true, TotalAmount: money.FromUnits(90), PaymentType: model.Card})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if payments.registered == nil {
+		t.Fatal("payment was not registered")
+	}
+
+	if paymentResponse.PaymentID != 12 || paymentResponse.ReceiptPDF != nil || paymentResponse.PDFReceipt == "" {
+		t.Errorf("unexpected response %+v", paymentResponse)
+	}
+}
+
+// Una cita cancelada o vencida no admite pagos, tampoco si el trabajo de citas impagas la vence
+// mientras se registra el pago
+func TestPaymentRegisterRejectsUnpayableAppointments(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      model.AppointmentStatus
+		registerErr error
+		err         error
+	}{
+		{name: "cita vencida", status: model.AppointmentExpired, err: response.ErrorAppointmentNotPayable},
+		{name: "cita cancelada", status: model.AppointmentCancelled, err: response.ErrorAppointmentNotPayable},
+		{name: "vencida durante el registro", status: model.AppointmentScheduled, registerErr: response.ErrorAppointmentNotPayable, err: response.ErrorAppointmentNotPayable},
+		{name: "cita completada", status: model.AppointmentCompleted},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			payments := &fakePaymentRepository{registerErr: test.registerErr}
+			appointment := &model.Appointment{ID: 4, PatientID: 9, Status: test.status, PatientAmount: money.FromUnits(90)}
+
+			logic := NewPaymentLogic(&fakeAppointmentRepository{appointment: appointment}, payments, &fakeCashSessionRepository{}, &fakeCurrencyLogic{}, storage.NewLocalStore(t.TempDir()), &pdftemplate.Renderer{})
+
+			_, err := logic.PaymentRegister(&model.Payment{AppoimentID: 4, Paid: true, TotalAmount: money.FromUnits(90), PaymentType: model.Card})
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+
+			if test.err != nil && payments.registered != nil {
+				t.Error("payment registered for an appointment that no longer admits payments")
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package logic
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// Ruta pública que resuelve el QR impreso en la receta
+const prescriptionVerifyPath = "/api/v1/prescriptions/verify/"
+
+type PrescriptionLogic interface {
+	GetPrescriptionByID(ID uint, user model.User) (*model.Prescription, error)
+	GetPrescriptionsByAppointment(appointmentID uint, user model.User) ([]model.Prescription, error)
+	CreatePrescription(appointmentID uint, request *model.CreatePrescriptionRequest, user model.User) (*model.Prescription, error)
+	GeneratePrescriptionPDF(ID uint, user model.User) ([]byte, error)
+	VerifyPrescription(code string) (*model.PrescriptionVerification, error)
+}
+
+type prescriptionLogic struct {
+	repositoryPrescriptionMain repository.PrescriptionRepository
+	repositoryAppointmentMain  repository.AppointmentRepository
+	repositoryEncounterMain    repository.EncounterRepository
+}
+
+func NewPrescriptionLogic(
+	repositoryPrescriptionMain repository.PrescriptionRepository,
+	repositoryAppointmentMain repository.AppointmentRepository,
+	repositoryEncounterMain repository.EncounterRepository,
+) PrescriptionLogic {
+	return &prescriptionLogic{
+		repositoryPrescriptionMain: repositoryPrescriptionMain,
+		repositoryAppointmentMain:  repositoryAppointmentMain,
+		repositoryEncounterMain:    repositoryEncounterMain,
+	}
+}
+
+// Solo el médico que emitió la receta y los administradores pueden verla
+func (l *prescriptionLogic) GetPrescriptionByID(ID uint, user model.User) (*model.Prescription, error) {
+	prescription, err := l.repositoryPrescriptionMain.GetByID(ID)
+	if err != nil {
+		log.Printf("prescription-logic: Error fetching prescription with ID %d: %v", ID, err)
+		return nil, response.ErrorPrescriptionNotFound
+	}
+
+	if user.Role != model.RoleAdmin && !isTreatingDoctor(user, prescription.DoctorID) {
+		return nil, response.ErrorPrescriptionForbidden
+	}
+
+	return prescription, nil
+}
+
+func (l *prescriptionLogic) GetPrescriptionsByAppointment(appointmentID uint, user model.User) ([]model.Prescription, error) {
+	appointment, err := l.repositoryAppointmentMain.GetByID(appointmentID)
+	if err != nil {
+		log.Printf("prescription-logic: Error fetching appointment with ID %d: %v", appointmentID, err)
+		return nil, response.ErrorAppointmentNotFound
+	}
+
+	if user.Role != model.RoleAdmin && !isTreatingDoctor(user, appointment.DoctorID) {
+		return nil, response.ErrorPrescriptionForbidden
+	}
+
+	prescriptions, err := l.repositoryPrescriptionMain.GetByAppointment(appointmentID)
+	if err != nil {
+		log.Printf("prescription-logic: Error fetching prescriptions of appointment ID %d: %v", appointmentID, err)
+		return nil, response.ErrorFetchingPrescriptions
+	}
+
+	return prescriptions, nil
+}
+
+// La receta la emite el médico que atendió la cita una vez completada y queda enlazada a su registro clínico si existe
+func (l *prescriptionLogic) CreatePrescription(appointmentID uint, request *model.CreatePrescriptionRequest, user model.User) (*model.Prescription, error) {
+	appointment, err := l.repositoryAppointmentMain.GetByID(appointmentID)
+	if err != nil {
+		log.Printf("prescription-logic: Error fetching appointment with ID %d: %v", appointmentID, err)
+		return nil, response.ErrorAppointmentNotFound
+	}
+
+	if !isTreatingDoctor(user, appointment.DoctorID) {
+		return nil, response.ErrorPrescriptionForbidden
+	}
+
+	if appointment.Status != model.AppointmentCompleted {
+		return nil, response.ErrorPrescriptionNotCompleted
+	}
+
+	code, err := newRandomCode()
+	if err != nil {
+		log.Printf("prescription-logic: Error generating verification code: %v", err)
+		return nil, response.ErrorToCreatedPrescription
+	}
+
+	prescription := model.Prescription{
+		AppointmentID:    appointmentID,
+		DoctorID:         appointment.DoctorID,
+		PatientID:        appointment.PatientID,
+		VerificationCode: code,
+		Notes:            strings.TrimSpace(request.Notes),
+		IssuedBy:         user.Email,
+	}
+
+	encounter, err := l.repositoryEncounterMain.GetByAppointmentID(appointmentID)
+	if err == nil {
+		prescription.EncounterID = &encounter.ID
+	} else if !errors.Is(err, response.ErrorEncounterNotFound) {
+		log.Printf("prescription-logic: Error fetching encounter of appointment ID %d: %v", appointmentID, err)
+		return nil, response.ErrorToCreatedPrescription
+	}
+
+	for _, item := range request.Items {
+		prescription.Items = append(prescription.Items, model.PrescriptionItem{
+			Medication:   strings.TrimSpace(item.Medication),
+			Dose:         strings.TrimSpace(item.Dose),
+			Frequency:    strings.TrimSpace(item.Frequency),
+			Duration:     strings.TrimSpace(item.Duration),
+			Instructions: strings.TrimSpace(item.Instructions),
+		})
+	}
+
+	err = l.repositoryPrescriptionMain.Create(&prescription)
+	if err != nil {
+		log.Printf("prescription-logic: Error saving prescription for appointment ID %d: %v", appointmentID, err)
+		return nil, response.ErrorToCreatedPrescription
+	}
+
+	return &prescription, nil
+}
+
+func (l *prescriptionLogic) GeneratePrescriptionPDF(ID uint, user model.User) ([]byte, error) {
+	prescription, err := l.GetPrescriptionByID(ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	pdfBytes, err := GeneratePrescriptionPDF(prescription)
+	if err != nil {
+		log.Printf("prescription-logic: Error generating PDF for prescription ID %d: %v", ID, err)
+		return nil, response.ErrorGeneratingPDF
+	}
+
+	return pdfBytes, nil
+}
+
+// Lo consulta la farmacia al escanear el QR; no expone datos de contacto del paciente
+func (l *prescriptionLogic) VerifyPrescription(code string) (*model.PrescriptionVerification, error) {
+	prescription, err := l.repositoryPrescriptionMain.GetByCode(strings.ToLower(strings.TrimSpace(code)))
+	if err != nil {
+		log.Printf("prescription-logic: Error verifying prescription code %q: %v", code, err)
+		return nil, response.ErrorPrescriptionNotVerified
+	}
+
+	verification := model.PrescriptionVerification{
+		Valid:    true,
+		Code:     prescription.VerificationCode,
+		IssuedAt: prescription.CreatedAt,
+		Patient:  maskPatientName(prescription.Patient),
+		Items:    prescription.Items,
+	}
+
+	if prescription.Doctor != nil {
+		verification.DoctorName = prescription.Doctor.Name + " " + prescription.Doctor.LastName
+	}
+
+	return &verification, nil
+}
+
+// Código aleatorio de 32 caracteres hexadecimales (recetas y nombres de archivo)
+func newRandomCode() (string, error) {
+	code := make([]byte, 16)
+
+	_, err := rand.Read(code)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(code), nil
+}
+
+// Nombre del paciente con el apellido abreviado, p. ej. "Juan P."
+func maskPatientName(patient *model.Patient) string {
+	if patient == nil {
+		return ""
+	}
+
+	lastName := strings.TrimSpace(patient.LastName)
+	if lastName == "" {
+		return patient.Name
+	}
+
+	return fmt.Sprintf("%s %s.", patient.Name, string([]rune(lastName)[0]))
+}
+
+func prescriptionVerifyURL(code string) string {
+	return strings.TrimRight(config.Envs.PublicHost, "/") + prescriptionVerifyPath + code
+}
+
+func GeneratePrescriptionPDF(prescription *model.Prescription) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, tr("Receta Médica"))
+	pdf.Ln(12)
+
+	// Información de la receta
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(0, 10, tr(fmt.Sprintf("Receta: %d (cita %d)", prescription.ID, prescription.AppointmentID)))
+	pdf.Ln(8)
+	pdf.Cell(0, 10, tr(fmt.Sprintf("Fecha de emisión: %s", prescription.CreatedAt.Format("2006-01-02 15:04"))))
+	pdf.Ln(8)
+	if prescription.Doctor != nil {
+		pdf.Cell(0, 10, tr(fmt.Sprintf("Médico: %s %s", prescription.Doctor.Name, prescription.Doctor.LastName)))
+		pdf.Ln(8)
+	}
+	if prescription.Patient != nil {
+		pdf.Cell(0, 10, tr(fmt.Sprintf("Paciente: %s %s (DNI %s)", prescription.Patient.Name, prescription.Patient.LastName, prescription.Patient.DNI)))
+		pdf.Ln(8)
+	}
+	pdf.Ln(4)
+
+	// Medicamentos recetados
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(0, 10, "Medicamentos")
+	pdf.Ln(8)
+	for i, item := range prescription.Items {
+		pdf.SetFont("Arial", "B", 11)
+		pdf.Cell(0, 10, tr(fmt.Sprintf("%d. %s", i+1, item.Medication)))
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "", 11)
+		pdf.Cell(0, 10, tr(fmt.Sprintf("    Dosis: %s - Frecuencia: %s", item.Dose, item.Frequency)))
+		pdf.Ln(6)
+		if item.Duration != "" {
+			pdf.Cell(0, 10, tr(fmt.Sprintf("    Duración: %s", item.Duration)))
+			pdf.Ln(6)
+		}
+		if item.Instructions != "" {
+			pdf.MultiCell(0, 6, tr(fmt.Sprintf("    Indicaciones: %s", item.Instructions)), "", "", false)
+		}
+		pdf.Ln(2)
+	}
+	pdf.Ln(4)
+	if prescription.Notes != "" {
+		pdf.SetFont("Arial", "", 12)
+		pdf.MultiCell(0, 8, tr(fmt.Sprintf("Observaciones: %s", prescription.Notes)), "", "", false)
+		pdf.Ln(4)
+	}
+
+	// QR de verificación para la farmacia
+	qrPNG, err := qrcode.Encode(prescriptionVerifyURL(prescription.VerificationCode), qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	imgOpts := gofpdf.ImageOptions{ImageType: "PNG", ReadDpi: false}
+	pdf.RegisterImageOptionsReader("qr", imgOpts, bytes.NewReader(qrPNG))
+	pdf.Image("qr", 10, pdf.GetY(), 50, 50, false, "qr", 0, "")
+	pdf.SetY(pdf.GetY() + 52)
+	pdf.SetFont("Arial", "", 10)
+	pdf.Cell(0, 10, tr(fmt.Sprintf("Código de verificación: %s", prescription.VerificationCode)))
+
+	var buf bytes.Buffer
+	err = pdf.Output(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,146 @@
+package logic
+
+import (
+	"log"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
+)
+
+type PricingRuleLogic interface {
+	GetPricingRuleByID(ID uint) (*model.PricingRule, error)
+	GetAllPricingRules(limit, offset int) ([]model.PricingRule, error)
+	CreatePricingRule(rule *model.PricingRule) error
+	UpdatePricingRule(ID uint, rule *model.PricingRule) error
+	DeletePricingRule(ID uint) error
+}
+
+type pricingRuleLogic struct {
+	repositoryPricingRule repository.Repository[model.PricingRule]
+	repositoryPackageMain repository.PackageRepository
+}
+
+func NewPricingRuleLogic(repositoryPricingRule repository.Repository[model.PricingRule], repositoryPackageMain repository.PackageRepository) PricingRuleLogic {
+	return &pricingRuleLogic{repositoryPricingRule: repositoryPricingRule, repositoryPackageMain: repositoryPackageMain}
+}
+
+func (l *pricingRuleLogic) GetPricingRuleByID(ID uint) (*model.PricingRule, error) {
+	rule, err := l.repositoryPricingRule.GetByID(ID)
+	if err != nil {
+		log.Printf("pricing-logic: Error fetching pricing rule with ID %d: %v", ID, err)
+		return nil, response.ErrorPricingRuleNotFound
+	}
+
+	return rule, nil
+}
+
+func (l *pricingRuleLogic) GetAllPricingRules(limit, offset int) ([]model.PricingRule, error) {
+	rules, err := l.repositoryPricingRule.GetAll(limit, offset)
+	if err != nil {
+		log.Printf("pricing-logic: Error fetching pricing rules: %v", err)
+		return nil, response.ErrorPricingRulesNotFound
+	}
+
+	return rules, nil
+}
+
+func (l *pricingRuleLogic) CreatePricingRule(rule *model.PricingRule) error {
+	err := l.validatePricingRule(rule)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryPricingRule.Create(rule)
+	if err != nil {
+		log.Printf("pricing-logic: Error saving pricing rule: %v", err)
+		return response.ErrorToCreatedPricingRule
+	}
+
+	return nil
+}
+
+func (l *pricingRuleLogic) UpdatePricingRule(ID uint, rule *model.PricingRule) error {
+	ruleUpdate, err := l.GetPricingRuleByID(ID)
+	if err != nil {
+		return err
+	}
+
+	err = l.validatePricingRule(rule)
+	if err != nil {
+		return err
+	}
+
+	ruleUpdate.Name = rule.Name
+	ruleUpdate.Scope = rule.Scope
+	ruleUpdate.PackageID = rule.PackageID
+	ruleUpdate.ServiceCategory = rule.ServiceCategory
+	ruleUpdate.Percentage = rule.Percentage
+	ruleUpdate.ValidFrom = rule.ValidFrom
+	ruleUpdate.ValidTo = rule.ValidTo
+	ruleUpdate.Active = rule.Active
+
+	err = l.repositoryPricingRule.Update(ruleUpdate)
+	if err != nil {
+		log.Printf("pricing-logic: Error updating pricing rule with ID %d: %v", ID, err)
+		return response.ErrorToUpdatedPricingRule
+	}
+
+	return nil
+}
+
+func (l *pricingRuleLogic) DeletePricingRule(ID uint) error {
+	_, err := l.GetPricingRuleByID(ID)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryPricingRule.Delete(ID)
+	if err != nil {
+		log.Printf("pricing-logic: Error deleting pricing rule with ID %d: %v", ID, err)
+		return response.ErrorToDeletedPricingRule
+	}
+
+	return nil
+}
+
+func (l *pricingRuleLogic) validatePricingRule(rule *model.PricingRule) error {
+	switch rule.Scope {
+	case model.ScopePackage:
+		rule.ServiceCategory = ""
+		if rule.PackageID != nil {
+			_, err := l.repositoryPackageMain.GetByID(*rule.PackageID)
+			if err != nil {
+				return response.ErrorPackageNotFound
+			}
+		}
+	case model.ScopeServiceCategory:
+		rule.PackageID = nil
+		if rule.ServiceCategory == "" {
+			return response.ErrorPricingRuleCategoryRequired
+		}
+	default:
+		return response.ErrorInvalidPricingRuleScope
+	}
+
+	if rule.ValidFrom != "" {
+		_, err := validate.ParseDate(rule.ValidFrom)
+		if err != nil {
+			return err
+		}
+	}
+
+	if rule.ValidTo != "" {
+		_, err := validate.ParseDate(rule.ValidTo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if rule.ValidFrom != "" && rule.ValidTo != "" && rule.ValidFrom > rule.ValidTo {
+		return response.ErrorPricingRuleValidity
+	}
+
+	return nil
+}
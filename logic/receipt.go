@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+// Ruta pública que resuelve el QR impreso en el recibo
+const receiptVerifyPath = "/api/v1/verify/"
+
+// Token compacto del QR: datos del pago en JSON y su firma HMAC-SHA256, ambos en base64url y unidos por "."
+func signReceiptToken(payment *model.Payment) (string, error) {
+	claims := model.ReceiptClaims{
+		PaymentID:     payment.ID,
+		AppointmentID: payment.AppoimentID,
+		Amount:        payment.TotalAmount,
+		Currency:      payment.Currency,
+		PaidAt:        payment.CreatedAt.Unix(),
+		InvoiceNumber: payment.InvoiceNumber(),
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+
+	return payload + "." + receiptSignature(payload), nil
+}
+
+// Devuelve los datos del token solo si la firma es válida
+func parseReceiptToken(token string) (*model.ReceiptClaims, error) {
+	payload, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(receiptSignature(payload))) {
+		return nil, response.ErrorReceiptTokenInvalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, response.ErrorReceiptTokenInvalid
+	}
+
+	var claims model.ReceiptClaims
+
+	err = json.Unmarshal(data, &claims)
+	if err != nil {
+		return nil, response.ErrorReceiptTokenInvalid
+	}
+
+	return &claims, nil
+}
+
+func receiptSignature(payload string) string {
+	mac := hmac.New(sha256.New, []byte(config.Envs.ReceiptSecret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func receiptVerifyURL(token string) string {
+	return strings.TrimRight(config.Envs.PublicHost, "/") + receiptVerifyPath + token
+}
@@ -0,0 +1,169 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/jobs"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+)
+
+// Nombres de los trabajos en segundo plano de las citas
+const (
+	JobScheduleReminders = "programar_recordatorios"
+	JobSendReminder      = "enviar_recordatorio"
+	JobExpireBookings    = "vencer_citas_impagas"
+)
+
+// Horas antes de la cita en que se envían recordatorios, de mayor a menor
+var reminderHours = []int{24, 2}
+
+// Cita y momento exacto del recordatorio; si la cita se reprograma el recordatorio viejo se descarta
+type reminderPayload struct {
+	AppointmentID uint   `json:"appointment_id"`
+	HoursBefore   int    `json:"hours_before"`
+	StartsAt      string `json:"starts_at"`
+}
+
+type ReminderLogic interface {
+	ScheduleReminders() error
+	SendReminder(payload string) error
+	ExpireUnpaidBookings() error
+}
+
+type reminderLogic struct {
+	repositoryAppointmentMain repository.AppointmentRepository
+	logicNotification         NotificationLogic
+	runner                    *jobs.Runner
+}
+
+func NewReminderLogic(
+	repositoryAppointmentMain repository.AppointmentRepository,
+	logicNotification NotificationLogic,
+	runner *jobs.Runner,
+) ReminderLogic {
+	return &reminderLogic{
+		repositoryAppointmentMain: repositoryAppointmentMain,
+		logicNotification:         logicNotification,
+		runner:                    runner,
+	}
+}
+
+// Encola un recordatorio por cita y ventana; cada ventana usa la más cercana que ya se cumplió, así
+// una cita reservada con 1 hora de anticipación recibe solo el de 2 horas. La clave del trabajo
+// evita duplicados entre revisiones y entre instancias.
+func (l *reminderLogic) ScheduleReminders() error {
+	now := time.Now()
+
+	appointments, err := l.repositoryAppointmentMain.GetScheduledBetween(now, now.Add(time.Duration(reminderHours[0])*time.Hour))
+	if err != nil {
+		log.Printf("reminder: Error fetching upcoming appointments: %v", err)
+		return err
+	}
+
+	for _, appointment := range appointments {
+		startsAt, err := appointmentStart(&appointment)
+		if err != nil {
+			continue
+		}
+
+		untilStart := startsAt.Sub(now)
+		if untilStart <= 0 {
+			continue
+		}
+
+		hoursBefore := 0
+		for _, hours := range reminderHours {
+			if untilStart <= time.Duration(hours)*time.Hour {
+				hoursBefore = hours
+			}
+		}
+
+		if hoursBefore == 0 {
+			continue
+		}
+
+		payload := reminderPayload{
+			AppointmentID: appointment.ID,
+			HoursBefore:   hoursBefore,
+			StartsAt:      startsAt.Format(time.RFC3339),
+		}
+		key := fmt.Sprintf("recordatorio_%dh:%d:%s", hoursBefore, appointment.ID, startsAt.Format("200601021504"))
+
+		_, err = l.runner.Enqueue(JobSendReminder, key, payload, now)
+		if err != nil {
+			log.Printf("reminder: Error scheduling reminder for appointment ID %d: %v", appointment.ID, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *reminderLogic) SendReminder(payload string) error {
+	var reminder reminderPayload
+
+	err := json.Unmarshal([]byte(payload), &reminder)
+	if err != nil {
+		return err
+	}
+
+	appointment, err := l.repositoryAppointmentMain.GetByID(reminder.AppointmentID)
+	if err != nil {
+		log.Printf("reminder: Appointment ID %d no longer exists, reminder discarded", reminder.AppointmentID)
+		return nil
+	}
+
+	// La cita se canceló o se reprogramó después de encolar el recordatorio
+	startsAt, err := appointmentStart(appointment)
+	if err != nil || appointment.Status != model.AppointmentScheduled || startsAt.Format(time.RFC3339) != reminder.StartsAt {
+		return nil
+	}
+
+	return l.logicNotification.RemindAppointment(appointment, reminder.HoursBefore)
+}
+
+// Marca como vencidas las citas impagas cuyo inicio pasó hace más de STALE_BOOKING_HOURS
+func (l *reminderLogic) ExpireUnpaidBookings() error {
+	limit := time.Now().Add(-time.Duration(config.Envs.StaleBookingHours) * time.Hour)
+
+	appointments, err := l.repositoryAppointmentMain.GetUnpaidScheduledUntil(limit)
+	if err != nil {
+		log.Printf("reminder: Error fetching unpaid appointments: %v", err)
+		return err
+	}
+
+	expired := 0
+
+	for _, appointment := range appointments {
+		startsAt, err := appointmentStart(&appointment)
+		if err != nil || startsAt.After(limit) {
+			continue
+		}
+
+		updated, err := l.repositoryAppointmentMain.ExpireUnpaid(appointment.ID)
+		if err != nil {
+			log.Printf("reminder: Error expiring appointment ID %d: %v", appointment.ID, err)
+			return err
+		}
+
+		if updated {
+			expired++
+		}
+	}
+
+	if expired > 0 {
+		log.Printf("reminder: %d unpaid appointments marked as expired", expired)
+	}
+
+	return nil
+}
+
+// Fecha y hora de inicio de la cita en la zona horaria del servidor
+func appointmentStart(appointment *model.Appointment) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02 15:04", appointment.Date+" "+appointment.StartTime, time.Local)
+}
@@ -1,7 +1,9 @@
 package logic
 
 import (
+	"errors"
 	"log"
+	"strings"
 
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
@@ -10,19 +12,36 @@ import (
 
 type ServiceLogic interface {
 	GetServiceByID(ID uint) (*model.Service, error)
-	GetAllServices(limit, offset int) ([]model.Service, error)
+	GetAllServices(category string, onlyActive bool, limit, offset int) ([]model.Service, error)
 	CreateService(service *model.Service) error
 	UpdateService(ID uint, service *model.Service) error
+	UpdateServiceStatus(ID uint, active bool) (*model.Service, error)
 	DeleteService(ID uint) error
+	GetPriceHistory(ID uint) ([]model.PriceHistory, error)
 }
 
 type serviceLogic struct {
-	repository        repository.Repository[model.Service]
-	repositoryService repository.ServiceRepository
+	repository          repository.Repository[model.Service]
+	repositoryService   repository.ServiceRepository
+	repositoryHistory   repository.PriceHistoryRepository
+	repositoryCategory  repository.ServiceCategoryRepository
+	repositorySpecialty repository.SpecialtyRepository
 }
 
-func NewServiceLogic(repository repository.Repository[model.Service], repositoryService repository.ServiceRepository) ServiceLogic {
-	return &serviceLogic{repository: repository, repositoryService: repositoryService}
+func NewServiceLogic(
+	repository repository.Repository[model.Service],
+	repositoryService repository.ServiceRepository,
+	repositoryHistory repository.PriceHistoryRepository,
+	repositoryCategory repository.ServiceCategoryRepository,
+	repositorySpecialty repository.SpecialtyRepository,
+) ServiceLogic {
+	return &serviceLogic{
+		repository:          repository,
+		repositoryService:   repositoryService,
+		repositoryHistory:   repositoryHistory,
+		repositoryCategory:  repositoryCategory,
+		repositorySpecialty: repositorySpecialty,
+	}
 }
 
 func (l *serviceLogic) GetServiceByID(ID uint) (*model.Service, error) {
@@ -35,8 +54,9 @@ func (l *serviceLogic) GetServiceByID(ID uint) (*model.Service, error) {
 	return service, nil
 }
 
-func (l *serviceLogic) GetAllServices(limit, offset int) ([]model.Service, error) {
-	services, err := l.repository.GetAll(limit, offset)
+// Admite filtrar por categoría y listar solo los servicios activos, que son los que se pueden reservar
+func (l *serviceLogic) GetAllServices(category string, onlyActive bool, limit, offset int) ([]model.Service, error) {
+	services, err := l.repositoryService.GetAllServices(strings.TrimSpace(category), onlyActive, limit, offset)
 	if err != nil {
 		log.Printf("service-logic: Error fetching services: %v", err)
 		return nil, response.ErrorServiceNotFound
@@ -45,14 +65,30 @@ func (l *serviceLogic) GetAllServices(limit, offset int) ([]model.Service, error
 	return services, nil
 }
 
+// Los servicios se crean activos; la categoría debe existir en el catálogo de categorías
 func (l *serviceLogic) CreateService(service *model.Service) error {
-	err := l.repository.Create(service)
+	category, err := l.resolveCategory(service.Category)
+	if err != nil {
+		return err
+	}
+
+	err = l.checkSpecialty(service.SpecialtyID)
+	if err != nil {
+		return err
+	}
+
+	service.Category = category
+	service.Specialty = nil
+	service.Active = true
+	service.Currency = NormalizeCurrency(service.Currency)
+
+	err = l.repository.Create(service)
 	if err != nil {
 		log.Printf("service-logic: Error saving medical service: %v", err)
 		return response.ErrorToCreatedService
 	}
 
-	return nil
+	return recordPrice(l.repositoryHistory, servicePriceEntry(service))
 }
 
 func (l *serviceLogic) UpdateService(ID uint, service *model.Service) error {
@@ -62,9 +98,22 @@ func (l *serviceLogic) UpdateService(ID uint, service *model.Service) error {
 		return response.ErrorServiceNotFound
 	}
 
+	category, err := l.resolveCategory(service.Category)
+	if err != nil {
+		return err
+	}
+
+	err = l.checkSpecialty(service.SpecialtyID)
+	if err != nil {
+		return err
+	}
+
 	serviceUpdate.Name = service.Name
 	serviceUpdate.Description = service.Description
+	serviceUpdate.Category = category
+	serviceUpdate.SpecialtyID = service.SpecialtyID
 	serviceUpdate.Price = service.Price
+	serviceUpdate.Currency = NormalizeCurrency(service.Currency)
 
 	err = l.repository.Update(serviceUpdate)
 	if err != nil {
@@ -72,9 +121,28 @@ func (l *serviceLogic) UpdateService(ID uint, service *model.Service) error {
 		return response.ErrorToUpdatedService
 	}
 
-	return nil
+	return recordPrice(l.repositoryHistory, servicePriceEntry(serviceUpdate))
+}
+
+// Retira o reactiva el servicio; el servicio retirado ya no se puede reservar
+func (l *serviceLogic) UpdateServiceStatus(ID uint, active bool) (*model.Service, error) {
+	service, err := l.GetServiceByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	service.Active = active
+
+	err = l.repository.Update(service)
+	if err != nil {
+		log.Printf("service-logic: Error updating status of medical service with ID %d: %v", ID, err)
+		return nil, response.ErrorToUpdatedService
+	}
+
+	return service, nil
 }
 
+// Un servicio ya reservado no se elimina para conservar el historial de citas; debe desactivarse
 func (l *serviceLogic) DeleteService(ID uint) error {
 	_, err := l.repository.GetByID(ID)
 	if err != nil {
@@ -82,6 +150,16 @@ func (l *serviceLogic) DeleteService(ID uint) error {
 		return response.ErrorServiceNotFound
 	}
 
+	count, err := l.repositoryService.CountAppointments(ID)
+	if err != nil {
+		log.Printf("service-logic: Error counting appointments of service with ID %d: %v", ID, err)
+		return response.ErrorToDeletedService
+	}
+
+	if count > 0 {
+		return response.ErrorServiceInUse
+	}
+
 	err = l.repositoryService.Delete(ID)
 	if err != nil {
 		log.Printf("services-logic: Error deleting customer with ID %d: %v", ID, err)
@@ -90,3 +168,56 @@ func (l *serviceLogic) DeleteService(ID uint) error {
 
 	return nil
 }
+
+func (l *serviceLogic) GetPriceHistory(ID uint) ([]model.PriceHistory, error) {
+	_, err := l.GetServiceByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return getPriceHistory(l.repositoryHistory, model.CatalogService, ID)
+}
+
+// Devuelve el nombre registrado de la categoría indicada; sin categoría el servicio queda sin categorizar
+func (l *serviceLogic) resolveCategory(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+
+	category, err := l.repositoryCategory.GetByName(name)
+	if err != nil {
+		if !errors.Is(err, response.ErrorServiceCategoryNotFound) {
+			log.Printf("service-logic: Error fetching service category %s: %v", name, err)
+		}
+
+		return "", response.ErrorServiceCategoryNotFound
+	}
+
+	return category.Name, nil
+}
+
+// Sin especialidad el servicio lo puede realizar cualquier médico
+func (l *serviceLogic) checkSpecialty(specialtyID *uint) error {
+	if specialtyID == nil {
+		return nil
+	}
+
+	_, err := l.repositorySpecialty.GetByIDs([]uint{*specialtyID})
+	if err != nil {
+		log.Printf("service-logic: Error fetching specialty with ID %d: %v", *specialtyID, err)
+		return response.ErrorSpecialtyNotFound
+	}
+
+	return nil
+}
+
+func servicePriceEntry(service *model.Service) model.PriceHistory {
+	return model.PriceHistory{
+		ItemType: model.CatalogService,
+		ItemID:   service.ID,
+		Name:     service.Name,
+		Price:    service.Price,
+		Currency: service.Currency,
+	}
+}
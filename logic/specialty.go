@@ -0,0 +1,158 @@
+package logic
+
+import (
+	"log"
+	"strings"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+type SpecialtyLogic interface {
+	GetSpecialtyByID(ID uint) (*model.Specialty, error)
+	GetAllSpecialties(limit, offset int) ([]model.Specialty, error)
+	CreateSpecialty(specialty *model.Specialty) error
+	UpdateSpecialty(ID uint, specialty *model.Specialty) error
+	DeleteSpecialty(ID uint) error
+	MergeSpecialty(ID uint, request *model.MergeSpecialtyRequest) (*model.Specialty, error)
+}
+
+type specialtyLogic struct {
+	repositorySpecialty     repository.Repository[model.Specialty]
+	repositorySpecialtyMain repository.SpecialtyRepository
+}
+
+func NewSpecialtyLogic(repositorySpecialty repository.Repository[model.Specialty], repositorySpecialtyMain repository.SpecialtyRepository) SpecialtyLogic {
+	return &specialtyLogic{repositorySpecialty: repositorySpecialty, repositorySpecialtyMain: repositorySpecialtyMain}
+}
+
+func (l *specialtyLogic) GetSpecialtyByID(ID uint) (*model.Specialty, error) {
+	specialty, err := l.repositorySpecialty.GetByID(ID)
+	if err != nil {
+		log.Printf("specialty-logic: Error fetching specialty with ID %d: %v", ID, err)
+		return nil, response.ErrorSpecialtyNotFound
+	}
+
+	return specialty, nil
+}
+
+func (l *specialtyLogic) GetAllSpecialties(limit, offset int) ([]model.Specialty, error) {
+	specialties, err := l.repositorySpecialty.GetAll(limit, offset)
+	if err != nil {
+		log.Printf("specialty-logic: Error fetching specialties: %v", err)
+		return nil, response.ErrorSpecialtiesNotFound
+	}
+
+	return specialties, nil
+}
+
+func (l *specialtyLogic) CreateSpecialty(specialty *model.Specialty) error {
+	specialty.Name = strings.TrimSpace(specialty.Name)
+
+	err := l.checkNameAvailable(specialty.Name, 0)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositorySpecialty.Create(specialty)
+	if err != nil {
+		log.Printf("specialty-logic: Error saving specialty: %v", err)
+		return response.ErrorToCreatedSpecialty
+	}
+
+	return nil
+}
+
+func (l *specialtyLogic) UpdateSpecialty(ID uint, specialty *model.Specialty) error {
+	specialtyUpdate, err := l.GetSpecialtyByID(ID)
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSpace(specialty.Name)
+
+	err = l.checkNameAvailable(name, ID)
+	if err != nil {
+		return err
+	}
+
+	specialtyUpdate.Name = name
+
+	err = l.repositorySpecialty.Update(specialtyUpdate)
+	if err != nil {
+		log.Printf("specialty-logic: Error updating specialty with ID %d: %v", ID, err)
+		return response.ErrorToUpdatedSpecialty
+	}
+
+	return nil
+}
+
+func (l *specialtyLogic) DeleteSpecialty(ID uint) error {
+	_, err := l.GetSpecialtyByID(ID)
+	if err != nil {
+		return err
+	}
+
+	count, err := l.repositorySpecialtyMain.CountReferences(ID)
+	if err != nil {
+		log.Printf("specialty-logic: Error counting references of specialty with ID %d: %v", ID, err)
+		return response.ErrorToDeletedSpecialty
+	}
+
+	if count > 0 {
+		return response.ErrorSpecialtyInUse
+	}
+
+	err = l.repositorySpecialty.Delete(ID)
+	if err != nil {
+		log.Printf("specialty-logic: Error deleting specialty with ID %d: %v", ID, err)
+		return response.ErrorToDeletedSpecialty
+	}
+
+	return nil
+}
+
+// Une una especialidad duplicada con otro nombre (p. ej. "Cardio" en "Cardiología"): sus médicos y
+// servicios pasan a la de destino y la duplicada se elimina
+func (l *specialtyLogic) MergeSpecialty(ID uint, request *model.MergeSpecialtyRequest) (*model.Specialty, error) {
+	if ID == request.TargetID {
+		return nil, response.ErrorSpecialtyMergeSame
+	}
+
+	_, err := l.GetSpecialtyByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := l.GetSpecialtyByID(request.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.repositorySpecialtyMain.Merge(ID, target.ID)
+	if err != nil {
+		log.Printf("specialty-logic: Error merging specialty with ID %d into ID %d: %v", ID, target.ID, err)
+		return nil, response.ErrorToMergeSpecialty
+	}
+
+	return target, nil
+}
+
+// Dos especialidades no pueden diferir solo en mayúsculas, tildes o espacios
+func (l *specialtyLogic) checkNameAvailable(name string, ID uint) error {
+	specialties, err := l.repositorySpecialtyMain.GetAll()
+	if err != nil {
+		log.Printf("specialty-logic: Error fetching specialties: %v", err)
+		return response.ErrorSpecialtiesNotFound
+	}
+
+	key := model.SpecialtyKey(name)
+	for _, specialty := range specialties {
+		if specialty.ID != ID && model.SpecialtyKey(specialty.Name) == key {
+			return response.ErrorSpecialtyExists
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+)
+
+type fakeSpecialtyStore struct {
+	repository.Repository[model.Specialty]
+	specialties map[uint]model.Specialty
+}
+
+func (r *fakeSpecialtyStore) GetByID(ID uint) (*model.Specialty, error) {
+	specialty, exists := r.specialties[ID]
+	if !exists {
+		return nil, errors.New("record not found")
+	}
+
+	return &specialty, nil
+}
+
+type fakeSpecialtyRepository struct {
+	repository.SpecialtyRepository
+	merged [][2]uint
+}
+
+func (r *fakeSpecialtyRepository) Merge(sourceID, targetID uint) error {
+	r.merged = append(r.merged, [2]uint{sourceID, targetID})
+	return nil
+}
+
+func TestMergeSpecialty(t *testing.T) {
+	specialties := map[uint]model.Specialty{
+		1: {ID: 1, Name: "Cardiología"},
+		2: {ID: 2, Name: "Cardio"},
+	}
+
+	tests := []struct {
+		name     string
+		ID       uint
+		targetID uint
+		err      error
+	}{
+		{name: "une el nombre abreviado en el completo", ID: 2, targetID: 1},
+		{name: "consigo misma", ID: 1, targetID: 1, err: response.ErrorSpecialtyMergeSame},
+		{name: "origen inexistente", ID: 9, targetID: 1, err: response.ErrorSpecialtyNotFound},
+		{name: "destino inexistente", ID: 2, targetID: 9, err: response.ErrorSpecialtyNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repositoryMain := &fakeSpecialtyRepository{}
+			logic := NewSpecialtyLogic(&fakeSpecialtyStore{specialties: specialties}, repositoryMain)
+
+			target, err := logic.MergeSpecialty(test.ID, &model.MergeSpecialtyRequest{TargetID: test.targetID})
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("expected error %v, got %v", test.err, err)
+				}
+
+				if len(repositoryMain.merged) != 0 {
+					t.Errorf("merged %v after a rejected request", repositoryMain.merged)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if target.Name != "Cardiología" {
+				t.Errorf("kept %q, want Cardiología", target.Name)
+			}
+
+			if len(repositoryMain.merged) != 1 || repositoryMain.merged[0] != [2]uint{2, 1} {
+				t.Errorf("merged %v, want [[2 1]]", repositoryMain.merged)
+			}
+		})
+	}
+}
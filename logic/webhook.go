@@ -0,0 +1,369 @@
+package logic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/events"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/jobs"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/webhook"
+)
+
+// Trabajo que envía un evento a una suscripción
+const JobSendWebhook = "enviar_webhook"
+
+// Reintentos de cada envío: 1, 2, 4, 8, 16, 32 y 64 minutos después de cada fallo
+var webhookRetryPolicy = jobs.RetryPolicy{
+	MaxAttempts: 8,
+	Backoff:     jobs.ExponentialBackoff(time.Minute, 2*time.Hour),
+}
+
+type webhookJob struct {
+	SubscriptionID uint `json:"subscription_id"`
+	EventID        uint `json:"event_id"`
+}
+
+type WebhookLogic interface {
+	GetWebhookByID(ID uint) (*model.WebhookSubscription, error)
+	GetAllWebhooks(limit, offset int) ([]model.WebhookSubscription, error)
+	CreateWebhook(request *model.WebhookRequest) (*model.WebhookSubscription, error)
+	UpdateWebhook(ID uint, request *model.WebhookRequest) (*model.WebhookSubscription, error)
+	DeleteWebhook(ID uint) error
+	GetDeliveries(ID uint, limit, offset int) ([]model.WebhookDelivery, error)
+	ReplayDelivery(deliveryID uint) (*model.WebhookDelivery, error)
+	SendWebhook(payload string) error
+}
+
+type webhookLogic struct {
+	repositoryWebhook     repository.Repository[model.WebhookSubscription]
+	repositoryWebhookMain repository.WebhookRepository
+	repositoryOutbox      repository.OutboxRepository
+	sender                *webhook.Sender
+	runner                *jobs.Runner
+}
+
+// Registra el envío de webhooks en el runner y lo suscribe a todos los eventos del dominio
+func NewWebhookLogic(
+	repositoryWebhook repository.Repository[model.WebhookSubscription],
+	repositoryWebhookMain repository.WebhookRepository,
+	repositoryOutbox repository.OutboxRepository,
+	sender *webhook.Sender,
+	runner *jobs.Runner,
+	dispatcher *events.Dispatcher,
+) WebhookLogic {
+	l := &webhookLogic{
+		repositoryWebhook:     repositoryWebhook,
+		repositoryWebhookMain: repositoryWebhookMain,
+		repositoryOutbox:      repositoryOutbox,
+		sender:                sender,
+		runner:                runner,
+	}
+
+	runner.SetRetryPolicy(JobSendWebhook, webhookRetryPolicy)
+	runner.Handle(JobSendWebhook, l.SendWebhook)
+
+	for _, eventType := range model.WebhookEvents {
+		dispatcher.Subscribe(eventType, "webhooks", l.enqueueDeliveries)
+	}
+
+	return l
+}
+
+// El secreto no se muestra al consultar
+func (l *webhookLogic) GetWebhookByID(ID uint) (*model.WebhookSubscription, error) {
+	subscription, err := l.repositoryWebhookMain.GetByID(ID)
+	if err != nil {
+		log.Printf("webhook-logic: Error fetching webhook with ID %d: %v", ID, err)
+		return nil, response.ErrorWebhookNotFound
+	}
+
+	subscription.Secret = ""
+
+	return subscription, nil
+}
+
+func (l *webhookLogic) GetAllWebhooks(limit, offset int) ([]model.WebhookSubscription, error) {
+	subscriptions, err := l.repositoryWebhookMain.GetAll(limit, offset)
+	if err != nil {
+		log.Printf("webhook-logic: Error fetching webhooks: %v", err)
+		return nil, response.ErrorWebhooksNotFound
+	}
+
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+// Devuelve la suscripción con su secreto, la única vez que se muestra
+func (l *webhookLogic) CreateWebhook(request *model.WebhookRequest) (*model.WebhookSubscription, error) {
+	err := validateWebhookRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		secret, err = webhook.NewSecret()
+		if err != nil {
+			log.Printf("webhook-logic: Error generating webhook secret: %v", err)
+			return nil, response.ErrorToCreatedWebhook
+		}
+	}
+
+	subscription := model.WebhookSubscription{
+		Name:   request.Name,
+		URL:    request.URL,
+		Events: request.Events,
+		Secret: secret,
+		Active: request.Active,
+	}
+
+	err = l.repositoryWebhook.Create(&subscription)
+	if err != nil {
+		log.Printf("webhook-logic: Error saving webhook: %v", err)
+		return nil, response.ErrorToCreatedWebhook
+	}
+
+	return &subscription, nil
+}
+
+// El secreto solo cambia si se envía uno nuevo, y solo en ese caso se devuelve
+func (l *webhookLogic) UpdateWebhook(ID uint, request *model.WebhookRequest) (*model.WebhookSubscription, error) {
+	subscription, err := l.repositoryWebhookMain.GetByID(ID)
+	if err != nil {
+		log.Printf("webhook-logic: Error fetching webhook with ID %d: %v", ID, err)
+		return nil, response.ErrorWebhookNotFound
+	}
+
+	err = validateWebhookRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Name = request.Name
+	subscription.URL = request.URL
+	subscription.Events = request.Events
+	subscription.Active = request.Active
+
+	if request.Secret != "" {
+		subscription.Secret = request.Secret
+	}
+
+	err = l.repositoryWebhook.Update(subscription)
+	if err != nil {
+		log.Printf("webhook-logic: Error updating webhook with ID %d: %v", ID, err)
+		return nil, response.ErrorToUpdatedWebhook
+	}
+
+	if request.Secret == "" {
+		subscription.Secret = ""
+	}
+
+	return subscription, nil
+}
+
+// Los envíos registrados se conservan; los pendientes se descartan al no encontrar la suscripción
+func (l *webhookLogic) DeleteWebhook(ID uint) error {
+	_, err := l.GetWebhookByID(ID)
+	if err != nil {
+		return err
+	}
+
+	err = l.repositoryWebhook.Delete(ID)
+	if err != nil {
+		log.Printf("webhook-logic: Error deleting webhook with ID %d: %v", ID, err)
+		return response.ErrorToDeletedWebhook
+	}
+
+	return nil
+}
+
+func (l *webhookLogic) GetDeliveries(ID uint, limit, offset int) ([]model.WebhookDelivery, error) {
+	_, err := l.GetWebhookByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := l.repositoryWebhookMain.GetDeliveries(ID, limit, offset)
+	if err != nil {
+		log.Printf("webhook-logic: Error fetching deliveries of webhook ID %d: %v", ID, err)
+		return nil, response.ErrorFetchingWebhookDeliveries
+	}
+
+	return deliveries, nil
+}
+
+// Vuelve a enviar en el momento el evento de un envío registrado, p. ej. después de que el receptor
+// corrigió un error, aunque la suscripción esté desactivada. Devuelve el nuevo intento; que el
+// receptor falle no es un error del reenvío.
+func (l *webhookLogic) ReplayDelivery(deliveryID uint) (*model.WebhookDelivery, error) {
+	previous, err := l.repositoryWebhookMain.GetDelivery(deliveryID)
+	if err != nil {
+		log.Printf("webhook-logic: Error fetching webhook delivery with ID %d: %v", deliveryID, err)
+		return nil, response.ErrorWebhookDeliveryNotFound
+	}
+
+	subscription, err := l.repositoryWebhookMain.GetByID(previous.SubscriptionID)
+	if err != nil {
+		log.Printf("webhook-logic: Error fetching webhook with ID %d: %v", previous.SubscriptionID, err)
+		return nil, response.ErrorWebhookNotFound
+	}
+
+	event, err := l.repositoryOutbox.GetByID(previous.EventID)
+	if err != nil {
+		log.Printf("webhook-logic: Error fetching event with ID %d: %v", previous.EventID, err)
+		return nil, response.ErrorWebhookEventNotFound
+	}
+
+	delivery, err := l.deliver(subscription, event, true)
+	if err != nil {
+		return nil, response.ErrorToReplayWebhook
+	}
+
+	return delivery, nil
+}
+
+// Handler de JobSendWebhook; un fallo del receptor se devuelve para que el runner lo reintente
+func (l *webhookLogic) SendWebhook(payload string) error {
+	var job webhookJob
+
+	err := json.Unmarshal([]byte(payload), &job)
+	if err != nil {
+		return err
+	}
+
+	subscription, err := l.repositoryWebhookMain.GetByID(job.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, response.ErrorWebhookNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	// La suscripción se desactivó mientras el envío esperaba su turno
+	if !subscription.Active {
+		return nil
+	}
+
+	event, err := l.repositoryOutbox.GetByID(job.EventID)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := l.deliver(subscription, event, false)
+	if err != nil {
+		return err
+	}
+
+	if delivery.Status == model.WebhookFailed {
+		return errors.New(delivery.Error)
+	}
+
+	return nil
+}
+
+// Suscriptor del outbox: encola un envío por cada suscripción activa al evento
+func (l *webhookLogic) enqueueDeliveries(event *model.OutboxEvent) error {
+	subscriptions, err := l.repositoryWebhookMain.GetActive()
+	if err != nil {
+		log.Printf("webhook-logic: Error fetching active webhooks: %v", err)
+		return err
+	}
+
+	now := time.Now()
+
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribed(event.EventType) {
+			continue
+		}
+
+		key := fmt.Sprintf("webhook:%d:%d", event.ID, subscription.ID)
+
+		_, err := l.runner.Enqueue(JobSendWebhook, key, webhookJob{SubscriptionID: subscription.ID, EventID: event.ID}, now)
+		if err != nil {
+			log.Printf("webhook-logic: Error enqueuing event ID %d for webhook ID %d: %v", event.ID, subscription.ID, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Envía el evento y registra el intento; un fallo del receptor queda en el registro, el error
+// devuelto es solo el de la base de datos
+func (l *webhookLogic) deliver(subscription *model.WebhookSubscription, event *model.OutboxEvent, replay bool) (*model.WebhookDelivery, error) {
+	attempts, err := l.repositoryWebhookMain.CountDeliveries(subscription.ID, event.ID)
+	if err != nil {
+		log.Printf("webhook-logic: Error counting deliveries of event ID %d for webhook ID %d: %v", event.ID, subscription.ID, err)
+		return nil, err
+	}
+
+	result, sendErr := l.sender.Send(subscription.URL, subscription.Secret, &webhook.Payload{
+		ID:        event.ID,
+		Type:      string(event.EventType),
+		CreatedAt: event.CreatedAt,
+		Data:      json.RawMessage(event.Payload),
+	})
+
+	delivery := model.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.EventType,
+		URL:            subscription.URL,
+		Attempt:        int(attempts) + 1,
+		Replay:         replay,
+		Status:         model.WebhookDelivered,
+	}
+
+	if result != nil {
+		delivery.StatusCode = result.StatusCode
+		delivery.ResponseBody = limitText(result.Body, 500)
+		delivery.DurationMs = result.Duration.Milliseconds()
+	}
+
+	if sendErr != nil {
+		log.Printf("webhook-logic: Error sending event ID %d to webhook ID %d: %v", event.ID, subscription.ID, sendErr)
+		delivery.Status = model.WebhookFailed
+		delivery.Error = limitText(sendErr.Error(), 255)
+	}
+
+	err = l.repositoryWebhookMain.CreateDelivery(&delivery)
+	if err != nil {
+		log.Printf("webhook-logic: Error saving delivery of event ID %d for webhook ID %d: %v", event.ID, subscription.ID, err)
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func validateWebhookRequest(request *model.WebhookRequest) error {
+	target, err := url.Parse(request.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return response.ErrorInvalidWebhookURL
+	}
+
+	for _, event := range request.Events {
+		valid := false
+		for _, webhookEvent := range model.WebhookEvents {
+			if event == webhookEvent {
+				valid = true
+			}
+		}
+
+		if !valid {
+			return response.ErrorInvalidWebhookEvent
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package logic
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/events"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/jobs"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/webhook"
+)
+
+type fakeJobRepository struct {
+	repository.JobRepository
+	jobs []model.Job
+}
+
+func (r *fakeJobRepository) Enqueue(job *model.Job) (bool, error) {
+	r.jobs = append(r.jobs, *job)
+	return true, nil
+}
+
+type fakeOutboxRepository struct {
+	repository.OutboxRepository
+	events map[uint]*model.OutboxEvent
+}
+
+func (r *fakeOutboxRepository) GetByID(ID uint) (*model.OutboxEvent, error) {
+	return r.events[ID], nil
+}
+
+type fakeWebhookRepository struct {
+	repository.WebhookRepository
+	subscriptions []model.WebhookSubscription
+	deliveries    []model.WebhookDelivery
+}
+
+func (r *fakeWebhookRepository) GetByID(ID uint) (*model.WebhookSubscription, error) {
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == ID {
+			subscription := r.subscriptions[i]
+			return &subscription, nil
+		}
+	}
+
+	return nil, response.ErrorWebhookNotFound
+}
+
+func (r *fakeWebhookRepository) GetActive() ([]model.WebhookSubscription, error) {
+	active := []model.WebhookSubscription{}
+	for _, subscription := range r.subscriptions {
+		if subscription.Active {
+			active = append(active, subscription)
+		}
+	}
+
+	return active, nil
+}
+
+func (r *fakeWebhookRepository) CreateDelivery(delivery *model.WebhookDelivery) error {
+	delivery.ID = uint(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+func (r *fakeWebhookRepository) CountDeliveries(subscriptionID, eventID uint) (int64, error) {
+	var count int64
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.EventID == eventID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Receptor que guarda los envíos y responde con status
+type webhookReceiver struct {
+	mu     sync.Mutex
+	status int
+	bodies []string
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !webhook.Verify("whsec_0123456789abcdef", req.Header.Get(webhook.HeaderTimestamp), body, req.Header.Get(webhook.HeaderSignature)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	r.bodies = append(r.bodies, string(body))
+	w.WriteHeader(r.status)
+}
+
+func newTestWebhookLogic(t *testing.T, url string, outboxEvents ...*model.OutboxEvent) (*webhookLogic, *fakeWebhookRepository, *fakeJobRepository) {
+	t.Helper()
+
+	webhooks := &fakeWebhookRepository{subscriptions: []model.WebhookSubscription{
+		{ID: 1, Name: "laboratorio", URL: url, Secret: "whsec_0123456789abcdef", Active: true,
+			Events: []model.EventType{model.EventPaymentRegistered, model.EventPatientUpdated}},
+		{ID: 2, Name: "contabilidad", URL: url, Secret: "whsec_0123456789abcdef", Active: true,
+			Events: []model.EventType{model.EventAppointmentBooked}},
+		{ID: 3, Name: "inactivo", URL: url, Secret: "whsec_0123456789abcdef", Active: false,
+			Events: []model.EventType{model.EventPaymentRegistered}},
+	}}
+
+	outbox := &fakeOutboxRepository{events: map[uint]*model.OutboxEvent{}}
+	for _, event := range outboxEvents {
+		outbox.events[event.ID] = event
+	}
+
+	jobRepository := &fakeJobRepository{}
+	runner := jobs.NewRunner(jobRepository, time.Second)
+	dispatcher := events.NewDispatcher(outbox, runner, time.Second)
+
+	logic := NewWebhookLogic(nil, webhooks, outbox, webhook.NewSender(time.Second), runner, dispatcher)
+
+	return logic.(*webhookLogic), webhooks, jobRepository
+}
+
+func TestWebhookRetryPolicy(t *testing.T) {
+	if webhookRetryPolicy.MaxAttempts != 8 {
+		t.Errorf("MaxAttempts = %d, want 8", webhookRetryPolicy.MaxAttempts)
+	}
+
+	want := []time.Duration{
+		time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute,
+		16 * time.Minute, 32 * time.Minute, 64 * time.Minute, 2 * time.Hour,
+	}
+
+	for i, wait := range want {
+		if got := webhookRetryPolicy.Backoff(i + 1); got != wait {
+			t.Errorf("backoff after attempt %d = %s, want %s", i+1, got, wait)
+		}
+	}
+}
+
+// Solo las suscripciones activas al evento reciben un envío, y cada envío se encola con 8 intentos
+func TestEnqueueDeliveries(t *testing.T) {
+	event := &model.OutboxEvent{ID: 10, EventType: model.EventPaymentRegistered, Payload: `{"payment_id":7}`}
+	logic, _, jobRepository := newTestWebhookLogic(t, "http://127.0.0.1:0", event)
+
+	err := logic.enqueueDeliveries(event)
+	if err != nil {
+		t.Fatalf("enqueueDeliveries: %v", err)
+	}
+
+	var sends []model.Job
+	for _, job := range jobRepository.jobs {
+		if job.Name == JobSendWebhook {
+			sends = append(sends, job)
+		}
+	}
+
+	if len(sends) != 1 {
+		t.Fatalf("enqueued %d webhook jobs, want 1: %+v", len(sends), sends)
+	}
+
+	if sends[0].Key != "webhook:10:1" || sends[0].MaxAttempts != 8 {
+		t.Errorf("job key %q with %d attempts, want webhook:10:1 with 8", sends[0].Key, sends[0].MaxAttempts)
+	}
+
+	if sends[0].Payload != `{"subscription_id":1,"event_id":10}` {
+		t.Errorf("job payload = %s", sends[0].Payload)
+	}
+}
+
+// Cada intento fallido queda registrado con su número y se devuelve error para que el runner reintente
+func TestSendWebhookRecordsAttempts(t *testing.T) {
+	receiver := &webhookReceiver{status: http.StatusInternalServerError}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	event := &model.OutboxEvent{ID: 10, EventType: model.EventPaymentRegistered, Payload: `{"payment_id":7}`}
+	logic, webhooks, _ := newTestWebhookLogic(t, server.URL, event)
+
+	payload := `{"subscription_id":1,"event_id":10}`
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		err := logic.SendWebhook(payload)
+		if err == nil || !strings.Contains(err.Error(), "500") {
+			t.Fatalf("attempt %d: got %v, want the receiver error", attempt, err)
+		}
+	}
+
+	receiver.status = http.StatusOK
+
+	err := logic.SendWebhook(payload)
+	if err != nil {
+		t.Fatalf("delivery after the receiver recovered: %v", err)
+	}
+
+	if len(webhooks.deliveries) != 4 {
+		t.Fatalf("recorded %d deliveries, want 4", len(webhooks.deliveries))
+	}
+
+	for i, delivery := range webhooks.deliveries {
+		wantStatus := model.WebhookFailed
+		if i == 3 {
+			wantStatus = model.WebhookDelivered
+		}
+
+		if delivery.Attempt != i+1 || delivery.Status != wantStatus || delivery.Replay {
+			t.Errorf("delivery %d = attempt %d %s (replay %t), want attempt %d %s", i, delivery.Attempt, delivery.Status, delivery.Replay, i+1, wantStatus)
+		}
+	}
+
+	var body webhook.Payload
+	err = json.Unmarshal([]byte(receiver.bodies[3]), &body)
+	if err != nil || body.ID != 10 || body.Type != string(model.EventPaymentRegistered) || string(body.Data) != `{"payment_id":7}` {
+		t.Errorf("unexpected body %s (%v)", receiver.bodies[3], err)
+	}
+}
+
+// Un envío pendiente de una suscripción eliminada o desactivada se descarta sin reintentos
+func TestSendWebhookSkipsRemovedSubscriptions(t *testing.T) {
+	logic, webhooks, _ := newTestWebhookLogic(t, "http://127.0.0.1:0")
+
+	for _, payload := range []string{`{"subscription_id":99,"event_id":10}`, `{"subscription_id":3,"event_id":10}`} {
+		err := logic.SendWebhook(payload)
+		if err != nil {
+			t.Errorf("SendWebhook(%s) = %v, want nil", payload, err)
+		}
+	}
+
+	if len(webhooks.deliveries) != 0 {
+		t.Errorf("recorded %d deliveries, want none", len(webhooks.deliveries))
+	}
+}
+
+// PatientUpdated sale hacia los webhooks solo con el ID del paciente, sin sus datos personales
+func TestWebhookPatientUpdatedSendsOnlyTheID(t *testing.T) {
+	receiver := &webhookReceiver{status: http.StatusOK}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	payload, err := json.Marshal(model.NewPatientEvent(&model.Patient{Person: model.Person{ID: 3, Name: "María", DNI: "12345678", PhoneNumber: "999888777", Address: "Av. Lima 123"}}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	event := &model.OutboxEvent{ID: 11, EventType: model.EventPatientUpdated, Payload: string(payload)}
+	logic, _, _ := newTestWebhookLogic(t, server.URL, event)
+
+	err = logic.SendWebhook(`{"subscription_id":1,"event_id":11}`)
+	if err != nil {
+		t.Fatalf("SendWebhook: %v", err)
+	}
+
+	var body webhook.Payload
+	err = json.Unmarshal([]byte(receiver.bodies[0]), &body)
+	if err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+
+	if string(body.Data) != `{"patient_id":3}` {
+		t.Errorf("data = %s, want only the patient ID", body.Data)
+	}
+}
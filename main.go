@@ -19,9 +19,16 @@ func main() {
 
 	// Inicializar la configuración cargando las variables de entorno
 	cfg := config.InitConfig()
+	config.Envs = cfg
+
+	// Sin las claves de firma la API no arranca
+	err := cfg.Validate()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	// Conectar a la base de datos utilizando la configuración cargada
-	err := db.Connection(cfg)
+	err = db.Connection(cfg)
 	if err != nil {
 		log.Fatalf("Error connecting to database: %v", err)
 	}
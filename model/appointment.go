@@ -1,27 +1,114 @@
 package model
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
 // Cita médica
 type Appointment struct {
-	ID          uint     `gorm:"primaryKey;autoIncrement" json:"id"`
-	DoctorID    uint     `json:"doctor_id" validate:"required"`
-	PatientID   uint     `json:"-"`
-	Patient     *Patient `json:"patient,omitempty" gorm:"foreignKey:PatientID"`
-	PatientDNI  string   `json:"patient_dni" validate:"required,max=20" gorm:"-"`
-	ServiceID   uint     `json:"service_id"`
-	PackageID   uint     `json:"package_id"`
-	Date        string   `json:"date" validate:"required"`
-	StartTime   string   `json:"start_time" validate:"required"`
-	EndTime     string   `json:"end_time" validate:"required"`
-	Paid        bool     `json:"paid"`
-	TotalAmount float64  `json:"total_amount"`
-}
-
-// Pago
+	ID             uint              `gorm:"primaryKey;autoIncrement" json:"id"`
+	DoctorID       uint              `json:"doctor_id" validate:"required"`
+	PatientID      uint              `json:"-"`
+	Patient        *Patient          `json:"patient,omitempty" gorm:"foreignKey:PatientID"`
+	PatientDNI     string            `json:"patient_dni" validate:"required,max=20" gorm:"-"`
+	ServiceID      uint              `json:"service_id"`
+	PackageID      uint              `json:"package_id"`
+	Date           string            `json:"date" validate:"required"`
+	StartTime      string            `json:"start_time" validate:"required"`
+	EndTime        string            `json:"end_time" validate:"required"`
+	Paid           bool              `json:"paid"`
+	Status         AppointmentStatus `json:"status" gorm:"size:20;default:programada"`
+	CouponCode     string            `json:"coupon_code" gorm:"size:30" validate:"max=30"`
+	CouponID       *uint             `json:"coupon_id"`
+	CouponDiscount money.Money       `json:"coupon_discount"`
+	PolicyID       *uint             `json:"policy_id"`
+	CreditID       *uint             `json:"credit_id"`
+	TotalAmount    money.Money       `json:"total_amount"`
+	InsurerAmount  money.Money       `json:"insurer_amount"`
+	PatientAmount  money.Money       `json:"patient_amount"`
+	Items          []AppointmentItem `json:"items" gorm:"foreignKey:AppointmentID;constraint:OnDelete:CASCADE"`
+}
+
+// Línea de la cita con el nombre, el precio y los descuentos vigentes al reservar,
+// para que los cambios posteriores del catálogo no alteren lo cobrado
+type AppointmentItem struct {
+	ID              uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	AppointmentID   uint            `json:"-" gorm:"index"`
+	ItemType        CatalogItemType `json:"item_type" gorm:"size:20"`
+	ServiceID       uint            `json:"service_id"`
+	PackageID       uint            `json:"package_id"`
+	Name            string          `json:"name" gorm:"size:50"`
+	Quantity        int             `json:"quantity"`
+	UnitPrice       money.Money     `json:"unit_price"`
+	Discount        money.Money     `json:"discount"`
+	CouponDiscount  money.Money     `json:"coupon_discount"`
+	PrepaidDiscount money.Money     `json:"prepaid_discount"`
+	FinalPrice      money.Money     `json:"final_price"`
+	InsurerAmount   money.Money     `json:"insurer_amount"`
+	PatientAmount   money.Money     `json:"patient_amount"`
+	// Reglas de precio que dieron el descuento de la línea, con sus montos ya multiplicados por la cantidad
+	AppliedRules []AppliedPricingRule `json:"applied_rules" gorm:"serializer:json"`
+}
+
+// La cita cambió de día, de horario o de médico respecto de previous
+func (a *Appointment) IsRescheduled(previous *Appointment) bool {
+	return a.Date != previous.Date || a.StartTime != previous.StartTime || a.EndTime != previous.EndTime || a.DoctorID != previous.DoctorID
+}
+
+// Estado de la cita
+type AppointmentStatus string
+
+const (
+	AppointmentScheduled AppointmentStatus = "programada"
+	AppointmentCompleted AppointmentStatus = "completada"
+	AppointmentCancelled AppointmentStatus = "cancelada"
+	// La cita pasó sin que se registrara su pago; la marca el trabajo de citas impagas
+	AppointmentExpired AppointmentStatus = "vencida"
+)
+
+// Estados en que la cita admite pagos; la completada puede pagarse después de la atención
+var PayableAppointmentStatuses = []AppointmentStatus{AppointmentScheduled, AppointmentCompleted}
+
+func (s AppointmentStatus) Payable() bool {
+	for _, status := range PayableAppointmentStatuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Cambio de estado de la cita
+type AppointmentStatusRequest struct {
+	Status AppointmentStatus `json:"status" validate:"required"`
+}
+
+// Pago registrado en el libro de pagos
 type Payment struct {
-	AppoimentID uint        `json:"appoiment_id" validate:"required"`
-	Paid        bool        `json:"paid" validate:"required"`
-	TotalAmount float64     `json:"total_amount"`
-	PaymentType PaymentType `json:"payment_type" validate:"required"`
+	ID            uint        `json:"id" gorm:"primaryKey;autoIncrement"`
+	AppoimentID   uint        `json:"appoiment_id" validate:"required"`
+	ClaimID       *uint       `json:"claim_id,omitempty" gorm:"index"`
+	PurchaseID    *uint       `json:"purchase_id,omitempty" gorm:"index"`
+	CashSessionID *uint       `json:"cash_session_id"`
+	Paid          bool        `json:"paid" validate:"required"`
+	TotalAmount   money.Money `json:"total_amount"`
+	Currency      string      `json:"currency" gorm:"size:3"`
+	ExchangeRate  money.Rate  `json:"exchange_rate"`
+	BaseAmount    money.Money `json:"base_amount"`
+	PaymentType   PaymentType `json:"payment_type" gorm:"size:20" validate:"required"`
+	CreatedAt     time.Time   `json:"created_at"`
+	RefundedAt    *time.Time  `json:"refunded_at,omitempty"`
+	RefundedBy    string      `json:"refunded_by,omitempty" gorm:"size:50"`
+	RefundReason  string      `json:"refund_reason,omitempty" gorm:"size:250"`
+}
+
+// Número de boleta impreso en el recibo y firmado en su QR
+func (p *Payment) InvoiceNumber() string {
+	return fmt.Sprintf("B001-%08d", p.ID)
 }
 
 // Método de pago
@@ -31,10 +118,51 @@ const (
 	Cash        PaymentType = "efectivo"
 	Card        PaymentType = "tarjeta"
 	Application PaymentType = "applicativo"
+	Transfer    PaymentType = "transferencia"
 )
 
-// Respuesta al realizar el pago
+// Métodos de pago aceptados, en el orden en que se muestran en los reportes
+var PaymentTypes = []PaymentType{Cash, Card, Application, Transfer}
+
+// Respuesta al realizar el pago con las URLs de descarga del QR y del recibo; ReceiptPDF (en base64)
+// solo se incluye si se pide el recibo en línea
 type PaymentResponse struct {
-	QRCode     string `json:"qr_code"`
-	PDFReceipt string `json:"pdf_receipt"`
+	PaymentID     uint   `json:"payment_id"`
+	InvoiceNumber string `json:"invoice_number"`
+	QRCode        string `json:"qr_code"`
+	PDFReceipt    string `json:"pdf_receipt"`
+	ReceiptPDF    []byte `json:"pdf_receipt_data,omitempty"`
+}
+
+// Reembolso de un pago; anula su recibo
+type RefundRequest struct {
+	Reason string `json:"reason" validate:"required,max=250"`
 }
+
+// Datos firmados en el QR del recibo
+type ReceiptClaims struct {
+	PaymentID     uint        `json:"i"`
+	AppointmentID uint        `json:"a"`
+	Amount        money.Money `json:"m"`
+	Currency      string      `json:"c"`
+	PaidAt        int64       `json:"t"`
+	InvoiceNumber string      `json:"n"`
+}
+
+// Estado del recibo que devuelve la verificación pública del QR
+type ReceiptVerification struct {
+	Valid         bool        `json:"valid"`
+	Status        string      `json:"status"`
+	InvoiceNumber string      `json:"invoice_number"`
+	AppointmentID uint        `json:"appointment_id"`
+	Amount        money.Money `json:"amount"`
+	Currency      string      `json:"currency"`
+	PaidAt        time.Time   `json:"paid_at"`
+	RefundedAt    *time.Time  `json:"refunded_at,omitempty"`
+}
+
+// Estados del recibo verificado
+const (
+	ReceiptPaid     = "pagado"
+	ReceiptRefunded = "reembolsado"
+)
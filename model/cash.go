@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Estado de la sesión de caja
+type CashSessionStatus string
+
+const (
+	CashSessionOpen   CashSessionStatus = "abierta"
+	CashSessionClosed CashSessionStatus = "cerrada"
+)
+
+// Sesión de caja diaria
+type CashSession struct {
+	ID           uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	OpenedBy     string             `json:"opened_by" gorm:"size:50"`
+	ClosedBy     string             `json:"closed_by" gorm:"size:50"`
+	OpeningFloat money.Money        `json:"opening_float" validate:"min=0"`
+	Status       CashSessionStatus  `json:"status" gorm:"size:20;index"`
+	OpenedAt     time.Time          `json:"opened_at"`
+	ClosedAt     *time.Time         `json:"closed_at"`
+	Notes        string             `json:"notes" gorm:"size:250" validate:"max=250"`
+	Counts       []CashSessionCount `json:"counts" gorm:"foreignKey:CashSessionID;constraint:OnDelete:CASCADE"`
+	// Vale true mientras la sesión está abierta y NULL al cerrarla; su índice único impide que dos
+	// aperturas simultáneas dejen dos cajas abiertas (el índice admite varios NULL)
+	OpenGuard *bool `json:"-" gorm:"uniqueIndex"`
+}
+
+// Conteo de cierre por método de pago y moneda, con la diferencia registrada.
+// Los montos *Base están expresados en la moneda base de la clínica.
+type CashSessionCount struct {
+	ID              uint        `json:"-" gorm:"primaryKey;autoIncrement"`
+	CashSessionID   uint        `json:"-" gorm:"index"`
+	PaymentType     PaymentType `json:"payment_type" gorm:"size:20"`
+	Currency        string      `json:"currency" gorm:"size:3"`
+	Expected        money.Money `json:"expected"`
+	Counted         money.Money `json:"counted"`
+	Discrepancy     money.Money `json:"discrepancy"`
+	ExpectedBase    money.Money `json:"expected_base"`
+	CountedBase     money.Money `json:"counted_base"`
+	DiscrepancyBase money.Money `json:"discrepancy_base"`
+}
+
+// Apertura de caja
+type OpenCashSessionRequest struct {
+	OpeningFloat money.Money `json:"opening_float" validate:"min=0"`
+	Notes        string      `json:"notes" validate:"max=250"`
+}
+
+// Cierre de caja con los montos contados
+type CloseCashSessionRequest struct {
+	Counts []CountedAmount `json:"counts" validate:"required,dive"`
+	Notes  string          `json:"notes" validate:"max=250"`
+}
+
+type CountedAmount struct {
+	PaymentType PaymentType `json:"payment_type" validate:"required"`
+	Currency    string      `json:"currency"`
+	Counted     money.Money `json:"counted" validate:"min=0"`
+}
+
+// Reporte de fin de día: esperado vs contado por método de pago; los totales están en moneda base
+type CashSessionReport struct {
+	BaseCurrency     string             `json:"base_currency"`
+	Session          CashSession        `json:"session"`
+	Lines            []CashSessionCount `json:"lines"`
+	TotalExpected    money.Money        `json:"total_expected"`
+	TotalCounted     money.Money        `json:"total_counted"`
+	TotalDiscrepancy money.Money        `json:"total_discrepancy"`
+	Payments         []Payment          `json:"payments"`
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Estado del reclamo a la aseguradora
+type ClaimStatus string
+
+const (
+	ClaimSubmitted     ClaimStatus = "presentado"
+	ClaimApproved      ClaimStatus = "aprobado"
+	ClaimPartiallyPaid ClaimStatus = "pagado_parcial"
+	ClaimPaid          ClaimStatus = "pagado"
+	ClaimRejected      ClaimStatus = "rechazado"
+)
+
+// Reclamo a una aseguradora por la parte cubierta de las citas completadas en un periodo.
+// Los montos están expresados en la moneda base de la clínica.
+type InsuranceClaim struct {
+	ID             uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProviderID     uint               `json:"provider_id" gorm:"index;not null"`
+	Provider       *InsuranceProvider `json:"provider,omitempty" gorm:"foreignKey:ProviderID"`
+	PeriodFrom     string             `json:"period_from" gorm:"size:10;not null"`
+	PeriodTo       string             `json:"period_to" gorm:"size:10;not null"`
+	Status         ClaimStatus        `json:"status" gorm:"size:20;index"`
+	ClaimedAmount  money.Money        `json:"claimed_amount"`
+	ApprovedAmount money.Money        `json:"approved_amount"`
+	PaidAmount     money.Money        `json:"paid_amount"`
+	Notes          string             `json:"notes" gorm:"size:250"`
+	CreatedBy      string             `json:"created_by" gorm:"size:50"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	Items          []ClaimItem        `json:"items,omitempty" gorm:"foreignKey:ClaimID;constraint:OnDelete:CASCADE"`
+}
+
+// Cita incluida en un reclamo, con los datos del paciente y la póliza al momento de presentarlo
+type ClaimItem struct {
+	ID            uint        `json:"id" gorm:"primaryKey;autoIncrement"`
+	ClaimID       uint        `json:"-" gorm:"index"`
+	AppointmentID uint        `json:"appointment_id" gorm:"index"`
+	Date          string      `json:"date" gorm:"size:10"`
+	PolicyNumber  string      `json:"policy_number" gorm:"size:50"`
+	PatientName   string      `json:"patient_name" gorm:"size:130"`
+	PatientDNI    string      `json:"patient_dni" gorm:"size:20"`
+	Amount        money.Money `json:"amount"`
+}
+
+// Generación de un reclamo
+type CreateClaimRequest struct {
+	ProviderID uint   `json:"provider_id" validate:"required"`
+	PeriodFrom string `json:"period_from" validate:"required"`
+	PeriodTo   string `json:"period_to" validate:"required"`
+	Notes      string `json:"notes" validate:"max=250"`
+}
+
+// Respuesta de la aseguradora; si se aprueba sin monto se aprueba lo reclamado
+type ClaimStatusRequest struct {
+	Status         ClaimStatus `json:"status" validate:"required"`
+	ApprovedAmount money.Money `json:"approved_amount" validate:"min=0"`
+	Notes          string      `json:"notes" validate:"max=250"`
+}
+
+// Pago de la aseguradora contra un reclamo aprobado
+type ClaimPaymentRequest struct {
+	Amount      money.Money `json:"amount" validate:"required"`
+	Currency    string      `json:"currency"`
+	PaymentType PaymentType `json:"payment_type" validate:"required"`
+}
+
+// Lote estructurado del reclamo para el envío electrónico a la aseguradora
+type ClaimBatch struct {
+	ClaimID       uint        `json:"claim_id"`
+	ProviderName  string      `json:"provider_name"`
+	ProviderTaxID string      `json:"provider_tax_id"`
+	PeriodFrom    string      `json:"period_from"`
+	PeriodTo      string      `json:"period_to"`
+	Currency      string      `json:"currency"`
+	ItemCount     int         `json:"item_count"`
+	TotalAmount   money.Money `json:"total_amount"`
+	Items         []ClaimItem `json:"items"`
+}
+
+// Cuentas por cobrar a una aseguradora: lo no reclamado de citas completadas y el saldo de los reclamos abiertos
+type ClaimReceivable struct {
+	ProviderID   uint        `json:"provider_id"`
+	ProviderName string      `json:"provider_name"`
+	Unclaimed    money.Money `json:"unclaimed"`
+	Claimed      money.Money `json:"claimed"`
+	Approved     money.Money `json:"approved"`
+	Paid         money.Money `json:"paid"`
+	Outstanding  money.Money `json:"outstanding"`
+	OpenClaims   int         `json:"open_claims"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Tipo de descuento de un cupón
+type CouponType string
+
+const (
+	CouponPercentage CouponType = "porcentaje"
+	CouponFixed      CouponType = "monto_fijo"
+)
+
+// Cupón de descuento de campañas de marketing.
+// Un cupón sin servicios ni paquetes elegibles aplica a cualquier cita.
+// El monto fijo está expresado en la moneda base. Un límite en 0 indica usos ilimitados.
+type Coupon struct {
+	ID                uint        `json:"id" gorm:"primaryKey;autoIncrement"`
+	Code              string      `json:"code" gorm:"size:30;not null;uniqueIndex"`
+	Description       string      `json:"description" gorm:"size:250"`
+	Type              CouponType  `json:"type" gorm:"size:20;not null"`
+	Percentage        float64     `json:"percentage"`
+	Amount            money.Money `json:"amount"`
+	ValidFrom         string      `json:"valid_from" gorm:"size:10"`
+	ValidTo           string      `json:"valid_to" gorm:"size:10"`
+	MaxUses           int         `json:"max_uses"`
+	MaxUsesPerPatient int         `json:"max_uses_per_patient"`
+	Active            bool        `json:"active"`
+	Services          []Service   `json:"services" gorm:"many2many:coupon_services;constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
+	Packages          []Package   `json:"packages" gorm:"many2many:coupon_packages;constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
+}
+
+// Creación y actualización de cupón
+type CouponRequest struct {
+	Code              string      `json:"code" validate:"required,max=30"`
+	Description       string      `json:"description" validate:"max=250"`
+	Type              CouponType  `json:"type" validate:"required"`
+	Percentage        float64     `json:"percentage" validate:"min=0,max=100"`
+	Amount            money.Money `json:"amount" validate:"min=0"`
+	ValidFrom         string      `json:"valid_from"`
+	ValidTo           string      `json:"valid_to"`
+	MaxUses           int         `json:"max_uses" validate:"min=0"`
+	MaxUsesPerPatient int         `json:"max_uses_per_patient" validate:"min=0"`
+	Active            bool        `json:"active"`
+	ServiceIDs        []uint      `json:"service_ids"`
+	PackageIDs        []uint      `json:"package_ids"`
+}
+
+// Uso de un cupón en una cita; al cancelar, vencer o eliminar la cita el uso se libera
+type CouponRedemption struct {
+	ID            uint         `json:"id" gorm:"primaryKey;autoIncrement"`
+	CouponID      uint         `json:"coupon_id" gorm:"index;not null"`
+	PatientID     uint         `json:"patient_id" gorm:"index"`
+	AppointmentID uint         `json:"appointment_id" gorm:"uniqueIndex"`
+	Appointment   *Appointment `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Sesiones de un servicio incluidas en un paquete prepagado
+type PackageSession struct {
+	ID        uint `json:"-" gorm:"primaryKey;autoIncrement"`
+	PackageID uint `json:"-" gorm:"index"`
+	ServiceID uint `json:"service_id" validate:"required"`
+	Sessions  int  `json:"sessions" validate:"min=1"`
+}
+
+// Compra de un paquete por un paciente, pagada al momento de la venta.
+// ExpiresOn vacío indica que los créditos no vencen.
+type PackagePurchase struct {
+	ID           uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	PackageID    uint            `json:"package_id" gorm:"index;not null"`
+	Package      *Package        `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+	PatientID    uint            `json:"patient_id" gorm:"index;not null"`
+	PurchaseDate string          `json:"purchase_date" gorm:"size:10"`
+	ExpiresOn    string          `json:"expires_on" gorm:"size:10"`
+	Price        money.Money     `json:"price"`
+	CreatedAt    time.Time       `json:"created_at"`
+	Credits      []PackageCredit `json:"credits" gorm:"foreignKey:PurchaseID;constraint:OnDelete:CASCADE"`
+}
+
+// Sesiones prepagadas de un servicio que el paciente consume con sus citas
+type PackageCredit struct {
+	ID         uint     `json:"id" gorm:"primaryKey;autoIncrement"`
+	PurchaseID uint     `json:"purchase_id" gorm:"index"`
+	PatientID  uint     `json:"patient_id" gorm:"index"`
+	ServiceID  uint     `json:"service_id" gorm:"index"`
+	Service    *Service `json:"service,omitempty" gorm:"foreignKey:ServiceID"`
+	Total      int      `json:"total"`
+	Used       int      `json:"used"`
+	ExpiresOn  string   `json:"expires_on" gorm:"size:10"`
+	Remaining  int      `json:"remaining" gorm:"-"`
+	Expired    bool     `json:"expired" gorm:"-"`
+}
+
+// Venta de un paquete prepagado
+type PackagePurchaseRequest struct {
+	PackageID   uint        `json:"package_id" validate:"required"`
+	PatientDNI  string      `json:"patient_dni" validate:"required,max=20"`
+	TotalAmount money.Money `json:"total_amount" validate:"min=0"`
+	Currency    string      `json:"currency"`
+	PaymentType PaymentType `json:"payment_type" validate:"required"`
+}
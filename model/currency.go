@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Tipo de cambio de una moneda hacia la moneda base, vigente desde la fecha indicada
+type ExchangeRate struct {
+	ID            uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Currency      string     `json:"currency" gorm:"size:3;not null;index:idx_rate_currency_date" validate:"required,len=3"`
+	Rate          money.Rate `json:"rate" validate:"required"`
+	EffectiveFrom string     `json:"effective_from" gorm:"size:10;not null;index:idx_rate_currency_date" validate:"required"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Cotización de una cita expresada en otra moneda
+type AppointmentQuote struct {
+	Price         PriceDetails `json:"price"`
+	BaseCurrency  string       `json:"base_currency"`
+	Currency      string       `json:"currency"`
+	ExchangeRate  money.Rate   `json:"exchange_rate"`
+	FinalPrice    money.Money  `json:"final_price"`
+	PatientAmount money.Money  `json:"patient_amount"`
+}
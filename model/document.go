@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Tipo de documento adjunto
+type DocumentCategory string
+
+const (
+	DocumentLabResult DocumentCategory = "resultado"
+	DocumentScan      DocumentCategory = "imagen"
+	DocumentConsent   DocumentCategory = "consentimiento"
+	DocumentOther     DocumentCategory = "otro"
+)
+
+var DocumentCategories = []DocumentCategory{DocumentLabResult, DocumentScan, DocumentConsent, DocumentOther}
+
+// Documento adjunto a un paciente y, opcionalmente, a una de sus citas. El archivo vive en el
+// almacenamiento configurado bajo StorageKey; Checksum es el SHA-256 del contenido subido y se comprueba
+// en cada descarga.
+type Document struct {
+	ID            uint             `json:"id" gorm:"primaryKey;autoIncrement"`
+	PatientID     uint             `json:"patient_id" gorm:"index;not null"`
+	AppointmentID *uint            `json:"appointment_id" gorm:"index"`
+	Category      DocumentCategory `json:"category" gorm:"size:20;not null"`
+	FileName      string           `json:"file_name" gorm:"size:255;not null"`
+	ContentType   string           `json:"content_type" gorm:"size:100;not null"`
+	Size          int64            `json:"size"`
+	Checksum      string           `json:"checksum" gorm:"size:64;not null"`
+	StorageKey    string           `json:"-" gorm:"size:255;uniqueIndex;not null"`
+	UploadedBy    string           `json:"uploaded_by" gorm:"size:50"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// Enlace de descarga firmado y de vigencia limitada
+type DocumentDownloadURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
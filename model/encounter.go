@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Registro clínico de una cita completada, escrito por el médico que atendió al paciente.
+// Se bloquea al firmarse o 24 horas después de crearse; cada edición previa guarda una revisión.
+type Encounter struct {
+	ID             uint                `json:"id" gorm:"primaryKey;autoIncrement"`
+	AppointmentID  uint                `json:"appointment_id" gorm:"uniqueIndex;not null"`
+	DoctorID       uint                `json:"doctor_id" gorm:"index;not null"`
+	Reason         string              `json:"reason" gorm:"size:250;not null"`
+	Findings       string              `json:"findings" gorm:"type:text"`
+	DiagnosisCodes []string            `json:"diagnosis_codes" gorm:"serializer:json"`
+	Plan           string              `json:"plan" gorm:"type:text"`
+	Notes          string              `json:"notes" gorm:"type:text"`
+	AuthoredBy     string              `json:"authored_by" gorm:"size:50"`
+	SignedAt       *time.Time          `json:"signed_at"`
+	Locked         bool                `json:"locked" gorm:"-"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	Revisions      []EncounterRevision `json:"revisions,omitempty" gorm:"foreignKey:EncounterID;constraint:OnDelete:CASCADE"`
+}
+
+// Contenido del registro clínico antes de una edición
+type EncounterRevision struct {
+	ID             uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	EncounterID    uint      `json:"-" gorm:"index"`
+	Reason         string    `json:"reason" gorm:"size:250"`
+	Findings       string    `json:"findings" gorm:"type:text"`
+	DiagnosisCodes []string  `json:"diagnosis_codes" gorm:"serializer:json"`
+	Plan           string    `json:"plan" gorm:"type:text"`
+	Notes          string    `json:"notes" gorm:"type:text"`
+	EditedBy       string    `json:"edited_by" gorm:"size:50"`
+	EditedAt       time.Time `json:"edited_at"`
+}
+
+// Creación o edición del registro clínico; los diagnósticos se indican con sus códigos (p. ej. CIE-10)
+type EncounterRequest struct {
+	Reason         string   `json:"reason" validate:"required,max=250"`
+	Findings       string   `json:"findings"`
+	DiagnosisCodes []string `json:"diagnosis_codes" validate:"dive,required,max=20"`
+	Plan           string   `json:"plan"`
+	Notes          string   `json:"notes"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Tipo de elemento del catálogo
+type CatalogItemType string
+
+const (
+	CatalogService CatalogItemType = "servicio"
+	CatalogPackage CatalogItemType = "paquete"
+)
+
+// Precio de un servicio o paquete vigente desde EffectiveFrom hasta el siguiente cambio.
+// EffectiveTo se calcula al consultar el historial y es nil para el precio actual.
+type PriceHistory struct {
+	ID            uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	ItemType      CatalogItemType `json:"item_type" gorm:"size:20;index:idx_price_history_item"`
+	ItemID        uint            `json:"item_id" gorm:"index:idx_price_history_item"`
+	Name          string          `json:"name" gorm:"size:50"`
+	Price         money.Money     `json:"price"`
+	Currency      string          `json:"currency" gorm:"size:3"`
+	EffectiveFrom time.Time       `json:"effective_from"`
+	EffectiveTo   *time.Time      `json:"effective_to" gorm:"-"`
+}
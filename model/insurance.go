@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Compañía aseguradora
+type InsuranceProvider struct {
+	ID     uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name   string          `json:"name" gorm:"size:80;not null;uniqueIndex" validate:"required,max=80"`
+	TaxID  string          `json:"tax_id" gorm:"size:20" validate:"max=20"`
+	Phone  string          `json:"phone" gorm:"size:20" validate:"max=20"`
+	Email  string          `json:"email" gorm:"size:100" validate:"omitempty,email,max=100"`
+	Active bool            `json:"active"`
+	Plans  []InsurancePlan `json:"plans,omitempty" gorm:"foreignKey:ProviderID"`
+}
+
+// Plan de una aseguradora. La cobertura por defecto aplica a las categorías sin cobertura propia.
+type InsurancePlan struct {
+	ID              uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProviderID      uint               `json:"provider_id" gorm:"index;not null" validate:"required"`
+	Provider        *InsuranceProvider `json:"provider,omitempty" gorm:"foreignKey:ProviderID"`
+	Name            string             `json:"name" gorm:"size:80;not null" validate:"required,max=80"`
+	DefaultCoverage float64            `json:"default_coverage" validate:"min=0,max=100"`
+	DefaultCopay    money.Money        `json:"default_copay" validate:"min=0"`
+	Active          bool               `json:"active"`
+	Coverages       []PlanCoverage     `json:"coverages" gorm:"foreignKey:PlanID;constraint:OnDelete:CASCADE" validate:"dive"`
+}
+
+// Cobertura del plan para una categoría de servicio.
+// El paciente paga primero el copago y la aseguradora cubre el porcentaje del resto.
+type PlanCoverage struct {
+	ID              uint        `json:"-" gorm:"primaryKey;autoIncrement"`
+	PlanID          uint        `json:"-" gorm:"index"`
+	ServiceCategory string      `json:"service_category" gorm:"size:50;not null" validate:"required,max=50"`
+	Percentage      float64     `json:"percentage" validate:"min=0,max=100"`
+	Copay           money.Money `json:"copay" validate:"min=0"`
+}
+
+// Póliza de un paciente en un plan de seguro
+type PatientPolicy struct {
+	ID           uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	PatientID    uint           `json:"patient_id" gorm:"index;not null" validate:"required"`
+	PlanID       uint           `json:"plan_id" gorm:"index;not null" validate:"required"`
+	Plan         *InsurancePlan `json:"plan,omitempty" gorm:"foreignKey:PlanID"`
+	PolicyNumber string         `json:"policy_number" gorm:"size:50;not null" validate:"required,max=50"`
+	ValidFrom    string         `json:"valid_from" gorm:"size:10;not null" validate:"required"`
+	ValidTo      string         `json:"valid_to" gorm:"size:10"`
+	Active       bool           `json:"active"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
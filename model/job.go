@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Estado de un trabajo en segundo plano
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pendiente"
+	JobRunning   JobStatus = "en_curso"
+	JobCompleted JobStatus = "completado"
+	JobFailed    JobStatus = "fallido"
+)
+
+// Trabajo en segundo plano guardado en la base de datos. Key evita encolar dos veces el mismo trabajo
+// (p. ej. el recordatorio de 24 h de una cita) aunque varias instancias lo intenten a la vez.
+// Los trabajos periódicos tienen Interval y vuelven a quedar pendientes al terminar.
+type Job struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Key         string     `gorm:"column:job_key;size:120;uniqueIndex" json:"key"`
+	Name        string     `gorm:"size:60;index" json:"name"`
+	Payload     string     `gorm:"type:text" json:"payload"`
+	Status      JobStatus  `gorm:"size:20;index" json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	Interval    int64      `gorm:"column:interval_seconds" json:"interval_seconds"`
+	RunAt       time.Time  `gorm:"index" json:"run_at"`
+	LockedBy    string     `gorm:"size:100" json:"locked_by,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	LastError   string     `gorm:"size:255" json:"last_error,omitempty"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
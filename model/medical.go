@@ -0,0 +1,120 @@
+package model
+
+// Gravedad de una alergia del paciente
+type AllergySeverity string
+
+const (
+	AllergyMild     AllergySeverity = "leve"
+	AllergyModerate AllergySeverity = "moderada"
+	AllergySevere   AllergySeverity = "grave"
+)
+
+var AllergySeverities = []AllergySeverity{AllergyMild, AllergyModerate, AllergySevere}
+
+// Registro del historial médico que pertenece a un paciente
+type PatientRecord interface {
+	SetID(ID uint)
+	GetPatientID() uint
+	SetPatientID(patientID uint)
+}
+
+// Alergia del paciente; se advierte al reservarle una cita
+type PatientAllergy struct {
+	ID        uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	PatientID uint            `json:"patient_id" gorm:"index;not null"`
+	Allergen  string          `json:"allergen" gorm:"size:100;not null" validate:"required,max=100"`
+	Reaction  string          `json:"reaction" gorm:"size:250" validate:"max=250"`
+	Severity  AllergySeverity `json:"severity" gorm:"size:20;not null" validate:"required"`
+}
+
+func (a *PatientAllergy) SetID(ID uint) {
+	a.ID = ID
+}
+
+func (a *PatientAllergy) GetPatientID() uint {
+	return a.PatientID
+}
+
+func (a *PatientAllergy) SetPatientID(patientID uint) {
+	a.PatientID = patientID
+}
+
+// Enfermedad crónica del paciente; el código es opcional (p. ej. CIE-10) y la fecha es AAAA-MM-DD
+type ChronicCondition struct {
+	ID          uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	PatientID   uint   `json:"patient_id" gorm:"index;not null"`
+	Name        string `json:"name" gorm:"size:100;not null" validate:"required,max=100"`
+	Code        string `json:"code" gorm:"size:20" validate:"max=20"`
+	DiagnosedOn string `json:"diagnosed_on" gorm:"size:10"`
+	Notes       string `json:"notes" gorm:"size:250" validate:"max=250"`
+}
+
+func (c *ChronicCondition) SetID(ID uint) {
+	c.ID = ID
+}
+
+func (c *ChronicCondition) GetPatientID() uint {
+	return c.PatientID
+}
+
+func (c *ChronicCondition) SetPatientID(patientID uint) {
+	c.PatientID = patientID
+}
+
+// Medicamento del paciente; sin fecha de fin (AAAA-MM-DD) se considera vigente
+type PatientMedication struct {
+	ID        uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	PatientID uint   `json:"patient_id" gorm:"index;not null"`
+	Name      string `json:"name" gorm:"size:100;not null" validate:"required,max=100"`
+	Dosage    string `json:"dosage" gorm:"size:100" validate:"max=100"`
+	Frequency string `json:"frequency" gorm:"size:100" validate:"max=100"`
+	StartDate string `json:"start_date" gorm:"size:10"`
+	EndDate   string `json:"end_date" gorm:"size:10"`
+}
+
+func (m *PatientMedication) SetID(ID uint) {
+	m.ID = ID
+}
+
+func (m *PatientMedication) GetPatientID() uint {
+	return m.PatientID
+}
+
+func (m *PatientMedication) SetPatientID(patientID uint) {
+	m.PatientID = patientID
+}
+
+// Indica si el medicamento sigue vigente en la fecha indicada (AAAA-MM-DD)
+func (m *PatientMedication) IsCurrentOn(date string) bool {
+	return m.EndDate == "" || m.EndDate >= date
+}
+
+// Antecedente familiar del paciente
+type FamilyHistory struct {
+	ID        uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	PatientID uint   `json:"patient_id" gorm:"index;not null"`
+	Relative  string `json:"relative" gorm:"size:50;not null" validate:"required,max=50"`
+	Condition string `json:"condition" gorm:"size:100;not null" validate:"required,max=100"`
+	Notes     string `json:"notes" gorm:"size:250" validate:"max=250"`
+}
+
+func (f *FamilyHistory) SetID(ID uint) {
+	f.ID = ID
+}
+
+func (f *FamilyHistory) GetPatientID() uint {
+	return f.PatientID
+}
+
+func (f *FamilyHistory) SetPatientID(patientID uint) {
+	f.PatientID = patientID
+}
+
+// Resumen del historial médico que ve el médico al abrir una cita; solo incluye los medicamentos vigentes
+type MedicalSummary struct {
+	Patient           *Patient            `json:"patient"`
+	Allergies         []PatientAllergy    `json:"allergies"`
+	ChronicConditions []ChronicCondition  `json:"chronic_conditions"`
+	Medications       []PatientMedication `json:"medications"`
+	FamilyHistory     []FamilyHistory     `json:"family_history"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Hecho de la cita o del pago que se avisa al paciente
+type NotificationEvent string
+
+const (
+	NotificationAppointmentBooked      NotificationEvent = "cita_reservada"
+	NotificationAppointmentRescheduled NotificationEvent = "cita_reprogramada"
+	NotificationAppointmentCancelled   NotificationEvent = "cita_cancelada"
+	NotificationPaymentReceived        NotificationEvent = "pago_recibido"
+	NotificationAppointmentReminder    NotificationEvent = "recordatorio_cita"
+)
+
+type NotificationChannel string
+
+const (
+	ChannelEmail NotificationChannel = "email"
+	ChannelSMS   NotificationChannel = "sms"
+)
+
+// Resultado de un envío; "omitido" cuando el paciente no tiene correo o teléfono registrado
+type NotificationStatus string
+
+const (
+	NotificationSent    NotificationStatus = "enviado"
+	NotificationFailed  NotificationStatus = "fallido"
+	NotificationSkipped NotificationStatus = "omitido"
+)
+
+// Canales por los que el paciente quiere recibir avisos; sin registro se usa solo el correo
+type NotificationPreference struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PatientID uint      `gorm:"uniqueIndex" json:"patient_id"`
+	Email     bool      `json:"email"`
+	SMS       bool      `json:"sms"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type NotificationPreferenceRequest struct {
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+}
+
+// Registro de cada aviso enviado, fallido u omitido
+type NotificationDelivery struct {
+	ID            uint                `gorm:"primaryKey;autoIncrement" json:"id"`
+	PatientID     uint                `gorm:"index" json:"patient_id"`
+	AppointmentID uint                `gorm:"index" json:"appointment_id"`
+	PaymentID     *uint               `json:"payment_id,omitempty"`
+	Event         NotificationEvent   `gorm:"size:30" json:"event"`
+	Channel       NotificationChannel `gorm:"size:10" json:"channel"`
+	Recipient     string              `gorm:"size:100" json:"recipient"`
+	Subject       string              `gorm:"size:200" json:"subject"`
+	Status        NotificationStatus  `gorm:"size:10" json:"status"`
+	Error         string              `gorm:"size:255" json:"error,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+)
+
+// Hecho del dominio que se guarda en el outbox junto con el cambio que lo produjo
+type EventType string
+
+const (
+	EventAppointmentBooked      EventType = "AppointmentBooked"
+	EventAppointmentCancelled   EventType = "AppointmentCancelled"
+	EventAppointmentRescheduled EventType = "AppointmentRescheduled"
+	EventPaymentRegistered      EventType = "PaymentRegistered"
+	EventPaymentRefunded        EventType = "PaymentRefunded"
+	EventPatientUpdated         EventType = "PatientUpdated"
+)
+
+// Evento del outbox. Se escribe en la misma transacción que el cambio de estado, así que solo existe
+// si el cambio se confirmó; DispatchedAt queda vacío hasta que se entrega a los suscriptores.
+type OutboxEvent struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventType    EventType  `gorm:"size:40;index" json:"event_type"`
+	AggregateID  uint       `gorm:"index" json:"aggregate_id"`
+	Payload      string     `gorm:"type:text" json:"payload"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DispatchedAt *time.Time `gorm:"index" json:"dispatched_at,omitempty"`
+}
+
+// Lee el payload del evento en target
+func (e *OutboxEvent) Decode(target interface{}) error {
+	return json.Unmarshal([]byte(e.Payload), target)
+}
+
+// Datos de la cita en AppointmentBooked, AppointmentCancelled y AppointmentRescheduled. Llevan la cita
+// completa, con sus líneas, porque una cita eliminada ya no se puede leer cuando el suscriptor recibe el
+// evento. Los campos Previous* solo van en AppointmentRescheduled, con el horario que tenía la cita.
+type AppointmentEvent struct {
+	AppointmentID uint              `json:"appointment_id"`
+	PatientID     uint              `json:"patient_id"`
+	DoctorID      uint              `json:"doctor_id"`
+	ServiceID     uint              `json:"service_id,omitempty"`
+	PackageID     uint              `json:"package_id,omitempty"`
+	Date          string            `json:"date"`
+	StartTime     string            `json:"start_time"`
+	EndTime       string            `json:"end_time"`
+	Status        AppointmentStatus `json:"status"`
+	Paid          bool              `json:"paid"`
+	TotalAmount   money.Money       `json:"total_amount"`
+	PatientAmount money.Money       `json:"patient_amount"`
+	Items         []AppointmentItem `json:"items"`
+
+	PreviousDoctorID  uint   `json:"previous_doctor_id,omitempty"`
+	PreviousDate      string `json:"previous_date,omitempty"`
+	PreviousStartTime string `json:"previous_start_time,omitempty"`
+	PreviousEndTime   string `json:"previous_end_time,omitempty"`
+}
+
+func NewAppointmentEvent(appointment *Appointment) AppointmentEvent {
+	return AppointmentEvent{
+		AppointmentID: appointment.ID,
+		PatientID:     appointment.PatientID,
+		DoctorID:      appointment.DoctorID,
+		ServiceID:     appointment.ServiceID,
+		PackageID:     appointment.PackageID,
+		Date:          appointment.Date,
+		StartTime:     appointment.StartTime,
+		EndTime:       appointment.EndTime,
+		Status:        appointment.Status,
+		Paid:          appointment.Paid,
+		TotalAmount:   appointment.TotalAmount,
+		PatientAmount: appointment.PatientAmount,
+		Items:         appointment.Items,
+	}
+}
+
+// Evento de la cita reprogramada con el horario que tenía antes del cambio
+func NewRescheduledEvent(appointment, previous *Appointment) AppointmentEvent {
+	event := NewAppointmentEvent(appointment)
+	event.PreviousDoctorID = previous.DoctorID
+	event.PreviousDate = previous.Date
+	event.PreviousStartTime = previous.StartTime
+	event.PreviousEndTime = previous.EndTime
+
+	return event
+}
+
+// Cita con los datos del evento, para quien necesita trabajar con el modelo
+func (e *AppointmentEvent) Appointment() *Appointment {
+	return &Appointment{
+		ID:            e.AppointmentID,
+		PatientID:     e.PatientID,
+		DoctorID:      e.DoctorID,
+		ServiceID:     e.ServiceID,
+		PackageID:     e.PackageID,
+		Date:          e.Date,
+		StartTime:     e.StartTime,
+		EndTime:       e.EndTime,
+		Status:        e.Status,
+		Paid:          e.Paid,
+		TotalAmount:   e.TotalAmount,
+		PatientAmount: e.PatientAmount,
+		Items:         e.Items,
+	}
+}
+
+// Horario anterior de la cita reprogramada; nil en los demás eventos
+func (e *AppointmentEvent) Previous() *Appointment {
+	if e.PreviousDate == "" {
+		return nil
+	}
+
+	return &Appointment{
+		ID:        e.AppointmentID,
+		PatientID: e.PatientID,
+		DoctorID:  e.PreviousDoctorID,
+		Date:      e.PreviousDate,
+		StartTime: e.PreviousStartTime,
+		EndTime:   e.PreviousEndTime,
+	}
+}
+
+// Datos del pago de una cita en PaymentRegistered y PaymentRefunded; RefundedAt solo va en el reembolso
+type PaymentEvent struct {
+	PaymentID     uint        `json:"payment_id"`
+	AppointmentID uint        `json:"appointment_id"`
+	PatientID     uint        `json:"patient_id"`
+	InvoiceNumber string      `json:"invoice_number"`
+	TotalAmount   money.Money `json:"total_amount"`
+	Currency      string      `json:"currency"`
+	BaseAmount    money.Money `json:"base_amount"`
+	PaymentType   PaymentType `json:"payment_type"`
+	CreatedAt     time.Time   `json:"created_at"`
+	RefundedAt    *time.Time  `json:"refunded_at,omitempty"`
+}
+
+func NewPaymentEvent(payment *Payment, patientID uint) PaymentEvent {
+	return PaymentEvent{
+		PaymentID:     payment.ID,
+		AppointmentID: payment.AppoimentID,
+		PatientID:     patientID,
+		InvoiceNumber: payment.InvoiceNumber(),
+		TotalAmount:   payment.TotalAmount,
+		Currency:      payment.Currency,
+		BaseAmount:    payment.BaseAmount,
+		PaymentType:   payment.PaymentType,
+		CreatedAt:     payment.CreatedAt,
+		RefundedAt:    payment.RefundedAt,
+	}
+}
+
+// Datos del paciente en PatientUpdated. Solo lleva el ID: los eventos salen por webhooks hacia sistemas
+// externos, así que los datos personales (DNI, dirección, teléfono) se consultan en la API con permisos.
+type PatientEvent struct {
+	PatientID uint `json:"patient_id"`
+}
+
+func NewPatientEvent(patient *Patient) PatientEvent {
+	return PatientEvent{PatientID: patient.ID}
+}
@@ -11,20 +11,20 @@ type Person struct {
 	Address     string `json:"address" validate:"required,max=200"`
 }
 
-// Médico
+// Médico; al registrarlo o actualizarlo se indican los IDs de sus especialidades
 type Doctor struct {
 	Person
-	Especialty string  `json:"especialty" validate:"required,max=50"`
-	Days       string  `json:"days" validate:"required"`
-	StartTime  string  `json:"start_time" validate:"required"`
-	EndTime    string  `json:"end_time" validate:"required"`
-	Salary     float64 `json:"salary" validate:"required,numeric"`
+	SpecialtyIDs []uint      `json:"specialty_ids,omitempty" gorm:"-" validate:"required,min=1"`
+	Specialties  []Specialty `json:"specialties" gorm:"many2many:doctor_specialties;constraint:OnDelete:CASCADE"`
+	Days         string      `json:"days" validate:"required"`
+	StartTime    string      `json:"start_time" validate:"required"`
+	EndTime      string      `json:"end_time" validate:"required"`
+	Salary       float64     `json:"salary" validate:"required,numeric"`
 }
 
-// Paciente
+// Paciente; su seguro médico se registra como pólizas (PatientPolicy)
 type Patient struct {
 	Person
-	Insurance bool `json:"health_insurance"`
 }
 
 // Días válidos para que trabaje el doctor
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// Receta electrónica emitida por el médico que atendió la cita. El código de verificación
+// se imprime como QR en el PDF para que la farmacia confirme su autenticidad.
+type Prescription struct {
+	ID               uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	AppointmentID    uint               `json:"appointment_id" gorm:"index;not null"`
+	EncounterID      *uint              `json:"encounter_id"`
+	DoctorID         uint               `json:"doctor_id" gorm:"index;not null"`
+	Doctor           *Doctor            `json:"doctor,omitempty" gorm:"foreignKey:DoctorID"`
+	PatientID        uint               `json:"patient_id" gorm:"index"`
+	Patient          *Patient           `json:"patient,omitempty" gorm:"foreignKey:PatientID"`
+	VerificationCode string             `json:"verification_code" gorm:"size:32;uniqueIndex;not null"`
+	Notes            string             `json:"notes" gorm:"size:250"`
+	IssuedBy         string             `json:"issued_by" gorm:"size:50"`
+	CreatedAt        time.Time          `json:"created_at"`
+	Items            []PrescriptionItem `json:"items" gorm:"foreignKey:PrescriptionID;constraint:OnDelete:CASCADE"`
+}
+
+// Medicamento recetado con su dosis, frecuencia, duración e indicaciones
+type PrescriptionItem struct {
+	ID             uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	PrescriptionID uint   `json:"-" gorm:"index"`
+	Medication     string `json:"medication" gorm:"size:100;not null" validate:"required,max=100"`
+	Dose           string `json:"dose" gorm:"size:100;not null" validate:"required,max=100"`
+	Frequency      string `json:"frequency" gorm:"size:100;not null" validate:"required,max=100"`
+	Duration       string `json:"duration" gorm:"size:100" validate:"max=100"`
+	Instructions   string `json:"instructions" gorm:"size:250" validate:"max=250"`
+}
+
+// Emisión de una receta
+type CreatePrescriptionRequest struct {
+	Items []PrescriptionItem `json:"items" validate:"required,min=1,dive"`
+	Notes string             `json:"notes" validate:"max=250"`
+}
+
+// Lo que ve la farmacia al escanear el QR: sin datos de contacto y con el nombre del paciente abreviado
+type PrescriptionVerification struct {
+	Valid      bool               `json:"valid"`
+	Code       string             `json:"code"`
+	IssuedAt   time.Time          `json:"issued_at"`
+	DoctorName string             `json:"doctor_name"`
+	Patient    string             `json:"patient"`
+	Items      []PrescriptionItem `json:"items"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import "github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+
+// Ámbito al que se aplica una regla de precios
+type PricingRuleScope string
+
+const (
+	ScopePackage         PricingRuleScope = "paquete"
+	ScopeServiceCategory PricingRuleScope = "categoria"
+)
+
+// Regla de descuento configurable por el administrador
+type PricingRule struct {
+	ID              uint             `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name            string           `json:"name" gorm:"size:80;not null" validate:"required,max=80"`
+	Scope           PricingRuleScope `json:"scope" gorm:"size:20;not null;index" validate:"required"`
+	PackageID       *uint            `json:"package_id"`
+	ServiceCategory string           `json:"service_category" gorm:"size:50" validate:"max=50"`
+	Percentage      float64          `json:"percentage" validate:"gt=0,lte=100"`
+	ValidFrom       string           `json:"valid_from" gorm:"size:10"`
+	ValidTo         string           `json:"valid_to" gorm:"size:10"`
+	Active          bool             `json:"active"`
+}
+
+// Detalle de una regla aplicada al calcular el precio de una cita
+type AppliedPricingRule struct {
+	RuleID     uint             `json:"rule_id"`
+	Name       string           `json:"name"`
+	Scope      PricingRuleScope `json:"scope"`
+	Percentage float64          `json:"percentage"`
+	Amount     money.Money      `json:"amount"`
+}
@@ -1,59 +1,237 @@
 package model
 
-//Servicio médico
+import "github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+
+//Servicio médico; el inactivo ya no se puede reservar pero se conserva en las citas registradas.
+//Sin especialidad lo puede realizar cualquier médico
 type Service struct {
-	ID          uint    `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name        string  `json:"name" gorm:"size:50;not null" validate:"required,max=50"`
-	Description string  `json:"description" gorm:"size:250;not null" validate:"required,max=250"`
-	Price       float64 `json:"price" validate:"min=0,numeric"`
+	ID          uint        `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string      `json:"name" gorm:"size:50;not null" validate:"required,max=50"`
+	Description string      `json:"description" gorm:"size:250;not null" validate:"required,max=250"`
+	Category    string      `json:"category" gorm:"size:50;index" validate:"max=50"`
+	SpecialtyID *uint       `json:"specialty_id"`
+	Specialty   *Specialty  `json:"specialty,omitempty" gorm:"foreignKey:SpecialtyID"`
+	Active      bool        `json:"active" gorm:"default:true"`
+	Price       money.Money `json:"price" validate:"min=0,numeric"`
+	Currency    string      `json:"currency" gorm:"size:3"`
+}
+
+// Activación o retiro de un servicio médico
+type ServiceStatusRequest struct {
+	Active *bool `json:"active" validate:"required"`
+}
+
+// Categoría del catálogo de servicios; las reglas de precios y las coberturas se asocian por su nombre
+type ServiceCategory struct {
+	ID          uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string `json:"name" gorm:"size:50;not null;uniqueIndex" validate:"required,max=50"`
+	Description string `json:"description" gorm:"size:250" validate:"max=250"`
 }
 
-//Paquete de servicios médicos
+//Paquete de servicios médicos; sin descuento propio se aplican las reglas de precios de paquetes.
+//Solo se vende dentro de su periodo de venta (AAAA-MM-DD) y el retirado se conserva en las citas registradas
 type Package struct {
-	ID       uint      `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name     string    `json:"name"`
-	Services []Service `json:"services" gorm:"many2many:package_services;constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
-	Price    float64   `json:"price"`
+	ID                 uint             `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name               string           `json:"name"`
+	Services           []Service        `json:"services" gorm:"many2many:package_services;constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
+	Sessions           []PackageSession `json:"sessions" gorm:"foreignKey:PackageID;constraint:OnDelete:CASCADE"`
+	ValidityDays       int              `json:"validity_days"`
+	DiscountPercentage *float64         `json:"discount_percentage"`
+	SaleFrom           string           `json:"sale_from" gorm:"size:10"`
+	SaleTo             string           `json:"sale_to" gorm:"size:10"`
+	Active             bool             `json:"active" gorm:"default:true"`
+	Price              money.Money      `json:"price"`
 }
 
-//Creación de paquete médico
+//Creación de paquete médico; los servicios sin sesiones indicadas incluyen una sesión
 type CreatePackageRequest struct {
-	Name       string `json:"name" validate:"required,max=50"`
-	ServiceIDs []uint `json:"service_ids" validate:"required"`
+	Name               string           `json:"name" validate:"required,max=50"`
+	ServiceIDs         []uint           `json:"service_ids" validate:"required"`
+	Sessions           []PackageSession `json:"sessions" validate:"dive"`
+	ValidityDays       int              `json:"validity_days" validate:"min=0"`
+	DiscountPercentage *float64         `json:"discount_percentage" validate:"omitempty,gte=0,lte=100"`
+	SaleFrom           string           `json:"sale_from"`
+	SaleTo             string           `json:"sale_to"`
+}
+
+// Activación o retiro de un paquete médico
+type PackageStatusRequest struct {
+	Active *bool `json:"active" validate:"required"`
 }
 
 type PriceDetails interface {
-	GetFinalPrice() float64
+	GetFinalPrice() money.Money
+	GetCouponDiscount() money.Money
+	GetInsurerAmount() money.Money
+	GetPatientAmount() money.Money
+	ApplyCoupon(code string, discounts []money.Money)
+	GetItems() []AppointmentItem
 }
 
-//Precio final de servicio médico con descuento por seguro médico del paciente
+// Reparto del precio final entre la aseguradora y el paciente
+type CoverageShares struct {
+	PolicyNumber  string
+	InsurerAmount money.Money
+	PatientAmount money.Money
+}
+
+func (c *CoverageShares) GetInsurerAmount() money.Money {
+	return c.InsurerAmount
+}
+
+func (c *CoverageShares) GetPatientAmount() money.Money {
+	return c.PatientAmount
+}
+
+//Precio final de servicio médico con la parte cubierta por el seguro médico del paciente
 type FinalServicePrice struct {
-	TotalAmount       float64
-	InsuranceDiscount float64
-	FinalPrice        float64
+	ServiceID        uint
+	Name             string
+	TotalAmount      money.Money
+	CategoryDiscount money.Money
+	CouponCode       string
+	CouponDiscount   money.Money
+	PackageCreditID  uint
+	PrepaidDiscount  money.Money
+	FinalPrice       money.Money
+	CoverageShares
+	AppliedRules []AppliedPricingRule
 }
 
-func (f *FinalServicePrice) GetFinalPrice() float64 {
+func (f *FinalServicePrice) GetFinalPrice() money.Money {
 	return f.FinalPrice
 }
 
+func (f *FinalServicePrice) GetCouponDiscount() money.Money {
+	return f.CouponDiscount
+}
+
+// El cupón se descuenta de la parte que paga el paciente; recibe un descuento por línea
+func (f *FinalServicePrice) ApplyCoupon(code string, discounts []money.Money) {
+	discount := discounts[0]
+	f.CouponCode = code
+	f.CouponDiscount = discount
+	f.FinalPrice -= discount
+	f.PatientAmount -= discount
+}
+
+// Línea de la cita con el precio y los descuentos del servicio
+func (f *FinalServicePrice) GetItems() []AppointmentItem {
+	return []AppointmentItem{{
+		ItemType:        CatalogService,
+		ServiceID:       f.ServiceID,
+		Name:            f.Name,
+		Quantity:        1,
+		UnitPrice:       f.TotalAmount,
+		Discount:        f.CategoryDiscount,
+		CouponDiscount:  f.CouponDiscount,
+		PrepaidDiscount: f.PrepaidDiscount,
+		FinalPrice:      f.FinalPrice,
+		InsurerAmount:   f.InsurerAmount,
+		PatientAmount:   f.PatientAmount,
+		AppliedRules:    f.AppliedRules,
+	}}
+}
+
+// La sesión se cubre con un crédito de paquete prepagado, por lo que el paciente y la aseguradora no pagan nada
+func (f *FinalServicePrice) ApplyPackageCredit(creditID uint) {
+	f.PackageCreditID = creditID
+	f.PrepaidDiscount = f.FinalPrice
+	f.FinalPrice = 0
+	f.CoverageShares = CoverageShares{}
+}
+
 //Precio final de paquete médico con descuento por paquete
 type FinalPackagePrice struct {
-	TotalAmount     float64
-	DiscountPackage float64
-	FinalPrice      float64
+	PackageID        uint
+	Name             string
+	TotalAmount      money.Money
+	CategoryDiscount money.Money
+	DiscountPackage  money.Money
+	FinalPrice       money.Money
 }
 
-func (f *FinalPackagePrice) GetFinalPrice() float64 {
+func (f *FinalPackagePrice) GetFinalPrice() money.Money {
 	return f.FinalPrice
 }
 
-// Precio final de paquete médico con descuento por paquete y con descuento de seguro médico del paciente
+// Precio final de paquete médico con descuento por paquete y con la parte cubierta por el seguro médico del paciente
 type FinalPackagePriceWithInsegurance struct {
-	InsuranceDiscount float64
+	CouponCode     string
+	CouponDiscount money.Money
 	FinalPackagePrice
+	CoverageShares
+	AppliedRules []AppliedPricingRule
 }
 
-func (f *FinalPackagePriceWithInsegurance) GetFinalPrice() float64 {
+func (f *FinalPackagePriceWithInsegurance) GetFinalPrice() money.Money {
 	return f.FinalPackagePrice.FinalPrice
 }
+
+func (f *FinalPackagePriceWithInsegurance) GetCouponDiscount() money.Money {
+	return f.CouponDiscount
+}
+
+// Línea de la cita con el precio y los descuentos del paquete
+func (f *FinalPackagePriceWithInsegurance) GetItems() []AppointmentItem {
+	return []AppointmentItem{{
+		ItemType:       CatalogPackage,
+		PackageID:      f.PackageID,
+		Name:           f.Name,
+		Quantity:       1,
+		UnitPrice:      f.TotalAmount,
+		Discount:       f.CategoryDiscount + f.DiscountPackage,
+		CouponDiscount: f.CouponDiscount,
+		FinalPrice:     f.FinalPackagePrice.FinalPrice,
+		InsurerAmount:  f.InsurerAmount,
+		PatientAmount:  f.PatientAmount,
+		AppliedRules:   f.AppliedRules,
+	}}
+}
+
+// El cupón se descuenta de la parte que paga el paciente; recibe un descuento por línea
+func (f *FinalPackagePriceWithInsegurance) ApplyCoupon(code string, discounts []money.Money) {
+	discount := discounts[0]
+	f.CouponCode = code
+	f.CouponDiscount = discount
+	f.FinalPackagePrice.FinalPrice -= discount
+	f.PatientAmount -= discount
+}
+
+// Precio final de una cita con varias líneas; cada línea conserva su precio, sus descuentos, sus reglas
+// aplicadas y su reparto con la aseguradora, y los montos de la cita son la suma de sus líneas
+type FinalAppointmentPrice struct {
+	Items          []AppointmentItem
+	TotalAmount    money.Money
+	Discount       money.Money
+	CouponCode     string
+	CouponDiscount money.Money
+	FinalPrice     money.Money
+	CoverageShares
+	AppliedRules []AppliedPricingRule
+}
+
+func (f *FinalAppointmentPrice) GetFinalPrice() money.Money {
+	return f.FinalPrice
+}
+
+func (f *FinalAppointmentPrice) GetCouponDiscount() money.Money {
+	return f.CouponDiscount
+}
+
+func (f *FinalAppointmentPrice) GetItems() []AppointmentItem {
+	return f.Items
+}
+
+// El cupón se descuenta de la parte que paga el paciente en cada línea
+func (f *FinalAppointmentPrice) ApplyCoupon(code string, discounts []money.Money) {
+	f.CouponCode = code
+	for i := range f.Items {
+		f.Items[i].CouponDiscount = discounts[i]
+		f.Items[i].FinalPrice -= discounts[i]
+		f.Items[i].PatientAmount -= discounts[i]
+		f.CouponDiscount += discounts[i]
+		f.FinalPrice -= discounts[i]
+		f.PatientAmount -= discounts[i]
+	}
+}
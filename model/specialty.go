@@ -0,0 +1,48 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Especialidad médica del catálogo; los médicos pueden tener varias
+type Specialty struct {
+	ID   uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name string `json:"name" gorm:"size:50;not null;uniqueIndex" validate:"required,max=50"`
+}
+
+// Disponibilidad de un médico en una fecha: los tramos de su turno que no ocupan sus citas
+type DoctorAvailability struct {
+	Doctor    Doctor     `json:"doctor"`
+	Date      string     `json:"date"`
+	FreeSlots []TimeSlot `json:"free_slots"`
+}
+
+// Tramo horario (HH:MM)
+type TimeSlot struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// Une la especialidad con la indicada en TargetID: sus médicos y servicios pasan a la de destino
+type MergeSpecialtyRequest struct {
+	TargetID uint `json:"target_id" validate:"required"`
+}
+
+// Clave para comparar nombres de especialidades sin distinguir mayúsculas, tildes ni espacios.
+// Nombres distintos de una misma especialidad (p. ej. "Cardio" y "Cardiología") no se reconocen;
+// el administrador los une con POST /specialties/:id/merge
+func SpecialtyKey(name string) string {
+	result := []rune{}
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		result = append(result, r)
+	}
+
+	return strings.Join(strings.Fields(string(result)), " ")
+}
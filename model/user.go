@@ -2,8 +2,20 @@ package model
 
 import "gorm.io/gorm"
 
+// Un usuario con rol médico se vincula con su registro de médico mediante DoctorID
 type User struct {
 	gorm.Model
-	Email    string `gorm:"size:50;not null"`
-	Password string `gorm:"size:50;not null"`
+	Email    string   `gorm:"size:50;not null"`
+	Password string   `gorm:"size:50;not null"`
+	Role     UserRole `gorm:"size:20;not null;default:admin"`
+	DoctorID *uint    `gorm:"index"`
 }
+
+// Rol del usuario dentro de la clínica
+type UserRole string
+
+const (
+	RoleAdmin  UserRole = "admin"
+	RoleStaff  UserRole = "personal"
+	RoleDoctor UserRole = "medico"
+)
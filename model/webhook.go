@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// Eventos del dominio a los que se puede suscribir un webhook
+var WebhookEvents = []EventType{
+	EventAppointmentBooked,
+	EventAppointmentCancelled,
+	EventAppointmentRescheduled,
+	EventPaymentRegistered,
+	EventPaymentRefunded,
+	EventPatientUpdated,
+}
+
+// Suscripción de un sistema externo (laboratorio, contabilidad) a eventos del dominio. El secreto
+// solo se muestra al registrarla o cambiarlo; con él el receptor valida la firma de cada envío.
+type WebhookSubscription struct {
+	ID        uint        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string      `gorm:"size:100" json:"name"`
+	URL       string      `gorm:"size:500" json:"url"`
+	Events    []EventType `gorm:"serializer:json" json:"events"`
+	Secret    string      `gorm:"size:100" json:"secret,omitempty"`
+	Active    bool        `json:"active"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Indica si la suscripción recibe los eventos de eventType
+func (s *WebhookSubscription) Subscribed(eventType EventType) bool {
+	for _, event := range s.Events {
+		if event == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sin secreto se genera uno al registrar el webhook y se conserva el actual al actualizarlo
+type WebhookRequest struct {
+	Name   string      `json:"name" validate:"required,max=100"`
+	URL    string      `json:"url" validate:"required,url,max=500"`
+	Events []EventType `json:"events" validate:"required,min=1"`
+	Secret string      `json:"secret" validate:"omitempty,min=16,max=100"`
+	Active bool        `json:"active"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDelivered WebhookDeliveryStatus = "entregado"
+	WebhookFailed    WebhookDeliveryStatus = "fallido"
+)
+
+// Registro de cada intento de envío de un evento a una suscripción. Replay marca los reenvíos
+// pedidos por un administrador.
+type WebhookDelivery struct {
+	ID             uint                  `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubscriptionID uint                  `gorm:"index" json:"subscription_id"`
+	EventID        uint                  `gorm:"index" json:"event_id"`
+	EventType      EventType             `gorm:"size:40" json:"event_type"`
+	URL            string                `gorm:"size:500" json:"url"`
+	Attempt        int                   `json:"attempt"`
+	Replay         bool                  `json:"replay"`
+	Status         WebhookDeliveryStatus `gorm:"size:20" json:"status"`
+	StatusCode     int                   `json:"status_code,omitempty"`
+	ResponseBody   string                `gorm:"size:500" json:"response_body,omitempty"`
+	Error          string                `gorm:"size:255" json:"error,omitempty"`
+	DurationMs     int64                 `json:"duration_ms"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
@@ -0,0 +1,212 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Monto de dinero expresado en céntimos (unidades menores) para evitar los errores de redondeo de float64.
+// Reglas de redondeo:
+//   - Los montos de entrada (JSON o texto) admiten como máximo dos decimales, no se redondean.
+//   - Los porcentajes se aplican redondeando al céntimo más cercano, la mitad se aleja del cero.
+//   - Un descuento se resta del monto original, por lo que monto = descuento + resultado siempre es exacto.
+type Money int64
+
+var ErrInvalidAmount = errors.New("el monto es inválido, use un número con hasta dos decimales")
+
+// Convierte un float64 redondeando al céntimo más cercano
+func FromFloat(amount float64) Money {
+	return Money(math.Round(amount * 100))
+}
+
+// Convierte un monto en unidades enteras (por ejemplo soles) a céntimos
+func FromUnits(units int64) Money {
+	return Money(units * 100)
+}
+
+// Interpreta un monto decimal como "84.99", "-3.5" o "120"
+func Parse(amount string) (Money, error) {
+	cents, err := parseFixed(amount, 2)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+
+	return Money(cents), nil
+}
+
+// Interpreta un número decimal con como máximo la cantidad de decimales indicada, escalado a entero
+func parseFixed(number string, decimals int) (int64, error) {
+	number = strings.TrimSpace(number)
+	if number == "" {
+		return 0, strconv.ErrSyntax
+	}
+
+	negative := false
+	if number[0] == '-' || number[0] == '+' {
+		negative = number[0] == '-'
+		number = number[1:]
+	}
+
+	integerPart, fractionPart, hasFraction := strings.Cut(number, ".")
+	if integerPart == "" || (hasFraction && (fractionPart == "" || len(fractionPart) > decimals)) {
+		return 0, strconv.ErrSyntax
+	}
+
+	scale := int64(math.Pow10(decimals))
+
+	units, err := strconv.ParseUint(integerPart, 10, 63)
+	if err != nil || units > uint64(math.MaxInt64/scale) {
+		return 0, strconv.ErrRange
+	}
+
+	var fraction uint64
+	if hasFraction {
+		fraction, err = strconv.ParseUint(fractionPart+strings.Repeat("0", decimals-len(fractionPart)), 10, 63)
+		if err != nil {
+			return 0, strconv.ErrSyntax
+		}
+	}
+
+	result := int64(units)*scale + int64(fraction)
+	if negative {
+		result = -result
+	}
+
+	return result, nil
+}
+
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Calcula el porcentaje indicado del monto (15 = 15%), redondeado al céntimo con la mitad alejándose del cero
+func (m Money) Percentage(percent float64) Money {
+	basisPoints := int64(math.Round(percent * 100))
+
+	return Money(roundDiv(int64(m)*basisPoints, 10000))
+}
+
+// Multiplica el monto por una cantidad entera
+func (m Money) Times(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// Reparte el monto en proporción a los pesos indicados; la última parte recibe el resto,
+// por lo que la suma de las partes siempre es igual al monto
+func (m Money) Allocate(weights []Money) []Money {
+	parts := make([]Money, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	var total Money
+	for _, weight := range weights {
+		total += weight
+	}
+
+	var allocated Money
+	for i := 0; i < len(weights)-1; i++ {
+		if total != 0 {
+			parts[i] = Money(roundBigDiv(big.NewInt(int64(m)), big.NewInt(int64(weights[i])), big.NewInt(int64(total))))
+		}
+
+		allocated += parts[i]
+	}
+
+	parts[len(weights)-1] = m - allocated
+
+	return parts
+}
+
+func roundDiv(numerator, denominator int64) int64 {
+	quotient := numerator / denominator
+	remainder := numerator % denominator
+	if remainder < 0 {
+		remainder = -remainder
+	}
+
+	if remainder*2 >= denominator {
+		if numerator < 0 {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+
+	return quotient
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// Acepta números JSON (84.99) o cadenas ("84.99")
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" || text == "" {
+		return nil
+	}
+
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// Se guarda como DECIMAL(12,2) en MySQL
+func (m Money) GormDataType() string {
+	return "decimal(12,2)"
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		return m.scanText(string(v))
+	case string:
+		return m.scanText(v)
+	case float64:
+		*m = FromFloat(v)
+		return nil
+	case int64:
+		*m = FromUnits(v)
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T", value)
+	}
+}
+
+func (m *Money) scanText(text string) error {
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
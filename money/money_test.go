@@ -0,0 +1,134 @@
+package money
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPercentage(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		percent float64
+		want    Money
+	}{
+		{name: "exacto", amount: FromUnits(200), percent: 15, want: FromUnits(30)},
+		{name: "redondea hacia arriba", amount: 333, percent: 10, want: 33},
+		{name: "media hacia arriba", amount: 5, percent: 10, want: 1},
+		{name: "media negativa se aleja del cero", amount: -5, percent: 10, want: -1},
+		{name: "porcentaje con decimales", amount: FromUnits(100), percent: 12.5, want: 1250},
+		{name: "porcentaje con dos decimales", amount: 9999, percent: 33.33, want: 3333},
+		{name: "cero por ciento", amount: FromUnits(80), percent: 0, want: 0},
+		{name: "cien por ciento", amount: 8499, percent: 100, want: 8499},
+		{name: "monto cero", amount: 0, percent: 50, want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.amount.Percentage(test.percent)
+			if got != test.want {
+				t.Errorf("%s.Percentage(%v) = %s, want %s", test.amount, test.percent, got, test.want)
+			}
+		})
+	}
+}
+
+// Un porcentaje de 0 a 100 nunca supera el monto, así que monto - descuento tampoco es negativo
+func TestPercentageWithinAmount(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		amount := Money(random.Int63n(10_000_000))
+		percent := float64(random.Intn(10001)) / 100
+
+		discount := amount.Percentage(percent)
+		if discount < 0 || discount > amount {
+			t.Fatalf("%s.Percentage(%v) = %s, outside [0, %s]", amount, percent, discount, amount)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		weights []Money
+		want    []Money
+	}{
+		{name: "partes iguales", amount: FromUnits(100), weights: []Money{1, 1}, want: []Money{5000, 5000}},
+		{name: "resto a la última parte", amount: 100, weights: []Money{1, 1, 1}, want: []Money{33, 33, 34}},
+		{name: "proporcional al precio", amount: FromUnits(90), weights: []Money{FromUnits(100), FromUnits(200)}, want: []Money{3000, 6000}},
+		{name: "pesos en cero", amount: 500, weights: []Money{0, 0}, want: []Money{0, 500}},
+		{name: "sin pesos", amount: 500, weights: nil, want: []Money{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.amount.Allocate(test.weights)
+			if len(got) != len(test.want) {
+				t.Fatalf("Allocate returned %d parts, want %d", len(got), len(test.want))
+			}
+
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Errorf("part %d = %s, want %s", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAllocateSumsToAmount(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		amount := Money(random.Int63n(10_000_000))
+		weights := make([]Money, 1+random.Intn(6))
+		for j := range weights {
+			weights[j] = Money(random.Int63n(1_000_000))
+		}
+
+		var sum Money
+		for _, part := range amount.Allocate(weights) {
+			sum += part
+		}
+
+		if sum != amount {
+			t.Fatalf("%s.Allocate(%v) sums to %s", amount, weights, sum)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{input: "84.99", want: 8499},
+		{input: "120", want: 12000},
+		{input: "-3.5", want: -350},
+		{input: " 0.01 ", want: 1},
+		{input: "1.999", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "1.", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := Parse(test.input)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Parse(%q) = %s, want error", test.input, got)
+				}
+
+				return
+			}
+
+			if err != nil || got != test.want {
+				t.Errorf("Parse(%q) = %s, %v, want %s", test.input, got, err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+const rateScale = 1000000
+
+// Tipo de cambio con seis decimales: unidades de la moneda base por una unidad de la otra moneda
+type Rate int64
+
+// Tipo de cambio de una moneda consigo misma
+const OneRate Rate = rateScale
+
+var ErrInvalidRate = errors.New("el tipo de cambio es inválido, use un número positivo con hasta seis decimales")
+
+func ParseRate(rate string) (Rate, error) {
+	scaled, err := parseFixed(rate, 6)
+	if err != nil || scaled <= 0 {
+		return 0, ErrInvalidRate
+	}
+
+	return Rate(scaled), nil
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%d.%06d", int64(r)/rateScale, int64(r)%rateScale)
+}
+
+// Convierte un monto en la moneda del tipo de cambio a la moneda base
+func (m Money) ToBase(rate Rate) Money {
+	return Money(roundBigDiv(big.NewInt(int64(m)), big.NewInt(int64(rate)), big.NewInt(rateScale)))
+}
+
+// Convierte un monto en moneda base a la moneda del tipo de cambio
+func (m Money) FromBase(rate Rate) Money {
+	return Money(roundBigDiv(big.NewInt(int64(m)), big.NewInt(rateScale), big.NewInt(int64(rate))))
+}
+
+// Calcula a*b/c redondeando la mitad lejos del cero
+func roundBigDiv(a, b, c *big.Int) int64 {
+	numerator := new(big.Int).Mul(a, b)
+	quotient, remainder := new(big.Int).QuoRem(numerator, c, new(big.Int))
+
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(new(big.Int).Abs(c)) >= 0 {
+		if numerator.Sign()*c.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return math.MaxInt64
+	}
+
+	return quotient.Int64()
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" || text == "" {
+		return nil
+	}
+
+	parsed, err := ParseRate(text)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+// Se guarda como DECIMAL(18,6) en MySQL
+func (r Rate) GormDataType() string {
+	return "decimal(18,6)"
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+func (r *Rate) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = 0
+		return nil
+	case []byte:
+		return r.scanText(string(v))
+	case string:
+		return r.scanText(v)
+	case float64:
+		*r = Rate(math.Round(v * rateScale))
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan rate %T", value)
+	}
+}
+
+func (r *Rate) scanText(text string) error {
+	parsed, err := ParseRate(text)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+)
+
+// Medio por el que se envía un mensaje al paciente (correo o SMS)
+type Channel interface {
+	Send(message *Message) error
+}
+
+// Mensaje ya armado; To es el correo o el teléfono según el canal. Los SMS solo usan Body.
+type Message struct {
+	To          string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Crea el canal de correo indicado en NOTIFICATION_EMAIL_DRIVER
+func NewEmailChannel(cfg *config.Config) (Channel, error) {
+	switch cfg.EmailDriver {
+	case "fake":
+		return NewFakeChannel("email"), nil
+	case "smtp":
+		if cfg.SMTPHost == "" || cfg.SMTPFrom == "" {
+			return nil, errors.New("SMTP_HOST and SMTP_FROM are required for the smtp email driver")
+		}
+
+		return NewSMTPChannel(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom), nil
+	}
+
+	return nil, fmt.Errorf("unknown email driver %q", cfg.EmailDriver)
+}
+
+// Crea el canal de SMS indicado en NOTIFICATION_SMS_DRIVER
+func NewSMSChannel(cfg *config.Config) (Channel, error) {
+	switch cfg.SMSDriver {
+	case "fake":
+		return NewFakeChannel("sms"), nil
+	case "http":
+		if cfg.SMSAPIURL == "" {
+			return nil, errors.New("SMS_API_URL is required for the http sms driver")
+		}
+
+		return NewSMSProviderChannel(NewHTTPSMSProvider(cfg.SMSAPIURL, cfg.SMSAPIKey, cfg.SMSSender)), nil
+	}
+
+	return nil, fmt.Errorf("unknown sms driver %q", cfg.SMSDriver)
+}
@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"log"
+	"sync"
+)
+
+// Canal que no envía nada: registra los mensajes en el log y en memoria. Es el canal por defecto
+// en desarrollo y el que usan las pruebas para revisar qué se habría enviado.
+type FakeChannel struct {
+	name     string
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewFakeChannel(name string) *FakeChannel {
+	return &FakeChannel{name: name}
+}
+
+func (c *FakeChannel) Send(message *Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages = append(c.messages, *message)
+
+	log.Printf("notification: fake %s to %s: %q (%d attachments)", c.name, message.To, message.Subject, len(message.Attachments))
+
+	return nil
+}
+
+// Copia de los mensajes enviados hasta ahora
+func (c *FakeChannel) Messages() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Message(nil), c.messages...)
+}
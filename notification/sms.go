@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Proveedor de SMS; cada proveedor (Twilio, una pasarela local, etc.) se integra implementando este método
+type SMSProvider interface {
+	SendSMS(to, text string) error
+}
+
+// Canal de SMS sobre un proveedor; el asunto y los adjuntos del mensaje no se envían
+type SMSProviderChannel struct {
+	provider SMSProvider
+}
+
+func NewSMSProviderChannel(provider SMSProvider) *SMSProviderChannel {
+	return &SMSProviderChannel{provider: provider}
+}
+
+func (c *SMSProviderChannel) Send(message *Message) error {
+	return c.provider.SendSMS(message.To, message.Body)
+}
+
+// Pasarela de SMS por HTTP: envía {"from", "to", "text"} en JSON a SMS_API_URL con la clave como Bearer
+type HTTPSMSProvider struct {
+	url    string
+	apiKey string
+	sender string
+	client *http.Client
+}
+
+func NewHTTPSMSProvider(url, apiKey, sender string) *HTTPSMSProvider {
+	return &HTTPSMSProvider{
+		url:    url,
+		apiKey: apiKey,
+		sender: sender,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *HTTPSMSProvider) SendSMS(to, text string) error {
+	body, err := json.Marshal(map[string]string{
+		"from": p.sender,
+		"to":   to,
+		"text": text,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms provider responded %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// Envía correos por SMTP; con usuario se autentica con PLAIN (net/smtp exige TLS salvo en localhost)
+type SMTPChannel struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPChannel(host, port, username, password, from string) *SMTPChannel {
+	channel := &SMTPChannel{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+	}
+
+	if username != "" {
+		channel.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return channel
+}
+
+func (c *SMTPChannel) Send(message *Message) error {
+	data, err := buildEmail(c.from, message)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(c.addr, c.auth, c.from, []string{message.To}, data)
+}
+
+// Correo MIME multipart/mixed: el texto en UTF-8 y cada adjunto en base64
+func buildEmail(from string, message *Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", writer.Boundary())
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeBase64(part, []byte(message.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range message.Attachments {
+		part, err = writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = writeBase64(part, attachment.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Base64 en líneas de 76 caracteres, como pide MIME
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	for len(encoded) > 76 {
+		_, err := fmt.Fprintf(w, "%s\r\n", encoded[:76])
+		if err != nil {
+			return err
+		}
+
+		encoded = encoded[76:]
+	}
+
+	_, err := fmt.Fprintf(w, "%s\r\n", encoded)
+
+	return err
+}
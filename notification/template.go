@@ -0,0 +1,184 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+)
+
+// Datos disponibles en las plantillas de los avisos
+type Data struct {
+	ClinicName        string
+	PatientName       string
+	DoctorName        string
+	AppointmentID     uint
+	Date              string
+	StartTime         string
+	EndTime           string
+	PreviousDate      string
+	PreviousStartTime string
+	InvoiceNumber     string
+	Amount            string
+	HoursBefore       int
+}
+
+// Plantilla de un aviso: asunto y cuerpo del correo, y el texto corto del SMS
+type Template struct {
+	Subject string
+	Body    string
+	SMS     string
+}
+
+var templates = map[model.NotificationEvent]map[string]Template{
+	model.NotificationAppointmentBooked: {
+		"es": {
+			Subject: "Cita reservada para el {{.Date}}",
+			Body: `Hola {{.PatientName}},
+
+Su cita N.° {{.AppointmentID}} quedó reservada para el {{.Date}} de {{.StartTime}} a {{.EndTime}}{{if .DoctorName}} con {{.DoctorName}}{{end}}.
+{{if .Amount}}Monto a pagar: {{.Amount}}
+{{end}}
+Le esperamos,
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: su cita quedó reservada para el {{.Date}} a las {{.StartTime}}.",
+		},
+		"en": {
+			Subject: "Appointment booked for {{.Date}}",
+			Body: `Hello {{.PatientName}},
+
+Your appointment #{{.AppointmentID}} is booked for {{.Date}} from {{.StartTime}} to {{.EndTime}}{{if .DoctorName}} with {{.DoctorName}}{{end}}.
+{{if .Amount}}Amount due: {{.Amount}}
+{{end}}
+See you soon,
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: your appointment is booked for {{.Date}} at {{.StartTime}}.",
+		},
+	},
+	model.NotificationAppointmentRescheduled: {
+		"es": {
+			Subject: "Cita reprogramada para el {{.Date}}",
+			Body: `Hola {{.PatientName}},
+
+Su cita N.° {{.AppointmentID}} del {{.PreviousDate}} a las {{.PreviousStartTime}} se reprogramó para el {{.Date}} de {{.StartTime}} a {{.EndTime}}{{if .DoctorName}} con {{.DoctorName}}{{end}}.
+
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: su cita se reprogramó para el {{.Date}} a las {{.StartTime}}.",
+		},
+		"en": {
+			Subject: "Appointment rescheduled to {{.Date}}",
+			Body: `Hello {{.PatientName}},
+
+Your appointment #{{.AppointmentID}} on {{.PreviousDate}} at {{.PreviousStartTime}} was rescheduled to {{.Date}} from {{.StartTime}} to {{.EndTime}}{{if .DoctorName}} with {{.DoctorName}}{{end}}.
+
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: your appointment was rescheduled to {{.Date}} at {{.StartTime}}.",
+		},
+	},
+	model.NotificationAppointmentCancelled: {
+		"es": {
+			Subject: "Cita del {{.Date}} cancelada",
+			Body: `Hola {{.PatientName}},
+
+Su cita N.° {{.AppointmentID}} del {{.Date}} a las {{.StartTime}} fue cancelada. Si desea, puede reservar una nueva cita.
+
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: su cita del {{.Date}} a las {{.StartTime}} fue cancelada.",
+		},
+		"en": {
+			Subject: "Appointment on {{.Date}} cancelled",
+			Body: `Hello {{.PatientName}},
+
+Your appointment #{{.AppointmentID}} on {{.Date}} at {{.StartTime}} was cancelled. You are welcome to book a new one.
+
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: your appointment on {{.Date}} at {{.StartTime}} was cancelled.",
+		},
+	},
+	model.NotificationPaymentReceived: {
+		"es": {
+			Subject: "Pago recibido - {{.InvoiceNumber}}",
+			Body: `Hola {{.PatientName}},
+
+Recibimos su pago de {{.Amount}} por la cita N.° {{.AppointmentID}} del {{.Date}}. Adjuntamos el recibo {{.InvoiceNumber}}.
+
+Gracias,
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: recibimos su pago de {{.Amount}} ({{.InvoiceNumber}}).",
+		},
+		"en": {
+			Subject: "Payment received - {{.InvoiceNumber}}",
+			Body: `Hello {{.PatientName}},
+
+We received your payment of {{.Amount}} for appointment #{{.AppointmentID}} on {{.Date}}. Receipt {{.InvoiceNumber}} is attached.
+
+Thank you,
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: we received your payment of {{.Amount}} ({{.InvoiceNumber}}).",
+		},
+	},
+	model.NotificationAppointmentReminder: {
+		"es": {
+			Subject: "Recordatorio: su cita es el {{.Date}} a las {{.StartTime}}",
+			Body: `Hola {{.PatientName}},
+
+Le recordamos que su cita N.° {{.AppointmentID}} es en {{.HoursBefore}} horas: el {{.Date}} de {{.StartTime}} a {{.EndTime}}{{if .DoctorName}} con {{.DoctorName}}{{end}}.
+Si no puede asistir, por favor avísenos para reprogramarla.
+
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: recuerde su cita del {{.Date}} a las {{.StartTime}}.",
+		},
+		"en": {
+			Subject: "Reminder: your appointment is on {{.Date}} at {{.StartTime}}",
+			Body: `Hello {{.PatientName}},
+
+This is a reminder that your appointment #{{.AppointmentID}} is in {{.HoursBefore}} hours: {{.Date}} from {{.StartTime}} to {{.EndTime}}{{if .DoctorName}} with {{.DoctorName}}{{end}}.
+If you cannot attend, please let us know so we can reschedule it.
+
+{{.ClinicName}}`,
+			SMS: "{{.ClinicName}}: reminder of your appointment on {{.Date}} at {{.StartTime}}.",
+		},
+	},
+}
+
+// Arma el aviso del evento en el idioma pedido con los datos ya reemplazados; si no hay plantilla
+// en ese idioma se usa la española
+func Render(event model.NotificationEvent, lang string, data *Data) (*Template, error) {
+	byLanguage, exists := templates[event]
+	if !exists {
+		return nil, fmt.Errorf("no notification template for event %q", event)
+	}
+
+	tmpl, exists := byLanguage[lang]
+	if !exists {
+		tmpl = byLanguage["es"]
+	}
+
+	rendered := &Template{}
+
+	for _, part := range []struct {
+		text string
+		out  *string
+	}{
+		{tmpl.Subject, &rendered.Subject},
+		{tmpl.Body, &rendered.Body},
+		{tmpl.SMS, &rendered.SMS},
+	} {
+		parsed, err := template.New(string(event)).Parse(part.text)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+
+		err = parsed.Execute(&buf, data)
+		if err != nil {
+			return nil, err
+		}
+
+		*part.out = buf.String()
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,91 @@
+package pdftemplate
+
+import "strings"
+
+// Idiomas en que se imprimen los documentos
+var Languages = []string{"es", "en"}
+
+// Textos de los documentos por idioma; las claves son las que usan las plantillas
+var labels = map[string]map[string]string{
+	"es": {
+		"receipt":        "Recibo de Cita Médica",
+		"invoice":        "Comprobante de Pago",
+		"confirmation":   "Confirmación de Cita",
+		"invoice_number": "Boleta",
+		"issued_at":      "Fecha de emisión",
+		"appointment":    "ID de Cita",
+		"patient":        "Paciente",
+		"patient_dni":    "DNI",
+		"doctor":         "Médico",
+		"date":           "Fecha",
+		"start_time":     "Hora de Inicio",
+		"end_time":       "Hora de Fin",
+		"status":         "Estado",
+		"payment_type":   "Método de pago",
+		"exchange_rate":  "Tipo de cambio",
+		"detail":         "Detalle",
+		"discount":       "Descuento",
+		"coupon":         "Cupón",
+		"prepaid":        "Crédito prepagado",
+		"each":           "c/u",
+		"credit":         "Cubierto con un crédito de paquete prepagado",
+		"insurer":        "Cubierto por el seguro",
+		"total":          "Monto Total",
+		"equivalent":     "Equivalente",
+		"refunded":       "ANULADO: pago reembolsado el",
+		"verify":         "Escanee el código QR para verificar la autenticidad del recibo",
+		"page":           "Página",
+	},
+	"en": {
+		"receipt":        "Medical Appointment Receipt",
+		"invoice":        "Payment Invoice",
+		"confirmation":   "Appointment Confirmation",
+		"invoice_number": "Invoice",
+		"issued_at":      "Issue date",
+		"appointment":    "Appointment ID",
+		"patient":        "Patient",
+		"patient_dni":    "ID number",
+		"doctor":         "Doctor",
+		"date":           "Date",
+		"start_time":     "Start time",
+		"end_time":       "End time",
+		"status":         "Status",
+		"payment_type":   "Payment method",
+		"exchange_rate":  "Exchange rate",
+		"detail":         "Detail",
+		"discount":       "Discount",
+		"coupon":         "Coupon",
+		"prepaid":        "Prepaid credit",
+		"each":           "each",
+		"credit":         "Covered by a prepaid package credit",
+		"insurer":        "Covered by insurance",
+		"total":          "Total amount",
+		"equivalent":     "Equivalent",
+		"refunded":       "VOID: payment refunded on",
+		"verify":         "Scan the QR code to verify the receipt is authentic",
+		"page":           "Page",
+	},
+}
+
+// Normaliza el idioma pedido; uno no soportado se reemplaza por fallback
+func Language(lang, fallback string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if _, exists := labels[lang]; exists {
+		return lang
+	}
+
+	return fallback
+}
+
+// Texto de la clave en el idioma; si falta se usa el español y, en último caso, la propia clave
+func Label(lang, key string) string {
+	if text, exists := labels[lang][key]; exists {
+		return text
+	}
+
+	if text, exists := labels["es"][key]; exists {
+		return text
+	}
+
+	return key
+}
@@ -0,0 +1,265 @@
+package pdftemplate
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// DejaVu Sans Condensed: cubre los acentos y la ñ del español, que las fuentes base del PDF no imprimen bien
+//
+//go:embed fonts/*.ttf
+var embeddedFonts embed.FS
+
+const fontFamily = "dejavu"
+
+// Datos de un documento ya calculados; la plantilla decide cuáles se imprimen y en qué orden
+type Document struct {
+	Kind     Kind
+	Language string
+	IssuedAt time.Time
+	Fields   map[string]string
+	Lines    []Line
+	Totals   []Field
+	Notices  []Field
+	QRCode   []byte
+}
+
+// Línea del detalle con sus descuentos
+type Line struct {
+	Description string
+	Amount      string
+	Details     []Field
+}
+
+// Dato con su etiqueta; Key es la clave de la etiqueta y Value puede ir vacío
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Imprime los documentos con la marca de la clínica. Se crea una vez al iniciar y puede usarse
+// desde varias peticiones a la vez: cada Render arma su propio PDF.
+type Renderer struct {
+	clinicName string
+	header     string
+	footer     string
+	logo       []byte
+	logoType   string
+	regular    []byte
+	bold       []byte
+	templates  map[Kind]Template
+	language   string
+}
+
+func NewRenderer(cfg *config.Config) (*Renderer, error) {
+	regular, err := readFont(cfg.PDFFontPath, "fonts/DejaVuSansCondensed.ttf")
+	if err != nil {
+		return nil, err
+	}
+
+	bold, err := readFont(cfg.PDFFontBoldPath, "fonts/DejaVuSansCondensed-Bold.ttf")
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := loadTemplates(cfg.PDFTemplatesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	renderer := &Renderer{
+		clinicName: cfg.ClinicName,
+		header:     cfg.ClinicHeader,
+		footer:     cfg.ClinicFooter,
+		regular:    regular,
+		bold:       bold,
+		templates:  templates,
+		language:   Language(cfg.DefaultLanguage, "es"),
+	}
+
+	if cfg.ClinicLogoPath != "" {
+		renderer.logoType, err = imageType(cfg.ClinicLogoPath)
+		if err != nil {
+			return nil, err
+		}
+
+		renderer.logo, err = os.ReadFile(cfg.ClinicLogoPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return renderer, nil
+}
+
+// Idioma a usar para lang; vacío o no soportado devuelve el idioma por defecto
+func (r *Renderer) Language(lang string) string {
+	return Language(lang, r.language)
+}
+
+// Idioma por defecto configurado en DEFAULT_LANGUAGE
+func (r *Renderer) DefaultLanguage() string {
+	return r.language
+}
+
+// Genera el PDF del documento. Las fechas del PDF son IssuedAt, así que el mismo documento
+// siempre produce los mismos bytes.
+func (r *Renderer) Render(doc *Document) ([]byte, error) {
+	template, exists := r.templates[doc.Kind]
+	if !exists {
+		return nil, fmt.Errorf("unknown PDF template %q", doc.Kind)
+	}
+
+	lang := r.Language(doc.Language)
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCreationDate(doc.IssuedAt)
+	pdf.SetModificationDate(doc.IssuedAt)
+	pdf.SetCatalogSort(true)
+	pdf.SetTitle(Label(lang, template.Title), true)
+	pdf.SetAuthor(r.clinicName, true)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", r.regular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", r.bold)
+
+	if r.logo != nil {
+		pdf.RegisterImageOptionsReader("logo", gofpdf.ImageOptions{ImageType: r.logoType}, bytes.NewReader(r.logo))
+	}
+
+	pdf.SetHeaderFunc(func() { r.writeHeader(pdf) })
+	pdf.SetFooterFunc(func() { r.writeFooter(pdf, lang) })
+	pdf.AddPage()
+
+	pdf.SetFont(fontFamily, "B", 16)
+	pdf.Cell(0, 10, Label(lang, template.Title))
+	pdf.Ln(12)
+
+	// Avisos como la anulación del pago van antes que los datos
+	pdf.SetFont(fontFamily, "B", 12)
+	for _, notice := range doc.Notices {
+		pdf.MultiCell(0, 8, labeled(lang, notice, " "), "", "", false)
+	}
+
+	pdf.SetFont(fontFamily, "", 12)
+	for _, key := range template.Fields {
+		value, exists := doc.Fields[key]
+		if !exists || value == "" {
+			continue
+		}
+
+		pdf.MultiCell(0, 8, fmt.Sprintf("%s: %s", Label(lang, key), value), "", "", false)
+	}
+	pdf.Ln(4)
+
+	if *template.ShowLines && len(doc.Lines) > 0 {
+		pdf.SetFont(fontFamily, "B", 12)
+		pdf.Cell(0, 10, Label(lang, "detail"))
+		pdf.Ln(8)
+		pdf.SetFont(fontFamily, "", 11)
+		for _, line := range doc.Lines {
+			pdf.CellFormat(140, 6, line.Description, "", 0, "", false, 0, "")
+			pdf.CellFormat(0, 6, line.Amount, "", 1, "R", false, 0, "")
+			for _, detail := range line.Details {
+				pdf.CellFormat(140, 6, "    "+Label(lang, detail.Key), "", 0, "", false, 0, "")
+				pdf.CellFormat(0, 6, detail.Value, "", 1, "R", false, 0, "")
+			}
+		}
+		pdf.Ln(4)
+	}
+
+	if *template.ShowTotals {
+		pdf.SetFont(fontFamily, "", 12)
+		for _, total := range doc.Totals {
+			pdf.MultiCell(0, 8, labeled(lang, total, ": "), "", "", false)
+		}
+		pdf.Ln(4)
+	}
+
+	if *template.ShowQRCode && doc.QRCode != nil {
+		pdf.RegisterImageOptionsReader("qr", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(doc.QRCode))
+		pdf.Image("qr", 10, pdf.GetY(), 50, 50, false, "qr", 0, "")
+		pdf.SetY(pdf.GetY() + 52)
+		pdf.SetFont(fontFamily, "", 9)
+		pdf.Cell(0, 6, Label(lang, "verify"))
+	}
+
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Logo, nombre de la clínica y texto de cabecera en cada página
+func (r *Renderer) writeHeader(pdf *gofpdf.Fpdf) {
+	left := 10.0
+	if r.logo != nil {
+		pdf.ImageOptions("logo", 10, 8, 0, 16, false, gofpdf.ImageOptions{ImageType: r.logoType}, 0, "")
+		left = 32
+	}
+
+	pdf.SetXY(left, 9)
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.Cell(0, 7, r.clinicName)
+
+	if r.header != "" {
+		pdf.SetXY(left, 16)
+		pdf.SetFont(fontFamily, "", 9)
+		pdf.MultiCell(0, 4, r.header, "", "", false)
+	}
+
+	pdf.SetY(28)
+	pdf.Line(10, pdf.GetY(), 200, pdf.GetY())
+	pdf.Ln(4)
+}
+
+// Texto de pie y número de página
+func (r *Renderer) writeFooter(pdf *gofpdf.Fpdf, lang string) {
+	pdf.Line(10, 277, 200, 277)
+	pdf.SetFont(fontFamily, "", 8)
+
+	if r.footer != "" {
+		pdf.SetY(-19)
+		pdf.MultiCell(150, 4, r.footer, "", "", false)
+	}
+
+	pdf.SetXY(160, -19)
+	pdf.CellFormat(40, 4, fmt.Sprintf("%s %d", Label(lang, "page"), pdf.PageNo()), "", 0, "R", false, 0, "")
+}
+
+func labeled(lang string, field Field, separator string) string {
+	if field.Value == "" {
+		return Label(lang, field.Key)
+	}
+
+	return Label(lang, field.Key) + separator + field.Value
+}
+
+// Fuente de PDF_FONT_PATH / PDF_FONT_BOLD_PATH o, si no se indica, la incluida en el binario
+func readFont(path, embedded string) ([]byte, error) {
+	if path != "" {
+		return os.ReadFile(path)
+	}
+
+	return embeddedFonts.ReadFile(embedded)
+}
+
+func imageType(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return "PNG", nil
+	case ".jpg", ".jpeg":
+		return "JPG", nil
+	}
+
+	return "", fmt.Errorf("unsupported clinic logo format %s: use PNG or JPEG", path)
+}
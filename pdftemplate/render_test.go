@@ -0,0 +1,131 @@
+package pdftemplate
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
+	"github.com/skip2/go-qrcode"
+)
+
+// go test ./pdftemplate -update regenera los archivos de testdata tras un cambio intencional en el diseño
+var update = flag.Bool("update", false, "rewrite the golden PDFs in testdata")
+
+func testRenderer(t *testing.T) *Renderer {
+	t.Helper()
+
+	renderer, err := NewRenderer(&config.Config{
+		ClinicName:      "Clínica Hackacode",
+		ClinicHeader:    "Av. Arequipa 1234, Lima · RUC 20123456789",
+		ClinicFooter:    "Gracias por su preferencia",
+		DefaultLanguage: "es",
+	})
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	return renderer
+}
+
+func testDocument(t *testing.T, kind Kind, lang string) *Document {
+	t.Helper()
+
+	qrCode, err := qrcode.Encode("https://clinica.example/receipts/verify?token=golden", qrcode.Medium, 256)
+	if err != nil {
+		t.Fatalf("qrcode: %v", err)
+	}
+
+	return &Document{
+		Kind:     kind,
+		Language: lang,
+		IssuedAt: time.Date(2026, time.March, 14, 9, 30, 0, 0, time.UTC),
+		Fields: map[string]string{
+			"invoice_number": "B001-00000042",
+			"issued_at":      "2026-03-14 09:30",
+			"appointment":    "42",
+			"patient":        "María Peña",
+			"patient_dni":    "12345678",
+			"doctor":         "Dr. José Núñez",
+			"date":           "2026-03-20",
+			"start_time":     "10:00",
+			"end_time":       "10:30",
+			"status":         "programada",
+			"payment_type":   "tarjeta",
+		},
+		Lines: []Line{
+			{Description: "Consulta de cardiología", Amount: "100.00", Details: []Field{{Key: "discount", Value: "-10.00"}}},
+			{Description: "Electrocardiograma x 2", Amount: "91.00", Details: []Field{{Key: "each", Value: "45.50"}}},
+		},
+		Totals: []Field{
+			{Key: "coupon", Value: "BIENVENIDA -5.00 PEN"},
+			{Key: "insurer", Value: "64.00 PEN"},
+			{Key: "total", Value: "112.00 PEN"},
+		},
+		QRCode: qrCode,
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	renderer := testRenderer(t)
+
+	for _, kind := range []Kind{KindReceipt, KindInvoice, KindConfirmation} {
+		for _, lang := range Languages {
+			name := string(kind) + "_" + lang
+
+			t.Run(name, func(t *testing.T) {
+				got, err := renderer.Render(testDocument(t, kind, lang))
+				if err != nil {
+					t.Fatalf("Render: %v", err)
+				}
+
+				golden := filepath.Join("testdata", name+".pdf")
+
+				if *update {
+					err = os.WriteFile(golden, got, 0o644)
+					if err != nil {
+						t.Fatalf("writing %s: %v", golden, err)
+					}
+				}
+
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("reading %s (run with -update to create it): %v", golden, err)
+				}
+
+				if !bytes.Equal(got, want) {
+					t.Errorf("%s differs from the rendered PDF; if the change is intended run go test ./pdftemplate -update", golden)
+				}
+			})
+		}
+	}
+}
+
+// Las fechas del PDF salen de IssuedAt, así que dos renderizados del mismo documento son idénticos
+func TestRenderIsDeterministic(t *testing.T) {
+	renderer := testRenderer(t)
+
+	first, err := renderer.Render(testDocument(t, KindReceipt, "es"))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	second, err := renderer.Render(testDocument(t, KindReceipt, "es"))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if !bytes.Equal(first, second) {
+		t.Error("rendering the same document twice produced different PDFs")
+	}
+}
+
+func TestRenderUnknownKind(t *testing.T) {
+	_, err := testRenderer(t).Render(&Document{Kind: "prescription"})
+	if err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+}
@@ -0,0 +1,115 @@
+package pdftemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Tipo de documento que se imprime
+type Kind string
+
+const (
+	KindReceipt      Kind = "receipt"
+	KindInvoice      Kind = "invoice"
+	KindConfirmation Kind = "confirmation"
+)
+
+// Diseño de un documento: título y orden de los datos que se imprimen. Title y Fields son claves
+// de las etiquetas (ver Label); una clave sin traducción se imprime tal cual.
+type Template struct {
+	Title      string   `json:"title"`
+	Fields     []string `json:"fields"`
+	ShowLines  *bool    `json:"show_lines,omitempty"`
+	ShowTotals *bool    `json:"show_totals,omitempty"`
+	ShowQRCode *bool    `json:"show_qr_code,omitempty"`
+}
+
+func enabled() *bool {
+	value := true
+	return &value
+}
+
+func disabled() *bool {
+	value := false
+	return &value
+}
+
+// Plantillas por defecto; PDF_TEMPLATES_PATH puede reemplazar cualquiera de sus partes
+func defaultTemplates() map[Kind]Template {
+	return map[Kind]Template{
+		KindReceipt: {
+			Title:      "receipt",
+			Fields:     []string{"invoice_number", "appointment", "patient", "date", "start_time", "end_time"},
+			ShowLines:  enabled(),
+			ShowTotals: enabled(),
+			ShowQRCode: enabled(),
+		},
+		KindInvoice: {
+			Title:      "invoice",
+			Fields:     []string{"invoice_number", "issued_at", "patient", "patient_dni", "payment_type", "appointment", "date"},
+			ShowLines:  enabled(),
+			ShowTotals: enabled(),
+			ShowQRCode: enabled(),
+		},
+		KindConfirmation: {
+			Title:      "confirmation",
+			Fields:     []string{"appointment", "patient", "doctor", "date", "start_time", "end_time", "status"},
+			ShowLines:  enabled(),
+			ShowTotals: enabled(),
+			ShowQRCode: disabled(),
+		},
+	}
+}
+
+// Lee las plantillas del archivo JSON ({"receipt": {...}, "invoice": {...}}) y las combina con las
+// por defecto; lo que el archivo no indica se mantiene
+func loadTemplates(path string) (map[Kind]Template, error) {
+	templates := defaultTemplates()
+	if path == "" {
+		return templates, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := map[Kind]Template{}
+
+	err = json.Unmarshal(data, &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PDF templates file %s: %w", path, err)
+	}
+
+	for kind, override := range overrides {
+		template, exists := templates[kind]
+		if !exists {
+			return nil, fmt.Errorf("unknown PDF template %q in %s", kind, path)
+		}
+
+		if override.Title != "" {
+			template.Title = override.Title
+		}
+
+		if override.Fields != nil {
+			template.Fields = override.Fields
+		}
+
+		if override.ShowLines != nil {
+			template.ShowLines = override.ShowLines
+		}
+
+		if override.ShowTotals != nil {
+			template.ShowTotals = override.ShowTotals
+		}
+
+		if override.ShowQRCode != nil {
+			template.ShowQRCode = override.ShowQRCode
+		}
+
+		templates[kind] = template
+	}
+
+	return templates, nil
+}
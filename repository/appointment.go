@@ -8,6 +8,7 @@ import (
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/validate"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AppointmentRepository interface {
@@ -15,8 +16,15 @@ type AppointmentRepository interface {
 	GetAll(limit, offset int) ([]model.Appointment, error)
 	GetAppointmentsByDoctor(doctorID uint) ([]model.Appointment, error)
 	GetAppointmentsByDoctorAndDate(doctorID uint, date time.Time) ([]model.Appointment, error)
+	Create(appointment *model.Appointment) error
+	Delete(appointment *model.Appointment) error
 	UpdatePaid(appointmentID uint) error
+	UpdateStatus(appointmentID uint, status model.AppointmentStatus) error
+	UpdateWithItems(appointment, previous *model.Appointment) error
 	UnlinkPatientAppointments(patientID uint) error
+	GetScheduledBetween(from, to time.Time) ([]model.Appointment, error)
+	GetUnpaidScheduledUntil(date time.Time) ([]model.Appointment, error)
+	ExpireUnpaid(appointmentID uint) (bool, error)
 }
 
 type appointmentRepository struct {
@@ -31,6 +39,7 @@ func (r *appointmentRepository) GetByID(ID uint) (*model.Appointment, error) {
 	var appointment model.Appointment
 	err := r.db.
 		Preload("Patient").
+		Preload("Items").
 		Where("id = ?", ID).
 		First(&appointment).
 		Error
@@ -48,7 +57,7 @@ func (r *appointmentRepository) GetByID(ID uint) (*model.Appointment, error) {
 
 func (r *appointmentRepository) GetAll(limit, offset int) ([]model.Appointment, error) {
 	var appointments []model.Appointment
-	query := r.db.Preload("Patient")
+	query := r.db.Preload("Patient").Preload("Items")
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -85,8 +94,9 @@ func (r *appointmentRepository) GetAppointmentsByDoctorAndDate(doctorID uint, da
 
 	dateStr := validate.FormatDate(date)
 
+	//las citas canceladas no ocupan el horario del médico
 	err := r.db.
-		Where("doctor_id = ? AND date = ?", doctorID, dateStr).
+		Where("doctor_id = ? AND date = ? AND status <> ?", doctorID, dateStr, model.AppointmentCancelled).
 		Find(&appointments).
 		Error
 	if err != nil {
@@ -96,22 +106,133 @@ func (r *appointmentRepository) GetAppointmentsByDoctorAndDate(doctorID uint, da
 	return appointments, nil
 }
 
+// Marca la cita como pagada solo si sigue impaga y admite pagos; el trabajo de citas impagas puede
+// haberla vencido mientras se registraba el pago
 func (r *appointmentRepository) UpdatePaid(appointmentID uint) error {
-	appointment := model.Appointment{}
+	result := r.db.
+		Model(&model.Appointment{}).
+		Where("id = ? AND paid = ? AND status IN ?", appointmentID, false, model.PayableAppointmentStatuses).
+		Update("paid", true)
+	if result.Error != nil {
+		return result.Error
+	}
 
-	err := r.db.First(&appointment, appointmentID).Error
-	if err != nil {
-		return err
+	if result.RowsAffected == 0 {
+		return notPayableReason(r.db, appointmentID)
 	}
 
-	appointment.Paid = true
+	return nil
+}
 
-	err = r.db.Save(&appointment).Error
+// Motivo por el que la cita no se pudo marcar como pagada
+func notPayableReason(db *gorm.DB, appointmentID uint) error {
+	var appointment model.Appointment
+
+	err := db.Select("id", "paid", "status").First(&appointment, appointmentID).Error
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if appointment.Paid {
+		return response.ErrorAppointmentPaid
+	}
+
+	return response.ErrorAppointmentNotPayable
+}
+
+// Reserva la cita y escribe AppointmentBooked en la misma transacción
+func (r *appointmentRepository) Create(appointment *model.Appointment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createAppointment(tx, appointment)
+	})
+}
+
+// Cambia el estado de una cita programada; al cancelarla devuelve la sesión prepagada, libera el uso
+// del cupón y escribe AppointmentCancelled en la misma transacción
+func (r *appointmentRepository) UpdateStatus(appointmentID uint, status model.AppointmentStatus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&model.Appointment{}).
+			Where("id = ? AND status = ?", appointmentID, model.AppointmentScheduled).
+			Update("status", status)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Otra petición la completó o canceló mientras tanto
+		if result.RowsAffected == 0 {
+			return response.ErrorAppointmentNotScheduled
+		}
+
+		if status != model.AppointmentCancelled {
+			return nil
+		}
+
+		appointment := model.Appointment{}
+
+		err := tx.Preload("Items").First(&appointment, appointmentID).Error
+		if err != nil {
+			return err
+		}
+
+		err = releaseCredit(tx, &appointment)
+		if err != nil {
+			return err
+		}
+
+		err = releaseCoupon(tx, appointment.ID)
+		if err != nil {
+			return err
+		}
+
+		return addEvent(tx, model.EventAppointmentCancelled, appointment.ID, model.NewAppointmentEvent(&appointment))
+	})
+}
+
+// Elimina la cita; si seguía programada, para el paciente queda cancelada: se devuelve la sesión
+// prepagada y se escribe AppointmentCancelled. La cita se bloquea para leer su estado actual.
+func (r *appointmentRepository) Delete(appointment *model.Appointment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		current := model.Appointment{}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, appointment.ID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Delete(&model.Appointment{}, appointment.ID).Error
+		if err != nil || current.Status != model.AppointmentScheduled {
+			return err
+		}
+
+		err = releaseCredit(tx, &current)
+		if err != nil {
+			return err
+		}
+
+		event := model.NewAppointmentEvent(appointment)
+		event.Status = model.AppointmentCancelled
+
+		return addEvent(tx, model.EventAppointmentCancelled, appointment.ID, event)
+	})
+}
+
+// Reemplaza las líneas de la cita por las del precio recalculado; si cambió el día, el horario o el
+// médico respecto de previous escribe AppointmentRescheduled en la misma transacción
+func (r *appointmentRepository) UpdateWithItems(appointment, previous *model.Appointment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("appointment_id = ?", appointment.ID).Delete(&model.AppointmentItem{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Save(appointment).Error
+		if err != nil || !appointment.IsRescheduled(previous) {
+			return err
+		}
+
+		return addEvent(tx, model.EventAppointmentRescheduled, appointment.ID, model.NewRescheduledEvent(appointment, previous))
+	})
 }
 
 func (r *appointmentRepository) UnlinkPatientAppointments(patientID uint) error {
@@ -126,3 +247,73 @@ func (r *appointmentRepository) UnlinkPatientAppointments(patientID uint) error
 
 	return nil
 }
+
+// Citas programadas entre dos días (inclusive)
+func (r *appointmentRepository) GetScheduledBetween(from, to time.Time) ([]model.Appointment, error) {
+	var appointments []model.Appointment
+
+	err := r.db.
+		Where("status = ? AND date BETWEEN ? AND ?", model.AppointmentScheduled, validate.FormatDate(from), validate.FormatDate(to)).
+		Find(&appointments).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// Citas programadas hasta el día indicado que no se pagaron; las cubiertas con un crédito de paquete no se cobran
+func (r *appointmentRepository) GetUnpaidScheduledUntil(date time.Time) ([]model.Appointment, error) {
+	var appointments []model.Appointment
+
+	err := r.db.
+		Where("status = ? AND paid = ? AND credit_id IS NULL AND date <= ?", model.AppointmentScheduled, false, validate.FormatDate(date)).
+		Find(&appointments).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// Marca la cita como vencida solo si sigue programada y sin pagar, por si se pagó mientras tanto,
+// y libera el uso del cupón en la misma transacción
+func (r *appointmentRepository) ExpireUnpaid(appointmentID uint) (bool, error) {
+	expired := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&model.Appointment{}).
+			Where("id = ? AND status = ? AND paid = ?", appointmentID, model.AppointmentScheduled, false).
+			Update("status", model.AppointmentExpired)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		expired = true
+
+		return releaseCoupon(tx, appointmentID)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return expired, nil
+}
+
+// Inserta la cita con sus líneas y su evento AppointmentBooked; la usan todas las formas de reservar
+// (sin cupón, con cupón y con crédito de paquete) dentro de su propia transacción
+func createAppointment(tx *gorm.DB, appointment *model.Appointment) error {
+	if appointment.Status == "" {
+		appointment.Status = model.AppointmentScheduled
+	}
+
+	err := tx.Create(appointment).Error
+	if err != nil {
+		return err
+	}
+
+	return addEvent(tx, model.EventAppointmentBooked, appointment.ID, model.NewAppointmentEvent(appointment))
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CashSessionRepository interface {
+	GetByID(ID uint) (*model.CashSession, error)
+	GetAll(limit, offset int) ([]model.CashSession, error)
+	GetOpen() (*model.CashSession, error)
+	Open(session *model.CashSession) error
+	Close(session *model.CashSession) error
+}
+
+type cashSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewCashSessionRepository(db *gorm.DB) CashSessionRepository {
+	return &cashSessionRepository{db: db}
+}
+
+func (r *cashSessionRepository) GetByID(ID uint) (*model.CashSession, error) {
+	var session model.CashSession
+
+	err := r.db.
+		Preload("Counts").
+		First(&session, ID).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorCashSessionNotFound
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *cashSessionRepository) GetAll(limit, offset int) ([]model.CashSession, error) {
+	var sessions []model.CashSession
+
+	query := r.db.Preload("Counts").Order("opened_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *cashSessionRepository) GetOpen() (*model.CashSession, error) {
+	var session model.CashSession
+
+	err := r.db.
+		Where("status = ?", model.CashSessionOpen).
+		First(&session).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorCashSessionNotOpen
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Abre la sesión marcando OpenGuard; si otra apertura se confirmó antes, el índice único rechaza esta
+func (r *cashSessionRepository) Open(session *model.CashSession) error {
+	openGuard := true
+	session.Status = model.CashSessionOpen
+	session.OpenGuard = &openGuard
+
+	err := r.db.Create(session).Error
+	if err != nil {
+		_, openErr := r.GetOpen()
+		if openErr == nil {
+			return response.ErrorCashSessionAlreadyOpen
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Guarda el cierre de la sesión junto con sus conteos. La sesión se bloquea y se vuelve a leer su estado
+// para que dos cierres simultáneos no registren dos arqueos
+func (r *cashSessionRepository) Close(session *model.CashSession) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		current := model.CashSession{}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, session.ID).Error
+		if err != nil {
+			return err
+		}
+
+		if current.Status == model.CashSessionClosed {
+			return response.ErrorCashSessionAlreadyClosed
+		}
+
+		err = tx.Where("cash_session_id = ?", session.ID).Delete(&model.CashSessionCount{}).Error
+		if err != nil {
+			return err
+		}
+
+		session.OpenGuard = nil
+
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(session).Error
+	})
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+)
+
+type ServiceCategoryRepository interface {
+	GetByName(name string) (*model.ServiceCategory, error)
+	CountServices(name string) (int64, error)
+	Rename(category *model.ServiceCategory, oldName string) error
+}
+
+type serviceCategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewServiceCategoryRepository(db *gorm.DB) ServiceCategoryRepository {
+	return &serviceCategoryRepository{db: db}
+}
+
+func (r *serviceCategoryRepository) GetByName(name string) (*model.ServiceCategory, error) {
+	var category model.ServiceCategory
+
+	err := r.db.Where("name = ?", name).First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorServiceCategoryNotFound
+		}
+
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *serviceCategoryRepository) CountServices(name string) (int64, error) {
+	var count int64
+
+	err := r.db.Model(&model.Service{}).Where("category = ?", name).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Guarda la categoría y, si cambió su nombre, lo actualiza en los servicios, las reglas de precios
+// y las coberturas que la referencian, para que sigan asociados
+func (r *serviceCategoryRepository) Rename(category *model.ServiceCategory, oldName string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(category).Error
+		if err != nil {
+			return err
+		}
+
+		if category.Name == oldName {
+			return nil
+		}
+
+		err = tx.Model(&model.Service{}).Where("category = ?", oldName).Update("category", category.Name).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.PricingRule{}).Where("service_category = ?", oldName).Update("service_category", category.Name).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.PlanCoverage{}).Where("service_category = ?", oldName).Update("service_category", category.Name).Error
+	})
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/money"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ClaimRepository interface {
+	GetByID(ID uint) (*model.InsuranceClaim, error)
+	GetAll(providerID uint, status model.ClaimStatus, limit, offset int) ([]model.InsuranceClaim, error)
+	GetOpen() ([]model.InsuranceClaim, error)
+	GetUnclaimedByProvider() (map[uint]money.Money, error)
+	CreateClaim(claim *model.InsuranceClaim) error
+	UpdateStatus(claim *model.InsuranceClaim) error
+	RegisterPayment(claimID uint, payment *model.Payment) (*model.InsuranceClaim, error)
+}
+
+type claimRepository struct {
+	db *gorm.DB
+}
+
+func NewClaimRepository(db *gorm.DB) ClaimRepository {
+	return &claimRepository{db: db}
+}
+
+// Estados en los que el reclamo todavía tiene saldo por cobrar
+var openClaimStatuses = []model.ClaimStatus{model.ClaimSubmitted, model.ClaimApproved, model.ClaimPartiallyPaid}
+
+func (r *claimRepository) GetByID(ID uint) (*model.InsuranceClaim, error) {
+	var claim model.InsuranceClaim
+
+	err := r.db.
+		Preload("Provider").
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("date, appointment_id")
+		}).
+		First(&claim, "id = ?", ID).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorClaimNotFound
+		}
+
+		return nil, err
+	}
+
+	return &claim, nil
+}
+
+func (r *claimRepository) GetAll(providerID uint, status model.ClaimStatus, limit, offset int) ([]model.InsuranceClaim, error) {
+	var claims []model.InsuranceClaim
+
+	query := r.db.Preload("Provider").Order("created_at DESC, id DESC")
+	if providerID != 0 {
+		query = query.Where("provider_id = ?", providerID)
+	}
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&claims).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (r *claimRepository) GetOpen() ([]model.InsuranceClaim, error) {
+	var claims []model.InsuranceClaim
+
+	err := r.db.
+		Preload("Provider").
+		Where("status IN ?", openClaimStatuses).
+		Find(&claims).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// Suma por aseguradora de la parte cubierta de las citas completadas que aún no se reclamaron
+func (r *claimRepository) GetUnclaimedByProvider() (map[uint]money.Money, error) {
+	var rows []struct {
+		ProviderID uint
+		Amount     money.Money
+	}
+
+	err := claimableAppointments(r.db).
+		Select("insurance_plans.provider_id, SUM(appointments.insurer_amount) AS amount").
+		Group("insurance_plans.provider_id").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	unclaimed := map[uint]money.Money{}
+	for _, row := range rows {
+		unclaimed[row.ProviderID] = row.Amount
+	}
+
+	return unclaimed, nil
+}
+
+// Las citas se reclaman dentro de la transacción para que dos reclamos simultáneos no incluyan la misma cita
+func (r *claimRepository) CreateClaim(claim *model.InsuranceClaim) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var items []model.ClaimItem
+
+		err := claimableAppointments(tx).
+			Select("appointments.id AS appointment_id, appointments.date, appointments.insurer_amount AS amount, "+
+				"patient_policies.policy_number, "+
+				"COALESCE(CONCAT(patients.name, ' ', patients.last_name), '') AS patient_name, "+
+				"COALESCE(patients.dni, '') AS patient_dni").
+			Joins("LEFT JOIN patients ON patients.id = appointments.patient_id").
+			Where("insurance_plans.provider_id = ?", claim.ProviderID).
+			Where("appointments.date BETWEEN ? AND ?", claim.PeriodFrom, claim.PeriodTo).
+			Order("appointments.date, appointments.id").
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "appointments"}}).
+			Scan(&items).
+			Error
+		if err != nil {
+			return err
+		}
+
+		if len(items) == 0 {
+			return response.ErrorClaimNoAppointments
+		}
+
+		claim.ClaimedAmount = 0
+		for _, item := range items {
+			claim.ClaimedAmount += item.Amount
+		}
+
+		claim.Items = items
+
+		return tx.Create(claim).Error
+	})
+}
+
+func (r *claimRepository) UpdateStatus(claim *model.InsuranceClaim) error {
+	return r.db.
+		Model(&model.InsuranceClaim{ID: claim.ID}).
+		Select("status", "approved_amount", "notes").
+		Updates(claim).
+		Error
+}
+
+// Registra el pago en el libro y actualiza el saldo del reclamo; el reclamo se bloquea para no cobrar de más
+func (r *claimRepository) RegisterPayment(claimID uint, payment *model.Payment) (*model.InsuranceClaim, error) {
+	var claim model.InsuranceClaim
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&claim, claimID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return response.ErrorClaimNotFound
+			}
+
+			return err
+		}
+
+		if claim.Status != model.ClaimApproved && claim.Status != model.ClaimPartiallyPaid {
+			return response.ErrorClaimNotPayable
+		}
+
+		if claim.PaidAmount+payment.BaseAmount > claim.ApprovedAmount {
+			return response.ErrorClaimOverpaid
+		}
+
+		payment.ClaimID = &claim.ID
+
+		err = tx.Create(payment).Error
+		if err != nil {
+			return err
+		}
+
+		claim.PaidAmount += payment.BaseAmount
+		claim.Status = model.ClaimPartiallyPaid
+		if claim.PaidAmount == claim.ApprovedAmount {
+			claim.Status = model.ClaimPaid
+		}
+
+		return tx.
+			Model(&model.InsuranceClaim{ID: claim.ID}).
+			Select("paid_amount", "status").
+			Updates(&claim).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &claim, nil
+}
+
+// Citas completadas con parte cubierta por el seguro que no están en un reclamo vigente;
+// las citas de un reclamo rechazado pueden volver a reclamarse
+func claimableAppointments(db *gorm.DB) *gorm.DB {
+	claimed := db.
+		Table("claim_items").
+		Select("claim_items.appointment_id").
+		Joins("JOIN insurance_claims ON insurance_claims.id = claim_items.claim_id").
+		Where("insurance_claims.status <> ?", model.ClaimRejected)
+
+	return db.
+		Model(&model.Appointment{}).
+		Joins("JOIN patient_policies ON patient_policies.id = appointments.policy_id").
+		Joins("JOIN insurance_plans ON insurance_plans.id = patient_policies.plan_id").
+		Where("appointments.status = ? AND appointments.insurer_amount > 0", model.AppointmentCompleted).
+		Where("appointments.id NOT IN (?)", claimed)
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CouponRepository interface {
+	GetByID(ID uint) (*model.Coupon, error)
+	GetByCode(code string) (*model.Coupon, error)
+	GetAll(limit, offset int) ([]model.Coupon, error)
+	CountRedemptions(couponID uint) (int64, error)
+	CountPatientRedemptions(couponID, patientID uint) (int64, error)
+	ClearEligibility(couponID uint) error
+	Delete(ID uint) error
+	CreateAppointmentWithCoupon(appointment *model.Appointment, coupon *model.Coupon) error
+}
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+func NewCouponRepository(db *gorm.DB) CouponRepository {
+	return &couponRepository{db: db}
+}
+
+func (r *couponRepository) GetByID(ID uint) (*model.Coupon, error) {
+	var coupon model.Coupon
+
+	err := r.db.
+		Preload("Services").
+		Preload("Packages").
+		First(&coupon, "id = ?", ID).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorCouponNotFound
+		}
+
+		return nil, err
+	}
+
+	return &coupon, nil
+}
+
+func (r *couponRepository) GetByCode(code string) (*model.Coupon, error) {
+	var coupon model.Coupon
+
+	err := r.db.
+		Preload("Services").
+		Preload("Packages").
+		First(&coupon, "code = ?", code).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorCouponNotFound
+		}
+
+		return nil, err
+	}
+
+	return &coupon, nil
+}
+
+func (r *couponRepository) GetAll(limit, offset int) ([]model.Coupon, error) {
+	var coupons []model.Coupon
+
+	query := r.db.Preload("Services").Preload("Packages")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&coupons).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return coupons, nil
+}
+
+func (r *couponRepository) CountRedemptions(couponID uint) (int64, error) {
+	return countRedemptions(r.db, couponID, 0)
+}
+
+func (r *couponRepository) CountPatientRedemptions(couponID, patientID uint) (int64, error) {
+	return countRedemptions(r.db, couponID, patientID)
+}
+
+func (r *couponRepository) ClearEligibility(couponID uint) error {
+	err := r.db.Exec("DELETE FROM coupon_services WHERE coupon_id = ?", couponID).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.Exec("DELETE FROM coupon_packages WHERE coupon_id = ?", couponID).Error
+}
+
+func (r *couponRepository) Delete(ID uint) error {
+	err := r.ClearEligibility(ID)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Delete(&model.Coupon{}, ID).Error
+}
+
+// Registra la cita y el uso del cupón en una sola transacción.
+// El cupón se bloquea para que los límites de uso se verifiquen sin carreras entre reservas simultáneas.
+func (r *couponRepository) CreateAppointmentWithCoupon(appointment *model.Appointment, coupon *model.Coupon) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		locked := model.Coupon{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, coupon.ID).Error
+		if err != nil {
+			return err
+		}
+
+		if locked.MaxUses > 0 {
+			used, err := countRedemptions(tx, coupon.ID, 0)
+			if err != nil {
+				return err
+			}
+
+			if used >= int64(locked.MaxUses) {
+				return response.ErrorCouponExhausted
+			}
+		}
+
+		if locked.MaxUsesPerPatient > 0 {
+			used, err := countRedemptions(tx, coupon.ID, appointment.PatientID)
+			if err != nil {
+				return err
+			}
+
+			if used >= int64(locked.MaxUsesPerPatient) {
+				return response.ErrorCouponPatientLimit
+			}
+		}
+
+		err = createAppointment(tx, appointment)
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&model.CouponRedemption{
+			CouponID:      coupon.ID,
+			PatientID:     appointment.PatientID,
+			AppointmentID: appointment.ID,
+		}).Error
+	})
+}
+
+// Libera el uso del cupón de una cita que no llega a realizarse; la usan la cancelación y el vencimiento
+// de la cita dentro de su propia transacción
+func releaseCoupon(tx *gorm.DB, appointmentID uint) error {
+	return tx.Where("appointment_id = ?", appointmentID).Delete(&model.CouponRedemption{}).Error
+}
+
+func countRedemptions(db *gorm.DB, couponID, patientID uint) (int64, error) {
+	var count int64
+
+	query := db.Model(&model.CouponRedemption{}).Where("coupon_id = ?", couponID)
+	if patientID != 0 {
+		query = query.Where("patient_id = ?", patientID)
+	}
+
+	err := query.Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PackageCreditRepository interface {
+	GetPurchaseByID(ID uint) (*model.PackagePurchase, error)
+	GetCreditByID(ID uint) (*model.PackageCredit, error)
+	GetCreditsByPatient(patientID uint) ([]model.PackageCredit, error)
+	GetAvailableCredit(patientID, serviceID uint, date string) (*model.PackageCredit, error)
+	CreatePurchase(purchase *model.PackagePurchase, payment *model.Payment) error
+	CreateAppointmentWithCredit(appointment *model.Appointment) error
+}
+
+type packageCreditRepository struct {
+	db *gorm.DB
+}
+
+func NewPackageCreditRepository(db *gorm.DB) PackageCreditRepository {
+	return &packageCreditRepository{db: db}
+}
+
+func (r *packageCreditRepository) GetPurchaseByID(ID uint) (*model.PackagePurchase, error) {
+	var purchase model.PackagePurchase
+
+	err := r.db.
+		Preload("Package").
+		Preload("Credits.Service").
+		First(&purchase, "id = ?", ID).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorPackagePurchaseNotFound
+		}
+
+		return nil, err
+	}
+
+	return &purchase, nil
+}
+
+func (r *packageCreditRepository) GetCreditByID(ID uint) (*model.PackageCredit, error) {
+	var credit model.PackageCredit
+
+	err := r.db.First(&credit, "id = ?", ID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorPackageCreditNotFound
+		}
+
+		return nil, err
+	}
+
+	return &credit, nil
+}
+
+func (r *packageCreditRepository) GetCreditsByPatient(patientID uint) ([]model.PackageCredit, error) {
+	var credits []model.PackageCredit
+
+	err := r.db.
+		Preload("Service").
+		Where("patient_id = ?", patientID).
+		Order("id DESC").
+		Find(&credits).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return credits, nil
+}
+
+// Crédito con sesiones disponibles vigente en la fecha; se usa primero el que vence antes
+// y los créditos sin vencimiento quedan al final
+func (r *packageCreditRepository) GetAvailableCredit(patientID, serviceID uint, date string) (*model.PackageCredit, error) {
+	var credit model.PackageCredit
+
+	err := r.db.
+		Where("patient_id = ? AND service_id = ? AND used < total", patientID, serviceID).
+		Where("expires_on = '' OR expires_on >= ?", date).
+		Order("expires_on = '', expires_on, id").
+		First(&credit).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorPackageCreditNotFound
+		}
+
+		return nil, err
+	}
+
+	return &credit, nil
+}
+
+// La venta, sus créditos y el pago en el libro se registran juntos
+func (r *packageCreditRepository) CreatePurchase(purchase *model.PackagePurchase, payment *model.Payment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(purchase).Error
+		if err != nil {
+			return err
+		}
+
+		payment.PurchaseID = &purchase.ID
+
+		return tx.Create(payment).Error
+	})
+}
+
+// Consume una sesión del crédito y registra la cita; el crédito se bloquea para no consumir de más
+func (r *packageCreditRepository) CreateAppointmentWithCredit(appointment *model.Appointment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		credit := model.PackageCredit{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&credit, *appointment.CreditID).Error
+		if err != nil {
+			return err
+		}
+
+		if credit.Used >= credit.Total {
+			return response.ErrorPackageCreditExhausted
+		}
+
+		if credit.ExpiresOn != "" && credit.ExpiresOn < appointment.Date {
+			return response.ErrorPackageCreditExpired
+		}
+
+		err = tx.Model(&credit).Update("used", gorm.Expr("used + 1")).Error
+		if err != nil {
+			return err
+		}
+
+		return createAppointment(tx, appointment)
+	})
+}
+
+// Devuelve al crédito la sesión de una cita que no llega a realizarse; la usan la cancelación y la
+// eliminación de la cita dentro de su propia transacción
+func releaseCredit(tx *gorm.DB, appointment *model.Appointment) error {
+	if appointment.CreditID == nil {
+		return nil
+	}
+
+	return tx.
+		Model(&model.PackageCredit{}).
+		Where("id = ? AND used > 0", *appointment.CreditID).
+		Update("used", gorm.Expr("used - 1")).
+		Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+)
+
+type ExchangeRateRepository interface {
+	GetAll(currency string, limit, offset int) ([]model.ExchangeRate, error)
+	GetEffective(currency, date string) (*model.ExchangeRate, error)
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) GetAll(currency string, limit, offset int) ([]model.ExchangeRate, error) {
+	var rates []model.ExchangeRate
+
+	query := r.db.Order("currency, effective_from DESC, id DESC")
+	if currency != "" {
+		query = query.Where("currency = ?", currency)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// Último tipo de cambio registrado con vigencia igual o anterior a la fecha (AAAA-MM-DD)
+func (r *exchangeRateRepository) GetEffective(currency, date string) (*model.ExchangeRate, error) {
+	var rate model.ExchangeRate
+
+	err := r.db.
+		Where("currency = ? AND effective_from <= ?", currency, date).
+		Order("effective_from DESC, id DESC").
+		First(&rate).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorExchangeRateNotFound
+		}
+
+		return nil, err
+	}
+
+	return &rate, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
 	"gorm.io/gorm"
@@ -8,6 +10,9 @@ import (
 
 type DoctorRepository interface {
 	GetDoctorByDNI(DNI string) (*model.Doctor, error)
+	GetByID(ID uint) (*model.Doctor, error)
+	GetAll(specialtyID uint, limit, offset int) ([]model.Doctor, error)
+	UpdateWithSpecialties(doctor *model.Doctor) error
 }
 
 type doctorRepository struct {
@@ -22,6 +27,7 @@ func (r *doctorRepository) GetDoctorByDNI(DNI string) (*model.Doctor, error) {
 	var doctor model.Doctor
 
 	err := r.db.
+		Preload("Specialties").
 		Where("dni = ?", DNI).
 		First(&doctor).
 		Error
@@ -35,3 +41,59 @@ func (r *doctorRepository) GetDoctorByDNI(DNI string) (*model.Doctor, error) {
 
 	return &doctor, nil
 }
+
+func (r *doctorRepository) GetByID(ID uint) (*model.Doctor, error) {
+	var doctor model.Doctor
+
+	err := r.db.
+		Preload("Specialties").
+		First(&doctor, "id = ?", ID).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorDoctorNotFoundID
+		}
+
+		return nil, err
+	}
+
+	return &doctor, nil
+}
+
+// Médicos con sus especialidades; con specialtyID distinto de 0 solo los que tienen esa especialidad
+func (r *doctorRepository) GetAll(specialtyID uint, limit, offset int) ([]model.Doctor, error) {
+	var doctors []model.Doctor
+
+	query := r.db.Preload("Specialties")
+
+	if specialtyID != 0 {
+		query = query.Where("id IN (?)", r.db.Table("doctor_specialties").Select("doctor_id").Where("specialty_id = ?", specialtyID))
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&doctors).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return doctors, nil
+}
+
+// Actualiza el médico y reemplaza sus especialidades
+func (r *doctorRepository) UpdateWithSpecialties(doctor *model.Doctor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("Specialties").Save(doctor).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(doctor).Association("Specialties").Replace(doctor.Specialties)
+	})
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"gorm.io/gorm"
+)
+
+type DocumentRepository interface {
+	GetByPatient(patientID uint) ([]model.Document, error)
+	GetByAppointment(appointmentID uint) ([]model.Document, error)
+}
+
+type documentRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentRepository(db *gorm.DB) DocumentRepository {
+	return &documentRepository{db: db}
+}
+
+func (r *documentRepository) GetByPatient(patientID uint) ([]model.Document, error) {
+	var documents []model.Document
+
+	err := r.db.Where("patient_id = ?", patientID).Order("created_at DESC, id DESC").Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+func (r *documentRepository) GetByAppointment(appointmentID uint) ([]model.Document, error) {
+	var documents []model.Document
+
+	err := r.db.Where("appointment_id = ?", appointmentID).Order("created_at DESC, id DESC").Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+)
+
+type EncounterRepository interface {
+	GetByID(ID uint) (*model.Encounter, error)
+	GetByAppointmentID(appointmentID uint) (*model.Encounter, error)
+	Create(encounter *model.Encounter) error
+	UpdateWithRevision(encounter *model.Encounter, revision *model.EncounterRevision) error
+	Sign(encounter *model.Encounter) error
+}
+
+type encounterRepository struct {
+	db *gorm.DB
+}
+
+func NewEncounterRepository(db *gorm.DB) EncounterRepository {
+	return &encounterRepository{db: db}
+}
+
+func (r *encounterRepository) GetByID(ID uint) (*model.Encounter, error) {
+	return r.first("id = ?", ID)
+}
+
+func (r *encounterRepository) GetByAppointmentID(appointmentID uint) (*model.Encounter, error) {
+	return r.first("appointment_id = ?", appointmentID)
+}
+
+func (r *encounterRepository) first(query string, arg uint) (*model.Encounter, error) {
+	var encounter model.Encounter
+
+	err := r.db.
+		Preload("Revisions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("edited_at, id")
+		}).
+		First(&encounter, query, arg).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorEncounterNotFound
+		}
+
+		return nil, err
+	}
+
+	return &encounter, nil
+}
+
+func (r *encounterRepository) Create(encounter *model.Encounter) error {
+	return r.db.Create(encounter).Error
+}
+
+// Guarda el contenido anterior como revisión y el nuevo contenido en la misma transacción
+func (r *encounterRepository) UpdateWithRevision(encounter *model.Encounter, revision *model.EncounterRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(revision).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(encounter).
+			Select("Reason", "Findings", "DiagnosisCodes", "Plan", "Notes").
+			Updates(encounter).
+			Error
+	})
+}
+
+func (r *encounterRepository) Sign(encounter *model.Encounter) error {
+	return r.db.Model(encounter).Update("signed_at", encounter.SignedAt).Error
+}
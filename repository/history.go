@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"gorm.io/gorm"
+)
+
+type PriceHistoryRepository interface {
+	Create(entry *model.PriceHistory) error
+	GetByItem(itemType model.CatalogItemType, itemID uint) ([]model.PriceHistory, error)
+	GetLatest(itemType model.CatalogItemType, itemID uint) (*model.PriceHistory, error)
+}
+
+type priceHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceHistoryRepository(db *gorm.DB) PriceHistoryRepository {
+	return &priceHistoryRepository{db: db}
+}
+
+func (r *priceHistoryRepository) Create(entry *model.PriceHistory) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *priceHistoryRepository) GetByItem(itemType model.CatalogItemType, itemID uint) ([]model.PriceHistory, error) {
+	var entries []model.PriceHistory
+
+	err := r.db.
+		Where("item_type = ? AND item_id = ?", itemType, itemID).
+		Order("effective_from, id").
+		Find(&entries).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Devuelve el último precio registrado o nil si el elemento aún no tiene historial
+func (r *priceHistoryRepository) GetLatest(itemType model.CatalogItemType, itemID uint) (*model.PriceHistory, error) {
+	var entries []model.PriceHistory
+
+	err := r.db.
+		Where("item_type = ? AND item_id = ?", itemType, itemID).
+		Order("effective_from DESC, id DESC").
+		Limit(1).
+		Find(&entries).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	return &entries[0], nil
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+)
+
+type InsuranceRepository interface {
+	GetProviderByID(ID uint) (*model.InsuranceProvider, error)
+	GetAllProviders(limit, offset int) ([]model.InsuranceProvider, error)
+	GetPlanByID(ID uint) (*model.InsurancePlan, error)
+	GetAllPlans(providerID uint, limit, offset int) ([]model.InsurancePlan, error)
+	UpdatePlan(plan *model.InsurancePlan) error
+	CountProviderPlans(providerID uint) (int64, error)
+	GetPolicyByID(ID uint) (*model.PatientPolicy, error)
+	GetPoliciesByPatient(patientID uint) ([]model.PatientPolicy, error)
+	CountPlanPolicies(planID uint) (int64, error)
+	DeletePatientPolicies(patientID uint) error
+}
+
+type insuranceRepository struct {
+	db *gorm.DB
+}
+
+func NewInsuranceRepository(db *gorm.DB) InsuranceRepository {
+	return &insuranceRepository{db: db}
+}
+
+func (r *insuranceRepository) GetProviderByID(ID uint) (*model.InsuranceProvider, error) {
+	var provider model.InsuranceProvider
+
+	err := r.db.
+		Preload("Plans.Coverages").
+		First(&provider, "id = ?", ID).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorInsuranceProviderNotFound
+		}
+
+		return nil, err
+	}
+
+	return &provider, nil
+}
+
+func (r *insuranceRepository) GetAllProviders(limit, offset int) ([]model.InsuranceProvider, error) {
+	var providers []model.InsuranceProvider
+
+	query := r.db.Preload("Plans.Coverages").Order("name")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&providers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return providers, nil
+}
+
+func (r *insuranceRepository) GetPlanByID(ID uint) (*model.InsurancePlan, error) {
+	var plan model.InsurancePlan
+
+	err := r.db.
+		Preload("Provider").
+		Preload("Coverages").
+		First(&plan, "id = ?", ID).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorInsurancePlanNotFound
+		}
+
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+func (r *insuranceRepository) GetAllPlans(providerID uint, limit, offset int) ([]model.InsurancePlan, error) {
+	var plans []model.InsurancePlan
+
+	query := r.db.Preload("Provider").Preload("Coverages")
+	if providerID != 0 {
+		query = query.Where("provider_id = ?", providerID)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&plans).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+// Reemplaza las coberturas del plan junto con sus datos en una sola transacción
+func (r *insuranceRepository) UpdatePlan(plan *model.InsurancePlan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("plan_id = ?", plan.ID).Delete(&model.PlanCoverage{}).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range plan.Coverages {
+			plan.Coverages[i].ID = 0
+		}
+
+		return tx.Omit("Provider").Session(&gorm.Session{FullSaveAssociations: true}).Save(plan).Error
+	})
+}
+
+func (r *insuranceRepository) CountProviderPlans(providerID uint) (int64, error) {
+	var count int64
+
+	err := r.db.Model(&model.InsurancePlan{}).Where("provider_id = ?", providerID).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *insuranceRepository) GetPolicyByID(ID uint) (*model.PatientPolicy, error) {
+	var policy model.PatientPolicy
+
+	err := r.db.
+		Preload("Plan.Provider").
+		Preload("Plan.Coverages").
+		First(&policy, "id = ?", ID).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorPatientPolicyNotFound
+		}
+
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// Pólizas del paciente, la de inicio de vigencia más reciente primero
+func (r *insuranceRepository) GetPoliciesByPatient(patientID uint) ([]model.PatientPolicy, error) {
+	var policies []model.PatientPolicy
+
+	err := r.db.
+		Preload("Plan.Provider").
+		Preload("Plan.Coverages").
+		Where("patient_id = ?", patientID).
+		Order("valid_from DESC, id DESC").
+		Find(&policies).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+func (r *insuranceRepository) CountPlanPolicies(planID uint) (int64, error) {
+	var count int64
+
+	err := r.db.Model(&model.PatientPolicy{}).Where("plan_id = ?", planID).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *insuranceRepository) DeletePatientPolicies(patientID uint) error {
+	return r.db.Where("patient_id = ?", patientID).Delete(&model.PatientPolicy{}).Error
+}
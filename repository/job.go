@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository interface {
+	Enqueue(job *model.Job) (bool, error)
+	GetDue(now time.Time, limit int) ([]model.Job, error)
+	Claim(ID uint, owner string, now, lockedUntil time.Time) (bool, error)
+	Release(ID uint, owner string, values map[string]interface{}) error
+	GetAll(status model.JobStatus, limit, offset int) ([]model.Job, error)
+	Retry(ID uint, now time.Time) (bool, error)
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// Inserta el trabajo salvo que ya exista otro con la misma clave; indica si se insertó
+func (r *jobRepository) Enqueue(job *model.Job) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Trabajos que ya deben correr: pendientes o en curso con el bloqueo vencido (la instancia que los tomó se cayó)
+func (r *jobRepository) GetDue(now time.Time, limit int) ([]model.Job, error) {
+	var jobs []model.Job
+
+	err := r.db.
+		Where("run_at <= ?", now).
+		Where("status = ? OR (status = ? AND locked_until < ?)", model.JobPending, model.JobRunning, now).
+		Order("run_at, id").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Toma el trabajo para owner. La condición va en el mismo UPDATE, así que si dos instancias lo
+// intentan a la vez solo una afecta la fila; run_at se vuelve a comprobar porque otra instancia pudo
+// ejecutarlo y reprogramarlo después de que GetDue lo leyera.
+func (r *jobRepository) Claim(ID uint, owner string, now, lockedUntil time.Time) (bool, error) {
+	result := r.db.
+		Model(&model.Job{}).
+		Where("id = ? AND run_at <= ?", ID, now).
+		Where("status = ? OR (status = ? AND locked_until < ?)", model.JobPending, model.JobRunning, now).
+		Updates(map[string]interface{}{
+			"status":       model.JobRunning,
+			"locked_by":    owner,
+			"locked_until": lockedUntil,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_run_at":  now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Guarda el resultado del trabajo y lo libera, solo si owner todavía lo tiene tomado
+func (r *jobRepository) Release(ID uint, owner string, values map[string]interface{}) error {
+	values["locked_by"] = ""
+	values["locked_until"] = nil
+
+	return r.db.
+		Model(&model.Job{}).
+		Where("id = ? AND locked_by = ?", ID, owner).
+		Updates(values).
+		Error
+}
+
+func (r *jobRepository) GetAll(status model.JobStatus, limit, offset int) ([]model.Job, error) {
+	var jobs []model.Job
+	query := r.db.Order("run_at DESC, id DESC")
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Vuelve a dejar pendiente un trabajo fallido, con sus intentos en cero
+func (r *jobRepository) Retry(ID uint, now time.Time) (bool, error) {
+	result := r.db.
+		Model(&model.Job{}).
+		Where("id = ? AND status = ?", ID, model.JobFailed).
+		Updates(map[string]interface{}{
+			"status":   model.JobPending,
+			"attempts": 0,
+			"run_at":   now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"gorm.io/gorm"
+)
+
+// Registros del historial médico de un paciente (alergias, enfermedades crónicas, medicamentos y antecedentes)
+type PatientRecordRepository[T any] interface {
+	GetByPatient(patientID uint) ([]T, error)
+}
+
+type patientRecordRepository[T any] struct {
+	db *gorm.DB
+}
+
+func NewPatientRecordRepository[T any](db *gorm.DB) PatientRecordRepository[T] {
+	return &patientRecordRepository[T]{db: db}
+}
+
+func (r *patientRecordRepository[T]) GetByPatient(patientID uint) ([]T, error) {
+	var records []T
+
+	err := r.db.Where("patient_id = ?", patientID).Order("id").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+type MedicalHistoryRepository interface {
+	DeletePatientHistory(patientID uint) error
+}
+
+type medicalHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewMedicalHistoryRepository(db *gorm.DB) MedicalHistoryRepository {
+	return &medicalHistoryRepository{db: db}
+}
+
+// Elimina todo el historial médico del paciente en una sola transacción
+func (r *medicalHistoryRepository) DeletePatientHistory(patientID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		records := []any{&model.PatientAllergy{}, &model.ChronicCondition{}, &model.PatientMedication{}, &model.FamilyHistory{}}
+
+		for _, record := range records {
+			err := tx.Where("patient_id = ?", patientID).Delete(record).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	GetPreference(patientID uint) (*model.NotificationPreference, error)
+	SavePreference(preference *model.NotificationPreference) error
+	CreateDelivery(delivery *model.NotificationDelivery) error
+	GetDeliveries(patientID uint, limit, offset int) ([]model.NotificationDelivery, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Preferencias del paciente; si nunca las cambió se devuelven las por defecto (solo correo)
+func (r *notificationRepository) GetPreference(patientID uint) (*model.NotificationPreference, error) {
+	var preference model.NotificationPreference
+
+	err := r.db.Where("patient_id = ?", patientID).First(&preference).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.NotificationPreference{PatientID: patientID, Email: true}, nil
+		}
+
+		return nil, err
+	}
+
+	return &preference, nil
+}
+
+// Crea o actualiza las preferencias; Select("*") guarda también los canales desactivados
+func (r *notificationRepository) SavePreference(preference *model.NotificationPreference) error {
+	if preference.ID == 0 {
+		return r.db.Create(preference).Error
+	}
+
+	return r.db.Model(preference).Select("*").Updates(preference).Error
+}
+
+func (r *notificationRepository) CreateDelivery(delivery *model.NotificationDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *notificationRepository) GetDeliveries(patientID uint, limit, offset int) ([]model.NotificationDelivery, error) {
+	var deliveries []model.NotificationDelivery
+	query := r.db.Where("patient_id = ?", patientID).Order("created_at DESC, id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
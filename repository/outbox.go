@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	GetByID(ID uint) (*model.OutboxEvent, error)
+	GetPending(limit int) ([]model.OutboxEvent, error)
+	MarkDispatched(ID uint, now time.Time) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) GetByID(ID uint) (*model.OutboxEvent, error) {
+	var event model.OutboxEvent
+
+	err := r.db.First(&event, ID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// Eventos sin entregar, en el orden en que se escribieron
+func (r *outboxRepository) GetPending(limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+
+	err := r.db.
+		Where("dispatched_at IS NULL").
+		Order("id").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *outboxRepository) MarkDispatched(ID uint, now time.Time) error {
+	return r.db.
+		Model(&model.OutboxEvent{}).
+		Where("id = ? AND dispatched_at IS NULL", ID).
+		Update("dispatched_at", now).
+		Error
+}
+
+// Escribe el evento con la transacción del cambio que lo produjo; si la transacción se revierte el
+// evento tampoco queda
+func addEvent(tx *gorm.DB, eventType model.EventType, aggregateID uint, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&model.OutboxEvent{
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     string(data),
+	}).Error
+}
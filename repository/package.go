@@ -9,8 +9,10 @@ import (
 
 type PackageRepository interface {
 	GetByID(ID uint) (*model.Package, error)
-	GetAll(limit, offset int) ([]model.Package, error)
+	GetAll(onlyActive bool, limit, offset int) ([]model.Package, error)
 	ClearServices(packageID uint) error
+	ClearSessions(packageID uint) error
+	CountReferences(ID uint) (int64, error)
 	Delete(ID uint) error
 }
 
@@ -27,6 +29,7 @@ func (r *packageRepository) GetByID(ID uint) (*model.Package, error) {
 
 	err := r.db.
 		Preload("Services").
+		Preload("Sessions").
 		First(pkg, "id = ?", ID).
 		Error
 	if err != nil {
@@ -37,10 +40,14 @@ func (r *packageRepository) GetByID(ID uint) (*model.Package, error) {
 	return pkg, nil
 }
 
-func (r *packageRepository) GetAll(limit, offset int) ([]model.Package, error) {
+func (r *packageRepository) GetAll(onlyActive bool, limit, offset int) ([]model.Package, error) {
 	var packages []model.Package
 
-	query := r.db.Preload("Services")
+	query := r.db.Preload("Services").Preload("Sessions")
+	if onlyActive {
+		query = query.Where("active = ?", true)
+	}
+
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
@@ -61,11 +68,46 @@ func (r *packageRepository) ClearServices(packageID uint) error {
 	return r.db.Exec("DELETE FROM package_services WHERE package_id = ?", packageID).Error
 }
 
+func (r *packageRepository) ClearSessions(packageID uint) error {
+	return r.db.Where("package_id = ?", packageID).Delete(&model.PackageSession{}).Error
+}
+
+// Citas, líneas de citas y ventas que usan el paquete
+func (r *packageRepository) CountReferences(ID uint) (int64, error) {
+	var appointments int64
+
+	err := r.db.Model(&model.Appointment{}).Where("package_id = ?", ID).Count(&appointments).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var items int64
+
+	err = r.db.Model(&model.AppointmentItem{}).Where("package_id = ?", ID).Count(&items).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var purchases int64
+
+	err = r.db.Model(&model.PackagePurchase{}).Where("package_id = ?", ID).Count(&purchases).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return appointments + items + purchases, nil
+}
+
 func (r *packageRepository) Delete(ID uint) error {
 	err := r.ClearServices(ID)
 	if err != nil {
 		return err
 	}
 
+	err = r.ClearSessions(ID)
+	if err != nil {
+		return err
+	}
+
 	return r.db.Delete(&model.Package{}, ID).Error
 }
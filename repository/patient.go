@@ -8,6 +8,7 @@ import (
 
 type PatientRepository interface {
 	GetPatientByDNI(DNI string) (*model.Patient, error)
+	Update(patient *model.Patient) error
 }
 
 type patientRepository struct {
@@ -35,3 +36,15 @@ func (r *patientRepository) GetPatientByDNI(DNI string) (*model.Patient, error)
 
 	return &patient, nil
 }
+
+// Guarda los datos del paciente y escribe PatientUpdated en la misma transacción
+func (r *patientRepository) Update(patient *model.Patient) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(patient).Error
+		if err != nil {
+			return err
+		}
+
+		return addEvent(tx, model.EventPatientUpdated, patient.ID, model.NewPatientEvent(patient))
+	})
+}
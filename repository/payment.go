@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	GetByID(ID uint) (*model.Payment, error)
+	Create(payment *model.Payment) error
+	Register(payment *model.Payment, patientID uint) error
+	Refund(payment *model.Payment, patientID uint) error
+	GetByCashSession(cashSessionID uint) ([]model.Payment, error)
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) GetByID(ID uint) (*model.Payment, error) {
+	var payment model.Payment
+
+	err := r.db.First(&payment, ID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *paymentRepository) Create(payment *model.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+// Guarda el pago de una cita, la marca pagada y escribe PaymentRegistered en una sola transacción.
+// La cita solo se marca si seguía impaga y admitía pagos, así dos pagos simultáneos no se registran
+// ambos y no se cobra una cita que el trabajo de citas impagas acaba de vencer.
+func (r *paymentRepository) Register(payment *model.Payment, patientID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&model.Appointment{}).
+			Where("id = ? AND paid = ? AND status IN ?", payment.AppoimentID, false, model.PayableAppointmentStatuses).
+			Update("paid", true)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return notPayableReason(tx, payment.AppoimentID)
+		}
+
+		err := tx.Create(payment).Error
+		if err != nil {
+			return err
+		}
+
+		return addEvent(tx, model.EventPaymentRegistered, payment.ID, model.NewPaymentEvent(payment, patientID))
+	})
+}
+
+func (r *paymentRepository) GetByCashSession(cashSessionID uint) ([]model.Payment, error) {
+	var payments []model.Payment
+
+	err := r.db.
+		Where("cash_session_id = ?", cashSessionID).
+		Order("created_at").
+		Find(&payments).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// Guarda el reembolso, deja la cita pendiente de pago y escribe PaymentRefunded en una sola transacción.
+// El pago solo se marca si no estaba reembolsado, así dos reembolsos simultáneos no se registran ambos.
+func (r *paymentRepository) Refund(payment *model.Payment, patientID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(payment).
+			Where("refunded_at IS NULL").
+			Select("refunded_at", "refunded_by", "refund_reason").
+			Updates(payment)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return response.ErrorPaymentRefunded
+		}
+
+		err := tx.Model(&model.Appointment{}).
+			Where("id = ?", payment.AppoimentID).
+			Update("paid", false).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return addEvent(tx, model.EventPaymentRefunded, payment.ID, model.NewPaymentEvent(payment, patientID))
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+)
+
+type PrescriptionRepository interface {
+	GetByID(ID uint) (*model.Prescription, error)
+	GetByCode(code string) (*model.Prescription, error)
+	GetByAppointment(appointmentID uint) ([]model.Prescription, error)
+	Create(prescription *model.Prescription) error
+}
+
+type prescriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewPrescriptionRepository(db *gorm.DB) PrescriptionRepository {
+	return &prescriptionRepository{db: db}
+}
+
+func (r *prescriptionRepository) GetByID(ID uint) (*model.Prescription, error) {
+	return r.first("id = ?", ID)
+}
+
+func (r *prescriptionRepository) GetByCode(code string) (*model.Prescription, error) {
+	return r.first("verification_code = ?", code)
+}
+
+func (r *prescriptionRepository) first(query string, arg any) (*model.Prescription, error) {
+	var prescription model.Prescription
+
+	err := r.db.
+		Preload("Doctor").
+		Preload("Patient").
+		Preload("Items").
+		First(&prescription, query, arg).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorPrescriptionNotFound
+		}
+
+		return nil, err
+	}
+
+	return &prescription, nil
+}
+
+func (r *prescriptionRepository) GetByAppointment(appointmentID uint) ([]model.Prescription, error) {
+	var prescriptions []model.Prescription
+
+	err := r.db.
+		Preload("Items").
+		Where("appointment_id = ?", appointmentID).
+		Order("created_at, id").
+		Find(&prescriptions).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return prescriptions, nil
+}
+
+func (r *prescriptionRepository) Create(prescription *model.Prescription) error {
+	return r.db.Create(prescription).Error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"gorm.io/gorm"
+)
+
+type PricingRuleRepository interface {
+	GetActiveRules(date string) ([]model.PricingRule, error)
+}
+
+type pricingRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewPricingRuleRepository(db *gorm.DB) PricingRuleRepository {
+	return &pricingRuleRepository{db: db}
+}
+
+// Reglas activas cuya vigencia incluye la fecha indicada (AAAA-MM-DD)
+func (r *pricingRuleRepository) GetActiveRules(date string) ([]model.PricingRule, error) {
+	var rules []model.PricingRule
+
+	err := r.db.
+		Where("active = ?", true).
+		Where("valid_from = '' OR valid_from IS NULL OR valid_from <= ?", date).
+		Where("valid_to = '' OR valid_to IS NULL OR valid_to >= ?", date).
+		Find(&rules).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
@@ -7,6 +7,8 @@ import (
 
 type ServiceRepository interface {
 	GetAll() ([]model.Appointment, error)
+	GetAllServices(category string, onlyActive bool, limit, offset int) ([]model.Service, error)
+	CountAppointments(ID uint) (int64, error)
 	Delete(ID uint) error
 }
 
@@ -29,6 +31,44 @@ func (r *serviceRepository) GetAll() ([]model.Appointment, error) {
 	return appointments, nil
 }
 
+// Servicios filtrados por categoría (vacía para todas) y, si se indica, solo los activos
+func (r *serviceRepository) GetAllServices(category string, onlyActive bool, limit, offset int) ([]model.Service, error) {
+	var services []model.Service
+
+	query := r.db.Preload("Specialty").Limit(limit).Offset(offset)
+
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	if onlyActive {
+		query = query.Where("active = ?", true)
+	}
+
+	err := query.Find(&services).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// Citas que reservaron el servicio, como servicio de la cita o en alguna de sus líneas
+func (r *serviceRepository) CountAppointments(ID uint) (int64, error) {
+	var count int64
+
+	err := r.db.
+		Model(&model.Appointment{}).
+		Where("service_id = ? OR id IN (?)", ID, r.db.Model(&model.AppointmentItem{}).Select("appointment_id").Where("service_id = ?", ID)).
+		Count(&count).
+		Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (r *serviceRepository) GetAllServicesByID(ID []uint) ([]model.Service, error) {
 	var services []model.Service
 
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+)
+
+type SpecialtyRepository interface {
+	GetAll() ([]model.Specialty, error)
+	GetByIDs(IDs []uint) ([]model.Specialty, error)
+	CountReferences(ID uint) (int64, error)
+	Merge(sourceID, targetID uint) error
+}
+
+type specialtyRepository struct {
+	db *gorm.DB
+}
+
+func NewSpecialtyRepository(db *gorm.DB) SpecialtyRepository {
+	return &specialtyRepository{db: db}
+}
+
+func (r *specialtyRepository) GetAll() ([]model.Specialty, error) {
+	var specialties []model.Specialty
+
+	err := r.db.Order("name").Find(&specialties).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return specialties, nil
+}
+
+// Devuelve las especialidades indicadas; falla si alguna no existe
+func (r *specialtyRepository) GetByIDs(IDs []uint) ([]model.Specialty, error) {
+	var specialties []model.Specialty
+
+	err := r.db.Where("id IN ?", IDs).Find(&specialties).Error
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[uint]bool{}
+	for _, specialty := range specialties {
+		found[specialty.ID] = true
+	}
+
+	for _, ID := range IDs {
+		if !found[ID] {
+			return nil, response.ErrorSpecialtyNotFound
+		}
+	}
+
+	return specialties, nil
+}
+
+// Médicos y servicios que usan la especialidad
+func (r *specialtyRepository) CountReferences(ID uint) (int64, error) {
+	var doctors int64
+
+	err := r.db.Table("doctor_specialties").Where("specialty_id = ?", ID).Count(&doctors).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var services int64
+
+	err = r.db.Model(&model.Service{}).Where("specialty_id = ?", ID).Count(&services).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return doctors + services, nil
+}
+
+// Pasa los médicos y servicios de la especialidad de origen a la de destino y elimina la de origen.
+// Al médico que ya tenía ambas solo se le quita la de origen.
+func (r *specialtyRepository) Merge(sourceID, targetID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var doctorIDs []uint
+
+		err := tx.Table("doctor_specialties").Where("specialty_id = ?", targetID).Pluck("doctor_id", &doctorIDs).Error
+		if err != nil {
+			return err
+		}
+
+		if len(doctorIDs) > 0 {
+			err = tx.Exec("DELETE FROM doctor_specialties WHERE specialty_id = ? AND doctor_id IN ?", sourceID, doctorIDs).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.Exec("UPDATE doctor_specialties SET specialty_id = ? WHERE specialty_id = ?", targetID, sourceID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.Service{}).Where("specialty_id = ?", sourceID).Update("specialty_id", targetID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&model.Specialty{}, sourceID).Error
+	})
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/response"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	GetByID(ID uint) (*model.WebhookSubscription, error)
+	GetAll(limit, offset int) ([]model.WebhookSubscription, error)
+	GetActive() ([]model.WebhookSubscription, error)
+	CreateDelivery(delivery *model.WebhookDelivery) error
+	GetDelivery(ID uint) (*model.WebhookDelivery, error)
+	GetDeliveries(subscriptionID uint, limit, offset int) ([]model.WebhookDelivery, error)
+	CountDeliveries(subscriptionID, eventID uint) (int64, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) GetByID(ID uint) (*model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+
+	err := r.db.First(&subscription, ID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorWebhookNotFound
+		}
+
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (r *webhookRepository) GetAll(limit, offset int) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+
+	err := r.db.
+		Order("id").
+		Limit(limit).
+		Offset(offset).
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// Los eventos se guardan como JSON, así que el filtro por evento se hace al recorrerlas
+func (r *webhookRepository) GetActive() ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+
+	err := r.db.Where("active = ?", true).Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) CreateDelivery(delivery *model.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *webhookRepository) GetDelivery(ID uint) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+
+	err := r.db.First(&delivery, ID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ErrorWebhookDeliveryNotFound
+		}
+
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// Intentos de la suscripción, los más recientes primero
+func (r *webhookRepository) GetDeliveries(subscriptionID uint, limit, offset int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+
+	err := r.db.
+		Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) CountDeliveries(subscriptionID, eventID uint) (int64, error) {
+	var count int64
+
+	err := r.db.
+		Model(&model.WebhookDelivery{}).
+		Where("subscription_id = ? AND event_id = ?", subscriptionID, eventID).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -2,7 +2,6 @@ package response
 
 import (
 	"errors"
-	"fmt"
 
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
 	"github.com/labstack/echo/v4"
@@ -46,6 +45,7 @@ var (
 	ErrorTokenEmailInvalid      = errors.New("el campo 'email' está ausente o no es válido en los claims del token")
 	ErrorSigningMethodInvalid   = errors.New("el método de firma del token no es válido")
 	ErrorTokenMissingInRequest  = errors.New("no se encontró un token en la solicitud")
+	ErrorForbiddenRole          = errors.New("no tiene permisos para realizar esta acción")
 )
 
 // Mensajes de éxito para servicios médicos
@@ -56,6 +56,7 @@ const (
 	SuccessServicesListEmpty = "No se encontraron servicios"
 	SuccessServiceCreated    = "¡Servicio médico creado exitosamente!"
 	SuccessServiceDeleted    = "¡Servicio médico eliminado exitosamente!"
+	SuccessServiceStatus     = "¡Estado del servicio médico actualizado exitosamente!"
 )
 
 // Mensajes de error para servicios médicos
@@ -67,6 +68,30 @@ var (
 	ErrorToCreatedService  = errors.New("no se pudo crear el servicio médico")
 	ErrorToUpdatedService  = errors.New("no se pudo actualizar el servicio médico")
 	ErrorToDeletedService  = errors.New("no se pudo eliminar el servicio médico")
+	ErrorServiceInUse      = errors.New("el servicio médico tiene citas registradas, desactívelo en lugar de eliminarlo")
+	ErrorServiceInactive   = errors.New("el servicio médico está inactivo y no puede reservarse")
+	ErrorDoctorSpecialty   = errors.New("el médico no tiene la especialidad requerida por el servicio médico")
+)
+
+// Mensajes de éxito para categorías de servicios
+const (
+	SuccessServiceCategoryFound   = "¡Categoría de servicios encontrada exitosamente!"
+	SuccessServiceCategoriesFound = "¡Categorías de servicios encontradas exitosamente!"
+	SuccessServiceCategoriesEmpty = "No se encontraron categorías de servicios"
+	SuccessServiceCategoryCreated = "¡Categoría de servicios creada exitosamente!"
+	SuccessServiceCategoryUpdated = "¡Categoría de servicios actualizada exitosamente!"
+	SuccessServiceCategoryDeleted = "¡Categoría de servicios eliminada exitosamente!"
+)
+
+// Mensajes de error para categorías de servicios
+var (
+	ErrorServiceCategoryNotFound   = errors.New("la categoría de servicios no fue encontrada")
+	ErrorServiceCategoriesNotFound = errors.New("no fueron encontradas categorías de servicios")
+	ErrorServiceCategoryExists     = errors.New("ya existe una categoría de servicios con ese nombre")
+	ErrorServiceCategoryInUse      = errors.New("la categoría tiene servicios registrados, reasígnelos antes de eliminarla")
+	ErrorToCreatedServiceCategory  = errors.New("no se pudo crear la categoría de servicios")
+	ErrorToUpdatedServiceCategory  = errors.New("no se pudo actualizar la categoría de servicios")
+	ErrorToDeletedServiceCategory  = errors.New("no se pudo eliminar la categoría de servicios")
 )
 
 // Mensajes de éxito para paquetes
@@ -77,31 +102,71 @@ const (
 	SuccessPackagesListEmpty = "No se encontraron paquetes"
 	SuccessPackageCreated    = "¡Paquete creado exitosamente!"
 	SuccessPackageDeleted    = "¡Paquete eliminado exitosamente!"
+	SuccessPackageRetired    = "El paquete tiene citas o ventas registradas, por lo que se retiró en lugar de eliminarse"
+	SuccessPackageStatus     = "¡Estado del paquete actualizado exitosamente!"
 )
 
 // Mensajes de error para paquetes
 var (
-	ErrorPackageNotFound    = errors.New("el paquete no fue encontrado")
-	ErrorPackagesNotFound   = errors.New("no fueron encontrados paquetes")
-	ErrorListPackagesEmpty  = errors.New("no fueron encontrados paquetes")
-	ErrorBadRequestPackage  = errors.New("el cuerpo de la solicitud no es válido para el paquete")
-	ErrorToCreatedPackage   = errors.New("no se pudo crear el paquete")
-	ErrorToUpdatedPackage   = errors.New("no se pudo actualizar el paquete")
-	ErrorToDeletedPackage   = errors.New("no se pudo eliminar el paquete")
-	ErrorNoServicesProvided = errors.New("no se proporcionaron servicios para el paquete")
-	ErrorFetchingServices   = errors.New("no se pudieron obtener los servicios para el paquete")
-	ErrorClearingServices   = errors.New("no se pudieron actualizar correctamente los servicios del paquete")
+	ErrorPackageNotFound           = errors.New("el paquete no fue encontrado")
+	ErrorPackagesNotFound          = errors.New("no fueron encontrados paquetes")
+	ErrorListPackagesEmpty         = errors.New("no fueron encontrados paquetes")
+	ErrorBadRequestPackage         = errors.New("el cuerpo de la solicitud no es válido para el paquete")
+	ErrorToCreatedPackage          = errors.New("no se pudo crear el paquete")
+	ErrorToUpdatedPackage          = errors.New("no se pudo actualizar el paquete")
+	ErrorToDeletedPackage          = errors.New("no se pudo eliminar el paquete")
+	ErrorNoServicesProvided        = errors.New("no se proporcionaron servicios para el paquete")
+	ErrorFetchingServices          = errors.New("no se pudieron obtener los servicios para el paquete")
+	ErrorClearingServices          = errors.New("no se pudieron actualizar correctamente los servicios del paquete")
+	ErrorClearingSessions          = errors.New("no se pudieron actualizar correctamente las sesiones del paquete")
+	ErrorSessionServiceNotIncluded = errors.New("las sesiones solo pueden indicarse para servicios incluidos en el paquete")
+	ErrorDuplicatedSession         = errors.New("el servicio se repite en las sesiones del paquete")
+	ErrorPackageSaleWindow         = errors.New("la fecha de inicio de venta del paquete no puede ser posterior a la fecha de fin")
+	ErrorPackageDiscount           = errors.New("el descuento del paquete debe estar entre 0 y 100")
+	ErrorPackageNotOnSale          = errors.New("el paquete está retirado o fuera de su periodo de venta")
+)
+
+// Mensajes del historial de precios
+const (
+	SuccessPriceHistoryFound = "¡Historial de precios encontrado exitosamente!"
+)
+
+var (
+	ErrorRecordingPriceHistory = errors.New("el precio se guardó pero no se pudo registrar en el historial de precios")
+	ErrorFetchingPriceHistory  = errors.New("no se pudo obtener el historial de precios")
+)
+
+// Mensajes de éxito de paquetes prepagados
+const (
+	SuccessPackagePurchased     = "¡Paquete vendido exitosamente!"
+	SuccessPackagePurchaseFound = "¡Venta de paquete encontrada exitosamente!"
+	SuccessPackageCreditsFound  = "¡Créditos del paciente encontrados exitosamente!"
+	SuccessPackageCreditsEmpty  = "El paciente no tiene créditos de paquetes"
+)
+
+// Mensajes de error de paquetes prepagados
+var (
+	ErrorPackagePurchaseNotFound = errors.New("la venta de paquete no fue encontrada")
+	ErrorPackageCreditNotFound   = errors.New("el crédito de paquete no fue encontrado")
+	ErrorPackageCreditExhausted  = errors.New("el crédito de paquete no tiene sesiones disponibles")
+	ErrorPackageCreditExpired    = errors.New("el crédito de paquete vence antes de la fecha de la cita")
+	ErrorCouponWithCredit        = errors.New("la cita se cubre con un crédito de paquete, el cupón no aplica")
+	ErrorCreditServiceChange     = errors.New("la cita se cubre con un crédito de paquete, cancélela para cambiar el servicio o el paciente")
+	ErrorFetchingPackageCredits  = errors.New("no se pudieron obtener los créditos de paquetes del paciente")
+	ErrorToPurchasePackage       = errors.New("no se pudo registrar la venta del paquete")
 )
 
 // Mensajes de exito de doctores
 
 const (
-	SuccessDoctorFound      = "¡Médico encontrado exitosamente!"
-	SuccessDoctorUpdated    = "¡Médico actualizado exitosamente!"
-	SuccessDoctorsFound     = "¡Médicos encontrados exitosamente!"
-	SuccessDoctorsListEmpty = "No se encontraron doctores"
-	SuccessDoctorCreated    = "¡Médico creado exitosamente!"
-	SuccessDoctorDeleted    = "¡Médico eliminado exitosamente!"
+	SuccessDoctorFound       = "¡Médico encontrado exitosamente!"
+	SuccessDoctorUpdated     = "¡Médico actualizado exitosamente!"
+	SuccessDoctorsFound      = "¡Médicos encontrados exitosamente!"
+	SuccessDoctorsListEmpty  = "No se encontraron doctores"
+	SuccessDoctorCreated     = "¡Médico creado exitosamente!"
+	SuccessDoctorDeleted     = "¡Médico eliminado exitosamente!"
+	SuccessAvailabilityFound = "¡Disponibilidad de médicos encontrada exitosamente!"
+	SuccessAvailabilityEmpty = "No hay médicos disponibles en la fecha indicada"
 )
 
 // Mensajes de error para doctores
@@ -123,6 +188,31 @@ var (
 	ErrorDoctorExistsEmail            = errors.New("el email ingresado ya existe")
 	ErrorDoctorInvalidDateFormat      = errors.New("ingrese el formato adecuado para la fecha de nacimiento del médico")
 	ErrorDoctorBirthDateIsFuture      = errors.New("la fecha de cumpleaños debe ser en tiempo pasado")
+	ErrorFetchingAvailability         = errors.New("no se pudo obtener la disponibilidad de los médicos")
+)
+
+// Mensajes de éxito de especialidades
+const (
+	SuccessSpecialtyFound   = "¡Especialidad encontrada exitosamente!"
+	SuccessSpecialtiesFound = "¡Especialidades encontradas exitosamente!"
+	SuccessSpecialtiesEmpty = "No se encontraron especialidades"
+	SuccessSpecialtyCreated = "¡Especialidad creada exitosamente!"
+	SuccessSpecialtyUpdated = "¡Especialidad actualizada exitosamente!"
+	SuccessSpecialtyDeleted = "¡Especialidad eliminada exitosamente!"
+	SuccessSpecialtyMerged  = "¡Especialidades unidas exitosamente!"
+)
+
+// Mensajes de error de especialidades
+var (
+	ErrorSpecialtyNotFound   = errors.New("la especialidad no fue encontrada")
+	ErrorSpecialtiesNotFound = errors.New("no fueron encontradas especialidades")
+	ErrorSpecialtyExists     = errors.New("ya existe una especialidad con ese nombre")
+	ErrorSpecialtyInUse      = errors.New("la especialidad está asignada a médicos o servicios, reasígnelos antes de eliminarla")
+	ErrorToCreatedSpecialty  = errors.New("no se pudo crear la especialidad")
+	ErrorToUpdatedSpecialty  = errors.New("no se pudo actualizar la especialidad")
+	ErrorToDeletedSpecialty  = errors.New("no se pudo eliminar la especialidad")
+	ErrorSpecialtyMergeSame  = errors.New("no se puede unir una especialidad consigo misma")
+	ErrorToMergeSpecialty    = errors.New("no se pudieron unir las especialidades")
 )
 
 // Mensajes de exito de pacientes
@@ -163,6 +253,8 @@ const (
 	SuccessAppointmentsEmpty  = "No se encontraron patients"
 	SuccessAppointmentCreated = "¡Cita registrada exitosamente, proceda a realizar el pago!"
 	SuccessAppointmentDeleted = "¡Cita eliminada exitosamente!"
+	SuccessAppointmentQuoted  = "¡Cotización de la cita generada exitosamente!"
+	SuccessAppointmentStatus  = "¡Estado de la cita actualizado exitosamente!"
 )
 
 // Mensajes de error para citas
@@ -186,23 +278,359 @@ var (
 	ErrorInvalidAppointment           = errors.New("debe seleccionar al menos un paquete o servicio para la cita")
 	ErrorPackageAndServiceEmpty       = errors.New("se necesita especificar el ID de un paquete o de un servicio médico")
 	ErrorFetchingAppointments         = errors.New("no se pudo obtener la disponibilidad del médico para la fecha seleccionada")
+	ErrorInvalidAppointmentStatus     = errors.New("el estado de la cita es inválido, ingrese: completada o cancelada")
+	ErrorAppointmentNotScheduled      = errors.New("la cita ya fue completada o cancelada y no puede modificarse")
+	ErrorInvalidAppointmentItem       = errors.New("cada línea de la cita debe indicar un servicio o un paquete, pero no ambos")
+	ErrorInvalidItemQuantity          = errors.New("la cantidad de cada línea de la cita debe ser mayor a cero")
+)
+
+// Mensajes de éxito de reglas de precios
+const (
+	SuccessPricingRuleFound   = "¡Regla de precios encontrada exitosamente!"
+	SuccessPricingRulesFound  = "¡Reglas de precios encontradas exitosamente!"
+	SuccessPricingRulesEmpty  = "No se encontraron reglas de precios"
+	SuccessPricingRuleCreated = "¡Regla de precios creada exitosamente!"
+	SuccessPricingRuleUpdated = "¡Regla de precios actualizada exitosamente!"
+	SuccessPricingRuleDeleted = "¡Regla de precios eliminada exitosamente!"
+)
+
+// Mensajes de error de reglas de precios
+var (
+	ErrorPricingRuleNotFound         = errors.New("la regla de precios no fue encontrada")
+	ErrorPricingRulesNotFound        = errors.New("no fueron encontradas reglas de precios")
+	ErrorFetchingPricingRules        = errors.New("no se pudieron obtener las reglas de precios vigentes")
+	ErrorToCreatedPricingRule        = errors.New("no se pudo crear la regla de precios")
+	ErrorToUpdatedPricingRule        = errors.New("no se pudo actualizar la regla de precios")
+	ErrorToDeletedPricingRule        = errors.New("no se pudo eliminar la regla de precios")
+	ErrorInvalidPricingRuleScope     = errors.New("el ámbito de la regla es inválido, ingrese: paquete, seguro o categoria")
+	ErrorPricingRuleCategoryRequired = errors.New("la categoría de servicio es obligatoria para reglas por categoría")
+	ErrorPricingRuleValidity         = errors.New("la fecha de inicio de vigencia debe ser anterior o igual a la fecha de fin")
+)
+
+// Mensajes de éxito de tipos de cambio
+const (
+	SuccessExchangeRateFound   = "¡Tipo de cambio encontrado exitosamente!"
+	SuccessExchangeRatesFound  = "¡Tipos de cambio encontrados exitosamente!"
+	SuccessExchangeRatesEmpty  = "No se encontraron tipos de cambio"
+	SuccessExchangeRateCreated = "¡Tipo de cambio registrado exitosamente!"
+	SuccessExchangeRateDeleted = "¡Tipo de cambio eliminado exitosamente!"
+)
+
+// Mensajes de error de tipos de cambio
+var (
+	ErrorExchangeRateNotFound     = errors.New("no hay un tipo de cambio vigente para la moneda indicada")
+	ErrorExchangeRatesNotFound    = errors.New("no fueron encontrados tipos de cambio")
+	ErrorExchangeRateBaseCurrency = errors.New("no se registra tipo de cambio para la moneda base")
+	ErrorToCreatedExchangeRate    = errors.New("no se pudo registrar el tipo de cambio")
+	ErrorToDeletedExchangeRate    = errors.New("no se pudo eliminar el tipo de cambio")
+)
+
+// Mensajes de éxito de cupones
+const (
+	SuccessCouponFound   = "¡Cupón encontrado exitosamente!"
+	SuccessCouponsFound  = "¡Cupones encontrados exitosamente!"
+	SuccessCouponsEmpty  = "No se encontraron cupones"
+	SuccessCouponCreated = "¡Cupón creado exitosamente!"
+	SuccessCouponUpdated = "¡Cupón actualizado exitosamente!"
+	SuccessCouponDeleted = "¡Cupón eliminado exitosamente!"
+)
+
+// Mensajes de error de cupones
+var (
+	ErrorCouponNotFound       = errors.New("el cupón no fue encontrado")
+	ErrorCouponsNotFound      = errors.New("no fueron encontrados cupones")
+	ErrorCouponCodeExists     = errors.New("ya existe un cupón con el código indicado")
+	ErrorInvalidCouponType    = errors.New("el tipo de cupón es inválido, ingrese: porcentaje o monto_fijo")
+	ErrorCouponValue          = errors.New("el cupón debe tener un porcentaje mayor a 0 o un monto fijo mayor a 0 según su tipo")
+	ErrorCouponValidity       = errors.New("la fecha de inicio del cupón no puede ser posterior a la fecha de fin")
+	ErrorCouponNotValid       = errors.New("el cupón no está activo o no está vigente")
+	ErrorCouponNotApplicable  = errors.New("el cupón no aplica al servicio o paquete seleccionado")
+	ErrorCouponExhausted      = errors.New("el cupón alcanzó su límite de usos")
+	ErrorCouponPatientLimit   = errors.New("el paciente alcanzó el límite de usos del cupón")
+	ErrorCouponInUse          = errors.New("el cupón ya fue utilizado, desactívelo en lugar de eliminarlo")
+	ErrorFetchingCouponUsage  = errors.New("no se pudo verificar el uso del cupón")
+	ErrorToCreatedCoupon      = errors.New("no se pudo crear el cupón")
+	ErrorToUpdatedCoupon      = errors.New("no se pudo actualizar el cupón")
+	ErrorToDeletedCoupon      = errors.New("no se pudo eliminar el cupón")
+	ErrorClearingCouponTarget = errors.New("no se pudieron actualizar los servicios y paquetes del cupón")
+)
+
+// Mensajes de éxito de seguros médicos
+const (
+	SuccessInsuranceProviderFound   = "¡Aseguradora encontrada exitosamente!"
+	SuccessInsuranceProvidersFound  = "¡Aseguradoras encontradas exitosamente!"
+	SuccessInsuranceProvidersEmpty  = "No se encontraron aseguradoras"
+	SuccessInsuranceProviderCreated = "¡Aseguradora registrada exitosamente!"
+	SuccessInsuranceProviderUpdated = "¡Aseguradora actualizada exitosamente!"
+	SuccessInsuranceProviderDeleted = "¡Aseguradora eliminada exitosamente!"
+	SuccessInsurancePlanFound       = "¡Plan de seguro encontrado exitosamente!"
+	SuccessInsurancePlansFound      = "¡Planes de seguro encontrados exitosamente!"
+	SuccessInsurancePlansEmpty      = "No se encontraron planes de seguro"
+	SuccessInsurancePlanCreated     = "¡Plan de seguro registrado exitosamente!"
+	SuccessInsurancePlanUpdated     = "¡Plan de seguro actualizado exitosamente!"
+	SuccessInsurancePlanDeleted     = "¡Plan de seguro eliminado exitosamente!"
+	SuccessPatientPolicyFound       = "¡Póliza encontrada exitosamente!"
+	SuccessPatientPoliciesFound     = "¡Pólizas del paciente encontradas exitosamente!"
+	SuccessPatientPoliciesEmpty     = "El paciente no tiene pólizas registradas"
+	SuccessPatientPolicyCreated     = "¡Póliza registrada exitosamente!"
+	SuccessPatientPolicyUpdated     = "¡Póliza actualizada exitosamente!"
+	SuccessPatientPolicyDeleted     = "¡Póliza eliminada exitosamente!"
+)
+
+// Mensajes de error de seguros médicos
+var (
+	ErrorInsuranceProviderNotFound  = errors.New("la aseguradora no fue encontrada")
+	ErrorInsuranceProvidersNotFound = errors.New("no fueron encontradas aseguradoras")
+	ErrorInsuranceProviderHasPlans  = errors.New("la aseguradora tiene planes registrados, elimínelos o desactívela")
+	ErrorToCreatedInsuranceProvider = errors.New("no se pudo registrar la aseguradora")
+	ErrorToUpdatedInsuranceProvider = errors.New("no se pudo actualizar la aseguradora")
+	ErrorToDeletedInsuranceProvider = errors.New("no se pudo eliminar la aseguradora")
+	ErrorInsurancePlanNotFound      = errors.New("el plan de seguro no fue encontrado")
+	ErrorInsurancePlansNotFound     = errors.New("no fueron encontrados planes de seguro")
+	ErrorInsurancePlanHasPolicies   = errors.New("el plan tiene pólizas registradas, desactívelo en lugar de eliminarlo")
+	ErrorDuplicatedCoverage         = errors.New("la categoría de servicio se repite en las coberturas del plan")
+	ErrorToCreatedInsurancePlan     = errors.New("no se pudo registrar el plan de seguro")
+	ErrorToUpdatedInsurancePlan     = errors.New("no se pudo actualizar el plan de seguro")
+	ErrorToDeletedInsurancePlan     = errors.New("no se pudo eliminar el plan de seguro")
+	ErrorPatientPolicyNotFound      = errors.New("la póliza no fue encontrada")
+	ErrorPatientPoliciesNotFound    = errors.New("no se pudieron obtener las pólizas del paciente")
+	ErrorPatientPolicyValidity      = errors.New("la fecha de inicio de la póliza no puede ser posterior a la fecha de fin")
+	ErrorToCreatedPatientPolicy     = errors.New("no se pudo registrar la póliza")
+	ErrorToUpdatedPatientPolicy     = errors.New("no se pudo actualizar la póliza")
+	ErrorToDeletedPatientPolicy     = errors.New("no se pudo eliminar la póliza")
+	ErrorDeletingPatientPolicies    = errors.New("no se pudieron eliminar las pólizas del paciente")
+)
+
+// Mensajes de éxito de reclamos a aseguradoras
+const (
+	SuccessClaimFound            = "¡Reclamo encontrado exitosamente!"
+	SuccessClaimsFound           = "¡Reclamos encontrados exitosamente!"
+	SuccessClaimsEmpty           = "No se encontraron reclamos"
+	SuccessClaimCreated          = "¡Reclamo generado exitosamente!"
+	SuccessClaimStatus           = "¡Estado del reclamo actualizado exitosamente!"
+	SuccessClaimPayment          = "¡Pago del reclamo registrado exitosamente!"
+	SuccessClaimBatch            = "¡Lote del reclamo generado exitosamente!"
+	SuccessClaimReceivables      = "¡Cuentas por cobrar a aseguradoras obtenidas exitosamente!"
+	SuccessClaimReceivablesEmpty = "No hay cuentas por cobrar a aseguradoras"
+)
+
+// Mensajes de error de reclamos a aseguradoras
+var (
+	ErrorClaimNotFound       = errors.New("el reclamo no fue encontrado")
+	ErrorClaimsNotFound      = errors.New("no fueron encontrados reclamos")
+	ErrorClaimPeriod         = errors.New("la fecha de inicio del periodo no puede ser posterior a la fecha de fin")
+	ErrorClaimNoAppointments = errors.New("no hay citas completadas pendientes de reclamo para la aseguradora en el periodo indicado")
+	ErrorInvalidClaimStatus  = errors.New("el estado del reclamo es inválido, ingrese: aprobado o rechazado")
+	ErrorClaimNotSubmitted   = errors.New("solo se puede aprobar o rechazar un reclamo presentado")
+	ErrorClaimApprovedAmount = errors.New("el monto aprobado no puede ser mayor al monto reclamado")
+	ErrorClaimNotPayable     = errors.New("solo se pueden registrar pagos de reclamos aprobados con saldo pendiente")
+	ErrorClaimOverpaid       = errors.New("el pago supera el saldo pendiente del reclamo")
+	ErrorClaimPaymentAmount  = errors.New("el monto del pago debe ser mayor a 0")
+	ErrorToCreatedClaim      = errors.New("no se pudo generar el reclamo")
+	ErrorToUpdatedClaim      = errors.New("no se pudo actualizar el estado del reclamo")
+	ErrorToSaveClaimPayment  = errors.New("no se pudo registrar el pago del reclamo")
+	ErrorGeneratingClaimCSV  = errors.New("error al generar el reclamo en formato csv")
+	ErrorFetchingReceivables = errors.New("no se pudieron obtener las cuentas por cobrar a aseguradoras")
 )
 
 // Mensajes de éxito de pago realizado
 const (
 	SuccessPaymentRegister = "Pago registrado exitosamente"
+	SuccessPaymentRefunded = "Pago reembolsado exitosamente, su recibo quedó anulado"
+	SuccessReceiptVerified = "Recibo verificado"
 )
 
 // Mensajes de error del pago
 var (
-	ErrorPaidNotTrue           = errors.New("el pago debe ser confirmado")
-	ErrorTotalAmountEmpty      = errors.New("por favor ingresar la cantidad de dinero")
-	ErrorTotalAmountBadRequest = errors.New("por favor ingresar la cantidad de dinero adecuada")
-	ErrorToUpdatePaid          = errors.New("error al actualizar el estado del pago")
-	ErrorGeneratingQRCode      = errors.New("error al generar el código QR")
-	ErrorGeneratingPDF         = errors.New("error al generar la boleta en formato pdf")
-	ErrorInvalidPaymentType    = errors.New("el tipo de pago es inválido, ingrese: efectivo, pago por aplicación o pago con tarjeta")
-	ErrorProcessingPayment     = errors.New("error al procesar el pago")
+	ErrorPaidNotTrue            = errors.New("el pago debe ser confirmado")
+	ErrorTotalAmountEmpty       = errors.New("por favor ingresar la cantidad de dinero")
+	ErrorTotalAmountBadRequest  = errors.New("por favor ingresar la cantidad de dinero adecuada")
+	ErrorToUpdatePaid           = errors.New("error al actualizar el estado del pago")
+	ErrorGeneratingQRCode       = errors.New("error al generar el código QR")
+	ErrorGeneratingPDF          = errors.New("error al generar la boleta en formato pdf")
+	ErrorInvalidPaymentType     = errors.New("el tipo de pago es inválido, ingrese: efectivo, pago por aplicación, pago con tarjeta o transferencia")
+	ErrorProcessingPayment      = errors.New("error al procesar el pago")
+	ErrorAppointmentPaid        = errors.New("la cita ya fue pagada")
+	ErrorAppointmentNotPayable  = errors.New("la cita fue cancelada o venció y ya no admite pagos")
+	ErrorToSavePayment          = errors.New("error al registrar el pago en el libro de pagos")
+	ErrorPaymentNotFound        = errors.New("el pago no fue encontrado")
+	ErrorPaymentWithoutReceipt  = errors.New("el pago no corresponde a una cita y no tiene recibo")
+	ErrorReadingReceipt         = errors.New("error al leer el recibo del pago")
+	ErrorGeneratingConfirmation = errors.New("error al generar la confirmación de la cita en formato pdf")
+	ErrorPaymentRefunded        = errors.New("el pago ya fue reembolsado")
+	ErrorToRefundPayment        = errors.New("error al registrar el reembolso del pago")
+	ErrorReceiptTokenInvalid    = errors.New("el código QR no corresponde a un recibo emitido por la clínica")
+)
+
+// Mensajes de éxito de caja
+const (
+	SuccessCashSessionOpened = "¡Caja abierta exitosamente!"
+	SuccessCashSessionClosed = "¡Caja cerrada exitosamente!"
+	SuccessCashSessionFound  = "¡Sesión de caja encontrada exitosamente!"
+	SuccessCashSessionsFound = "¡Sesiones de caja encontradas exitosamente!"
+	SuccessCashSessionsEmpty = "No se encontraron sesiones de caja"
+	SuccessCashSessionReport = "¡Reporte de cierre generado exitosamente!"
+)
+
+// Mensajes de error de caja
+var (
+	ErrorCashSessionNotFound      = errors.New("la sesión de caja no fue encontrada")
+	ErrorCashSessionsNotFound     = errors.New("no fueron encontradas sesiones de caja")
+	ErrorCashSessionNotOpen       = errors.New("no hay una caja abierta, abra la caja antes de recibir pagos en efectivo")
+	ErrorCashSessionAlreadyOpen   = errors.New("ya existe una caja abierta, ciérrela antes de abrir otra")
+	ErrorCashSessionAlreadyClosed = errors.New("la sesión de caja ya fue cerrada")
+	ErrorToOpenCashSession        = errors.New("no se pudo abrir la caja")
+	ErrorToCloseCashSession       = errors.New("no se pudo cerrar la caja")
+	ErrorFetchingCashPayments     = errors.New("no se pudieron obtener los pagos de la sesión de caja")
+	ErrorDuplicatedCount          = errors.New("el método de pago se repite en el conteo de cierre")
+	ErrorGeneratingCashReport     = errors.New("error al generar el reporte de cierre en formato pdf")
+)
+
+// Mensajes de éxito del historial médico
+const (
+	SuccessPatientRecordsFound  = "¡Historial médico del paciente encontrado exitosamente!"
+	SuccessPatientRecordsEmpty  = "El paciente no tiene registros en esta sección del historial médico"
+	SuccessPatientRecordCreated = "¡Registro del historial médico creado exitosamente!"
+	SuccessPatientRecordUpdated = "¡Registro del historial médico actualizado exitosamente!"
+	SuccessPatientRecordDeleted = "¡Registro del historial médico eliminado exitosamente!"
+	SuccessMedicalSummaryFound  = "¡Resumen del historial médico encontrado exitosamente!"
+)
+
+// Mensajes de error del historial médico
+var (
+	ErrorPatientRecordNotFound  = errors.New("el registro del historial médico no fue encontrado")
+	ErrorFetchingPatientRecords = errors.New("no se pudo obtener el historial médico del paciente")
+	ErrorToCreatedPatientRecord = errors.New("no se pudo crear el registro del historial médico")
+	ErrorToUpdatedPatientRecord = errors.New("no se pudo actualizar el registro del historial médico")
+	ErrorToDeletedPatientRecord = errors.New("no se pudo eliminar el registro del historial médico")
+	ErrorDeletingPatientHistory = errors.New("no se pudo eliminar el historial médico del paciente")
+	ErrorInvalidAllergySeverity = errors.New("la gravedad de la alergia es inválida, ingrese: leve, moderada o grave")
+	ErrorInvalidRecordDates     = errors.New("la fecha de inicio no puede ser posterior a la fecha de fin")
+)
+
+// Mensajes de éxito de registros clínicos
+const (
+	SuccessEncounterFound   = "¡Registro clínico encontrado exitosamente!"
+	SuccessEncounterCreated = "¡Registro clínico creado exitosamente!"
+	SuccessEncounterUpdated = "¡Registro clínico actualizado exitosamente!"
+	SuccessEncounterSigned  = "¡Registro clínico firmado exitosamente!"
+)
+
+// Mensajes de error de registros clínicos
+var (
+	ErrorEncounterNotFound     = errors.New("el registro clínico no fue encontrado")
+	ErrorEncounterExists       = errors.New("la cita ya tiene un registro clínico, edítelo en lugar de crear otro")
+	ErrorEncounterNotCompleted = errors.New("el registro clínico solo puede crearse para una cita completada")
+	ErrorEncounterForbidden    = errors.New("solo el médico que atendió la cita puede escribir su registro clínico, y solo él y los administradores pueden verlo")
+	ErrorEncounterLocked       = errors.New("el registro clínico está firmado o pasaron 24 horas desde su creación y ya no puede modificarse")
+	ErrorToCreatedEncounter    = errors.New("no se pudo crear el registro clínico")
+	ErrorToUpdatedEncounter    = errors.New("no se pudo actualizar el registro clínico")
+	ErrorToSignEncounter       = errors.New("no se pudo firmar el registro clínico")
+)
+
+// Mensajes de éxito de recetas
+const (
+	SuccessPrescriptionFound    = "¡Receta encontrada exitosamente!"
+	SuccessPrescriptionsFound   = "¡Recetas de la cita encontradas exitosamente!"
+	SuccessPrescriptionsEmpty   = "La cita no tiene recetas emitidas"
+	SuccessPrescriptionCreated  = "¡Receta emitida exitosamente!"
+	SuccessPrescriptionVerified = "¡Receta auténtica emitida por la clínica!"
+)
+
+// Mensajes de error de recetas
+var (
+	ErrorPrescriptionNotFound     = errors.New("la receta no fue encontrada")
+	ErrorPrescriptionNotCompleted = errors.New("la receta solo puede emitirse para una cita completada")
+	ErrorPrescriptionForbidden    = errors.New("solo el médico que atendió la cita puede emitir su receta, y solo él y los administradores pueden verla")
+	ErrorPrescriptionNotVerified  = errors.New("el código no corresponde a ninguna receta emitida por la clínica")
+	ErrorFetchingPrescriptions    = errors.New("no se pudieron obtener las recetas de la cita")
+	ErrorToCreatedPrescription    = errors.New("no se pudo emitir la receta")
+)
+
+// Mensajes de éxito de documentos
+const (
+	SuccessDocumentFound      = "¡Documento encontrado exitosamente!"
+	SuccessDocumentsFound     = "¡Documentos encontrados exitosamente!"
+	SuccessDocumentsEmpty     = "No hay documentos adjuntos"
+	SuccessDocumentUploaded   = "¡Documento subido exitosamente!"
+	SuccessDocumentDeleted    = "¡Documento eliminado exitosamente!"
+	SuccessDocumentURLCreated = "¡Enlace de descarga generado exitosamente!"
+)
+
+// Mensajes de error de documentos
+var (
+	ErrorDocumentNotFound        = errors.New("el documento no fue encontrado")
+	ErrorDocumentFileRequired    = errors.New("debe adjuntar el archivo en el campo file")
+	ErrorDocumentTooLarge        = errors.New("el archivo supera el tamaño máximo permitido")
+	ErrorDocumentEmpty           = errors.New("el archivo está vacío")
+	ErrorDocumentContentType     = errors.New("tipo de archivo no permitido, solo se aceptan PDF, JPEG y PNG")
+	ErrorInvalidDocumentCategory = errors.New("la categoría del documento es inválida, ingrese: resultado, imagen, consentimiento u otro")
+	ErrorDocumentLinkInvalid     = errors.New("el enlace de descarga es inválido o ha expirado")
+	ErrorFetchingDocuments       = errors.New("no se pudieron obtener los documentos")
+	ErrorToUploadDocument        = errors.New("no se pudo guardar el documento")
+	ErrorToReadDocument          = errors.New("no se pudo leer el documento")
+	ErrorDocumentChecksum        = errors.New("el archivo almacenado no coincide con el documento subido")
+	ErrorToDeletedDocument       = errors.New("no se pudo eliminar el documento")
+)
+
+// Mensajes de éxito de notificaciones
+const (
+	SuccessNotificationPreferenceFound   = "¡Preferencias de notificación encontradas exitosamente!"
+	SuccessNotificationPreferenceUpdated = "¡Preferencias de notificación actualizadas exitosamente!"
+	SuccessNotificationsFound            = "¡Notificaciones encontradas exitosamente!"
+	SuccessNotificationsEmpty            = "No hay notificaciones enviadas al paciente"
+)
+
+// Mensajes de error de notificaciones
+var (
+	ErrorFetchingNotificationPreference = errors.New("no se pudieron obtener las preferencias de notificación")
+	ErrorToSaveNotificationPreference   = errors.New("no se pudieron guardar las preferencias de notificación")
+	ErrorFetchingNotifications          = errors.New("no se pudieron obtener las notificaciones")
+)
+
+// Mensajes de éxito de trabajos en segundo plano
+const (
+	SuccessJobFound   = "¡Trabajo encontrado exitosamente!"
+	SuccessJobsFound  = "¡Trabajos encontrados exitosamente!"
+	SuccessJobsEmpty  = "No hay trabajos registrados"
+	SuccessJobRetried = "¡El trabajo se volverá a ejecutar!"
+)
+
+// Mensajes de error de trabajos en segundo plano
+var (
+	ErrorJobNotFound      = errors.New("el trabajo no fue encontrado")
+	ErrorFetchingJobs     = errors.New("no se pudieron obtener los trabajos")
+	ErrorInvalidJobStatus = errors.New("el estado del trabajo es inválido, ingrese: pendiente, en_curso, completado o fallido")
+	ErrorJobNotFailed     = errors.New("solo se puede reintentar un trabajo fallido")
+	ErrorToRetryJob       = errors.New("no se pudo reintentar el trabajo")
+)
+
+// Mensajes de éxito de webhooks
+const (
+	SuccessWebhookFound           = "¡Webhook encontrado exitosamente!"
+	SuccessWebhooksFound          = "¡Webhooks encontrados exitosamente!"
+	SuccessWebhooksEmpty          = "No hay webhooks registrados"
+	SuccessWebhookCreated         = "¡Webhook registrado exitosamente!"
+	SuccessWebhookUpdated         = "¡Webhook actualizado exitosamente!"
+	SuccessWebhookDeleted         = "¡Webhook eliminado exitosamente!"
+	SuccessWebhookDeliveriesFound = "¡Envíos del webhook encontrados exitosamente!"
+	SuccessWebhookDeliveriesEmpty = "No hay envíos registrados para el webhook"
+	SuccessWebhookReplayed        = "¡El evento se volvió a enviar!"
+)
+
+// Mensajes de error de webhooks
+var (
+	ErrorWebhookNotFound           = errors.New("el webhook no fue encontrado")
+	ErrorWebhooksNotFound          = errors.New("no se pudieron obtener los webhooks")
+	ErrorInvalidWebhookURL         = errors.New("la URL del webhook debe ser http o https")
+	ErrorInvalidWebhookEvent       = errors.New("evento de webhook inválido, ingrese: AppointmentBooked, AppointmentCancelled, AppointmentRescheduled, PaymentRegistered, PaymentRefunded o PatientUpdated")
+	ErrorToCreatedWebhook          = errors.New("no se pudo registrar el webhook")
+	ErrorToUpdatedWebhook          = errors.New("no se pudo actualizar el webhook")
+	ErrorToDeletedWebhook          = errors.New("no se pudo eliminar el webhook")
+	ErrorFetchingWebhookDeliveries = errors.New("no se pudieron obtener los envíos del webhook")
+	ErrorWebhookDeliveryNotFound   = errors.New("el envío del webhook no fue encontrado")
+	ErrorWebhookEventNotFound      = errors.New("el evento del envío ya no existe")
+	ErrorToReplayWebhook           = errors.New("no se pudo volver a enviar el evento")
 )
 
 type WriteResponse struct {
@@ -227,13 +655,25 @@ func WriteError(r *WriteResponse) error {
 	})
 }
 
-func WriteSuccessAppointmentDesc(r *WriteResponse, finalPricePkg *model.FinalPackagePriceWithInsegurance, hasInsurance bool) error {
+// Respuesta de la cita reservada con las alergias registradas del paciente
+func WriteSuccessAllergyWarnings(r *WriteResponse, allergies []model.PatientAllergy) error {
+	return r.C.JSON(int(r.Status), map[string]interface{}{
+		"status":           r.Status,
+		"message":          r.Message,
+		"data":             r.Data,
+		"allergy_warnings": allergies,
+	})
+}
+
+func WriteSuccessAppointmentDesc(r *WriteResponse, finalPricePkg *model.FinalPackagePriceWithInsegurance) error {
 	return r.C.JSON(int(r.Status), map[string]interface{}{
-		"El descuento por paquete es de: $/.": fmt.Sprintf("%.2f", finalPricePkg.DiscountPackage),
-		"El descuento por seguro es de: $/.":  fmt.Sprintf("%.2f", finalPricePkg.InsuranceDiscount),
-		"El precio de la cita es: $/.":        fmt.Sprintf("%.2f", finalPricePkg.TotalAmount),
-		"El precio final de la cita es: $/.":  fmt.Sprintf("%.2f", finalPricePkg.FinalPrice),
-		"tiene seguro":                        hasInsurance,
+		"El descuento por paquete es de: $/.": finalPricePkg.DiscountPackage.String(),
+		"El descuento por cupón es de: $/.":   finalPricePkg.CouponDiscount.String(),
+		"El precio de la cita es: $/.":        finalPricePkg.TotalAmount.String(),
+		"El precio final de la cita es: $/.":  finalPricePkg.FinalPrice.String(),
+		"La aseguradora cubre: $/.":           finalPricePkg.InsurerAmount.String(),
+		"El paciente paga: $/.":               finalPricePkg.PatientAmount.String(),
+		"tiene seguro":                        finalPricePkg.PolicyNumber != "",
 		"message":                             r.Message,
 		"status":                              r.Status,
 	})
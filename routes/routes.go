@@ -1,32 +1,149 @@
 package routes
 
 import (
+	"log"
+	"time"
+
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/appointment"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/auth"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/config"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/db"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/events"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/handler"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/jobs"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/logic"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/model"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/notification"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/pdftemplate"
 	"github.com/IsraelTeo/clinic-backend-hackacode-app/repository"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/storage"
+	"github.com/IsraelTeo/clinic-backend-hackacode-app/webhook"
 	"github.com/labstack/echo/v4"
 )
 
 const (
-	idPath    = "/:id"
-	voidPath  = ""
-	dniPath   = "/dni"
-	loginPath = "/login"
+	// Cada cuánto se eliminan las copias de recibos y QR que superan la retención
+	receiptRetentionInterval = 6 * time.Hour
+	// Cada cuánto se buscan citas próximas para encolar sus recordatorios
+	reminderScanInterval = 5 * time.Minute
+	// Cada cuánto se marcan como vencidas las citas impagas
+	expireBookingsInterval = time.Hour
+	// Cada cuánto se reparten los eventos del outbox; el runner igual los toma a su ritmo (JOB_POLL_SECONDS)
+	dispatchEventsInterval = 5 * time.Second
+)
+
+const (
+	idPath             = "/:id"
+	voidPath           = ""
+	dniPath            = "/dni"
+	loginPath          = "/login"
+	closePath          = "/:id/close"
+	reportPath         = "/:id/report"
+	reportPDFPath      = "/:id/report/pdf"
+	quotePath          = "/quote"
+	policiesPath       = "/:id/policies"
+	statusPath         = "/:id/status"
+	paymentsPath       = "/:id/payments"
+	csvPath            = "/:id/csv"
+	batchPath          = "/:id/batch"
+	receivablesPath    = "/receivables"
+	creditsPath        = "/:id/package-credits"
+	priceHistoryPath   = "/:id/price-history"
+	availabilityPath   = "/availability"
+	encounterPath      = "/:id/encounter"
+	signPath           = "/:id/sign"
+	allergiesPath      = "/:id/allergies"
+	conditionsPath     = "/:id/chronic-conditions"
+	medicationsPath    = "/:id/medications"
+	familyHistoryPath  = "/:id/family-history"
+	medicalSummaryPath = "/:id/medical-summary"
+	recordPath         = "/:record_id"
+	prescriptionsPath  = "/:id/prescriptions"
+	pdfPath            = "/:id/pdf"
+	verifyPath         = "/verify/:code"
+	documentsPath      = "/:id/documents"
+	downloadURLPath    = "/:id/download-url"
+	downloadPath       = "/:id/download"
+	receiptPath        = "/:id/receipt"
+	qrPath             = "/:id/qr"
+	refundPath         = "/:id/refund"
+	receiptVerifyPath  = "/verify/:token"
+	invoicePath        = "/:id/invoice"
+	confirmationPath   = "/:id/confirmation"
+	preferencesPath    = "/:id/notification-preferences"
+	notificationsPath  = "/:id/notifications"
+	retryPath          = "/:id/retry"
+	deliveriesPath     = "/:id/deliveries"
+	replayPath         = "/deliveries/:id/replay"
+	mergePath          = "/:id/merge"
 )
 
 func InitEnpoints(e *echo.Echo) {
 	api := e.Group("/api/v1")
+
+	// Documentos, recibos y QR se guardan en el almacenamiento elegido con STORAGE_DRIVER
+	fileStore, err := storage.New(config.Envs)
+	if err != nil {
+		log.Fatalf("Error initializing file storage: %v", err)
+	}
+
+	// Recibos, comprobantes y confirmaciones se imprimen con la marca y las plantillas de la clínica
+	renderer, err := pdftemplate.NewRenderer(config.Envs)
+	if err != nil {
+		log.Fatalf("Error initializing PDF templates: %v", err)
+	}
+
+	// Avisos al paciente por correo y SMS; los canales se eligen con NOTIFICATION_EMAIL_DRIVER y NOTIFICATION_SMS_DRIVER
+	emailChannel, err := notification.NewEmailChannel(config.Envs)
+	if err != nil {
+		log.Fatalf("Error initializing email channel: %v", err)
+	}
+
+	smsChannel, err := notification.NewSMSChannel(config.Envs)
+	if err != nil {
+		log.Fatalf("Error initializing sms channel: %v", err)
+	}
+
+	// Trabajos en segundo plano guardados en la base de datos; cada área registra los suyos
+	jobRepository := repository.NewJobRepository(db.GDB)
+	runner := jobs.NewRunner(jobRepository, time.Duration(config.Envs.JobPollSeconds)*time.Second)
+
+	// Eventos del dominio que se guardan con cada cambio y se entregan a los suscriptores por el runner
+	dispatcher := events.NewDispatcher(repository.NewOutboxRepository(db.GDB), runner, dispatchEventsInterval)
+
+	notificationLogic := logic.NewNotificationLogic(
+		repository.NewNotificationRepository(db.GDB),
+		repository.NewRepository[model.Patient](db.GDB),
+		repository.NewRepository[model.Doctor](db.GDB),
+		emailChannel,
+		smsChannel,
+	)
+
 	setUpService(api)
+	setUpServiceCategory(api)
 	setUpPackage(api)
 	setUpDoctor(api)
+	setUpSpecialty(api)
 	setUpPatient(api)
 	setUpAuth(api)
 	setUpAppointment(api)
-	setUpPayment(api)
+	setUpPayment(api, fileStore, renderer, notificationLogic, runner, dispatcher)
+	setUpCashSession(api)
+	setUpPricingRule(api)
+	setUpExchangeRate(api)
+	setUpCoupon(api)
+	setUpInsurance(api)
+	setUpClaim(api)
+	setUpPackagePurchase(api)
+	setUpEncounter(api)
+	setUpPrescription(api)
+	setUpDocument(api, fileStore)
+	setUpConfirmation(api, renderer)
+	setUpNotification(api, notificationLogic)
+	setUpJob(api, runner, jobRepository, notificationLogic)
+	setUpWebhook(api, runner, dispatcher)
+
+	runner.Start()
 }
 
 func setUpAuth(api *echo.Group) {
@@ -41,41 +158,84 @@ func setUpAuth(api *echo.Group) {
 func setUpService(api *echo.Group) {
 	serviceRepository := repository.NewRepository[model.Service](db.GDB)
 	serviceRepositoryMain := repository.NewServiceRepository(db.GDB)
-	serviceLogic := logic.NewServiceLogic(serviceRepository, serviceRepositoryMain)
+	priceHistoryRepositoryMain := repository.NewPriceHistoryRepository(db.GDB)
+	serviceCategoryRepositoryMain := repository.NewServiceCategoryRepository(db.GDB)
+	specialtyRepositoryMain := repository.NewSpecialtyRepository(db.GDB)
+	serviceLogic := logic.NewServiceLogic(serviceRepository, serviceRepositoryMain, priceHistoryRepositoryMain, serviceCategoryRepositoryMain, specialtyRepositoryMain)
 	serviceHandler := handler.NewServiceHandler(serviceLogic)
 
 	service := api.Group("/services")
 
 	service.GET(idPath, auth.ValidateJWT(serviceHandler.GetServiceByID))
 	service.GET(voidPath, auth.ValidateJWT(serviceHandler.GetAllServices))
+	service.GET(priceHistoryPath, auth.ValidateJWT(serviceHandler.GetServicePriceHistory))
 	service.POST(voidPath, auth.ValidateJWT(serviceHandler.CreateService))
 	service.PUT(idPath, auth.ValidateJWT(serviceHandler.UpdateService))
+	service.PUT(statusPath, auth.ValidateJWT(serviceHandler.UpdateServiceStatus))
 	service.DELETE(idPath, auth.ValidateJWT(serviceHandler.DeleteService))
 }
 
+func setUpServiceCategory(api *echo.Group) {
+	serviceCategoryRepository := repository.NewRepository[model.ServiceCategory](db.GDB)
+	serviceCategoryRepositoryMain := repository.NewServiceCategoryRepository(db.GDB)
+	serviceCategoryLogic := logic.NewServiceCategoryLogic(serviceCategoryRepository, serviceCategoryRepositoryMain)
+	serviceCategoryHandler := handler.NewServiceCategoryHandler(serviceCategoryLogic)
+
+	category := api.Group("/service-categories")
+
+	category.GET(idPath, auth.ValidateJWT(serviceCategoryHandler.GetCategoryByID))
+	category.GET(voidPath, auth.ValidateJWT(serviceCategoryHandler.GetAllCategories))
+	category.POST(voidPath, auth.ValidateJWT(auth.RequireRole(serviceCategoryHandler.CreateCategory, model.RoleAdmin)))
+	category.PUT(idPath, auth.ValidateJWT(auth.RequireRole(serviceCategoryHandler.UpdateCategory, model.RoleAdmin)))
+	category.DELETE(idPath, auth.ValidateJWT(auth.RequireRole(serviceCategoryHandler.DeleteCategory, model.RoleAdmin)))
+}
+
+func setUpSpecialty(api *echo.Group) {
+	specialtyRepository := repository.NewRepository[model.Specialty](db.GDB)
+	specialtyRepositoryMain := repository.NewSpecialtyRepository(db.GDB)
+	specialtyLogic := logic.NewSpecialtyLogic(specialtyRepository, specialtyRepositoryMain)
+	specialtyHandler := handler.NewSpecialtyHandler(specialtyLogic)
+
+	specialty := api.Group("/specialties")
+
+	specialty.GET(idPath, auth.ValidateJWT(specialtyHandler.GetSpecialtyByID))
+	specialty.GET(voidPath, auth.ValidateJWT(specialtyHandler.GetAllSpecialties))
+	specialty.POST(voidPath, auth.ValidateJWT(auth.RequireRole(specialtyHandler.CreateSpecialty, model.RoleAdmin)))
+	specialty.PUT(idPath, auth.ValidateJWT(auth.RequireRole(specialtyHandler.UpdateSpecialty, model.RoleAdmin)))
+	specialty.DELETE(idPath, auth.ValidateJWT(auth.RequireRole(specialtyHandler.DeleteSpecialty, model.RoleAdmin)))
+	specialty.POST(mergePath, auth.ValidateJWT(auth.RequireRole(specialtyHandler.MergeSpecialty, model.RoleAdmin)))
+}
+
 func setUpPackage(api *echo.Group) {
 	packageRepository := repository.NewRepository[model.Package](db.GDB)
 	packageRepositoryMain := repository.NewPackageRepository(db.GDB)
 	serviceRepository := repository.NewRepository[model.Service](db.GDB)
 	serviceRepositoryMain := repository.NewServiceRepository(db.GDB)
+	pricingRuleRepositoryMain := repository.NewPricingRuleRepository(db.GDB)
+	exchangeRateRepository := repository.NewRepository[model.ExchangeRate](db.GDB)
+	exchangeRateRepositoryMain := repository.NewExchangeRateRepository(db.GDB)
 
-	packageLogic := logic.NewPackageLogic(packageRepository, packageRepositoryMain, serviceRepository, serviceRepositoryMain)
-	serviceLogic := logic.NewServiceLogic(serviceRepository, serviceRepositoryMain)
+	currencyLogic := logic.NewCurrencyLogic(exchangeRateRepository, exchangeRateRepositoryMain)
+	priceHistoryRepositoryMain := repository.NewPriceHistoryRepository(db.GDB)
+	packageLogic := logic.NewPackageLogic(packageRepository, packageRepositoryMain, serviceRepository, serviceRepositoryMain, pricingRuleRepositoryMain, currencyLogic, priceHistoryRepositoryMain)
+	serviceLogic := logic.NewServiceLogic(serviceRepository, serviceRepositoryMain, priceHistoryRepositoryMain, repository.NewServiceCategoryRepository(db.GDB), repository.NewSpecialtyRepository(db.GDB))
 	packageHandler := handler.NewPackageHandler(packageLogic, serviceLogic)
 
 	packageServices := api.Group("/packages")
 
 	packageServices.GET(idPath, auth.ValidateJWT(packageHandler.GetPackageByID))
 	packageServices.GET(voidPath, auth.ValidateJWT(packageHandler.GetAllPackages))
+	packageServices.GET(priceHistoryPath, auth.ValidateJWT(packageHandler.GetPackagePriceHistory))
 	packageServices.POST(voidPath, auth.ValidateJWT(packageHandler.CreatePackage))
 	packageServices.PUT(idPath, auth.ValidateJWT(packageHandler.UpdatePackage))
+	packageServices.PUT(statusPath, auth.ValidateJWT(packageHandler.UpdatePackageStatus))
 	packageServices.DELETE(idPath, auth.ValidateJWT(packageHandler.DeletePackage))
 }
 
 func setUpDoctor(api *echo.Group) {
 	doctorRepository := repository.NewRepository[model.Doctor](db.GDB)
 	doctorRepositoryMain := repository.NewDoctorRepository(db.GDB)
-	doctorLogic := logic.NewDoctorLogic(doctorRepository, doctorRepositoryMain)
+	doctorLogic := logic.NewDoctorLogic(doctorRepository, doctorRepositoryMain, repository.NewSpecialtyRepository(db.GDB), repository.NewAppointmentRepository(db.GDB))
 	doctorHandler := handler.NewDoctorHandler(doctorLogic)
 
 	doctor := api.Group("/doctors")
@@ -83,6 +243,7 @@ func setUpDoctor(api *echo.Group) {
 	doctor.GET(idPath, auth.ValidateJWT(doctorHandler.GetDoctorByID))
 	doctor.GET(voidPath, auth.ValidateJWT(doctorHandler.GetAllDoctors))
 	doctor.GET(dniPath, auth.ValidateJWT(doctorHandler.GetDoctorByDNI))
+	doctor.GET(availabilityPath, auth.ValidateJWT(doctorHandler.GetAvailability))
 	doctor.POST(voidPath, auth.ValidateJWT(doctorHandler.CreateDoctor))
 	doctor.PUT(idPath, auth.ValidateJWT(doctorHandler.UpdateDoctor))
 	doctor.DELETE(idPath, auth.ValidateJWT(doctorHandler.DeleteDoctor))
@@ -92,7 +253,9 @@ func setUpPatient(api *echo.Group) {
 	patientRepository := repository.NewRepository[model.Patient](db.GDB)
 	patientRepositoryMain := repository.NewPatientRepository(db.GDB)
 	appointmentRepositoryMain := repository.NewAppointmentRepository(db.GDB)
-	patientLogic := logic.NewPatientLogic(patientRepository, patientRepositoryMain, appointmentRepositoryMain)
+	insuranceRepositoryMain := repository.NewInsuranceRepository(db.GDB)
+	medicalHistoryRepositoryMain := repository.NewMedicalHistoryRepository(db.GDB)
+	patientLogic := logic.NewPatientLogic(patientRepository, patientRepositoryMain, appointmentRepositoryMain, insuranceRepositoryMain, medicalHistoryRepositoryMain)
 	patientHandler := handler.NewPatientHandler(patientLogic)
 
 	patient := api.Group("/patients")
@@ -103,6 +266,81 @@ func setUpPatient(api *echo.Group) {
 	patient.POST(voidPath, auth.ValidateJWT(patientHandler.CreatePatient))
 	patient.PUT(idPath, auth.ValidateJWT(patientHandler.UpdatePatient))
 	patient.DELETE(idPath, auth.ValidateJWT(patientHandler.DeletePatient))
+
+	setUpMedicalHistory(patient, patientRepository, patientRepositoryMain)
+}
+
+// Historial médico estructurado bajo /patients/:id con un resumen para el médico que abre la cita
+func setUpMedicalHistory(patient *echo.Group, patientRepository repository.Repository[model.Patient], patientRepositoryMain repository.PatientRepository) {
+	allergyRepositoryMain := repository.NewPatientRecordRepository[model.PatientAllergy](db.GDB)
+	conditionRepositoryMain := repository.NewPatientRecordRepository[model.ChronicCondition](db.GDB)
+	medicationRepositoryMain := repository.NewPatientRecordRepository[model.PatientMedication](db.GDB)
+	familyRepositoryMain := repository.NewPatientRecordRepository[model.FamilyHistory](db.GDB)
+
+	allergyLogic := logic.NewAllergyLogic(repository.NewRepository[model.PatientAllergy](db.GDB), allergyRepositoryMain, patientRepository)
+	conditionLogic := logic.NewChronicConditionLogic(repository.NewRepository[model.ChronicCondition](db.GDB), conditionRepositoryMain, patientRepository)
+	medicationLogic := logic.NewMedicationLogic(repository.NewRepository[model.PatientMedication](db.GDB), medicationRepositoryMain, patientRepository)
+	familyLogic := logic.NewFamilyHistoryLogic(repository.NewRepository[model.FamilyHistory](db.GDB), familyRepositoryMain, patientRepository)
+	medicalHistoryLogic := logic.NewMedicalHistoryLogic(
+		patientRepository,
+		patientRepositoryMain,
+		allergyRepositoryMain,
+		conditionRepositoryMain,
+		medicationRepositoryMain,
+		familyRepositoryMain,
+	)
+
+	setUpPatientRecord(patient, allergiesPath, handler.NewPatientRecordHandler(allergyLogic, "allergies"))
+	setUpPatientRecord(patient, conditionsPath, handler.NewPatientRecordHandler(conditionLogic, "chronic conditions"))
+	setUpPatientRecord(patient, medicationsPath, handler.NewPatientRecordHandler(medicationLogic, "medications"))
+	setUpPatientRecord(patient, familyHistoryPath, handler.NewPatientRecordHandler(familyLogic, "family history"))
+
+	medicalHistoryHandler := handler.NewMedicalHistoryHandler(medicalHistoryLogic)
+
+	patient.GET(medicalSummaryPath, auth.ValidateJWT(auth.RequireRole(medicalHistoryHandler.GetMedicalSummary, model.RoleAdmin, model.RoleDoctor)))
+}
+
+// Los antecedentes clínicos solo los consultan y registran médicos y administradores, como las consultas
+func setUpPatientRecord[T any](patient *echo.Group, path string, recordHandler *handler.PatientRecordHandler[T]) {
+	patient.GET(path, auth.ValidateJWT(auth.RequireRole(recordHandler.GetRecords, model.RoleAdmin, model.RoleDoctor)))
+	patient.POST(path, auth.ValidateJWT(auth.RequireRole(recordHandler.CreateRecord, model.RoleAdmin, model.RoleDoctor)))
+	patient.PUT(path+recordPath, auth.ValidateJWT(auth.RequireRole(recordHandler.UpdateRecord, model.RoleAdmin, model.RoleDoctor)))
+	patient.DELETE(path+recordPath, auth.ValidateJWT(auth.RequireRole(recordHandler.DeleteRecord, model.RoleAdmin, model.RoleDoctor)))
+}
+
+func setUpInsurance(api *echo.Group) {
+	providerRepository := repository.NewRepository[model.InsuranceProvider](db.GDB)
+	planRepository := repository.NewRepository[model.InsurancePlan](db.GDB)
+	policyRepository := repository.NewRepository[model.PatientPolicy](db.GDB)
+	insuranceRepositoryMain := repository.NewInsuranceRepository(db.GDB)
+	patientRepository := repository.NewRepository[model.Patient](db.GDB)
+	insuranceLogic := logic.NewInsuranceLogic(providerRepository, planRepository, policyRepository, insuranceRepositoryMain, patientRepository)
+	insuranceHandler := handler.NewInsuranceHandler(insuranceLogic)
+
+	provider := api.Group("/insurance-providers")
+
+	provider.GET(idPath, auth.ValidateJWT(insuranceHandler.GetInsuranceProviderByID))
+	provider.GET(voidPath, auth.ValidateJWT(insuranceHandler.GetAllInsuranceProviders))
+	provider.POST(voidPath, auth.ValidateJWT(auth.RequireRole(insuranceHandler.CreateInsuranceProvider, model.RoleAdmin)))
+	provider.PUT(idPath, auth.ValidateJWT(auth.RequireRole(insuranceHandler.UpdateInsuranceProvider, model.RoleAdmin)))
+	provider.DELETE(idPath, auth.ValidateJWT(auth.RequireRole(insuranceHandler.DeleteInsuranceProvider, model.RoleAdmin)))
+
+	plan := api.Group("/insurance-plans")
+
+	plan.GET(idPath, auth.ValidateJWT(insuranceHandler.GetInsurancePlanByID))
+	plan.GET(voidPath, auth.ValidateJWT(insuranceHandler.GetAllInsurancePlans))
+	plan.POST(voidPath, auth.ValidateJWT(auth.RequireRole(insuranceHandler.CreateInsurancePlan, model.RoleAdmin)))
+	plan.PUT(idPath, auth.ValidateJWT(auth.RequireRole(insuranceHandler.UpdateInsurancePlan, model.RoleAdmin)))
+	plan.DELETE(idPath, auth.ValidateJWT(auth.RequireRole(insuranceHandler.DeleteInsurancePlan, model.RoleAdmin)))
+
+	policy := api.Group("/patient-policies")
+
+	policy.GET(idPath, auth.ValidateJWT(insuranceHandler.GetPatientPolicyByID))
+	policy.POST(voidPath, auth.ValidateJWT(insuranceHandler.CreatePatientPolicy))
+	policy.PUT(idPath, auth.ValidateJWT(insuranceHandler.UpdatePatientPolicy))
+	policy.DELETE(idPath, auth.ValidateJWT(insuranceHandler.DeletePatientPolicy))
+
+	api.GET("/patients"+policiesPath, auth.ValidateJWT(insuranceHandler.GetPatientPolicies))
 }
 
 func setUpAppointment(api *echo.Group) {
@@ -115,11 +353,27 @@ func setUpAppointment(api *echo.Group) {
 	serviceRepo := repository.NewRepository[model.Service](db.GDB)
 	packageRepo := repository.NewRepository[model.Package](db.GDB)
 	packageRepoMain := repository.NewPackageRepository(db.GDB)
+	pricingRuleRepoMain := repository.NewPricingRuleRepository(db.GDB)
+	exchangeRateRepo := repository.NewRepository[model.ExchangeRate](db.GDB)
+	exchangeRateRepoMain := repository.NewExchangeRateRepository(db.GDB)
+	currencyLogic := logic.NewCurrencyLogic(exchangeRateRepo, exchangeRateRepoMain)
+	couponRepoMain := repository.NewCouponRepository(db.GDB)
+	appointmentCouponLogic := appointment.NewAppointmentCoupon(appointmentRepoMain, couponRepoMain)
+	insuranceLogic := logic.NewInsuranceLogic(
+		repository.NewRepository[model.InsuranceProvider](db.GDB),
+		repository.NewRepository[model.InsurancePlan](db.GDB),
+		repository.NewRepository[model.PatientPolicy](db.GDB),
+		repository.NewInsuranceRepository(db.GDB),
+		patientRepo,
+	)
 	appointmentDoctorLogic := appointment.NewAppointmentDoctorID(doctorRepo)
-	appointmentServiceIDLogic := appointment.NewAppointmentServiceID(appointmentRepo, serviceRepo)
-	appointmentPackageIDLogic := appointment.NewAppointmentPackageID(packageRepoMain)
+	appointmentServiceIDLogic := appointment.NewAppointmentServiceID(appointmentRepo, serviceRepo, pricingRuleRepoMain, currencyLogic)
+	appointmentPackageIDLogic := appointment.NewAppointmentPackageID(packageRepoMain, pricingRuleRepoMain, currencyLogic)
 	appointmentTimeLogic := appointment.NewAppointmentTime(appointmentRepoMain, doctorRepo)
 	appointmentDoctor := appointment.NewAppointmentDoctorID(doctorRepo)
+	appointmentCreditLogic := appointment.NewAppointmentCredit(repository.NewPackageCreditRepository(db.GDB))
+	appointmentItemsLogic := appointment.NewAppointmentItems(appointmentServiceIDLogic, appointmentPackageIDLogic)
+	appointmentServicesLogic := appointment.NewAppointmentServices(repository.NewDoctorRepository(db.GDB), serviceRepo, packageRepoMain)
 
 	logicAppointmentCreate := appointment.NewAppointmentCreate(
 		appointmentRepo,
@@ -132,10 +386,17 @@ func setUpAppointment(api *echo.Group) {
 		appointmentPackageIDLogic,
 		appointmentServiceIDLogic,
 		appointmentTimeLogic,
+		currencyLogic,
+		appointmentCouponLogic,
+		insuranceLogic,
+		appointmentCreditLogic,
+		appointmentItemsLogic,
+		appointmentServicesLogic,
 	)
 
 	logicAppointmentUpdate := appointment.NewAppointmentUpdate(
 		appointmentRepo,
+		appointmentRepoMain,
 		doctorRepo,
 		serviceRepo,
 		packageRepo,
@@ -145,6 +406,11 @@ func setUpAppointment(api *echo.Group) {
 		appointmentPackageIDLogic,
 		appointmentServiceIDLogic,
 		appointmentTimeLogic,
+		appointmentCouponLogic,
+		insuranceLogic,
+		appointmentCreditLogic,
+		appointmentItemsLogic,
+		appointmentServicesLogic,
 	)
 
 	logicAppointment := appointment.NewAppointmentLogic(
@@ -159,22 +425,283 @@ func setUpAppointment(api *echo.Group) {
 		logicAppointmentUpdate,
 	)
 
-	appointmentHandler := handler.NewAppointmentHandler(logicAppointment)
+	medicalHistoryLogic := logic.NewMedicalHistoryLogic(
+		patientRepo,
+		patientRepoMain,
+		repository.NewPatientRecordRepository[model.PatientAllergy](db.GDB),
+		repository.NewPatientRecordRepository[model.ChronicCondition](db.GDB),
+		repository.NewPatientRecordRepository[model.PatientMedication](db.GDB),
+		repository.NewPatientRecordRepository[model.FamilyHistory](db.GDB),
+	)
+
+	appointmentHandler := handler.NewAppointmentHandler(logicAppointment, medicalHistoryLogic)
 
 	appointment := api.Group("/appointments")
 	appointment.GET(idPath, auth.ValidateJWT(appointmentHandler.GetAppointmentByID))
 	appointment.GET(voidPath, auth.ValidateJWT(appointmentHandler.GetAllAppointments))
 	appointment.POST(voidPath, auth.ValidateJWT(appointmentHandler.CreateAppointment))
+	appointment.POST(quotePath, auth.ValidateJWT(appointmentHandler.QuoteAppointment))
 	appointment.PUT(idPath, auth.ValidateJWT(appointmentHandler.UpdateAppointment))
+	appointment.PUT(statusPath, auth.ValidateJWT(appointmentHandler.UpdateAppointmentStatus))
 	appointment.DELETE(idPath, auth.ValidateJWT(appointmentHandler.DeleteAppointment))
 }
 
-func setUpPayment(api *echo.Group) {
+func setUpPayment(api *echo.Group, fileStore storage.FileStore, renderer *pdftemplate.Renderer, notificationLogic logic.NotificationLogic, runner *jobs.Runner, dispatcher *events.Dispatcher) {
 	paymentRepositoryMain := repository.NewAppointmentRepository(db.GDB)
-	paymentLogic := logic.NewPaymentLogic(paymentRepositoryMain)
+	paymentRepository := repository.NewPaymentRepository(db.GDB)
+	cashSessionRepositoryMain := repository.NewCashSessionRepository(db.GDB)
+	exchangeRateRepository := repository.NewRepository[model.ExchangeRate](db.GDB)
+	exchangeRateRepositoryMain := repository.NewExchangeRateRepository(db.GDB)
+	currencyLogic := logic.NewCurrencyLogic(exchangeRateRepository, exchangeRateRepositoryMain)
+	paymentLogic := logic.NewPaymentLogic(paymentRepositoryMain, paymentRepository, cashSessionRepositoryMain, currencyLogic, fileStore, renderer)
 	paymentHandler := handler.NewPaymentHandler(paymentLogic)
 
+	runner.Every(logic.JobPurgeReceipts, receiptRetentionInterval, logic.PurgeReceiptsJob(paymentLogic))
+	logic.SubscribeNotifications(dispatcher, notificationLogic, paymentLogic, paymentRepositoryMain, paymentRepository)
+
 	payment := api.Group("/payment/register")
 
 	payment.POST(voidPath, auth.ValidateJWT(paymentHandler.PaymentRegister))
+
+	payments := api.Group("/payments")
+
+	payments.GET(receiptPath, auth.ValidateJWT(paymentHandler.GetPaymentReceipt))
+	payments.GET(invoicePath, auth.ValidateJWT(paymentHandler.GetPaymentInvoice))
+	payments.GET(qrPath, auth.ValidateJWT(paymentHandler.GetPaymentQRCode))
+	payments.POST(refundPath, auth.ValidateJWT(auth.RequireRole(paymentHandler.RefundPayment, model.RoleAdmin)))
+
+	api.GET(receiptVerifyPath, paymentHandler.VerifyReceipt)
+}
+
+func setUpCashSession(api *echo.Group) {
+	cashSessionRepositoryMain := repository.NewCashSessionRepository(db.GDB)
+	paymentRepository := repository.NewPaymentRepository(db.GDB)
+	exchangeRateRepository := repository.NewRepository[model.ExchangeRate](db.GDB)
+	exchangeRateRepositoryMain := repository.NewExchangeRateRepository(db.GDB)
+	currencyLogic := logic.NewCurrencyLogic(exchangeRateRepository, exchangeRateRepositoryMain)
+	cashSessionLogic := logic.NewCashSessionLogic(cashSessionRepositoryMain, paymentRepository, currencyLogic)
+	cashSessionHandler := handler.NewCashSessionHandler(cashSessionLogic)
+
+	cashSession := api.Group("/cash-sessions")
+
+	cashSession.GET(idPath, auth.ValidateJWT(cashSessionHandler.GetCashSessionByID))
+	cashSession.GET(voidPath, auth.ValidateJWT(cashSessionHandler.GetAllCashSessions))
+	cashSession.GET(reportPath, auth.ValidateJWT(cashSessionHandler.GetCashSessionReport))
+	cashSession.GET(reportPDFPath, auth.ValidateJWT(cashSessionHandler.GetCashSessionReportPDF))
+	cashSession.POST(voidPath, auth.ValidateJWT(cashSessionHandler.OpenCashSession))
+	cashSession.PUT(closePath, auth.ValidateJWT(cashSessionHandler.CloseCashSession))
+}
+
+func setUpPricingRule(api *echo.Group) {
+	pricingRuleRepository := repository.NewRepository[model.PricingRule](db.GDB)
+	packageRepositoryMain := repository.NewPackageRepository(db.GDB)
+	pricingRuleLogic := logic.NewPricingRuleLogic(pricingRuleRepository, packageRepositoryMain)
+	pricingRuleHandler := handler.NewPricingRuleHandler(pricingRuleLogic)
+
+	pricingRule := api.Group("/pricing-rules")
+
+	pricingRule.GET(idPath, auth.ValidateJWT(auth.RequireRole(pricingRuleHandler.GetPricingRuleByID, model.RoleAdmin)))
+	pricingRule.GET(voidPath, auth.ValidateJWT(auth.RequireRole(pricingRuleHandler.GetAllPricingRules, model.RoleAdmin)))
+	pricingRule.POST(voidPath, auth.ValidateJWT(auth.RequireRole(pricingRuleHandler.CreatePricingRule, model.RoleAdmin)))
+	pricingRule.PUT(idPath, auth.ValidateJWT(auth.RequireRole(pricingRuleHandler.UpdatePricingRule, model.RoleAdmin)))
+	pricingRule.DELETE(idPath, auth.ValidateJWT(auth.RequireRole(pricingRuleHandler.DeletePricingRule, model.RoleAdmin)))
+}
+
+func setUpExchangeRate(api *echo.Group) {
+	exchangeRateRepository := repository.NewRepository[model.ExchangeRate](db.GDB)
+	exchangeRateRepositoryMain := repository.NewExchangeRateRepository(db.GDB)
+	currencyLogic := logic.NewCurrencyLogic(exchangeRateRepository, exchangeRateRepositoryMain)
+	exchangeRateHandler := handler.NewExchangeRateHandler(currencyLogic)
+
+	exchangeRate := api.Group("/exchange-rates")
+
+	exchangeRate.GET(idPath, auth.ValidateJWT(exchangeRateHandler.GetExchangeRateByID))
+	exchangeRate.GET(voidPath, auth.ValidateJWT(exchangeRateHandler.GetAllExchangeRates))
+	exchangeRate.POST(voidPath, auth.ValidateJWT(auth.RequireRole(exchangeRateHandler.CreateExchangeRate, model.RoleAdmin)))
+	exchangeRate.DELETE(idPath, auth.ValidateJWT(auth.RequireRole(exchangeRateHandler.DeleteExchangeRate, model.RoleAdmin)))
+}
+
+func setUpCoupon(api *echo.Group) {
+	couponRepository := repository.NewRepository[model.Coupon](db.GDB)
+	couponRepositoryMain := repository.NewCouponRepository(db.GDB)
+	serviceRepository := repository.NewRepository[model.Service](db.GDB)
+	packageRepository := repository.NewRepository[model.Package](db.GDB)
+	couponLogic := logic.NewCouponLogic(couponRepository, couponRepositoryMain, serviceRepository, packageRepository)
+	couponHandler := handler.NewCouponHandler(couponLogic)
+
+	coupon := api.Group("/coupons")
+
+	coupon.GET(idPath, auth.ValidateJWT(couponHandler.GetCouponByID))
+	coupon.GET(voidPath, auth.ValidateJWT(couponHandler.GetAllCoupons))
+	coupon.POST(voidPath, auth.ValidateJWT(auth.RequireRole(couponHandler.CreateCoupon, model.RoleAdmin)))
+	coupon.PUT(idPath, auth.ValidateJWT(auth.RequireRole(couponHandler.UpdateCoupon, model.RoleAdmin)))
+	coupon.DELETE(idPath, auth.ValidateJWT(auth.RequireRole(couponHandler.DeleteCoupon, model.RoleAdmin)))
+}
+
+func setUpClaim(api *echo.Group) {
+	claimRepositoryMain := repository.NewClaimRepository(db.GDB)
+	insuranceRepositoryMain := repository.NewInsuranceRepository(db.GDB)
+	cashSessionRepositoryMain := repository.NewCashSessionRepository(db.GDB)
+	exchangeRateRepository := repository.NewRepository[model.ExchangeRate](db.GDB)
+	exchangeRateRepositoryMain := repository.NewExchangeRateRepository(db.GDB)
+	currencyLogic := logic.NewCurrencyLogic(exchangeRateRepository, exchangeRateRepositoryMain)
+	claimLogic := logic.NewClaimLogic(claimRepositoryMain, insuranceRepositoryMain, cashSessionRepositoryMain, currencyLogic)
+	claimHandler := handler.NewClaimHandler(claimLogic)
+
+	claim := api.Group("/insurance-claims")
+
+	claim.GET(receivablesPath, auth.ValidateJWT(claimHandler.GetReceivables))
+	claim.GET(idPath, auth.ValidateJWT(claimHandler.GetClaimByID))
+	claim.GET(voidPath, auth.ValidateJWT(claimHandler.GetAllClaims))
+	claim.GET(csvPath, auth.ValidateJWT(claimHandler.GetClaimCSV))
+	claim.GET(batchPath, auth.ValidateJWT(claimHandler.GetClaimBatch))
+	claim.POST(voidPath, auth.ValidateJWT(auth.RequireRole(claimHandler.CreateClaim, model.RoleAdmin)))
+	claim.PUT(statusPath, auth.ValidateJWT(auth.RequireRole(claimHandler.UpdateClaimStatus, model.RoleAdmin)))
+	claim.POST(paymentsPath, auth.ValidateJWT(auth.RequireRole(claimHandler.RegisterClaimPayment, model.RoleAdmin)))
+}
+
+func setUpPackagePurchase(api *echo.Group) {
+	packageCreditRepositoryMain := repository.NewPackageCreditRepository(db.GDB)
+	packageRepositoryMain := repository.NewPackageRepository(db.GDB)
+	patientRepository := repository.NewRepository[model.Patient](db.GDB)
+	patientRepositoryMain := repository.NewPatientRepository(db.GDB)
+	pricingRuleRepositoryMain := repository.NewPricingRuleRepository(db.GDB)
+	cashSessionRepositoryMain := repository.NewCashSessionRepository(db.GDB)
+	exchangeRateRepository := repository.NewRepository[model.ExchangeRate](db.GDB)
+	exchangeRateRepositoryMain := repository.NewExchangeRateRepository(db.GDB)
+	currencyLogic := logic.NewCurrencyLogic(exchangeRateRepository, exchangeRateRepositoryMain)
+	packageCreditLogic := logic.NewPackageCreditLogic(
+		packageCreditRepositoryMain,
+		packageRepositoryMain,
+		patientRepository,
+		patientRepositoryMain,
+		pricingRuleRepositoryMain,
+		cashSessionRepositoryMain,
+		currencyLogic,
+	)
+	packageCreditHandler := handler.NewPackageCreditHandler(packageCreditLogic)
+
+	purchase := api.Group("/package-purchases")
+
+	purchase.GET(idPath, auth.ValidateJWT(packageCreditHandler.GetPackagePurchaseByID))
+	purchase.POST(voidPath, auth.ValidateJWT(packageCreditHandler.PurchasePackage))
+
+	api.GET("/patients"+creditsPath, auth.ValidateJWT(packageCreditHandler.GetPatientCredits))
+}
+
+// El registro clínico lo escribe el médico que atendió la cita; los administradores solo lo consultan
+func setUpEncounter(api *echo.Group) {
+	encounterRepositoryMain := repository.NewEncounterRepository(db.GDB)
+	appointmentRepositoryMain := repository.NewAppointmentRepository(db.GDB)
+	encounterLogic := logic.NewEncounterLogic(encounterRepositoryMain, appointmentRepositoryMain)
+	encounterHandler := handler.NewEncounterHandler(encounterLogic)
+
+	encounter := api.Group("/encounters")
+
+	encounter.GET(idPath, auth.ValidateJWT(auth.RequireRole(encounterHandler.GetEncounterByID, model.RoleAdmin, model.RoleDoctor)))
+	encounter.PUT(idPath, auth.ValidateJWT(auth.RequireRole(encounterHandler.UpdateEncounter, model.RoleDoctor)))
+	encounter.PUT(signPath, auth.ValidateJWT(auth.RequireRole(encounterHandler.SignEncounter, model.RoleDoctor)))
+
+	api.GET("/appointments"+encounterPath, auth.ValidateJWT(auth.RequireRole(encounterHandler.GetAppointmentEncounter, model.RoleAdmin, model.RoleDoctor)))
+	api.POST("/appointments"+encounterPath, auth.ValidateJWT(auth.RequireRole(encounterHandler.CreateEncounter, model.RoleDoctor)))
+}
+
+func setUpPrescription(api *echo.Group) {
+	prescriptionRepositoryMain := repository.NewPrescriptionRepository(db.GDB)
+	appointmentRepositoryMain := repository.NewAppointmentRepository(db.GDB)
+	encounterRepositoryMain := repository.NewEncounterRepository(db.GDB)
+	prescriptionLogic := logic.NewPrescriptionLogic(prescriptionRepositoryMain, appointmentRepositoryMain, encounterRepositoryMain)
+	prescriptionHandler := handler.NewPrescriptionHandler(prescriptionLogic)
+
+	prescription := api.Group("/prescriptions")
+
+	prescription.GET(verifyPath, prescriptionHandler.VerifyPrescription)
+	prescription.GET(idPath, auth.ValidateJWT(auth.RequireRole(prescriptionHandler.GetPrescriptionByID, model.RoleAdmin, model.RoleDoctor)))
+	prescription.GET(pdfPath, auth.ValidateJWT(auth.RequireRole(prescriptionHandler.GetPrescriptionPDF, model.RoleAdmin, model.RoleDoctor)))
+
+	api.GET("/appointments"+prescriptionsPath, auth.ValidateJWT(auth.RequireRole(prescriptionHandler.GetAppointmentPrescriptions, model.RoleAdmin, model.RoleDoctor)))
+	api.POST("/appointments"+prescriptionsPath, auth.ValidateJWT(auth.RequireRole(prescriptionHandler.CreatePrescription, model.RoleDoctor)))
+}
+
+// Documentos adjuntos a pacientes y citas. Solo médicos y administradores los consultan;
+// el personal de recepción también puede subirlos (órdenes, carnés del seguro) pero no leerlos
+func setUpDocument(api *echo.Group, fileStore storage.FileStore) {
+	documentRepository := repository.NewRepository[model.Document](db.GDB)
+	documentRepositoryMain := repository.NewDocumentRepository(db.GDB)
+	patientRepository := repository.NewRepository[model.Patient](db.GDB)
+	appointmentRepositoryMain := repository.NewAppointmentRepository(db.GDB)
+	documentLogic := logic.NewDocumentLogic(documentRepository, documentRepositoryMain, patientRepository, appointmentRepositoryMain, fileStore)
+	documentHandler := handler.NewDocumentHandler(documentLogic)
+
+	document := api.Group("/documents")
+
+	document.GET(idPath, auth.ValidateJWT(auth.RequireRole(documentHandler.GetDocumentByID, model.RoleAdmin, model.RoleDoctor)))
+	document.GET(downloadURLPath, auth.ValidateJWT(auth.RequireRole(documentHandler.GetDocumentDownloadURL, model.RoleAdmin, model.RoleDoctor)))
+	document.GET(downloadPath, documentHandler.DownloadDocument)
+	document.DELETE(idPath, auth.ValidateJWT(auth.RequireRole(documentHandler.DeleteDocument, model.RoleAdmin)))
+
+	api.GET("/patients"+documentsPath, auth.ValidateJWT(auth.RequireRole(documentHandler.GetPatientDocuments, model.RoleAdmin, model.RoleDoctor)))
+	api.POST("/patients"+documentsPath, auth.ValidateJWT(auth.RequireRole(documentHandler.UploadPatientDocument, model.RoleAdmin, model.RoleDoctor, model.RoleStaff)))
+	api.GET("/appointments"+documentsPath, auth.ValidateJWT(auth.RequireRole(documentHandler.GetAppointmentDocuments, model.RoleAdmin, model.RoleDoctor)))
+	api.POST("/appointments"+documentsPath, auth.ValidateJWT(auth.RequireRole(documentHandler.UploadAppointmentDocument, model.RoleAdmin, model.RoleDoctor, model.RoleStaff)))
+}
+
+// Confirmación de la cita en PDF
+func setUpConfirmation(api *echo.Group, renderer *pdftemplate.Renderer) {
+	appointmentRepositoryMain := repository.NewAppointmentRepository(db.GDB)
+	doctorRepository := repository.NewRepository[model.Doctor](db.GDB)
+	confirmationLogic := logic.NewConfirmationLogic(appointmentRepositoryMain, doctorRepository, renderer)
+	confirmationHandler := handler.NewConfirmationHandler(confirmationLogic)
+
+	api.GET("/appointments"+confirmationPath, auth.ValidateJWT(confirmationHandler.GetAppointmentConfirmation))
+}
+
+// Preferencias de aviso del paciente y registro de lo enviado
+func setUpNotification(api *echo.Group, notificationLogic logic.NotificationLogic) {
+	notificationHandler := handler.NewNotificationHandler(notificationLogic)
+
+	api.GET("/patients"+preferencesPath, auth.ValidateJWT(notificationHandler.GetNotificationPreference))
+	api.PUT("/patients"+preferencesPath, auth.ValidateJWT(notificationHandler.UpdateNotificationPreference))
+	api.GET("/patients"+notificationsPath, auth.ValidateJWT(notificationHandler.GetPatientNotifications))
+}
+
+// Recordatorios de citas, citas impagas y el estado de los trabajos para administradores
+func setUpJob(api *echo.Group, runner *jobs.Runner, jobRepositoryMain repository.JobRepository, notificationLogic logic.NotificationLogic) {
+	appointmentRepositoryMain := repository.NewAppointmentRepository(db.GDB)
+	reminderLogic := logic.NewReminderLogic(appointmentRepositoryMain, notificationLogic, runner)
+
+	runner.Every(logic.JobScheduleReminders, reminderScanInterval, func(string) error { return reminderLogic.ScheduleReminders() })
+	runner.Every(logic.JobExpireBookings, expireBookingsInterval, func(string) error { return reminderLogic.ExpireUnpaidBookings() })
+	runner.Handle(logic.JobSendReminder, reminderLogic.SendReminder)
+
+	jobLogic := logic.NewJobLogic(repository.NewRepository[model.Job](db.GDB), jobRepositoryMain)
+	jobHandler := handler.NewJobHandler(jobLogic)
+
+	job := api.Group("/jobs")
+
+	job.GET(voidPath, auth.ValidateJWT(auth.RequireRole(jobHandler.GetJobs, model.RoleAdmin)))
+	job.GET(idPath, auth.ValidateJWT(auth.RequireRole(jobHandler.GetJobByID, model.RoleAdmin)))
+	job.POST(retryPath, auth.ValidateJWT(auth.RequireRole(jobHandler.RetryJob, model.RoleAdmin)))
+}
+
+func setUpWebhook(api *echo.Group, runner *jobs.Runner, dispatcher *events.Dispatcher) {
+	webhookLogic := logic.NewWebhookLogic(
+		repository.NewRepository[model.WebhookSubscription](db.GDB),
+		repository.NewWebhookRepository(db.GDB),
+		repository.NewOutboxRepository(db.GDB),
+		webhook.NewSender(time.Duration(config.Envs.WebhookTimeoutSeconds)*time.Second),
+		runner,
+		dispatcher,
+	)
+	webhookHandler := handler.NewWebhookHandler(webhookLogic)
+
+	webhooks := api.Group("/webhooks")
+
+	webhooks.GET(voidPath, auth.ValidateJWT(auth.RequireRole(webhookHandler.GetAllWebhooks, model.RoleAdmin)))
+	webhooks.GET(idPath, auth.ValidateJWT(auth.RequireRole(webhookHandler.GetWebhookByID, model.RoleAdmin)))
+	webhooks.POST(voidPath, auth.ValidateJWT(auth.RequireRole(webhookHandler.CreateWebhook, model.RoleAdmin)))
+	webhooks.PUT(idPath, auth.ValidateJWT(auth.RequireRole(webhookHandler.UpdateWebhook, model.RoleAdmin)))
+	webhooks.DELETE(idPath, auth.ValidateJWT(auth.RequireRole(webhookHandler.DeleteWebhook, model.RoleAdmin)))
+	webhooks.GET(deliveriesPath, auth.ValidateJWT(auth.RequireRole(webhookHandler.GetDeliveries, model.RoleAdmin)))
+	webhooks.POST(replayPath, auth.ValidateJWT(auth.RequireRole(webhookHandler.ReplayDelivery, model.RoleAdmin)))
 }
@@ -0,0 +1,328 @@
+package storage
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "us-east-1"
+	testBucket    = "clinic-documents"
+)
+
+// Bucket en memoria que, como S3, rechaza cualquier petición cuya firma V4 no coincida
+type s3StandIn struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newS3StandIn(t *testing.T) *httptest.Server {
+	standIn := &s3StandIn{t: t, objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if reason := s.checkSignature(r, body); reason != "" {
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code><Message>"+reason+"</Message></Error>", http.StatusForbidden)
+		return
+	}
+
+	bucketPath := "/" + testBucket
+	if r.URL.Path == bucketPath && r.Method == http.MethodGet {
+		s.list(w, r)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, bucketPath+"/")
+	if !ok {
+		http.Error(w, "<Error><Code>NoSuchBucket</Code></Error>", http.StatusNotFound)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[key] = body
+		s.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, exists := s.objects[key]
+		if !exists {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", s.types[key])
+		w.Write(data)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// ListObjectsV2 con páginas de dos objetos para probar la continuación
+func (s *s3StandIn) list(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+	if query.Get("list-type") != "2" {
+		http.Error(w, "list-type=2 expected", http.StatusBadRequest)
+		return
+	}
+
+	keys := []string{}
+	for key := range s.objects {
+		if strings.HasPrefix(key, query.Get("prefix")) && key > query.Get("continuation-token") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var result listBucketResult
+	if len(keys) > 2 {
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[1]
+		keys = keys[:2]
+	}
+
+	for _, key := range keys {
+		result.Contents = append(result.Contents, struct {
+			Key          string    `xml:"Key"`
+			LastModified time.Time `xml:"LastModified"`
+		}{Key: key, LastModified: time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)})
+	}
+
+	xml.NewEncoder(w).Encode(result)
+}
+
+// Vuelve a firmar la petición tal como llegó al servidor y compara con la cabecera Authorization
+func (s *s3StandIn) checkSignature(r *http.Request, body []byte) string {
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash != sha256Hex(body) {
+		return "payload hash does not match the body"
+	}
+
+	amzDate, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil || time.Since(amzDate).Abs() > 15*time.Minute {
+		return "missing or stale X-Amz-Date"
+	}
+
+	var credential, signedHeaders, signature string
+	authorization, ok := strings.CutPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	if !ok {
+		return "unsupported authorization scheme"
+	}
+
+	for _, part := range strings.Split(authorization, ", ") {
+		name, value, _ := strings.Cut(part, "=")
+		switch name {
+		case "Credential":
+			credential = value
+		case "SignedHeaders":
+			signedHeaders = value
+		case "Signature":
+			signature = value
+		}
+	}
+
+	date := amzDate.Format("20060102")
+	scope := date + "/" + testRegion + "/s3/aws4_request"
+	if credential != testAccessKey+"/"+scope {
+		return "unexpected credential " + credential
+	}
+
+	canonicalHeaders := ""
+	for _, header := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(header)
+		if header == "host" {
+			value = r.Host
+		}
+
+		canonicalHeaders += header + ":" + strings.TrimSpace(value) + "\n"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", r.Header.Get("X-Amz-Date"), scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	expected := hex.EncodeToString(hmacSHA256(signingKey(testSecretKey, date, testRegion, "s3"), stringToSign))
+
+	if signature != expected {
+		return "signature mismatch"
+	}
+
+	return ""
+}
+
+func TestS3StorePutGetDelete(t *testing.T) {
+	server := newS3StandIn(t)
+	store := NewS3Store(server.URL+"/", testRegion, testBucket, testAccessKey, testSecretKey)
+
+	keys := []string{
+		"patients/3/9f2c.pdf",
+		"patients/3/informe médico (1).pdf",
+		"patients/3/a+b=c&d.png",
+	}
+
+	for _, key := range keys {
+		t.Run(key, func(t *testing.T) {
+			data := []byte("%PDF-1.4 contenido de " + key)
+
+			err := store.Put(key, data, "application/pdf")
+			if err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			file, err := store.Get(key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+
+			got, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				t.Fatalf("reading object: %v", err)
+			}
+
+			if string(got) != string(data) {
+				t.Errorf("Get returned %q, want %q", got, data)
+			}
+
+			err = store.Delete(key)
+			if err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+
+			_, err = store.Get(key)
+			if !errors.Is(err, ErrObjectNotFound) {
+				t.Errorf("Get after Delete: got %v, want ErrObjectNotFound", err)
+			}
+
+			// S3 no informa si el objeto existía
+			err = store.Delete(key)
+			if err != nil {
+				t.Errorf("deleting a missing object: %v", err)
+			}
+		})
+	}
+}
+
+func TestS3StoreList(t *testing.T) {
+	server := newS3StandIn(t)
+	store := NewS3Store(server.URL, testRegion, testBucket, testAccessKey, testSecretKey)
+
+	for i := 1; i <= 5; i++ {
+		err := store.Put(fmt.Sprintf("receipts/%d.pdf", i), []byte("pdf"), "application/pdf")
+		if err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	err := store.Put("patients/1/x.pdf", []byte("pdf"), "application/pdf")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	objects, err := store.List("receipts/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	if len(objects) != 5 {
+		t.Fatalf("List returned %d objects across pages, want 5: %+v", len(objects), objects)
+	}
+
+	for i, object := range objects {
+		if want := fmt.Sprintf("receipts/%d.pdf", i+1); object.Key != want {
+			t.Errorf("object %d = %s, want %s", i, object.Key, want)
+		}
+	}
+}
+
+func TestS3StoreRejectsWrongSecret(t *testing.T) {
+	server := newS3StandIn(t)
+	store := NewS3Store(server.URL, testRegion, testBucket, testAccessKey, "not-the-secret")
+
+	err := store.Put("patients/1/x.pdf", []byte("pdf"), "application/pdf")
+	if err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("Put with a wrong secret: got %v, want a 403 error", err)
+	}
+
+	_, err = store.Get("patients/1/x.pdf")
+	if err == nil || errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Get with a wrong secret: got %v, want a 403 error", err)
+	}
+}
+
+func TestS3StoreInvalidKey(t *testing.T) {
+	store := NewS3Store("http://127.0.0.1:0", testRegion, testBucket, testAccessKey, testSecretKey)
+
+	for _, key := range []string{"", "/abs", "a/../b", "a//b", `a\b`} {
+		err := store.Put(key, []byte("x"), "text/plain")
+		if err == nil || !strings.Contains(err.Error(), "invalid storage key") {
+			t.Errorf("Put(%q): got %v, want invalid storage key", key, err)
+		}
+	}
+}
+
+// Ejemplo de derivación de la clave de firma publicado en la documentación de AWS Signature V4
+func TestSigningKey(t *testing.T) {
+	got := hex.EncodeToString(signingKey(testSecretKey, "20120215", "us-east-1", "iam"))
+	want := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"
+
+	if got != want {
+		t.Errorf("signingKey = %s, want %s", got, want)
+	}
+}
+
+func TestEscapeKey(t *testing.T) {
+	tests := []struct {
+		key         string
+		encodeSlash bool
+		want        string
+	}{
+		{key: "patients/3/a-b_c.d~e.pdf", want: "patients/3/a-b_c.d~e.pdf"},
+		{key: "informe médico.pdf", want: "informe%20m%C3%A9dico.pdf"},
+		{key: "a+b=c&d", want: "a%2Bb%3Dc%26d"},
+		{key: "receipts/", encodeSlash: true, want: "receipts%2F"},
+	}
+
+	for _, test := range tests {
+		if got := escapeKey(test.key, test.encodeSlash); got != test.want {
+			t.Errorf("escapeKey(%q, %t) = %s, want %s", test.key, test.encodeSlash, got, test.want)
+		}
+	}
+}
@@ -32,12 +32,11 @@ const (
 	MsgEmail       = "El correo electrónico es obligatorio, debe ser válido y no exceder 100 caracteres."
 	MsgPhoneNumber = "El número de teléfono es obligatorio y no puede exceder 20 caracteres."
 	MsgAddress     = "La dirección es obligatoria y no puede exceder 200 caracteres."
-	MsgEspecialty  = "La especialidad es obligatoria y no puede exceder 50 caracteres."
+	MsgSpecialties = "Debe indicar al menos una especialidad."
 	MsgDays        = "Los días son obligatorios."
 	MsgStartTime   = "La hora de inicio es obligatoria (formato HH:mm)."
 	MsgEndTime     = "La hora de finalización es obligatoria (formato HH:mm)."
 	MsgSalary      = "El salario es obligatorio y debe ser un número."
-	MsgInsurance   = "El seguro de salud es obligatorio."
 )
 
 func (c *CustomValidator) Validate(i interface{}) error {
@@ -46,19 +45,18 @@ func (c *CustomValidator) Validate(i interface{}) error {
 		var validationErrorMessages []string
 
 		fieldMessages := map[string]string{
-			"Name":        MsgName,
-			"LastName":    MsgLastName,
-			"DNI":         MsgDNI,
-			"BirthDate":   MsgBirthDate,
-			"Email":       MsgEmail,
-			"PhoneNumber": MsgPhoneNumber,
-			"Address":     MsgAddress,
-			"Especialty":  MsgEspecialty,
-			"Days":        MsgDays,
-			"StartTime":   MsgStartTime,
-			"EndTime":     MsgEndTime,
-			"Salary":      MsgSalary,
-			"Insurance":   MsgInsurance,
+			"Name":         MsgName,
+			"LastName":     MsgLastName,
+			"DNI":          MsgDNI,
+			"BirthDate":    MsgBirthDate,
+			"Email":        MsgEmail,
+			"PhoneNumber":  MsgPhoneNumber,
+			"Address":      MsgAddress,
+			"SpecialtyIDs": MsgSpecialties,
+			"Days":         MsgDays,
+			"StartTime":    MsgStartTime,
+			"EndTime":      MsgEndTime,
+			"Salary":       MsgSalary,
 		}
 
 		for _, e := range err.(validator.ValidationErrors) {
@@ -78,7 +76,12 @@ func (c *CustomValidator) Validate(i interface{}) error {
 }
 
 func ParseID(c echo.Context) (uint, error) {
-	id, err := strconv.Atoi(c.Param("id"))
+	return ParseParamID(c, "id")
+}
+
+// Para rutas con más de un ID, como /patients/:id/allergies/:record_id
+func ParseParamID(c echo.Context, name string) (uint, error) {
+	id, err := strconv.Atoi(c.Param(name))
 	if err != nil || id <= 0 {
 		return 0, response.ErrorInvalidID
 	}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Bytes de la respuesta del receptor que se leen para el registro de envíos
+const maxResponseBody = 1 << 10
+
+// Cuerpo JSON de cada envío; Data es el payload del evento del outbox tal cual se guardó
+type Payload struct {
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Respuesta del receptor a un envío
+type Result struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+}
+
+// Envía los eventos por HTTP POST con su firma
+type Sender struct {
+	client *http.Client
+}
+
+// Las redirecciones no se siguen: el receptor debe responder 2xx en la URL registrada
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Envía el payload a url. Devuelve error si no hubo respuesta o si no fue 2xx; en el segundo caso
+// también devuelve el resultado para dejarlo registrado.
+func (s *Sender) Send(url, secret string, payload *Payload) (*Result, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "clinic-webhooks/1.0")
+	request.Header.Set(HeaderEvent, payload.Type)
+	request.Header.Set(HeaderEventID, strconv.FormatUint(uint64(payload.ID), 10))
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	start := time.Now()
+
+	resp, err := s.client.Do(request)
+	if err != nil {
+		return &Result{Duration: time.Since(start)}, err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+
+	result := &Result{
+		StatusCode: resp.StatusCode,
+		Body:       string(data),
+		Duration:   time.Since(start),
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, fmt.Errorf("webhook receiver responded with status %d", resp.StatusCode)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testPayload() *Payload {
+	return &Payload{
+		ID:        42,
+		Type:      "PaymentRegistered",
+		CreatedAt: time.Date(2026, time.March, 14, 9, 30, 0, 0, time.UTC),
+		Data:      json.RawMessage(`{"payment_id":7}`),
+	}
+}
+
+func TestSendSignsTheRequest(t *testing.T) {
+	var received *http.Request
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	result, err := NewSender(time.Second).Send(server.URL, "whsec_test", testPayload())
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if result.StatusCode != http.StatusOK || result.Body != "ok" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if received.Method != http.MethodPost || received.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected request %s %s", received.Method, received.Header.Get("Content-Type"))
+	}
+
+	if received.Header.Get(HeaderEvent) != "PaymentRegistered" || received.Header.Get(HeaderEventID) != "42" {
+		t.Errorf("unexpected event headers %v", received.Header)
+	}
+
+	if !Verify("whsec_test", received.Header.Get(HeaderTimestamp), body, received.Header.Get(HeaderSignature)) {
+		t.Error("the receiver could not verify the signature")
+	}
+
+	var payload Payload
+	err = json.Unmarshal(body, &payload)
+	if err != nil || payload.ID != 42 || string(payload.Data) != `{"payment_id":7}` {
+		t.Errorf("unexpected body %s (%v)", body, err)
+	}
+}
+
+func TestSendFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		status  int
+	}{
+		{
+			name:    "error del receptor",
+			handler: func(w http.ResponseWriter, r *http.Request) { http.Error(w, "boom", http.StatusInternalServerError) },
+			status:  http.StatusInternalServerError,
+		},
+		{
+			name: "firma rechazada",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:    "redirección",
+			handler: func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/other", http.StatusFound) },
+			status:  http.StatusFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(test.handler)
+			defer server.Close()
+
+			result, err := NewSender(time.Second).Send(server.URL, "whsec_test", testPayload())
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if result == nil || result.StatusCode != test.status {
+				t.Errorf("result = %+v, want status %d", result, test.status)
+			}
+		})
+	}
+}
+
+func TestSendTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	result, err := NewSender(50*time.Millisecond).Send(server.URL, "whsec_test", testPayload())
+	if err == nil || !strings.Contains(err.Error(), "Client.Timeout") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+
+	if result == nil || result.StatusCode != 0 {
+		t.Errorf("result = %+v, want no status", result)
+	}
+}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Cabeceras de cada envío
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Id"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+// Firma HMAC-SHA256 de "timestamp.body" con el secreto de la suscripción. El timestamp va firmado
+// para que el receptor pueda rechazar envíos viejos reenviados por un tercero.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Diferencia máxima entre HeaderTimestamp y el reloj del receptor para aceptar un envío
+const Tolerance = 5 * time.Minute
+
+// Comprueba la firma recibida en HeaderSignature y que el envío no tenga más de Tolerance; la usan
+// los receptores para validar los envíos y rechazar los reenviados por un tercero
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return verifyAt(secret, timestamp, body, signature, time.Now())
+}
+
+func verifyAt(secret, timestamp string, body []byte, signature string, now time.Time) bool {
+	value, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	age := now.Sub(time.Unix(value, 0))
+	if age > Tolerance || age < -Tolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, value, body)), []byte(signature))
+}
+
+// Secreto aleatorio para una suscripción que no indicó uno
+func NewSecret() (string, error) {
+	data := make([]byte, 32)
+
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(data), nil
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Firma calculada aparte con: printf '%s' '1700000000.{"id":1}' | openssl dgst -sha256 -hmac whsec_test
+func TestSign(t *testing.T) {
+	got := Sign("whsec_test", 1700000000, []byte(`{"id":1}`))
+	want := "sha256=2f441ba4b3b2d50d28a9ab9d9fd8880376ecd1eb5d0435401553f5d8d0a5dcf8"
+
+	if got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"id":1,"type":"PaymentRegistered"}`)
+	signature := Sign("whsec_test", now.Unix(), body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		signature string
+		now       time.Time
+		want      bool
+	}{
+		{name: "válida", secret: "whsec_test", timestamp: timestamp, body: body, signature: signature, now: now, want: true},
+		{name: "dentro de la tolerancia", secret: "whsec_test", timestamp: timestamp, body: body, signature: signature, now: now.Add(Tolerance), want: true},
+		{name: "reloj del receptor atrasado", secret: "whsec_test", timestamp: timestamp, body: body, signature: signature, now: now.Add(-Tolerance), want: true},
+		{name: "envío viejo", secret: "whsec_test", timestamp: timestamp, body: body, signature: signature, now: now.Add(Tolerance + time.Second), want: false},
+		{name: "timestamp en el futuro", secret: "whsec_test", timestamp: timestamp, body: body, signature: signature, now: now.Add(-Tolerance - time.Second), want: false},
+		{name: "otro secreto", secret: "whsec_other", timestamp: timestamp, body: body, signature: signature, now: now, want: false},
+		{name: "cuerpo modificado", secret: "whsec_test", timestamp: timestamp, body: []byte(`{"id":2,"type":"PaymentRegistered"}`), signature: signature, now: now, want: false},
+		{name: "timestamp cambiado", secret: "whsec_test", timestamp: strconv.FormatInt(now.Unix()+1, 10), body: body, signature: signature, now: now, want: false},
+		{name: "timestamp inválido", secret: "whsec_test", timestamp: "ayer", body: body, signature: signature, now: now, want: false},
+		{name: "sin prefijo", secret: "whsec_test", timestamp: timestamp, body: body, signature: strings.TrimPrefix(signature, signaturePrefix), now: now, want: false},
+		{name: "sin firma", secret: "whsec_test", timestamp: timestamp, body: body, signature: "", now: now, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := verifyAt(test.secret, test.timestamp, test.body, test.signature, test.now)
+			if got != test.want {
+				t.Errorf("verify = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret: %v", err)
+	}
+
+	second, err := NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret: %v", err)
+	}
+
+	if !strings.HasPrefix(first, "whsec_") || len(first) != len("whsec_")+64 {
+		t.Errorf("unexpected secret format %q", first)
+	}
+
+	if first == second {
+		t.Error("two secrets are equal")
+	}
+}